@@ -1,11 +1,15 @@
 package main
 
 import (
+	"bbrew/internal/logging"
 	"bbrew/internal/services"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
+	_ "net/http/pprof" // Registers the /debug/pprof/ handlers, served by --pprof
 	"os"
+	"time"
 )
 
 func main() {
@@ -13,22 +17,101 @@ func main() {
 	brewfilePath := flag.String("f", "", "Path to Brewfile (show only packages from this Brewfile)")
 	showVersion := flag.Bool("v", false, "Show version information")
 	flag.Bool("version", false, "Show version information")
+	query := flag.String("query", "", "Pre-populate the search field")
+	filterName := flag.String("filter", "", "Activate a filter (installed, outdated, leaves, casks, source, maintained, vulnerable, favorites)")
+	selectName := flag.String("select", "", "Focus a package by name")
+	onlyGroup := flag.String("only-group", "", "Only show packages of a Brewfile section or tag (requires -f)")
+	profileName := flag.String("profile", "", "Open a profile of the config (its Brewfile, group and filter)")
+	pickProfile := flag.Bool("profiles", false, "Open the profile picker at startup")
+	bundle := flag.Bool("bundle", false, "Delegate Brewfile operations to brew bundle (requires -f)")
+	readOnly := flag.Bool("read-only", false, "Disable installing, updating and removing packages")
+	installedOnly := flag.Bool("installed-only", false, "Show only the installed packages, skipping the download of the catalogs")
+	report := flag.String("report", "", "Write a markdown summary of the session to this file on quit")
+	logLevel := flag.String("log-level", "", "Log level of the log file in the state directory (debug, info, warn, error, off)")
+	profileStartup := flag.Bool("profile-startup", false, "Time the startup stages and print the breakdown on quit")
+	pprofAddr := flag.String("pprof", "", "Serve the pprof endpoints on this address (e.g. localhost:6060)")
+	allowIPC := flag.Bool("allow-ipc", false, "Serve a JSON-RPC API on a Unix socket, for other tools to query and drive bbrew")
 
 	// Custom usage message
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Bold Brew - A TUI for Homebrew package management\n\n")
 		fmt.Fprintf(os.Stderr, "Usage: bbrew [options]\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
-		fmt.Fprintf(os.Stderr, "  -f <path|url> Path or URL to Brewfile\n")
-		fmt.Fprintf(os.Stderr, "  -v, --version Show version information\n")
-		fmt.Fprintf(os.Stderr, "  -h, --help    Show this help message\n")
+		fmt.Fprintf(os.Stderr, "  -f <path|url>      Path or URL to Brewfile\n")
+		fmt.Fprintf(os.Stderr, "  --query <text>     Pre-populate the search field\n")
+		fmt.Fprintf(os.Stderr, "  --filter <name>    Activate a filter (installed, outdated, leaves, casks, source, maintained, vulnerable, favorites)\n")
+		fmt.Fprintf(os.Stderr, "  --select <name>    Focus a package by name\n")
+		fmt.Fprintf(os.Stderr, "  --only-group <g>   Only show packages of a Brewfile section or tag (requires -f)\n")
+		fmt.Fprintf(os.Stderr, "  --profile <name>   Open a profile of the config (its Brewfile, group and filter)\n")
+		fmt.Fprintf(os.Stderr, "  --profiles         Open the profile picker at startup\n")
+		fmt.Fprintf(os.Stderr, "  --bundle           Run Brewfile operations through brew bundle (requires -f)\n")
+		fmt.Fprintf(os.Stderr, "  --read-only        Browse only: disable installs, updates, removals and taps\n")
+		fmt.Fprintf(os.Stderr, "  --installed-only   Show only the installed packages, starting without the catalogs\n")
+		fmt.Fprintf(os.Stderr, "  --report <file>    Write a markdown summary of the session on quit\n")
+		fmt.Fprintf(os.Stderr, "  --log-level <l>    Log level: debug, info, warn (default), error, off\n")
+		fmt.Fprintf(os.Stderr, "  --profile-startup  Time the startup stages, printing the breakdown on quit\n")
+		fmt.Fprintf(os.Stderr, "  --pprof <addr>     Serve the pprof endpoints, e.g. on localhost:6060\n")
+		fmt.Fprintf(os.Stderr, "  --allow-ipc        Serve a JSON-RPC API on a Unix socket, for editors and scripts\n")
+		fmt.Fprintf(os.Stderr, "  -v, --version      Show version information\n")
+		fmt.Fprintf(os.Stderr, "  -h, --help         Show this help message\n")
+		fmt.Fprintf(os.Stderr, "\nCommands:\n")
+		fmt.Fprintf(os.Stderr, "  upgrade            Apply the upgrade policies once (auto-upgrade or notify)\n")
+		fmt.Fprintf(os.Stderr, "  upgrade --schedule Run it periodically (launchd agent or systemd timer)\n")
+		fmt.Fprintf(os.Stderr, "  upgrade --serve <addr>\n")
+		fmt.Fprintf(os.Stderr, "                     Keep running it periodically, serving Prometheus metrics on addr\n")
+		fmt.Fprintf(os.Stderr, "  lock -f <Brewfile> Record the installed versions in Brewfile.lock.json\n")
+		fmt.Fprintf(os.Stderr, "  verify -f <Brewfile>\n")
+		fmt.Fprintf(os.Stderr, "                     Report the drift from Brewfile.lock.json (exit code 1 on drift)\n")
+		fmt.Fprintf(os.Stderr, "  ipc <method> [json]\n")
+		fmt.Fprintf(os.Stderr, "                     Query a bbrew running with --allow-ipc (status, list, search, install)\n")
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  bbrew                    Launch the TUI with all packages\n")
 		fmt.Fprintf(os.Stderr, "  bbrew -f ~/Brewfile      Launch with packages from local Brewfile\n")
 		fmt.Fprintf(os.Stderr, "  bbrew -f https://...     Launch with packages from remote Brewfile\n")
+		fmt.Fprintf(os.Stderr, "  bbrew --filter outdated  Launch showing only outdated packages\n")
+		fmt.Fprintf(os.Stderr, "  bbrew --select node      Launch with the node package focused\n")
+		fmt.Fprintf(os.Stderr, "  bbrew --installed-only --filter outdated\n")
+		fmt.Fprintf(os.Stderr, "                           What needs updating, without loading the catalogs\n")
+		fmt.Fprintf(os.Stderr, "  bbrew -f ~/Brewfile --only-group work\n")
+		fmt.Fprintf(os.Stderr, "                           Launch with the \"work\" packages of the Brewfile\n")
+		fmt.Fprintf(os.Stderr, "  bbrew -f ~/Brewfile --report setup.md\n")
+		fmt.Fprintf(os.Stderr, "                           Provision from a Brewfile, keeping a report of the changes\n")
+	}
+
+	// The upgrade subcommand runs without the TUI, e.g. from its scheduled job
+	if len(os.Args) > 1 && os.Args[1] == "upgrade" {
+		runUpgrade(os.Args[2:])
+		return
+	}
+
+	// The lock and verify subcommands pin a Brewfile to the installed versions and check them, without the TUI
+	if len(os.Args) > 1 && (os.Args[1] == "lock" || os.Args[1] == "verify") {
+		runLock(os.Args[1], os.Args[2:])
+		return
+	}
+
+	// The ipc subcommand is a client of the control socket of a running bbrew, for scripts
+	if len(os.Args) > 1 && os.Args[1] == "ipc" {
+		if err := services.RunIPC(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "bbrew ipc: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The askpass subcommand is run by sudo in brew commands, to ask the TUI for the password
+	if len(os.Args) > 1 && os.Args[1] == "askpass" {
+		if err := services.RunAskpass(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "bbrew askpass: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	flag.Parse()
+	if *profileStartup {
+		services.EnableStartupProfile()
+	}
 
 	// Handle --version flag (check both -v and --version)
 	if *showVersion || isFlagPassed("version") {
@@ -36,8 +119,68 @@ func main() {
 		os.Exit(0)
 	}
 
+	// A profile stands for -f, with its group and filter unless given
+	if *profileName != "" {
+		if *brewfilePath != "" {
+			fmt.Fprintf(os.Stderr, "Error: --profile and -f can't be used together\n")
+			os.Exit(1)
+		}
+		profile, err := services.LoadProfile(*profileName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		*brewfilePath = profile.Brewfile
+		if *onlyGroup == "" {
+			*onlyGroup = profile.Group
+		}
+		if *filterName == "" {
+			*filterName = profile.Filter
+		}
+	}
+
+	// Validate the startup filter before doing any work
+	startupFilter := services.FilterNone
+	if *filterName != "" {
+		var err error
+		if startupFilter, err = services.ParseFilterType(*filterName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *onlyGroup != "" && *brewfilePath == "" {
+		fmt.Fprintf(os.Stderr, "Error: --only-group requires a Brewfile (-f)\n")
+		os.Exit(1)
+	}
+
+	if *installedOnly && *brewfilePath != "" {
+		fmt.Fprintf(os.Stderr, "Error: --installed-only can't be used with a Brewfile (-f or --profile)\n")
+		os.Exit(1)
+	}
+
+	if *bundle && *brewfilePath == "" {
+		fmt.Fprintf(os.Stderr, "Error: --bundle requires a Brewfile (-f)\n")
+		os.Exit(1)
+	}
+
+	// Log to the state directory: the TUI owns the terminal
+	closeLog, err := services.SetupLogging(*logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeLog()
+	// Restore the terminal and write a crash report on a panic, before the log is closed
+	defer services.RecoverCrash()
+
+	if *pprofAddr != "" {
+		startPprof(*pprofAddr)
+	}
+
 	// Resolve Brewfile path (handles both local and remote URLs)
 	var cleanup func()
+	brewfileSource := *brewfilePath
 	if *brewfilePath != "" {
 		localPath, cleanupFn, err := services.ResolveBrewfilePath(*brewfilePath)
 		if err != nil {
@@ -55,6 +198,20 @@ func main() {
 	if *brewfilePath != "" {
 		appService.SetBrewfilePath(*brewfilePath)
 	}
+	appService.SetStartupOptions(services.StartupOptions{
+		Query:     *query,
+		Filter:    startupFilter,
+		Select:    *selectName,
+		OnlyGroup: *onlyGroup,
+		Bundle:    *bundle,
+		ReadOnly:  *readOnly,
+		Brewfile:  brewfileSource,
+		Profile:   *profileName,
+		Profiles:  *pickProfile,
+
+		InstalledOnly: *installedOnly,
+		AllowIPC:      *allowIPC,
+	})
 
 	// Boot the application (load Homebrew data)
 	if err := appService.Boot(); err != nil {
@@ -63,9 +220,23 @@ func main() {
 
 	// Build and run the TUI
 	appService.BuildApp()
+	defer services.StopAskpass()
+	defer services.StopIPC()
 	if err := appService.GetApp().Run(); err != nil {
 		log.Fatalf("Application error: %v", err)
 	}
+
+	if profile := services.StartupProfile(); profile != "" {
+		fmt.Print(profile)
+	}
+
+	if *report != "" {
+		if err := appService.WriteSessionReport(*report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write the session report: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Session report written to %s\n", *report)
+	}
 }
 
 // isFlagPassed checks if a flag was explicitly passed on the command line.
@@ -78,3 +249,81 @@ func isFlagPassed(name string) bool {
 	})
 	return found
 }
+
+// startPprof serves the net/http/pprof endpoints on addr (/debug/pprof/), to profile a running session.
+func startPprof(addr string) {
+	server := &http.Server{Addr: addr, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		defer services.RecoverCrash()
+		if err := server.ListenAndServe(); err != nil {
+			logging.For("pprof").Error("pprof server stopped", "addr", addr, "err", err)
+		}
+	}()
+}
+
+// runUpgrade handles `bbrew upgrade [--schedule | --serve <addr>] [--interval 24h]`.
+func runUpgrade(args []string) {
+	upgradeFlags := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	schedule := upgradeFlags.Bool("schedule", false, "Install a launchd agent (macOS) or systemd timer (Linux) running bbrew upgrade")
+	serve := upgradeFlags.String("serve", "", "Keep running, upgrading every interval, and serve metrics on this address (e.g. :9102)")
+	interval := upgradeFlags.Duration("interval", 24*time.Hour, "Interval of the scheduled upgrade (with --schedule or --serve)")
+	_ = upgradeFlags.Parse(args)
+
+	if closeLog, err := services.SetupLogging(""); err == nil {
+		defer closeLog()
+	}
+
+	if *serve != "" {
+		if err := services.ServeUpgradeMetrics(*serve, *interval, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if !*schedule {
+		if err := services.RunScheduledUpgrade(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	files, enable, err := services.InstallUpgradeSchedule(*interval)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, file := range files {
+		fmt.Printf("Wrote %s\n", file)
+	}
+	fmt.Printf("\nEnable the schedule with:\n  %s\n", enable)
+}
+
+// runLock handles `bbrew lock -f <Brewfile>` and `bbrew verify -f <Brewfile>`.
+func runLock(command string, args []string) {
+	lockFlags := flag.NewFlagSet(command, flag.ExitOnError)
+	brewfilePath := lockFlags.String("f", "Brewfile", "Path to the Brewfile")
+	_ = lockFlags.Parse(args)
+
+	if closeLog, err := services.SetupLogging(""); err == nil {
+		defer closeLog()
+	}
+
+	if command == "lock" {
+		if err := services.LockBrewfile(*brewfilePath, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	drifted, err := services.VerifyBrewfileLock(*brewfilePath, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if drifted > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"bbrew/internal/models"
+	"sync"
+)
+
+// Type identifies the kind of an event.
+type Type int
+
+const (
+	// PackagesUpdated is published when the package lists or their metadata changed.
+	PackagesUpdated Type = iota
+	// OperationCompleted is published when a brew operation (install, update, remove, ...) finished.
+	OperationCompleted
+	// FilterChanged is published when the active filter changed.
+	FilterChanged
+	// BatchProgress is published for each step of a batch operation (see Progress).
+	BatchProgress
+)
+
+// Operation names used in OperationCompleted events.
+const (
+	OperationInstall    = "install"
+	OperationUpdate     = "update"
+	OperationRemove     = "remove"
+	OperationUpdateAll  = "update-all"
+	OperationBatch      = "batch"
+	OperationBrewUpdate = "brew-update"
+	OperationCleanup    = "cleanup"
+)
+
+// Phase is the state of a package in a batch operation.
+type Phase string
+
+const (
+	PhaseRunning Phase = "running"
+	PhaseSkipped Phase = "skipped"
+	PhaseDone    Phase = "done"
+	PhaseFailed  Phase = "failed"
+)
+
+// Progress describes a step of a batch operation.
+type Progress struct {
+	Index   int    // Position of the package in the batch, 0-based
+	Total   int    // Number of packages in the batch
+	Package string // Name of the package
+	Phase   Phase
+	Reason  string // PhaseSkipped: why the package was skipped
+	Err     error  // PhaseFailed: the failure
+}
+
+// Event is a notification delivered to the subscribers of its type.
+type Event struct {
+	Type      Type
+	Operation string          // OperationCompleted: one of the Operation* names
+	Package   *models.Package // OperationCompleted: the package acted on, nil for bulk operations
+	Err       error           // OperationCompleted: the failure, nil on success
+	Progress  *Progress       // BatchProgress: the step of the batch
+}
+
+// Handler processes an event.
+type Handler func(event Event)
+
+// Bus dispatches events to the handlers subscribed to their type, decoupling
+// the services that produce state changes from the components that display them.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[Type][]Handler
+}
+
+// NewBus creates an event bus with no subscribers.
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[Type][]Handler)}
+}
+
+// Subscribe registers a handler for the given event type.
+func (b *Bus) Subscribe(eventType Type, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish delivers an event to its subscribers, in subscription order.
+// Handlers run synchronously in the publisher's goroutine: those touching the UI from a
+// background publisher must go through Application.QueueUpdateDraw.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers[event.Type]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,781 @@
+package i18n
+
+import (
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// supportedLanguages lists the languages with a registered catalog. The first one is the fallback.
+var supportedLanguages = []language.Tag{
+	language.English,
+	language.Italian,
+}
+
+// Plural-sensitive messages. English keys are registered too, so that "1 packages" never shows up.
+const (
+	msgBatchConfirm  = "%s all packages from Brewfile?\n\nTotal: %d packages\nTo process: %d"
+	msgBatchDone     = "Completed! Processed %d packages"
+	msgBatchTitle    = "%s %d packages…"
+	msgVulnerable    = "%d installed packages have known vulnerabilities (press x)"
+	msgKnownVulns    = "%d known vulnerabilities"
+	msgGroupConfirm  = "%s the packages of %s?\n\nTotal: %d packages\nTo process: %d"
+	msgBatchFailed   = "%d packages failed (press F to retry)"
+	msgAddedToFile   = "Added %d packages to %s"
+	msgWatchNotify   = "%d watched packages have new versions (press V)"
+	msgWatchBadge    = "[%d watched updates - press V]"
+	msgExported      = "Exported %d packages to %s"
+	msgAdoptApps     = "Adopt %d apps as casks? Homebrew will manage and update them from now on."
+	msgMigrateTitle  = "Migrate to Homebrew (%d apps)"
+	msgTypeCounts    = "%d formulae, %d casks"
+	msgHeldOnly      = "Nothing to update: %d outdated packages are held"
+	msgSaveProfile   = "Save the %d packages shown as the profile"
+	msgLicensesTitle = "Licenses (%d installed packages)"
+	msgTapAuditTitle = "Audit of %s (%d problems in %d packages)"
+	msgShadowing     = "%s comes before Homebrew in the PATH, shadowing %d commands"
+	msgMarkedConfirm = "%s the %d marked packages?\n\n%s"
+	msgMoreRenamed   = "(and %d more renamed entries of the Brewfile)"
+)
+
+func init() {
+	registerEnglish()
+	registerItalian()
+}
+
+// registerEnglish registers the English plural forms. Plain messages need no entry.
+func registerEnglish() {
+	tag := language.English
+	_ = message.Set(tag, msgBatchConfirm, plural.Selectf(2, "%d",
+		"=1", "%s all packages from Brewfile?\n\nTotal: %d package\nTo process: %d",
+		"other", "%s all packages from Brewfile?\n\nTotal: %d packages\nTo process: %d",
+	))
+	_ = message.Set(tag, msgBatchDone, plural.Selectf(1, "%d",
+		"=1", "Completed! Processed %d package",
+		"other", "Completed! Processed %d packages",
+	))
+	_ = message.Set(tag, msgBatchTitle, plural.Selectf(2, "%d",
+		"=1", "%s %d package…",
+		"other", "%s %d packages…",
+	))
+	_ = message.Set(tag, msgVulnerable, plural.Selectf(1, "%d",
+		"=1", "%d installed package has known vulnerabilities (press x)",
+		"other", "%d installed packages have known vulnerabilities (press x)",
+	))
+	_ = message.Set(tag, msgKnownVulns, plural.Selectf(1, "%d",
+		"=1", "%d known vulnerability",
+		"other", "%d known vulnerabilities",
+	))
+	_ = message.Set(tag, msgGroupConfirm, plural.Selectf(3, "%d",
+		"=1", "%s the packages of %s?\n\nTotal: %d package\nTo process: %d",
+		"other", "%s the packages of %s?\n\nTotal: %d packages\nTo process: %d",
+	))
+	_ = message.Set(tag, msgBatchFailed, plural.Selectf(1, "%d",
+		"=1", "%d package failed (press F to retry)",
+		"other", "%d packages failed (press F to retry)",
+	))
+	_ = message.Set(tag, msgAddedToFile, plural.Selectf(1, "%d",
+		"=1", "Added %d package to %s",
+		"other", "Added %d packages to %s",
+	))
+	_ = message.Set(tag, msgWatchNotify, plural.Selectf(1, "%d",
+		"=1", "%d watched package has a new version (press V)",
+		"other", "%d watched packages have new versions (press V)",
+	))
+	_ = message.Set(tag, msgWatchBadge, plural.Selectf(1, "%d",
+		"=1", "[%d watched update - press V]",
+		"other", "[%d watched updates - press V]",
+	))
+	_ = message.Set(tag, msgExported, plural.Selectf(1, "%d",
+		"=1", "Exported %d package to %s",
+		"other", "Exported %d packages to %s",
+	))
+	_ = message.Set(tag, msgAdoptApps, plural.Selectf(1, "%d",
+		"=1", "Adopt %d app as a cask? Homebrew will manage and update it from now on.",
+		"other", "Adopt %d apps as casks? Homebrew will manage and update them from now on.",
+	))
+	_ = message.Set(tag, msgMigrateTitle, plural.Selectf(1, "%d",
+		"=1", "Migrate to Homebrew (%d app)",
+		"other", "Migrate to Homebrew (%d apps)",
+	))
+	_ = message.Set(tag, msgTypeCounts, plural.Selectf(1, "%d",
+		"=1", plural.Selectf(2, "%d", "=1", "%d formula, %d cask", "other", "%d formula, %d casks"),
+		"other", plural.Selectf(2, "%d", "=1", "%d formulae, %d cask", "other", "%d formulae, %d casks"),
+	))
+	_ = message.Set(tag, msgHeldOnly, plural.Selectf(1, "%d",
+		"=1", "Nothing to update: %d outdated package is held",
+		"other", "Nothing to update: %d outdated packages are held",
+	))
+	_ = message.Set(tag, msgSaveProfile, plural.Selectf(1, "%d",
+		"=1", "Save the %d package shown as the profile",
+		"other", "Save the %d packages shown as the profile",
+	))
+	_ = message.Set(tag, msgLicensesTitle, plural.Selectf(1, "%d",
+		"=1", "Licenses (%d installed package)",
+		"other", "Licenses (%d installed packages)",
+	))
+	_ = message.Set(tag, msgTapAuditTitle, plural.Selectf(2, "%d",
+		"=1", plural.Selectf(3, "%d", "=1", "Audit of %s (%d problem in %d package)", "other", "Audit of %s (%d problem in %d packages)"),
+		"other", plural.Selectf(3, "%d", "=1", "Audit of %s (%d problems in %d package)", "other", "Audit of %s (%d problems in %d packages)"),
+	))
+	_ = message.Set(tag, msgShadowing, plural.Selectf(2, "%d",
+		"=1", "%s comes before Homebrew in the PATH, shadowing %d command",
+		"other", "%s comes before Homebrew in the PATH, shadowing %d commands",
+	))
+	_ = message.Set(tag, msgMarkedConfirm, plural.Selectf(2, "%d",
+		"=1", "%s the %d marked package?\n\n%s",
+		"other", "%s the %d marked packages?\n\n%s",
+	))
+	_ = message.Set(tag, msgMoreRenamed, plural.Selectf(1, "%d",
+		"=1", "(and %d more renamed entry of the Brewfile)",
+		"other", "(and %d more renamed entries of the Brewfile)",
+	))
+}
+
+// registerItalian registers the Italian translations.
+func registerItalian() {
+	tag := language.Italian
+
+	_ = message.Set(tag, msgBatchConfirm, plural.Selectf(2, "%d",
+		"=1", "%s tutti i pacchetti dal Brewfile?\n\nTotale: %d pacchetto\nDa elaborare: %d",
+		"other", "%s tutti i pacchetti dal Brewfile?\n\nTotale: %d pacchetti\nDa elaborare: %d",
+	))
+	_ = message.Set(tag, msgBatchDone, plural.Selectf(1, "%d",
+		"=1", "Completato! Elaborato %d pacchetto",
+		"other", "Completato! Elaborati %d pacchetti",
+	))
+	_ = message.Set(tag, msgBatchTitle, plural.Selectf(2, "%d",
+		"=1", "%s di %d pacchetto…",
+		"other", "%s di %d pacchetti…",
+	))
+	_ = message.Set(tag, msgVulnerable, plural.Selectf(1, "%d",
+		"=1", "%d pacchetto installato ha vulnerabilità note (premi x)",
+		"other", "%d pacchetti installati hanno vulnerabilità note (premi x)",
+	))
+	_ = message.Set(tag, msgKnownVulns, plural.Selectf(1, "%d",
+		"=1", "%d vulnerabilità nota",
+		"other", "%d vulnerabilità note",
+	))
+	_ = message.Set(tag, msgGroupConfirm, plural.Selectf(3, "%d",
+		"=1", "%s dei pacchetti di %s?\n\nTotale: %d pacchetto\nDa elaborare: %d",
+		"other", "%s dei pacchetti di %s?\n\nTotale: %d pacchetti\nDa elaborare: %d",
+	))
+	_ = message.Set(tag, msgBatchFailed, plural.Selectf(1, "%d",
+		"=1", "%d pacchetto non riuscito (premi F per riprovare)",
+		"other", "%d pacchetti non riusciti (premi F per riprovare)",
+	))
+	_ = message.Set(tag, msgAddedToFile, plural.Selectf(1, "%d",
+		"=1", "Aggiunto %d pacchetto a %s",
+		"other", "Aggiunti %d pacchetti a %s",
+	))
+	_ = message.Set(tag, msgWatchNotify, plural.Selectf(1, "%d",
+		"=1", "%d pacchetto osservato ha una nuova versione (premi V)",
+		"other", "%d pacchetti osservati hanno nuove versioni (premi V)",
+	))
+	_ = message.Set(tag, msgWatchBadge, plural.Selectf(1, "%d",
+		"=1", "[%d aggiornamento osservato - premi V]",
+		"other", "[%d aggiornamenti osservati - premi V]",
+	))
+	_ = message.Set(tag, msgExported, plural.Selectf(1, "%d",
+		"=1", "Esportato %d pacchetto in %s",
+		"other", "Esportati %d pacchetti in %s",
+	))
+	_ = message.Set(tag, msgAdoptApps, plural.Selectf(1, "%d",
+		"=1", "Adottare %d app come cask? Da ora Homebrew la gestirà e aggiornerà.",
+		"other", "Adottare %d app come cask? Da ora Homebrew le gestirà e aggiornerà.",
+	))
+	_ = message.Set(tag, msgMigrateTitle, plural.Selectf(1, "%d",
+		"=1", "Migra a Homebrew (%d app)",
+		"other", "Migra a Homebrew (%d app)",
+	))
+	_ = message.Set(tag, msgTypeCounts, plural.Selectf(1, "%d",
+		"=1", "%d formula, %d cask",
+		"other", "%d formule, %d cask",
+	))
+	_ = message.Set(tag, msgHeldOnly, plural.Selectf(1, "%d",
+		"=1", "Niente da aggiornare: %d pacchetto obsoleto è bloccato",
+		"other", "Niente da aggiornare: %d pacchetti obsoleti sono bloccati",
+	))
+	_ = message.Set(tag, msgSaveProfile, plural.Selectf(1, "%d",
+		"=1", "Salva %d pacchetto mostrato come profilo",
+		"other", "Salva i %d pacchetti mostrati come profilo",
+	))
+	_ = message.Set(tag, msgLicensesTitle, plural.Selectf(1, "%d",
+		"=1", "Licenze (%d pacchetto installato)",
+		"other", "Licenze (%d pacchetti installati)",
+	))
+	_ = message.Set(tag, msgTapAuditTitle, plural.Selectf(2, "%d",
+		"=1", plural.Selectf(3, "%d", "=1", "Audit di %s (%d problema in %d pacchetto)", "other", "Audit di %s (%d problema in %d pacchetti)"),
+		"other", plural.Selectf(3, "%d", "=1", "Audit di %s (%d problemi in %d pacchetto)", "other", "Audit di %s (%d problemi in %d pacchetti)"),
+	))
+	_ = message.Set(tag, msgShadowing, plural.Selectf(2, "%d",
+		"=1", "%s precede Homebrew nel PATH, oscurando %d comando",
+		"other", "%s precede Homebrew nel PATH, oscurando %d comandi",
+	))
+	_ = message.Set(tag, msgMarkedConfirm, plural.Selectf(2, "%d",
+		"=1", "%s %d pacchetto marcato?\n\n%s",
+		"other", "%s i %d pacchetti marcati?\n\n%s",
+	))
+	_ = message.Set(tag, msgMoreRenamed, plural.Selectf(1, "%d",
+		"=1", "(e %d altra voce rinominata del Brewfile)",
+		"other", "(e altre %d voci rinominate del Brewfile)",
+	))
+
+	for key, msg := range map[string]string{
+		// Legend and help
+		"Search":                 "Cerca",
+		"Installed":              "Installati",
+		"Outdated":               "Da aggiornare",
+		"Leaves":                 "Foglie",
+		"Casks":                  "Cask",
+		"Install":                "Installa",
+		"Update":                 "Aggiorna",
+		"Remove":                 "Rimuovi",
+		"Update All":             "Aggiorna tutto",
+		"Install All (Brewfile)": "Installa tutto (Brewfile)",
+		"Remove All (Brewfile)":  "Rimuovi tutto (Brewfile)",
+		"Columns":                "Colonne",
+		"Help":                   "Aiuto",
+		"Back to Table":          "Torna alla tabella",
+		"Quit":                   "Esci",
+		"NAVIGATION":             "NAVIGAZIONE",
+		"FILTERS":                "FILTRI",
+		"ACTIONS":                "AZIONI",
+		"BREWFILE":               "BREWFILE",
+		"Navigate list":          "Scorri la lista",
+		"Focus search":           "Vai alla ricerca",
+		"Back to table, dismiss an error or unmark": "Torna alla tabella, chiudi un errore o smarca",
+		"Choose columns":    "Scegli le colonne",
+		"New version notes": "Note della nuova versione",
+		"Release Notes":     "Note di rilascio",
+		"What's New":        "Novità",
+		"Toggle installed":  "Mostra/nascondi installati",
+		"Toggle outdated":   "Mostra/nascondi da aggiornare",
+		"Toggle leaves":     "Mostra/nascondi foglie",
+		"Toggle casks":      "Mostra/nascondi cask",
+		"Source Builds":     "Da sorgente",
+		"Maintained":        "Mantenuti",
+		"Vulnerable":        "Vulnerabili",
+		"Favorites":         "Preferiti",
+		"Star":              "Preferito",
+		"Starred %s":        "%s aggiunto ai preferiti",
+		"Unstarred %s":      "%s rimosso dai preferiti",
+		"Watch":             "Osserva",
+		"Watchlist":         "Osservati",
+		"Watching %s":       "%s osservato",
+		"No changes":        "Nessuna novità",
+		"Files":             "File",
+		"Filter: ":          "Filtro: ",
+		"No files match":    "Nessun file corrispondente",
+		"not in PATH":       "non nel PATH",
+		"shadowed by %s":    "oscurato da %s",
+		"Licenses":          "Licenze",
+		"License audit":     "Verifica licenze",
+		"Raw Info":          "Dati grezzi",
+		"Inspect raw JSON":  "Ispeziona JSON grezzo",
+		"License: %s":       "Licenza: %s",
+		"Install selected, or the marked packages": "Installa selezionato, o i pacchetti marcati",
+		"Update selected, or the marked packages":  "Aggiorna selezionato, o i pacchetti marcati",
+		"Remove selected, or the marked packages":  "Rimuovi selezionato, o i pacchetti marcati",
+		"Update all":       "Aggiorna tutto",
+		"Update Homebrew":  "Aggiorna Homebrew",
+		"Install all":      "Installa tutto",
+		"Remove all":       "Rimuovi tutto",
+		"Group":            "Raggruppa",
+		"Group by section": "Raggruppa per sezione",
+		"Other":            "Altro",
+		"Install Group":    "Installa gruppo",
+		"Install section":  "Installa sezione",
+		"Install Taps":     "Installa tap",
+		"Retry failed":     "Riprova falliti",
+		"Failure Actions":  "Azioni sull'errore",
+		"View log":         "Vedi log",
+		"Retry":            "Riprova",
+		"Terminal":         "Terminale",
+		"Install or update in an external terminal": "Installa o aggiorna in un terminale esterno",
+		"Run in terminal":             "Esegui nel terminale",
+		"Password required":           "Password richiesta",
+		"enter: submit | esc: cancel": "invio: conferma | esc: annulla",
+		"Search issues":               "Cerca issue",
+		"(exit code %d)":              "(codice di uscita %d)",
+		"(!: actions)":                "(!: azioni)",
+		"Failed":                      "Non riusciti",
+		"Succeeded":                   "Riusciti",
+		"Skipped":                     "Saltati",
+		"Install missing taps":        "Installa i tap mancanti",
+		"Press any key to close":      "Premi un tasto per chiudere",
+
+		"space: toggle | J/K: move | esc: apply":     "spazio: mostra/nascondi | J/K: sposta | esc: applica",
+		"u: update now | esc: close":                 "u: aggiorna ora | esc: chiudi",
+		"enter: filter | e: export CSV | esc: close": "invio: filtra | e: esporta CSV | esc: chiudi",
+		"Toggle source builds (no bottle)":           "Mostra/nascondi build da sorgente (senza bottle)",
+		"Toggle maintained (GitHub)":                 "Mostra/nascondi mantenuti (GitHub)",
+		"Toggle vulnerable (OSV.dev)":                "Mostra/nascondi vulnerabili (OSV.dev)",
+		"Toggle favorites":                           "Mostra/nascondi preferiti",
+		"Star/unstar selected":                       "Aggiungi/rimuovi dai preferiti",
+		"Failed to save favorites: %v":               "Impossibile salvare i preferiti: %v",
+		"Failed to export favorites: %v":             "Impossibile esportare i preferiti: %v",
+		"Failed to save the watchlist: %v":           "Impossibile salvare gli osservati: %v",
+		"Stopped watching %s":                        "%s non più osservato",
+		"New versions since last seen":               "Nuove versioni dall'ultima visita",
+		"Watch/unwatch selected":                     "Osserva/smetti di osservare",
+		"Show watchlist":                             "Mostra osservati",
+		"All favorites are already in %s":            "Tutti i preferiti sono già in %s",
+		"Append favorites to Brewfile":               "Aggiungi i preferiti al Brewfile",
+		"Favorites to Brewfile":                      "Preferiti nel Brewfile",
+		"enter: append | esc: cancel":                "invio: aggiungi | esc: annulla",
+		"Update Homebrew (brew update)":              "Aggiorna Homebrew (brew update)",
+		"Collapse/expand section":                    "Comprimi/espandi sezione",
+		"esc: hide (keeps running)":                  "esc: nascondi (continua in background)",
+		"esc: close":                                 "esc: chiudi",
+		"F: retry failed | esc: close":               "F: riprova i falliti | esc: chiudi",
+		"Retry failed (%d)":                          "Riprova falliti (%d)",
+		"No failed packages to retry":                "Nessun pacchetto fallito da riprovare",
+		"Don't ask again this session (d)":           "Non chiedere più in questa sessione (d)",
+		"Toggle expert mode (no confirmation)":       "Modalità esperto (senza conferma)",
+		"Upgrade policy of %s: %s":                   "Criterio di aggiornamento di %s: %s",
+		"ask (notify only)":                          "chiedi (solo notifica)",
+		"hold (never upgrade)":                       "blocca (mai aggiornare)",
+		"Held by policy: %s":                         "Bloccati dal criterio: %s",
+		"Go to":                                      "Vai a",
+		"Go to: press a letter":                      "Vai a: premi una lettera",
+		"Jump to a letter (then press it)":           "Salta a una lettera (poi premila)",
+		"No package starting with %s":                "Nessun pacchetto che inizia con %s",
+		"Uninstall what's not in the Brewfile":       "Disinstalla ciò che non è nel Brewfile",
+		"Show this help":                             "Mostra questo aiuto",
+		"%d outdated [o]":                            "%d da aggiornare [o]",
+		"brew updated %s ago":                        "brew aggiornato %s fa",
+		"%s free":                                    "%s liberi",
+		"brew bundle failed: %v":                     "brew bundle non riuscito: %v",
+		"brew bundle cleanup failed: %v":             "brew bundle cleanup non riuscito: %v",
+		"brew bundle completed":                      "brew bundle completato",
+		"Bundle Cleanup":                             "Pulizia bundle",
+		"Failed to save settings: %v":                "Impossibile salvare le impostazioni: %v",
+		"Failed to save the note: %v":                "Impossibile salvare la nota: %v",
+		"y: confirm | n: cancel":                     "y: conferma | n: annulla",
+		"Session summary (%s)":                       "Riepilogo della sessione (%s)",
+		"Installed: %s":                              "Installati: %s",
+		"Updated: %s":                                "Aggiornati: %s",
+		"Removed: %s":                                "Rimossi: %s",
+		"Failed: %s":                                 "Non riusciti: %s",
+		"Quit Bold Brew?":                            "Uscire da Bold Brew?",
+		"Export Inventory":                           "Esporta inventario",
+		"Compare Inventory":                          "Confronta inventario",
+		"Export inventory to":                        "Esporta l'inventario in",
+		"enter: export | esc: cancel":                "invio: esporta | esc: annulla",
+		"Compare with inventory or Brewfile":         "Confronta con inventario o Brewfile",
+		"enter: compare | esc: cancel":               "invio: confronta | esc: annulla",
+		"Failed to export the inventory: %v":         "Impossibile esportare l'inventario: %v",
+		"Failed to read the inventory: %v":           "Impossibile leggere l'inventario: %v",
+		"Failed to write the Brewfile: %v":           "Impossibile scrivere il Brewfile: %v",
+		"%s has every package installed here":        "%s ha tutti i pacchetti installati qui",
+		"Brewfile for %s":                            "Brewfile per %s",
+		"This machine vs %s":                         "Questa macchina e %s",
+		"Only here":                                  "Solo qui",
+		"Only there":                                 "Solo là",
+		"Version mismatch":                           "Versioni diverse",
+		"Source":                                     "Sorgente",
+		"Source checksum":                            "Checksum sorgente",
+		"Bottle URL":                                 "URL bottle",
+		"Download URL":                               "URL di download",
+		"y: copy URL | d: download":                  "y: copia URL | d: scarica",
+		"Copy URL":                                   "Copia URL",
+		"Download":                                   "Scarica",
+		"Copy the bottle or download URL":            "Copia l'URL del bottle o di download",
+		"Download %s to":                             "Scarica %s in",
+		"enter: download | esc: cancel":              "invio: scarica | esc: annulla",
+		"downloading %s…":                            "download di %s…",
+		"Downloading %s...":                          "Download di %s...",
+		"Failed to download %s: %v":                  "Impossibile scaricare %s: %v",
+		"Downloaded %s":                              "Scaricato %s",
+		"Copied the URL of %s":                       "URL di %s copiato",
+		"No bottle or download URL for %s on %s":     "Nessun URL di bottle o download per %s su %s",
+		"Loading the details of %s...":               "Caricamento dei dettagli di %s...",
+		"Caveats for %s":                             "Avvertenze per %s",
+		"Suggested commands":                         "Comandi suggeriti",
+		"enter/esc: close":                           "invio/esc: chiudi",
+		"c: copy commands | enter/esc: close":        "c: copia i comandi | invio/esc: chiudi",
+		"Commands copied to the clipboard":           "Comandi copiati negli appunti",
+		"a: add to %s":                               "a: aggiungi a %s",
+		"Append to %s?":                              "Aggiungere a %s?",
+		"Failed to update %s: %v":                    "Impossibile aggiornare %s: %v",
+		"%s already has these lines":                 "%s contiene già queste righe",
+		"Created %s, open a new shell to apply":      "Creato %s, apri una nuova shell per applicarlo",
+		"Failed to install %s: %s":                   "Impossibile installare %s: %s",
+		"Suggested fix:":                             "Soluzione suggerita:",
+		"Copy it to the clipboard?":                  "Copiarla negli appunti?",
+		"Command copied to the clipboard":            "Comando copiato negli appunti",
+		"Checksum mismatch":                          "Checksum non corrispondente",
+		"Command Line Tools missing":                 "Command Line Tools mancanti",
+		"Sandbox error":                              "Errore della sandbox",
+		"Permission denied":                          "Permesso negato",
+		"Adopt":                                      "Adotta",
+		"Adopt the existing app as a cask":           "Adotta l'app esistente come cask",
+		"Only casks can adopt an existing app":       "Solo i cask possono adottare un'app esistente",
+		"%s is already installed":                    "%s è già installato",
+		"No app of %s found in %s":                   "Nessuna app di %s trovata in %s",
+		"Migrate":                                    "Migra",
+		"Migrate apps to Homebrew (macOS)":           "Migra le app a Homebrew (macOS)",
+		"Failed to scan %s: %v":                      "Impossibile analizzare %s: %v",
+		"Adopting":                                   "Adozione",
+		"Bold Brew session report":                   "Report della sessione di Bold Brew",
+		"Started":                                    "Inizio",
+		"Duration":                                   "Durata",
+		"Updated":                                    "Aggiornati",
+		"Removed":                                    "Rimossi",
+		"Failures":                                   "Errori",
+		"None":                                       "Nessuno",
+
+		"enter: fold | +/-: expand/collapse all | esc: close": "invio: espandi/comprimi | +/-: espandi/comprimi tutto | esc: chiudi",
+		"A backup of the file is saved next to it.":           "Una copia di backup del file viene salvata accanto.",
+		"Updated %s (backup: %s), open a new shell to apply":  "Aggiornato %s (backup: %s), apri una nuova shell per applicarlo",
+		"Act on the last failure (log, retry, issues)":        "Agisci sull'ultimo errore (log, riprova, issue)",
+		"Skip Gatekeeper quarantine (--no-quarantine)":        "Salta la quarantena di Gatekeeper (--no-quarantine)",
+
+		"The downloaded file doesn't match the checksum Homebrew expects: the download is corrupted, or the file changed upstream.": "Il file scaricato non corrisponde al checksum atteso da Homebrew: il download è corrotto, o il file è cambiato alla fonte.",
+		"Building this package requires the Xcode Command Line Tools, which are missing or outdated.":                               "La compilazione di questo pacchetto richiede i Command Line Tools di Xcode, mancanti o non aggiornati.",
+		"The build sandbox denied access to a file. Another app or a privacy setting of macOS may be blocking it.":                  "La sandbox di compilazione ha negato l'accesso a un file. Un'altra app o un'impostazione di privacy di macOS potrebbe bloccarlo.",
+		"Your user can't write to the Homebrew prefix, often after running brew or an installer with sudo.":                         "Il tuo utente non può scrivere nel prefisso di Homebrew, spesso dopo aver eseguito brew o un installer con sudo.",
+		"Run `%s` in an external terminal?":                                        "Eseguire `%s` in un terminale esterno?",
+		"Running `%s` in an external terminal...":                                  "Esecuzione di `%s` in un terminale esterno...",
+		"`%s` finished in the terminal":                                            "`%s` completato nel terminale",
+		"`%s` failed in the terminal: %v":                                          "`%s` non riuscito nel terminale: %v",
+		"Audit a local tap (for tap maintainers)":                                  "Verifica un tap locale (per i manutentori)",
+		"Audit a tap (brew audit --strict, brew style)":                            "Verifica un tap (brew audit --strict, brew style)",
+		"%s is not a local tap":                                                    "%s non è un tap locale",
+		"Failed to audit %s: %v":                                                   "Impossibile verificare %s: %v",
+		"%s is not shown in the table":                                             "%s non è mostrato nella tabella",
+		"enter: select in table | r: audit again | esc: close":                     "invio: seleziona nella tabella | r: verifica di nuovo | esc: chiudi",
+		"Find the package providing a command":                                     "Trova il pacchetto che fornisce un comando",
+		"Which package provides a command?":                                        "Quale pacchetto fornisce un comando?",
+		"%s is provided by %s (installed)":                                         "%s è fornito da %s (installato)",
+		"%s is provided by %s (not installed)":                                     "%s è fornito da %s (non installato)",
+		"%s is provided by %s (installed, not linked in the PATH)":                 "%s è fornito da %s (installato, non collegato nel PATH)",
+		"Failed to look up %s: %v":                                                 "Impossibile cercare %s: %v",
+		"List the files of the installed package":                                  "Elenca i file del pacchetto installato",
+		"%s is not installed":                                                      "%s non è installato",
+		"Listing the files of %s...":                                               "Elenco dei file di %s...",
+		"Failed to list the files of %s: %v":                                       "Impossibile elencare i file di %s: %v",
+		"Files of %s (%d, %s)":                                                     "File di %s (%d, %s)",
+		"type to filter | ↑/↓: scroll | esc: clear filter, close":                  "digita per filtrare | ↑/↓: scorri | esc: cancella filtro, chiudi",
+		"No terminal available, command copied to the clipboard":                   "Nessun terminale disponibile, comando copiato negli appunti",
+		"the installer needs your password (sudo)":                                 "l'installer richiede la tua password (sudo)",
+		"No browser available, issue search URL copied to the clipboard":           "Nessun browser disponibile, URL della ricerca issue copiato negli appunti",
+		"Migrating apps to Homebrew is only available on macOS":                    "La migrazione delle app a Homebrew è disponibile solo su macOS",
+		"All the apps with a cask are already managed by Homebrew.":                "Tutte le app con un cask sono già gestite da Homebrew.",
+		"enter: adopt | a: adopt all | esc: close":                                 "invio: adotta | a: adotta tutte | esc: chiudi",
+		"%s already exists, which makes the install fail: adopt it instead (A).":   "%s esiste già e farebbe fallire l'installazione: adottala invece (A).",
+		"Adopt %s as the cask %s? Homebrew will manage and update it from now on.": "Adottare %s come cask %s? Da ora Homebrew la gestirà e aggiornerà.",
+		"%s: another Homebrew process holds the lock.":                             "%s: un altro processo di Homebrew ha il lock.",
+		"Wait for it to finish, then retry automatically?":                         "Attendere che finisca, poi riprovare automaticamente?",
+		"Waiting for Homebrew to retry %s (next check in %s)...":                   "In attesa di Homebrew per riprovare %s (prossimo controllo tra %s)...",
+
+		"enter: match here (install, update, remove) | e: Brewfile for there | esc: close": "invio: allinea qui (installa, aggiorna, rimuovi) | e: Brewfile per l'altra | esc: chiudi",
+
+		"Expert mode on: installs, updates and removals run without confirmation": "Modalità esperto attiva: installazioni, aggiornamenti e rimozioni senza conferma",
+		"Expert mode off: operations are confirmed again":                         "Modalità esperto disattivata: le operazioni vengono di nuovo confermate",
+		"enter: save (empty to remove) | esc: cancel":                             "invio: salva (vuota per rimuovere) | esc: annulla",
+		"No favorites to export (press s to star packages)":                       "Nessun preferito da esportare (premi s per aggiungerne)",
+		"esc: close (marks new versions as seen)":                                 "esc: chiudi (segna le nuove versioni come viste)",
+		"Cycle upgrade policy (ask/auto/hold)":                                    "Cambia criterio di aggiornamento (chiedi/auto/blocca)",
+		"Export installed packages, to compare machines":                          "Esporta i pacchetti installati, per confrontare le macchine",
+		"Compare with another machine's inventory or Brewfile":                    "Confronta con l'inventario o il Brewfile di un'altra macchina",
+		"Download the bottle or cask to a directory":                              "Scarica il bottle o il cask in una cartella",
+		"No watched packages yet: press W on a package to watch it.":              "Nessun pacchetto osservato: premi W su un pacchetto per osservarlo.",
+
+		"Run brew bundle install on the Brewfile?\n\nIt installs and upgrades every entry, taps and mas apps included.": "Eseguire brew bundle install sul Brewfile?\n\nInstalla e aggiorna ogni voce, tap e app mas compresi.",
+		"Uninstall the packages listed in the output panel?\n\nThey are installed but not in the Brewfile.":             "Disinstallare i pacchetti elencati nel pannello di output?\n\nSono installati ma non sono nel Brewfile.",
+
+		// Search and counter
+		"Search (All): ":           "Cerca (Tutti): ",
+		"Search (%s): ":            "Cerca (%s): ",
+		"Search (Brewfile): ":      "Cerca (Brewfile): ",
+		"Search (Brewfile - %s): ": "Cerca (Brewfile - %s): ",
+		"Total: %d | Filtered: %d": "Totale: %d | Filtrati: %d",
+
+		// Table and details
+		"Type":                    "Tipo",
+		"Name":                    "Nome",
+		"Display Name":            "Nome visualizzato",
+		"Version":                 "Versione",
+		"Status":                  "Stato",
+		"Homepage":                "Sito",
+		"Description":             "Descrizione",
+		"Downloads":               "Download",
+		"Size":                    "Dimensione",
+		"Tap":                     "Tap",
+		"License":                 "Licenza",
+		"Details":                 "Dettagli",
+		"Output":                  "Output",
+		"Formula":                 "Formula",
+		"Cask":                    "Cask",
+		"Not installed":           "Non installato",
+		"Update available":        "Aggiornamento disponibile",
+		"Installation":            "Installazione",
+		"Installation Details":    "Dettagli installazione",
+		"Path":                    "Percorso",
+		"Installed on request":    "Installato su richiesta",
+		"Installed as dependency": "Installato come dipendenza",
+		"Installed version":       "Versione installata",
+		"Desktop Application":     "Applicazione desktop",
+		"Unknown":                 "Sconosciuto",
+		"Dependencies":            "Dipendenze",
+		"No dependencies":         "Nessuna dipendenza",
+		"Analytics":               "Statistiche",
+		"90d Global Rank":         "Classifica globale 90g",
+		"90d Downloads":           "Download 90g",
+		"Yes":                     "Sì",
+		"No":                      "No",
+		"No %s bottle":            "Nessun bottle %s",
+		"Repository":              "Repository",
+		"Stars":                   "Stelle",
+		"Fav":                     "Pref",
+		"Last push":               "Ultimo push",
+		"Archived":                "Archiviato",
+		"Security":                "Sicurezza",
+		"Fixed in %s":             "Corretto in %s",
+		"resolved by upgrading":   "risolto aggiornando",
+		"not fixed in %s yet":     "non ancora corretto in %s",
+		"No fix available":        "Nessuna correzione disponibile",
+		"Requires macOS %s":       "Richiede macOS %s",
+		"Brewfile note":           "Nota Brewfile",
+		"Note":                    "Nota",
+
+		// Modals
+		"Confirm": "Conferma",
+		"Cancel":  "Annulla",
+		"Are you sure you want to install the package: %s?": "Vuoi davvero installare il pacchetto: %s?",
+		"Are you sure you want to remove the package: %s?":  "Vuoi davvero rimuovere il pacchetto: %s?",
+		"Are you sure you want to update the package: %s?":  "Vuoi davvero aggiornare il pacchetto: %s?",
+		"Are you sure you want to update all Packages?":     "Vuoi davvero aggiornare tutti i pacchetti?",
+
+		// Notifications
+		"Installing %s...":                                "Installazione di %s...",
+		"Installed %s":                                    "%s installato",
+		"Failed to install %s":                            "Impossibile installare %s",
+		"Removing %s...":                                  "Rimozione di %s...",
+		"Removed %s":                                      "%s rimosso",
+		"Failed to remove %s":                             "Impossibile rimuovere %s",
+		"Adopting %s...":                                  "Adozione di %s...",
+		"adopting %s…":                                    "adozione di %s…",
+		"Adopted %s":                                      "%s adottato",
+		"Failed to adopt %s":                              "Impossibile adottare %s",
+		"Updating %s...":                                  "Aggiornamento di %s...",
+		"Updated %s":                                      "%s aggiornato",
+		"Failed to update %s":                             "Impossibile aggiornare %s",
+		"Updating all Packages...":                        "Aggiornamento di tutti i pacchetti...",
+		"Updated all Packages":                            "Tutti i pacchetti aggiornati",
+		"Failed to update all Packages":                   "Impossibile aggiornare tutti i pacchetti",
+		"Updating Homebrew formulae...":                   "Aggiornamento delle formule Homebrew...",
+		"Homebrew formulae updated successfully":          "Formule Homebrew aggiornate",
+		"Could not update Homebrew formulae":              "Impossibile aggiornare le formule Homebrew",
+		"Installing tap %s...":                            "Installazione del tap %s...",
+		"Tap %s installed":                                "Tap %s installato",
+		"Failed to install tap %s":                        "Impossibile installare il tap %s",
+		"All taps installed":                              "Tutti i tap installati",
+		"All taps are already installed":                  "Tutti i tap sono già installati",
+		"installing taps…":                                "installazione dei tap…",
+		"updating Homebrew…":                              "aggiornamento di Homebrew…",
+		"listing the packages missing from the Brewfile…": "elenco dei pacchetti assenti dal Brewfile…",
+		"Previous Operation":                              "Operazione precedente",
+		"Next Operation":                                  "Operazione successiva",
+		"Scroll the output to the previous operation":     "Scorri l'output all'operazione precedente",
+		"Scroll the output to the next operation":         "Scorri l'output all'operazione successiva",
+		"No other operation in the output":                "Nessun'altra operazione nell'output",
+		"No packages found in Brewfile":                   "Nessun pacchetto trovato nel Brewfile",
+		"No packages to process (%s)":                     "Nessun pacchetto da elaborare (%s)",
+		"Installing":                                      "Installazione",
+		"Removing":                                        "Rimozione",
+		"already installed":                               "già installato",
+		"not installed":                                   "non installato",
+		"At least one column must be visible":             "Almeno una colonna deve essere visibile",
+		"Column settings saved":                           "Impostazioni colonne salvate",
+		"Failed to save column settings: %v":              "Impossibile salvare le impostazioni colonne: %v",
+		"Failed to export licenses: %v":                   "Impossibile esportare le licenze: %v",
+		"Licenses exported to %s":                         "Licenze esportate in %s",
+		"Failed to inspect %s: %v":                        "Impossibile ispezionare %s: %v",
+		"Select a Brewfile section to install":            "Seleziona una sezione del Brewfile da installare",
+
+		"%s requires macOS %s (this Mac runs %s)": "%s richiede macOS %s (questo Mac ha %s)",
+
+		// Self update
+		"New Version Available: %s - press n":        "Nuova versione disponibile: %s - premi n",
+		"Loading release notes...":                   "Caricamento note di rilascio...",
+		"Could not load the release notes: %v":       "Impossibile caricare le note di rilascio: %v",
+		"No release notes for this version.":         "Nessuna nota di rilascio per questa versione.",
+		"Updated to %s, restart Bold Brew to use it": "Aggiornato a %s, riavvia Bold Brew per usarlo",
+		"Homebrew is required to update %s":          "Homebrew è necessario per aggiornare %s",
+
+		// Read-only mode
+		"Homebrew not installed": "Homebrew non installato",
+		"[Read-only]":            "[Sola lettura]",
+		"[Expert]":               "[Esperto]",
+		"Expert Mode":            "Modalità esperto",
+		"Upgrade Policy":         "Criterio aggiornamento",
+		"auto-upgrade":           "aggiornamento automatico",
+		"Upgrade policy":         "Criterio aggiornamento",
+		"Policy":                 "Criterio",
+		"hold":                   "bloccato",
+		"My note":                "La mia nota",
+		"Note for %s":            "Nota per %s",
+		"Note saved":             "Nota salvata",
+		"Edit note":              "Modifica nota",
+		"Read-only mode":         "Modalità sola lettura",
+
+		"Read-only mode: %s is disabled": "Modalità sola lettura: %s è disattivato",
+
+		"Packages can be browsed but not installed, updated or removed, and taps are not added.": "I pacchetti si possono consultare ma non installare, aggiornare o rimuovere, e i tap non vengono aggiunti.",
+
+		"Homebrew was not found, so packages can be browsed but not installed, updated or removed. To install Homebrew, run:": "Homebrew non è stato trovato: i pacchetti si possono consultare ma non installare, aggiornare o rimuovere. Per installare Homebrew, esegui:",
+
+		"Then restart Bold Brew. See https://brew.sh for details.": "Poi riavvia Bold Brew. Dettagli su https://brew.sh.",
+		"Checking what else would be upgraded…":                    "Verifica di cos'altro verrebbe aggiornato…",
+		"Could not check what else would be upgraded: %s":          "Impossibile verificare cos'altro verrebbe aggiornato: %s",
+		"…and %d more":                                "…e altri %d",
+		"Nothing else would be upgraded.":             "Nient'altro verrebbe aggiornato.",
+		"Protect":                                     "Proteggi",
+		"Protect from removal (toggle)":               "Proteggi dalla rimozione (attiva/disattiva)",
+		"Protection":                                  "Protezione",
+		"protected from removal":                      "protetto dalla rimozione",
+		"protected":                                   "protetto",
+		"%s is protected from removal":                "%s è protetto dalla rimozione",
+		"%s is no longer protected from removal":      "%s non è più protetto dalla rimozione",
+		"%s is protected, type its name to remove it": "%s è protetto, digita il suo nome per rimuoverlo",
+		"enter: remove | esc: cancel":                 "invio: rimuovi | esc: annulla",
+		"The name didn't match, %s was not removed":   "Il nome non corrisponde, %s non è stato rimosso",
+		"Protected, left out: %s":                     "Protetti, esclusi: %s",
+		"Restore":                                     "Ripristina",
+		"Reinstall a recently removed package":        "Reinstalla un pacchetto rimosso di recente",
+		"Recently removed":                            "Rimossi di recente",
+		"No packages removed yet.":                    "Nessun pacchetto rimosso finora.",
+		"enter: reinstall | esc: close":               "invio: reinstalla | esc: chiudi",
+		"%s ago":                                      "%s fa",
+		"Reinstall %s, removed on %s?":                "Reinstallare %s, rimosso il %s?",
+		"Options: %s":                                 "Opzioni: %s",
+		"%s was removed, %s will be installed.":       "Era stata rimossa la %s, verrà installata la %s.",
+		"Restored %s":                                 "%s ripristinato",
+		"Failed to update the Brewfile lock: %v":      "Impossibile aggiornare il lock del Brewfile: %v",
+		"Profiles":                                    "Profili",
+		"Switch, compare or save profiles":            "Cambia, confronta o salva i profili",
+		"No profiles yet: press s to save the packages shown as one.": "Ancora nessun profilo: premi s per salvare come profilo i pacchetti mostrati.",
+		"(group %s)":  "(gruppo %s)",
+		"(filter %s)": "(filtro %s)",
+		"enter: open | d: compare with this machine | s: save the packages shown | esc: close": "invio: apri | d: confronta con questa macchina | s: salva i pacchetti mostrati | esc: chiudi",
+		"Failed to open the profile %s: %v":                                                    "Impossibile aprire il profilo %s: %v",
+		"Opened the profile %s":                                                                "Aperto il profilo %s",
+		"Failed to save the profile: %v":                                                       "Impossibile salvare il profilo: %v",
+		"Saved the profile %s to %s":                                                           "Profilo %s salvato in %s",
+		"enter: save | esc: cancel":                                                            "invio: salva | esc: annulla",
+		"Health":                                                                               "Salute",
+		"Check the environment, e.g. commands shadowed in the PATH":                            "Controlla l'ambiente, ad es. i comandi oscurati nel PATH",
+		"Checking the environment...":                                                          "Controllo dell'ambiente...",
+		"r: check again | esc: close":                                                          "r: controlla di nuovo | esc: chiudi",
+		"Commands shadowed in the PATH":                                                        "Comandi oscurati nel PATH",
+		"The commands of Homebrew are the ones the PATH resolves":                              "I comandi di Homebrew sono quelli risolti dal PATH",
+		"your shell profile":                                                                   "il profilo della tua shell",
+		"%s is not in the PATH":                                                                "%s non è nel PATH",
+		"Add to %s: %s":                                                                        "Aggiungi a %s: %s",
+		"Add to the end of %s: %s":                                                             "Aggiungi in fondo a %s: %s",
+		"Or keep these commands of %s if you use them on purpose":                              "Oppure mantieni questi comandi di %s se li usi di proposito",
+		"%s: %s runs instead of %s":                                                            "%s: viene eseguito %s invece di %s",
+		"Settings":                                                                             "Impostazioni",
+		"Homebrew analytics and bbrew telemetry":                                               "Analytics di Homebrew e telemetria di bbrew",
+		"enter: toggle | esc: close":                                                           "invio: attiva/disattiva | esc: chiudi",
+		"Homebrew analytics":                                                                   "Analytics di Homebrew",
+		"Anonymous install events brew sends to Homebrew (brew analytics)":                     "Eventi di installazione anonimi che brew invia a Homebrew (brew analytics)",
+		"Disabled by HOMEBREW_NO_ANALYTICS":                                                    "Disattivate da HOMEBREW_NO_ANALYTICS",
+		"on":                                                                                   "attivo",
+		"off":                                                                                  "disattivo",
+		"unknown":                                                                              "sconosciuto",
+		"bbrew telemetry":                                                                      "Telemetria di bbrew",
+		"bbrew sends no usage data (telemetry in config.json)":                                 "bbrew non invia dati di utilizzo (telemetry in config.json)",
+		"Updating Homebrew analytics...":                                                       "Aggiornamento delle analytics di Homebrew...",
+		"Failed to update Homebrew analytics: %v":                                              "Impossibile aggiornare le analytics di Homebrew: %v",
+		"Homebrew analytics enabled":                                                           "Analytics di Homebrew attivate",
+		"Homebrew analytics disabled":                                                          "Analytics di Homebrew disattivate",
+		"[Installed only]":                                                                     "[Solo installati]",
+		"Formulae":                                                                             "Formule",
+		"Group by type":                                                                        "Raggruppa per tipo",
+		"Collapse/expand type (grouped by type)":                                               "Comprimi/espandi il tipo (raggruppato per tipo)",
+		"Macro":                                                                                "Macro",
+		"Macro: %s":                                                                            "Macro: %s",
+		"Record Macro":                                                                         "Registra macro",
+		"Record a macro, then bind it to a key":                                                "Registra una macro, poi associala a un tasto",
+		"[Recording macro]":                                                                    "[Registrazione macro]",
+		"Recording a macro: press Q to stop":                                                   "Registrazione di una macro: premi Q per terminare",
+		"Macro canceled: no action recorded":                                                   "Macro annullata: nessuna azione registrata",
+		"Bind the macro %s to a key":                                                           "Associa la macro %s a un tasto",
+		"Failed to save the macro: %v":                                                         "Impossibile salvare la macro: %v",
+		"Macro saved: press %s to run %s":                                                      "Macro salvata: premi %s per eseguire %s",
+		"Macro stopped: no action for the key %s here":                                         "Macro interrotta: nessuna azione per il tasto %s qui",
+		"Macro stopped at %s, which opened a dialog: %s not run":                               "Macro interrotta a %s, che ha aperto una finestra: %s non eseguito",
+		"Renamed":                    "Rinominato",
+		"%s in the Brewfile, now %s": "%s nel Brewfile, ora %s",
+		"%s was renamed to %s":       "%s è stato rinominato in %s",
+		"(held)":                     "(bloccato)",
+		"%d held":                    "%d bloccati",
+		"Held":                       "Bloccato",
+		"Held: %s available":         "Bloccato: %s disponibile",
+		"Disk Usage":                 "Spazio su disco",
+		"Disk usage of Homebrew's cache and old versions, to clean them up": "Spazio occupato dalla cache e dalle vecchie versioni di Homebrew, per ripulirle",
+		"Disk usage of Homebrew": "Spazio su disco di Homebrew",
+		"Download cache":         "Cache dei download",
+		"Kegs":                   "Keg",
+		"Old versions":           "Vecchie versioni",
+		"Package":                "Pacchetto",
+		"Total":                  "Totale",
+		"No old versions nor downloads to clean up.":                            "Nessuna vecchia versione né download da ripulire.",
+		"enter: brew cleanup the package | x: purge the cache | esc: close":     "invio: brew cleanup del pacchetto | x: svuota la cache | esc: chiudi",
+		"Measuring the disk usage of Homebrew...":                               "Misurazione dello spazio su disco di Homebrew...",
+		"Failed to find the Homebrew cache: %v":                                 "Impossibile trovare la cache di Homebrew: %v",
+		"Run brew cleanup %s? It frees %s.":                                     "Eseguire brew cleanup %s? Libera %s.",
+		"Cleaning up %s...":                                                     "Pulizia di %s...",
+		"cleaning up %s…":                                                       "pulizia di %s…",
+		"Failed to clean up %s: %v":                                             "Impossibile ripulire %s: %v",
+		"Cleaned up %s, %s freed":                                               "%s ripulito, %s liberati",
+		"Remove everything in %s (%s)? Homebrew downloads again what it needs.": "Rimuovere tutto il contenuto di %s (%s)? Homebrew scaricherà di nuovo ciò che gli serve.",
+		"Purging the Homebrew cache...":                                         "Svuotamento della cache di Homebrew...",
+		"Failed to purge the Homebrew cache: %v":                                "Impossibile svuotare la cache di Homebrew: %v",
+		"Purged the Homebrew cache, %s freed":                                   "Cache di Homebrew svuotata, %s liberati",
+		"%d of %d done":                                                         "%d di %d completati",
+		"Linux alternative":                                                     "Alternativa per Linux",
+		"%s is a macOS app: install flatpak or snap to get %s":                  "%s è un'app per macOS: installa flatpak o snap per ottenere %s",
+		"%s is a macOS app, which Homebrew can't install on Linux. Install the %s instead?": "%s è un'app per macOS, che Homebrew non può installare su Linux. Installare invece %s?",
+		"Failed to install %s: %v": "Impossibile installare %s: %v",
+		"Services":                 "Servizi",
+		"Start, stop or enable the services of formulae (systemd, on Linux)": "Avvia, ferma o abilita i servizi delle formule (systemd, su Linux)",
+		"On macOS, services are managed by brew services":                    "Su macOS i servizi sono gestiti da brew services",
+		"systemctl not found: services need systemd":                         "systemctl non trovato: i servizi richiedono systemd",
+		"Failed to %s %s: %v":                                        "Impossibile eseguire %s su %s: %v",
+		"Started the service of %s":                                  "Servizio di %s avviato",
+		"Stopped the service of %s":                                  "Servizio di %s fermato",
+		"The service of %s now starts at login":                      "Il servizio di %s ora si avvia all'accesso",
+		"The service of %s no longer starts at login":                "Il servizio di %s non si avvia più all'accesso",
+		"No installed formula has a service.":                        "Nessuna formula installata ha un servizio.",
+		"not generated":                                              "non generato",
+		"at login":                                                   "all'accesso",
+		"s: start | x: stop | e: start at login or not | esc: close": "s: avvia | x: ferma | e: avvio all'accesso sì/no | esc: chiudi",
+		"Services (systemd user units)":                              "Servizi (unità utente di systemd)",
+		"All":                                                        "Tutti",
+		"Show %s":                                                    "Mostra %s",
+		"(esc: dismiss)":                                             "(esc: chiudi)",
+		"Wait for the running operation to finish (%s)":              "Attendi la fine dell'operazione in corso (%s)",
+		"%s is disabled until the running operation finishes (%s)": "%s è disattivato fino alla fine dell'operazione in corso (%s)",
+		"purging the Homebrew cache…":                              "svuotamento della cache di Homebrew…",
+		"running %s in a terminal…":                                "esecuzione di %s in un terminale…",
+		"Mark":                                                     "Marca",
+		"Mark or unmark selected, for i, u and r":                  "Marca o smarca selezionato, per i, u e r",
+		"%d marked: i, u and r act on them, esc unmarks":           "%d marcati: i, u e r agiscono su di loro, esc li smarca",
+		"Updating":           "Aggiornamento",
+		"up to date or held": "aggiornato o bloccato",
+		"Unmark":             "Smarca",
+		"Unstar":             "Togli preferito",
+		"none":               "nessuna",
+		"Runtime":            "Esecuzione",
+		"Build only":         "Solo per la build",
+		"No bottle: building from source installs %s first":    "Nessun bottle: la build da sorgente installa prima %s",
+		"The build first installs its build dependencies: %s.": "La build installa prima le sue dipendenze di build: %s.",
+		"Show the full description":                            "Mostra la descrizione completa",
+	} {
+		_ = message.SetString(tag, key, msg)
+	}
+}
@@ -0,0 +1,62 @@
+package i18n
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// collator orders names in the active language: case-insensitive, with the numbers compared
+// by value, so that "python@3.9" comes before "python@3.10". A collator isn't safe for concurrent use.
+var (
+	collatorMu sync.Mutex
+	collator   = newCollator(language.English)
+)
+
+// newCollator returns the collator of names for a language.
+func newCollator(tag language.Tag) *collate.Collator {
+	return collate.New(tag, collate.IgnoreCase, collate.Numeric)
+}
+
+// Compare compares two names in the collation order of the active language, returning -1, 0 or +1.
+// Names differing only by case are ordered by byte value, so that the order is always the same.
+func Compare(a, b string) int {
+	collatorMu.Lock()
+	result := collator.CompareString(a, b)
+	collatorMu.Unlock()
+	if result == 0 {
+		return strings.Compare(a, b)
+	}
+	return result
+}
+
+// SortByName sorts items by the given name in the collation order of the active language, like Compare.
+// The sort is stable. The collation keys are computed once per item, for the long package lists.
+func SortByName[T any](items []T, name func(T) string) {
+	type keyed struct {
+		key  []byte
+		name string
+		item T
+	}
+	sorted := make([]keyed, len(items))
+	var buf collate.Buffer
+	collatorMu.Lock()
+	for i, item := range items {
+		sorted[i] = keyed{key: collator.KeyFromString(&buf, name(item)), name: name(item), item: item}
+	}
+	collatorMu.Unlock()
+
+	slices.SortStableFunc(sorted, func(a, b keyed) int {
+		if result := bytes.Compare(a.key, b.key); result != 0 {
+			return result
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	for i := range sorted {
+		items[i] = sorted[i].item
+	}
+}
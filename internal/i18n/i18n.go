@@ -0,0 +1,100 @@
+// Package i18n provides the message catalog and locale handling for Bold Brew.
+//
+// UI strings are written in English and used directly as catalog keys, following
+// the golang.org/x/text/message conventions:
+//
+//	i18n.T("Installing %s...", name)
+//
+// Translations (and plural forms) for other languages are registered in catalog.go.
+// A missing translation simply falls back to the English key.
+package i18n
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// printer is the active message printer, English until Init is called.
+var printer = message.NewPrinter(language.English)
+
+// Init selects the UI language. An empty locale detects it from the environment
+// (LC_ALL, LC_MESSAGES, LANG), falling back to English.
+func Init(locale string) {
+	if locale == "" {
+		locale = detectLocale()
+	}
+	tag := matchLanguage(locale)
+	printer = message.NewPrinter(tag)
+
+	collatorMu.Lock()
+	collator = newCollator(tag)
+	collatorMu.Unlock()
+}
+
+// T translates and formats a message using the active locale.
+func T(key string, args ...interface{}) string {
+	return printer.Sprintf(key, args...)
+}
+
+// Printer returns the active message printer, e.g. for locale-aware number formatting.
+func Printer() *message.Printer {
+	return printer
+}
+
+// Number formats an integer with the digit grouping of the active locale (12,345 or 12.345).
+func Number(n int) string {
+	return printer.Sprintf("%d", n)
+}
+
+// CompactNumber formats an integer in a short human-readable form: 950, 12.4k, 1.2M.
+// The decimal separator follows the active locale. Values without a suffix get a trailing
+// space, so that right-aligned columns keep their digits aligned.
+func CompactNumber(n int) string {
+	value := float64(n)
+	for _, unit := range []struct {
+		size   float64
+		suffix string
+	}{{1e9, "B"}, {1e6, "M"}, {1e3, "k"}} {
+		// Compare with the smallest value rounding to 1.0 of the unit, so 999,950 is 1.0M and not 1,000.0k
+		if threshold := unit.size * 0.99995; value >= threshold || value <= -threshold {
+			return printer.Sprintf("%.1f", value/unit.size) + unit.suffix
+		}
+	}
+	return printer.Sprintf("%d", n) + " "
+}
+
+// detectLocale returns the user's locale from the standard POSIX environment variables.
+func detectLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(env); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// matchLanguage converts a POSIX locale (e.g. "it_IT.UTF-8") to the closest supported language.
+func matchLanguage(locale string) language.Tag {
+	// Strip encoding and modifier: it_IT.UTF-8@euro → it_IT
+	if i := strings.IndexAny(locale, ".@"); i != -1 {
+		locale = locale[:i]
+	}
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return language.English
+	}
+
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return language.English
+	}
+
+	matcher := language.NewMatcher(supportedLanguages)
+	_, index, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return language.English
+	}
+	return supportedLanguages[index]
+}
@@ -0,0 +1,141 @@
+// Package logging provides the leveled loggers of bbrew. Logs go to a file of the state directory,
+// as text or JSON lines: writing to stderr would corrupt the TUI screen.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileName is the name of the log file in the log directory.
+const FileName = "bbrew.log"
+
+// maxFileSize is the size above which the log file is rotated on startup, keeping one previous file.
+const maxFileSize = 5 * 1024 * 1024
+
+var (
+	mu      sync.RWMutex
+	current slog.Handler = slog.DiscardHandler // Until Setup is called
+	logPath string                             // Path of the log file, "" when not logging
+)
+
+// levels are the accepted log levels, "off" disabling logging.
+var levels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// Setup sends the logs of the given level and above ("debug", "info", "warn", "error" or "off")
+// to the log file of dir, as JSON lines if jsonOutput is set. The returned function closes the file.
+func Setup(dir, level string, jsonOutput bool) (func(), error) {
+	level = strings.ToLower(level)
+	if level == "off" {
+		return func() {}, nil
+	}
+	minLevel, ok := levels[level]
+	if !ok {
+		return nil, fmt.Errorf("invalid log level %q (use debug, info, warn, error or off)", level)
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, FileName)
+	if info, err := os.Stat(path); err == nil && info.Size() > maxFileSize {
+		_ = os.Rename(path, path+".1")
+	}
+	// #nosec G304 -- path is built from the state directory
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: minLevel}
+	var handler slog.Handler = slog.NewTextHandler(file, options)
+	if jsonOutput {
+		handler = slog.NewJSONHandler(file, options)
+	}
+
+	mu.Lock()
+	current, logPath = handler, path
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		current, logPath = slog.DiscardHandler, ""
+		mu.Unlock()
+		_ = file.Close()
+	}, nil
+}
+
+// Tail returns the last n lines of the log file, nil when not logging.
+func Tail(n int) []string {
+	mu.RLock()
+	path := logPath
+	mu.RUnlock()
+	if path == "" {
+		return nil
+	}
+	// #nosec G304 -- path is built from the state directory
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	return lines[max(0, len(lines)-n):]
+}
+
+// For returns the logger of a component, tagging its records with it (component=brew).
+// Loggers can be created before Setup, e.g. as package variables: they follow the current setup.
+func For(component string) *slog.Logger {
+	return slog.New(&handler{ops: []func(slog.Handler) slog.Handler{
+		func(h slog.Handler) slog.Handler {
+			return h.WithAttrs([]slog.Attr{slog.String("component", component)})
+		},
+	}})
+}
+
+// handler forwards records to the current handler, replaying the attributes and groups added to the logger.
+type handler struct {
+	ops []func(slog.Handler) slog.Handler
+}
+
+func (h *handler) resolve() slog.Handler {
+	mu.RLock()
+	resolved := current
+	mu.RUnlock()
+	for _, op := range h.ops {
+		resolved = op(resolved)
+	}
+	return resolved
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	return h.resolve().Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *handler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &handler{ops: append(ops, op)}
+}
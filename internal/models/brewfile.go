@@ -1,13 +1,37 @@
 package models
 
+import "strings"
+
 // BrewfileEntry represents a single entry from a Brewfile
 type BrewfileEntry struct {
-	Name   string
-	IsCask bool
+	Name    string
+	IsCask  bool
+	Comment string   // Trailing comment of the line, e.g. "used by deploy scripts"
+	Group   string   // Section the entry belongs to, e.g. "Dev tools" for "# --- Dev tools ---"
+	Tags    []string // Tags from a "# tags: work,media" comment
+}
+
+// InGroup reports whether the entry belongs to the given section or has the given tag.
+func (e BrewfileEntry) InGroup(name string) bool {
+	return inBrewfileGroup(e.Group, e.Tags, name)
 }
 
 // BrewfileResult contains all parsed entries from a Brewfile
 type BrewfileResult struct {
 	Taps     []string        // List of taps to install
 	Packages []BrewfileEntry // List of packages (formulae and casks)
+	Groups   []string        // Section headers, in order of appearance
+}
+
+// inBrewfileGroup matches a group name against a section and tags, ignoring case.
+func inBrewfileGroup(group string, tags []string, name string) bool {
+	if strings.EqualFold(group, name) {
+		return true
+	}
+	for _, tag := range tags {
+		if strings.EqualFold(tag, name) {
+			return true
+		}
+	}
+	return false
 }
@@ -1,5 +1,12 @@
 package models
 
+import (
+	"bbrew/internal/version"
+	"fmt"
+	"path"
+	"strings"
+)
+
 // Cask represents a Homebrew cask (GUI application).
 type Cask struct {
 	Token                 string             `json:"token"`
@@ -14,7 +21,11 @@ type Cask struct {
 	Installed             *string            `json:"installed"`      // Null if not installed, version string if installed
 	InstalledTime         *int64             `json:"installed_time"` // Unix timestamp
 	Outdated              bool               `json:"outdated"`
+	AutoUpdates           bool               `json:"auto_updates"` // The app updates itself, brew only upgrades it with --greedy
 	SHA256                string             `json:"sha256"`
+	DependsOn             CaskDependsOn      `json:"depends_on"`
+	Caveats               string             `json:"caveats"`
+	Artifacts             []map[string]any   `json:"artifacts"`
 	Deprecated            bool               `json:"deprecated"`
 	DeprecationDate       interface{}        `json:"deprecation_date"`
 	DeprecationReason     interface{}        `json:"deprecation_reason"`
@@ -28,4 +39,144 @@ type Cask struct {
 	Analytics90dDownloads int                // Internal: Populated from analytics
 	LocallyInstalled      bool               `json:"-"` // Internal flag
 	IsCask                bool               `json:"-"` // Internal flag to distinguish from formulae
+	Partial               bool               `json:"-"` // Internal flag for summaries, without their full details
+}
+
+// CaskDependsOn holds the cask requirements relevant to installability.
+type CaskDependsOn struct {
+	// MacOS maps a comparison operator (">=", "<=", "==", ...) to macOS versions or codenames.
+	MacOS map[string][]string `json:"macos"`
+}
+
+// Summary returns a partial copy of the cask with only the data used by the table, search, filters
+// and the app lookups (its app artifacts). The full cask is loaded when the package is shown.
+func (c *Cask) Summary() Cask {
+	summary := Cask{
+		Token:                 c.Token,
+		FullToken:             c.FullToken,
+		OldTokens:             c.OldTokens,
+		Tap:                   c.Tap,
+		Name:                  c.Name,
+		Description:           c.Description,
+		Homepage:              c.Homepage,
+		Version:               c.Version,
+		Installed:             c.Installed,
+		InstalledTime:         c.InstalledTime,
+		Outdated:              c.Outdated,
+		AutoUpdates:           c.AutoUpdates,
+		DependsOn:             c.DependsOn,
+		Deprecated:            c.Deprecated,
+		Disabled:              c.Disabled,
+		Analytics90dRank:      c.Analytics90dRank,
+		Analytics90dDownloads: c.Analytics90dDownloads,
+		LocallyInstalled:      c.LocallyInstalled,
+		IsCask:                c.IsCask,
+		Partial:               true,
+	}
+	for _, artifact := range c.Artifacts {
+		if app, exists := artifact["app"]; exists {
+			summary.Artifacts = append(summary.Artifacts, map[string]any{"app": app})
+		}
+	}
+	return summary
+}
+
+// Apps returns the names of the app bundles the cask installs in /Applications (e.g. "Firefox.app"),
+// renamed by their target when the cask sets one.
+func (c *Cask) Apps() []string {
+	var apps []string
+	for _, artifact := range c.Artifacts {
+		entries, _ := artifact["app"].([]any)
+		for i, entry := range entries {
+			switch value := entry.(type) {
+			case string:
+				apps = append(apps, value)
+			case map[string]any:
+				// {"target": "Name.app"} renames the preceding app
+				if target, ok := value["target"].(string); ok && i > 0 && len(apps) > 0 {
+					apps[len(apps)-1] = target
+				}
+			}
+		}
+	}
+	return apps
+}
+
+// Binaries returns the names of the commands the cask links in the bin directory of the prefix,
+// renamed by their target when the cask sets one.
+func (c *Cask) Binaries() []string {
+	var binaries []string
+	for _, artifact := range c.Artifacts {
+		entries, _ := artifact["binary"].([]any)
+		for i, entry := range entries {
+			switch value := entry.(type) {
+			case string:
+				binaries = append(binaries, path.Base(value))
+			case map[string]any:
+				// {"target": "name"} renames the preceding binary
+				if target, ok := value["target"].(string); ok && i > 0 && len(binaries) > 0 {
+					binaries[len(binaries)-1] = path.Base(target)
+				}
+			}
+		}
+	}
+	return binaries
+}
+
+// MacOSRequirement returns a readable form of the macOS requirement (e.g. ">= 13"), or "" if none.
+func (c *Cask) MacOSRequirement() string {
+	var parts []string
+	for _, operator := range []string{">=", ">", "==", "<=", "<"} {
+		versions := c.DependsOn.MacOS[operator]
+		if len(versions) == 0 {
+			continue
+		}
+		resolved := make([]string, 0, len(versions))
+		for _, version := range versions {
+			resolved = append(resolved, MacOSVersion(version))
+		}
+		parts = append(parts, fmt.Sprintf("%s %s", operator, strings.Join(resolved, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// SupportsMacOS reports whether the cask can be installed on the given macOS version, compared by release
+// (see MacOSReleaseOf): "<= :ventura" accepts 13.5. Any of the releases listed by an operator satisfies it.
+// An empty version (e.g. on Linux or when detection failed) is never treated as a conflict.
+func (c *Cask) SupportsMacOS(macOS string) bool {
+	if macOS == "" {
+		return true
+	}
+
+	host := MacOSReleaseOf(macOS)
+	for operator, versions := range c.DependsOn.MacOS {
+		if len(versions) == 0 {
+			continue
+		}
+		matched := false
+		for _, required := range versions {
+			cmp := version.Compare(host, MacOSReleaseOf(MacOSVersion(required)))
+			switch operator {
+			case "==":
+				matched = cmp == 0
+			case ">=":
+				matched = cmp >= 0
+			case ">":
+				matched = cmp > 0
+			case "<=":
+				matched = cmp <= 0
+			case "<":
+				matched = cmp < 0
+			default:
+				matched = true // Unknown operator, not treated as a conflict
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestSupportsMacOS(t *testing.T) {
+	tests := []struct {
+		operator string
+		versions []string
+		macOS    string
+		want     bool
+	}{
+		// Point releases compare by their release
+		{"<=", []string{":ventura"}, "13.5", true},
+		{"<=", []string{":ventura"}, "14.0", false},
+		{"<", []string{":ventura"}, "12.7.4", true},
+		{"<", []string{":ventura"}, "13.5", false},
+		{">=", []string{":ventura"}, "13.0.1", true},
+		{">=", []string{":ventura"}, "12.7", false},
+		{">", []string{":ventura"}, "13.6", false},
+		{">", []string{":ventura"}, "14.1", true},
+		{"==", []string{":sonoma"}, "14.4.1", true},
+
+		// Before Big Sur, releases are major.minor
+		{"<=", []string{":catalina"}, "10.15.7", true},
+		{"<=", []string{":mojave"}, "10.15.7", false},
+		{">=", []string{"10.13"}, "10.14.6", true},
+
+		// Any listed release satisfies the operator
+		{"==", []string{":monterey", ":ventura", ":sonoma"}, "13.2", true},
+		{"==", []string{":monterey", ":ventura"}, "14.2", false},
+
+		// No known version is never a conflict
+		{">=", []string{":sequoia"}, "", true},
+	}
+
+	for _, tt := range tests {
+		cask := Cask{}
+		cask.DependsOn.MacOS = map[string][]string{tt.operator: tt.versions}
+		if got := cask.SupportsMacOS(tt.macOS); got != tt.want {
+			t.Errorf("SupportsMacOS(%q) with %s %v = %v, want %v", tt.macOS, tt.operator, tt.versions, got, tt.want)
+		}
+	}
+}
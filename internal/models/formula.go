@@ -1,5 +1,7 @@
 package models
 
+import "fmt"
+
 //type Formulae []Formula
 
 type Formula struct {
@@ -54,6 +56,7 @@ type Formula struct {
 	Analytics90dDownloads  int
 	LocallyInstalled       bool   `json:"-"` // Internal flag to indicate if the formula is installed locally [internal use]
 	LocalPath              string `json:"-"` // Internal path to the formula in the local Homebrew Cellar [internal use]
+	Partial                bool   `json:"-"` // Internal flag for formulae without their full details (v3 index, summaries) [internal use]
 }
 
 type Analytics struct {
@@ -134,3 +137,93 @@ type RuntimeDependency struct {
 type RubySourceChecksum struct {
 	Sha256 string `json:"sha256"`
 }
+
+// Summary returns a partial copy of the formula with only the data used by the table, search and filters.
+// Only the bottles of the given tags are kept. The full formula is loaded when the package is shown.
+func (f *Formula) Summary(tags []string) Formula {
+	summary := Formula{
+		Name:                  f.Name,
+		FullName:              f.FullName,
+		OldNames:              f.OldNames,
+		Tap:                   f.Tap,
+		Description:           f.Description,
+		License:               f.License,
+		Homepage:              f.Homepage,
+		Versions:              f.Versions,
+		Revision:              f.Revision,
+		Dependencies:          f.Dependencies,
+		BuildDependencies:     f.BuildDependencies,
+		KegOnly:               f.KegOnly,
+		Installed:             f.Installed,
+		Pinned:                f.Pinned,
+		Outdated:              f.Outdated,
+		Deprecated:            f.Deprecated,
+		Disabled:              f.Disabled,
+		Analytics90dRank:      f.Analytics90dRank,
+		Analytics90dDownloads: f.Analytics90dDownloads,
+		LocallyInstalled:      f.LocallyInstalled,
+		LocalPath:             f.LocalPath,
+		Partial:               true,
+	}
+	summary.Bottle.Stable.Rebuild = f.Bottle.Stable.Rebuild
+	summary.Bottle.Stable.RootURL = f.Bottle.Stable.RootURL
+	for _, tag := range tags {
+		if file, exists := f.Bottle.Stable.Files[tag]; exists {
+			if summary.Bottle.Stable.Files == nil {
+				summary.Bottle.Stable.Files = make(map[string]BottleFile, 1)
+			}
+			summary.Bottle.Stable.Files[tag] = file
+		}
+	}
+	return summary
+}
+
+// BottleTag returns the first of the given bottle tags that the formula has a bottle for,
+// or "" if none matches and the formula must be built from source.
+func (f *Formula) BottleTag(tags []string) string {
+	for _, tag := range tags {
+		if _, exists := f.Bottle.Stable.Files[tag]; exists {
+			return tag
+		}
+	}
+	return ""
+}
+
+// PkgVersion returns the version with its revision, as Homebrew names kegs and bottles: "1.2.3_1".
+func (f *Formula) PkgVersion() string {
+	if f.Revision > 0 {
+		return fmt.Sprintf("%s_%d", f.Versions.Stable, f.Revision)
+	}
+	return f.Versions.Stable
+}
+
+// BottleFileName returns the file name Homebrew gives the bottle of a tag,
+// e.g. "wget--1.24.5.arm64_sonoma.bottle.tar.gz".
+func (f *Formula) BottleFileName(tag string) string {
+	rebuild := ""
+	if f.Bottle.Stable.Rebuild > 0 {
+		rebuild = fmt.Sprintf(".%d", f.Bottle.Stable.Rebuild)
+	}
+	return fmt.Sprintf("%s--%s.%s.bottle%s.tar.gz", f.Name, f.PkgVersion(), tag, rebuild)
+}
+
+// EstimatedInstallMinutes gives a rough install time estimate: pouring a bottle is quick,
+// while a source build grows with the number of build dependencies.
+func (f *Formula) EstimatedInstallMinutes(hasBottle bool) int {
+	if hasBottle {
+		return 1
+	}
+	return 3 + 2*len(f.BuildDependencies)
+}
+
+// MissingBuildDependencies returns the build dependencies that a build from source installs first:
+// those that installed reports as not installed.
+func (f *Formula) MissingBuildDependencies(installed func(name string) bool) []string {
+	var missing []string
+	for _, dep := range f.BuildDependencies {
+		if !installed(dep) {
+			missing = append(missing, dep)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// maintainedWindow is how recent the last push must be for a repository to count as maintained.
+const maintainedWindow = 365 * 24 * time.Hour
+
+// GitHubMetadata holds the GitHub repository information of a package.
+// Field names follow the GitHub API, so the repository response can be decoded directly.
+type GitHubMetadata struct {
+	Repository string    `json:"full_name"`
+	Stars      int       `json:"stargazers_count"`
+	PushedAt   time.Time `json:"pushed_at"`
+	Archived   bool      `json:"archived"`
+	FetchedAt  time.Time `json:"fetched_at"` // Internal: when the metadata was retrieved
+}
+
+// RecentlyMaintained reports whether the repository is active: not archived and pushed to in the last year.
+func (m *GitHubMetadata) RecentlyMaintained() bool {
+	return !m.Archived && time.Since(m.PushedAt) < maintainedWindow
+}
@@ -0,0 +1,55 @@
+package models
+
+import "strings"
+
+// MacOSRelease pairs a macOS version with the codename Homebrew uses for it.
+type MacOSRelease struct {
+	Version  string
+	Codename string
+}
+
+// MacOSReleases lists the macOS releases known to Homebrew, newest first.
+var MacOSReleases = []MacOSRelease{
+	{"26", "tahoe"},
+	{"15", "sequoia"},
+	{"14", "sonoma"},
+	{"13", "ventura"},
+	{"12", "monterey"},
+	{"11", "big_sur"},
+	{"10.15", "catalina"},
+	{"10.14", "mojave"},
+	{"10.13", "high_sierra"},
+	{"10.12", "sierra"},
+	{"10.11", "el_capitan"},
+}
+
+// MacOSCodename returns the codename for a macOS version (e.g. "14.5" → "sonoma"), or "" if unknown.
+func MacOSCodename(version string) string {
+	for _, release := range MacOSReleases {
+		if version == release.Version || strings.HasPrefix(version, release.Version+".") {
+			return release.Codename
+		}
+	}
+	return ""
+}
+
+// MacOSVersion resolves a version or codename (e.g. "13", ":ventura") to a version string.
+func MacOSVersion(versionOrCodename string) string {
+	name := strings.TrimPrefix(versionOrCodename, ":")
+	for _, release := range MacOSReleases {
+		if name == release.Codename {
+			return release.Version
+		}
+	}
+	return name
+}
+
+// MacOSReleaseOf truncates a macOS version to its release, the way Homebrew compares versions: the major
+// version from Big Sur on (e.g. "13.5" → "13"), the major and minor versions before (e.g. "10.15.7" → "10.15").
+func MacOSReleaseOf(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if parts[0] == "10" && len(parts) > 1 {
+		return parts[0] + "." + parts[1]
+	}
+	return parts[0]
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 // PackageType distinguishes between formulae and casks.
 type PackageType string
 
@@ -12,6 +17,7 @@ const (
 type Package struct {
 	// Common fields
 	Name                  string      // Formula.Name or Cask.Token
+	FullName              string      // Formula.FullName or Cask.FullToken, e.g. "user/repo/tool" for tap packages
 	DisplayName           string      // Formula.FullName or Cask.Name[0]
 	Description           string      // desc
 	Homepage              string      // homepage
@@ -28,6 +34,14 @@ type Package struct {
 
 	// For leaves filter (only meaningful for formulae)
 	InstalledOnRequest bool
+
+	// Trailing comment, section and tags of the Brewfile entry (only in Brewfile mode)
+	BrewfileComment string
+	BrewfileGroup   string
+	BrewfileTags    []string
+
+	// Old name the Brewfile refers to the package by, when it was renamed (only in Brewfile mode)
+	RenamedFrom string
 }
 
 // NewPackageFromFormula creates a Package from a Formula.
@@ -39,6 +53,7 @@ func NewPackageFromFormula(f *Formula) Package {
 
 	return Package{
 		Name:                  f.Name,
+		FullName:              fullName(f.FullName, f.Name),
 		DisplayName:           f.FullName,
 		Description:           f.Description,
 		Homepage:              f.Homepage,
@@ -63,6 +78,7 @@ func NewPackageFromCask(c *Cask) Package {
 
 	return Package{
 		Name:                  c.Token,
+		FullName:              fullName(c.FullToken, c.Token),
 		DisplayName:           displayName,
 		Description:           c.Description,
 		Homepage:              c.Homepage,
@@ -77,3 +93,84 @@ func NewPackageFromCask(c *Cask) Package {
 		InstalledOnRequest:    true, // Casks are always explicitly installed
 	}
 }
+
+// OldNames returns the names the package had before being renamed: Formula.OldNames or Cask.OldTokens.
+func (p *Package) OldNames() []string {
+	if p.Formula != nil {
+		return p.Formula.OldNames
+	}
+	if p.Cask != nil {
+		return p.Cask.OldTokens
+	}
+	return nil
+}
+
+// fullName returns the tap-qualified name of a package, falling back to its short name.
+func fullName(full, short string) string {
+	if full == "" {
+		return short
+	}
+	return full
+}
+
+// ShortName returns a package name without its tap prefix ("user/repo/tool" → "tool").
+func ShortName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
+
+// coreTaps are the official taps, whose packages come with the Homebrew API.
+var coreTaps = map[string]bool{"homebrew/core": true, "homebrew/cask": true}
+
+// Tap returns the tap the package comes from (e.g. "homebrew/core", "user/repo"), or "" if unknown.
+func (p *Package) Tap() string {
+	if p.Formula != nil && p.Formula.Tap != "" {
+		return p.Formula.Tap
+	}
+	if p.Cask != nil && p.Cask.Tap != "" {
+		return p.Cask.Tap
+	}
+	// Packages without info (e.g. Brewfile placeholders) may still have a tap-qualified name
+	if i := strings.LastIndex(p.FullName, "/"); i > 0 {
+		return p.FullName[:i]
+	}
+	return ""
+}
+
+// IsThirdPartyTap reports whether the package comes from a tap other than homebrew/core and homebrew/cask.
+func (p *Package) IsThirdPartyTap() bool {
+	tap := p.Tap()
+	return tap != "" && !coreTaps[strings.ToLower(tap)]
+}
+
+// InstalledVersion returns the locally installed version, or "" if not installed.
+func (p *Package) InstalledVersion() string {
+	if !p.LocallyInstalled {
+		return ""
+	}
+	if p.Formula != nil && len(p.Formula.Installed) > 0 {
+		return p.Formula.Installed[0].Version
+	}
+	if p.Cask != nil && p.Cask.Installed != nil {
+		return *p.Cask.Installed
+	}
+	return ""
+}
+
+// LatestVersion returns the latest version in the format of InstalledVersion: with the revision of formulae
+// rebuilt without a new version (e.g. "1.2.3_1").
+func (p *Package) LatestVersion() string {
+	if p.Formula != nil && p.Formula.Revision > 0 {
+		return fmt.Sprintf("%s_%d", p.Version, p.Formula.Revision)
+	}
+	return p.Version
+}
+
+// IsPartial reports whether the package only has the summary of its formula or cask, without the full details.
+func (p *Package) IsPartial() bool {
+	return (p.Formula != nil && p.Formula.Partial) || (p.Cask != nil && p.Cask.Partial)
+}
+
+// InBrewfileGroup reports whether the package belongs to the given Brewfile section or has the given tag.
+func (p *Package) InBrewfileGroup(name string) bool {
+	return inBrewfileGroup(p.BrewfileGroup, p.BrewfileTags, name)
+}
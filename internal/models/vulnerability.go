@@ -0,0 +1,31 @@
+package models
+
+import "bbrew/internal/version"
+
+// Vulnerability is a known security advisory affecting an installed package version.
+type Vulnerability struct {
+	ID            string   `json:"id"`
+	Aliases       []string `json:"aliases"` // e.g. CVE identifiers
+	Summary       string   `json:"summary"`
+	FixedVersions []string `json:"fixed_versions"`
+}
+
+// CVE returns the first CVE identifier of the advisory, falling back to its ID.
+func (v *Vulnerability) CVE() string {
+	for _, alias := range v.Aliases {
+		if len(alias) > 4 && alias[:4] == "CVE-" {
+			return alias
+		}
+	}
+	return v.ID
+}
+
+// FixedBy reports whether upgrading to the given version resolves the advisory.
+func (v *Vulnerability) FixedBy(target string) bool {
+	for _, fixed := range v.FixedVersions {
+		if version.Compare(target, fixed) >= 0 {
+			return true
+		}
+	}
+	return false
+}
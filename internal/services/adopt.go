@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// caskAppDir is where casks install their apps, Homebrew's default appdir.
+const caskAppDir = "/Applications"
+
+// existingCaskApps returns the apps of a cask not installed by Homebrew that already exist in /Applications,
+// e.g. downloaded from the developer's site. Installing the cask fails on them ("already exists")
+// unless they are adopted.
+func existingCaskApps(pkg models.Package) []string {
+	if pkg.Cask == nil || pkg.LocallyInstalled {
+		return nil
+	}
+
+	var existing []string
+	for _, app := range pkg.Cask.Apps() {
+		if path, ok := caskAppPath(app); ok {
+			if _, err := os.Stat(path); err == nil {
+				existing = append(existing, path)
+			}
+		}
+	}
+	return existing
+}
+
+// caskAppPath returns the path of an app of a cask, as returned by Cask.Apps: in /Applications unless absolute
+// or in the home directory.
+func caskAppPath(app string) (string, bool) {
+	switch {
+	case strings.HasPrefix(app, "~/"):
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		return filepath.Join(home, app[2:]), true
+	case !filepath.IsAbs(app):
+		return filepath.Join(caskAppDir, app), true
+	}
+	return app, true
+}
+
+// unmanagedApp is an app of /Applications installed outside Homebrew, with the cask that can adopt it.
+type unmanagedApp struct {
+	Path string
+	Cask models.Package
+}
+
+// unmanagedApps scans /Applications for the apps installed outside Homebrew that a cask installs,
+// matched by the app artifacts of the casks. When several casks install the same app (e.g. firefox
+// and firefox@beta), the main one is preferred, then the most downloaded.
+func (s *AppService) unmanagedApps() ([]unmanagedApp, error) {
+	entries, err := os.ReadDir(caskAppDir)
+	if err != nil {
+		return nil, err
+	}
+
+	casks := make(map[string]models.Package) // By lowercased app name
+	managed := make(map[string]bool)         // Apps of installed casks, e.g. firefox for firefox@beta
+	for _, pkg := range s.store.All() {
+		if pkg.Cask == nil {
+			continue
+		}
+		for _, app := range pkg.Cask.Apps() {
+			key := strings.ToLower(app)
+			if pkg.LocallyInstalled {
+				managed[key] = true
+			} else if current, exists := casks[key]; !exists || preferredCask(pkg, current) {
+				casks[key] = pkg
+			}
+		}
+	}
+
+	var apps []unmanagedApp
+	for _, entry := range entries {
+		key := strings.ToLower(entry.Name())
+		if pkg, exists := casks[key]; exists && !managed[key] && strings.HasSuffix(key, ".app") {
+			apps = append(apps, unmanagedApp{Path: filepath.Join(caskAppDir, entry.Name()), Cask: pkg})
+		}
+	}
+	i18n.SortByName(apps, func(app unmanagedApp) string { return app.Path })
+	return apps, nil
+}
+
+// preferredCask reports whether a cask should adopt an app rather than another one installing it too.
+func preferredCask(pkg, other models.Package) bool {
+	if variant, otherVariant := strings.Contains(pkg.Name, "@"), strings.Contains(other.Name, "@"); variant != otherVariant {
+		return !variant
+	}
+	if pkg.Cask.Analytics90dDownloads != other.Cask.Analytics90dDownloads {
+		return pkg.Cask.Analytics90dDownloads > other.Cask.Analytics90dDownloads
+	}
+	return pkg.Name < other.Name
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OSV.dev API endpoints
+	osvQueryBatchURL = "https://api.osv.dev/v1/querybatch"
+	osvVulnURL       = "https://api.osv.dev/v1/vulns/%s"
+
+	// osvEcosystem is the OSV ecosystem formulae are looked up in. OSV has no Homebrew ecosystem:
+	// formulae are looked up by their upstream repository, whose advisories list the affected tags.
+	osvEcosystem = "GIT"
+
+	// osvBatchSize is the maximum number of queries in a single batch request.
+	osvBatchSize = 1000
+
+	// cacheFileAdvisories stores the advisories of installed package versions. Renamed from
+	// advisories.json, which held the empty results of lookups in a Homebrew ecosystem, then from
+	// advisories-git.json, whose fixed versions included the commit hashes of GIT ranges.
+	cacheFileAdvisories = "advisories-osv.json"
+
+	// advisoriesTTL is how long the advisories of a package version stay valid.
+	advisoriesTTL = 24 * time.Hour
+)
+
+// AdvisoryServiceInterface defines the contract for security advisory checks.
+type AdvisoryServiceInterface interface {
+	Check(ctx context.Context, packages []models.Package) error
+	Lookup(pkg models.Package) []models.Vulnerability
+}
+
+// AdvisoryService queries OSV.dev for known vulnerabilities of installed formulae.
+// Queries are batched, and results are cached per package version.
+type AdvisoryService struct {
+	mu         sync.Mutex
+	advisories map[string]*advisoryEntry // Keyed by "name@version"
+}
+
+// advisoryEntry holds the cached advisories of a package version.
+type advisoryEntry struct {
+	CheckedAt       time.Time              `json:"checked_at"`
+	Vulnerabilities []models.Vulnerability `json:"vulnerabilities"`
+}
+
+type osvQuery struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version string `json:"version"`
+}
+
+type osvVuln struct {
+	ID       string   `json:"id"`
+	Aliases  []string `json:"aliases"`
+	Summary  string   `json:"summary"`
+	Affected []struct {
+		Ranges []struct {
+			Type   string `json:"type"` // GIT, SEMVER or ECOSYSTEM
+			Events []struct {
+				Fixed string `json:"fixed"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+}
+
+// NewAdvisoryService creates a new instance of AdvisoryService, loading the cached advisories.
+var NewAdvisoryService = func() AdvisoryServiceInterface {
+	a := &AdvisoryService{advisories: make(map[string]*advisoryEntry)}
+	if data := readCacheFile(cacheFileAdvisories, 2); data != nil {
+		_ = json.Unmarshal(data, &a.advisories)
+	}
+	return a
+}
+
+// advisoryVersion returns the installed version of a package without its Homebrew revision suffix
+// (e.g. "3.1.2_1" → "3.1.2"), or "" if not installed. OSV matches it against the tags of the upstream
+// repository, loosely (v1.2.3 matches 1.2.3): repositories tagging releases otherwise, e.g. curl-8_5_0,
+// find no advisories.
+func advisoryVersion(pkg models.Package) string {
+	version, _, _ := strings.Cut(pkg.InstalledVersion(), "_")
+	return version
+}
+
+// advisoryKey returns the cache key of the installed version of a package, or "" if not installed.
+// The key is only an identifier: names may contain "@" themselves (python@3.12), so it isn't parsed.
+func advisoryKey(pkg models.Package) string {
+	version := advisoryVersion(pkg)
+	if version == "" {
+		return ""
+	}
+	return pkg.Name + "@" + version
+}
+
+// osvRepository returns the OSV package name of a formula, the URL of its upstream GitHub
+// repository, or "" when it isn't hosted on GitHub.
+func osvRepository(pkg models.Package) string {
+	owner, repo := githubRepo(pkg)
+	if owner == "" {
+		return ""
+	}
+	return "https://github.com/" + owner + "/" + repo
+}
+
+// Lookup returns the known vulnerabilities of the installed version of a package.
+func (a *AdvisoryService) Lookup(pkg models.Package) []models.Vulnerability {
+	key := advisoryKey(pkg)
+	if key == "" {
+		return nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if entry := a.advisories[key]; entry != nil {
+		return entry.Vulnerabilities
+	}
+	return nil
+}
+
+// Check queries the advisories of the installed formulae that aren't cached yet.
+// Formulae not hosted on GitHub have no upstream repository to look up and are skipped.
+func (a *AdvisoryService) Check(ctx context.Context, packages []models.Package) error {
+	var queries []osvQuery
+	var keys []string
+
+	a.mu.Lock()
+	for _, pkg := range packages {
+		key := advisoryKey(pkg)
+		if key == "" || pkg.Type != models.PackageTypeFormula {
+			continue
+		}
+		if entry := a.advisories[key]; entry != nil && time.Since(entry.CheckedAt) < advisoriesTTL {
+			continue
+		}
+
+		repository := osvRepository(pkg)
+		if repository == "" {
+			continue
+		}
+		query := osvQuery{Version: advisoryVersion(pkg)}
+		query.Package.Name = repository
+		query.Package.Ecosystem = osvEcosystem
+		queries = append(queries, query)
+		keys = append(keys, key)
+	}
+	a.mu.Unlock()
+
+	for start := 0; start < len(queries); start += osvBatchSize {
+		end := min(start+osvBatchSize, len(queries))
+		if err := a.queryBatch(ctx, queries[start:end], keys[start:end]); err != nil {
+			return err
+		}
+	}
+
+	a.save()
+	return nil
+}
+
+// queryBatch runs a single batch query and stores the advisories of each package version.
+func (a *AdvisoryService) queryBatch(ctx context.Context, queries []osvQuery, keys []string) error {
+	var response struct {
+		Results []struct {
+			Vulns []struct {
+				ID string `json:"id"`
+			} `json:"vulns"`
+		} `json:"results"`
+	}
+	if err := postOSV(ctx, osvQueryBatchURL, map[string]any{"queries": queries}, &response); err != nil {
+		return err
+	}
+
+	for i, result := range response.Results {
+		if i >= len(keys) {
+			break
+		}
+
+		// The batch endpoint only returns IDs, so fetch the details of each advisory
+		vulnerabilities := make([]models.Vulnerability, 0, len(result.Vulns))
+		for _, ref := range result.Vulns {
+			vuln, err := a.fetchVulnerability(ctx, ref.ID)
+			if err != nil {
+				vuln = models.Vulnerability{ID: ref.ID}
+			}
+			vulnerabilities = append(vulnerabilities, vuln)
+		}
+
+		a.mu.Lock()
+		a.advisories[keys[i]] = &advisoryEntry{CheckedAt: time.Now(), Vulnerabilities: vulnerabilities}
+		a.mu.Unlock()
+	}
+	return nil
+}
+
+// fetchVulnerability retrieves the summary, aliases and fixed versions of an advisory.
+func (a *AdvisoryService) fetchVulnerability(ctx context.Context, id string) (models.Vulnerability, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(osvVulnURL, id), nil)
+	if err != nil {
+		return models.Vulnerability{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return models.Vulnerability{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return models.Vulnerability{}, fmt.Errorf("OSV returned HTTP %d", resp.StatusCode)
+	}
+
+	var vuln osvVuln
+	if err := json.NewDecoder(resp.Body).Decode(&vuln); err != nil {
+		return models.Vulnerability{}, err
+	}
+
+	return models.Vulnerability{
+		ID: vuln.ID, Aliases: vuln.Aliases, Summary: vuln.Summary, FixedVersions: osvFixedVersions(vuln),
+	}, nil
+}
+
+// osvFixedVersions returns the versions fixing an advisory. The fixes of GIT ranges are commit hashes,
+// which can't be compared with versions: they are left out.
+func osvFixedVersions(vuln osvVuln) []string {
+	var fixed []string
+	for _, affected := range vuln.Affected {
+		for _, r := range affected.Ranges {
+			if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+				continue
+			}
+			for _, event := range r.Events {
+				if event.Fixed != "" {
+					fixed = append(fixed, event.Fixed)
+				}
+			}
+		}
+	}
+	return fixed
+}
+
+// save persists the advisories cache.
+func (a *AdvisoryService) save() {
+	a.mu.Lock()
+	data, err := json.Marshal(a.advisories)
+	a.mu.Unlock()
+
+	if err == nil && ensureCacheDir() == nil {
+		writeCacheFile(cacheFileAdvisories, data)
+	}
+}
+
+// postOSV sends a JSON request to the OSV API and decodes the response into v.
+func postOSV(ctx context.Context, url string, body any, v any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach OSV: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OSV returned HTTP %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
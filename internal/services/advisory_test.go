@@ -0,0 +1,32 @@
+package services
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestOSVFixedVersions(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges string
+		want   []string
+	}{
+		{"git range fixed by a commit", `[{"type": "GIT", "events": [{"introduced": "0"}, {"fixed": "a3f9c2e"}]}]`, nil},
+		{"semver range", `[{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.4"}]}]`, []string{"1.2.4"}},
+		{"ecosystem range", `[{"type": "ECOSYSTEM", "events": [{"introduced": "2.0"}, {"fixed": "2.1"}]}]`, []string{"2.1"}},
+		{"git and semver ranges",
+			`[{"type": "GIT", "events": [{"fixed": "9f3a1b0"}]}, {"type": "SEMVER", "events": [{"fixed": "3.0.1"}]}]`,
+			[]string{"3.0.1"}},
+	}
+
+	for _, tt := range tests {
+		var vuln osvVuln
+		if err := json.Unmarshal([]byte(`{"affected": [{"ranges": `+tt.ranges+`}]}`), &vuln); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := osvFixedVersions(vuln); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: osvFixedVersions() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
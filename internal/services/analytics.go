@@ -0,0 +1,45 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// brewAnalyticsState returns whether Homebrew's analytics are enabled, from `brew analytics state`
+// ("Analytics are enabled." or "Analytics are disabled.", InfluxDB analytics on older versions).
+func brewAnalyticsState() (bool, error) {
+	output, err := brewCommand("analytics", "state").CombinedOutput()
+	if err != nil {
+		return false, commandFailure("brew analytics state", err, output)
+	}
+	state := strings.ToLower(string(output))
+	switch {
+	case strings.Contains(state, "disabled"):
+		return false, nil
+	case strings.Contains(state, "enabled"):
+		return true, nil
+	default:
+		return false, fmt.Errorf("unexpected output of brew analytics state: %q", strings.TrimSpace(string(output)))
+	}
+}
+
+// setBrewAnalytics turns Homebrew's analytics on or off (`brew analytics on|off`), for every brew command
+// of the user, not only bbrew's.
+func setBrewAnalytics(enabled bool) error {
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	brewLog.Info("setting Homebrew analytics", "state", state)
+	if output, err := brewCommand("analytics", state).CombinedOutput(); err != nil {
+		return commandFailure("brew analytics "+state, err, output)
+	}
+	return nil
+}
+
+// brewAnalyticsForcedOff reports whether HOMEBREW_NO_ANALYTICS disables the analytics whatever
+// `brew analytics` says, as brew checks it first.
+func brewAnalyticsForcedOff() bool {
+	return os.Getenv("HOMEBREW_NO_ANALYTICS") != ""
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Homebrew's v3 API: a compact formula index signed as JWS, per bottle tag,
+// and one full formula JSON per name, fetched on demand.
+const (
+	formulaAPIv3URL       = "https://formulae.brew.sh/api/internal/formula.%s.jws.json"
+	formulaDetailsAPIURL  = "https://formulae.brew.sh/api/formula/%s.json"
+	cacheFileFormulaeV3   = "formula-v3.json"
+	formulaDetailsMinSize = 100
+)
+
+// formulaV3 is an entry of the v3 formula index. It only carries what the table needs:
+// the rest of the formula is fetched with FetchFormula when it is shown.
+//
+// The index generated by `brew generate-formula-api` stores each formula as a compact array of its
+// package version, bottle rebuild and bottle checksum for the tag (null without a bottle), e.g.
+//
+//	"wget": ["1.25.0_1", 0, "4d180cd4ead91a34e2c2672189fc366b87ae86e6caa3acbf4845b272f57c859a"]
+//
+// Earlier indexes stored objects with the fields below, which are still accepted.
+type formulaV3 struct {
+	Description       string          `json:"desc"`
+	License           string          `json:"license"`
+	Homepage          string          `json:"homepage"`
+	Version           string          `json:"version"`
+	StableVersion     string          `json:"stable_version"` // Older name of version
+	Revision          int             `json:"revision"`
+	Dependencies      []string        `json:"dependencies"`
+	BuildDependencies []string        `json:"build_dependencies"`
+	Bottle            json.RawMessage `json:"bottle"`
+	Deprecated        bool            `json:"deprecated"`
+	Disabled          bool            `json:"disabled"`
+}
+
+// UnmarshalJSON decodes an entry of either shape of the index, see formulaV3.
+func (f *formulaV3) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '[' {
+		type object formulaV3 // Without this method
+		return json.Unmarshal(data, (*object)(f))
+	}
+
+	var entry []json.RawMessage
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return err
+	}
+	if len(entry) < 3 {
+		return fmt.Errorf("unexpected v3 formula entry %s", data)
+	}
+	var pkgVersion string
+	if err := json.Unmarshal(entry[0], &pkgVersion); err != nil || pkgVersion == "" {
+		return fmt.Errorf("unexpected version in v3 formula entry %s", data)
+	}
+
+	// The package version carries the revision, if any: "1.25.0_1"
+	f.Version = pkgVersion
+	if i := strings.LastIndex(pkgVersion, "_"); i > 0 {
+		if revision, err := strconv.Atoi(pkgVersion[i+1:]); err == nil {
+			f.Version, f.Revision = pkgVersion[:i], revision
+		}
+	}
+	if string(entry[2]) != "null" {
+		f.Bottle = entry[2]
+	}
+	return nil
+}
+
+// formulaIndexV3 is the payload of the v3 formula index.
+type formulaIndexV3 struct {
+	Formulae map[string]formulaV3 `json:"formulae"`
+	Renames  map[string]string    `json:"renames"` // Old name → new name
+}
+
+// SetFormulaAPIv3 enables the v3 formula index, with the v2 formula.json as fallback.
+func (d *DataProvider) SetFormulaAPIv3(enabled bool) {
+	d.formulaAPIv3 = enabled
+}
+
+// getRemoteFormulaeV3 retrieves the formulae from the v3 index of the host bottle tag, optionally using cache.
+func (d *DataProvider) getRemoteFormulaeV3(forceRefresh bool) ([]models.Formula, error) {
+	if err := ensureCacheDir(); err != nil {
+		return nil, err
+	}
+
+	tag := GetPlatform().BottleTag()
+	if !forceRefresh {
+		if data := readCacheFile(cacheFileFormulaeV3, 1000); data != nil {
+			if formulae, err := parseFormulaIndexV3(data, tag); err == nil && len(formulae) > 0 {
+				d.track(cacheFileFormulae, data) // Same input as formula.json
+				return formulae, nil
+			}
+		}
+	}
+
+	body, err := fetchFromAPI(fmt.Sprintf(formulaAPIv3URL, tag))
+	if err != nil {
+		return nil, err
+	}
+
+	// The index is a JWS: the formulae are in its payload, as a JSON string
+	var signed struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &signed); err != nil {
+		return nil, err
+	}
+	if signed.Payload == "" {
+		return nil, errors.New("empty v3 formula index")
+	}
+
+	formulae, err := parseFormulaIndexV3([]byte(signed.Payload), tag)
+	if err != nil {
+		return nil, err
+	}
+	d.track(cacheFileFormulae, []byte(signed.Payload))
+	writeCacheFile(cacheFileFormulaeV3, []byte(signed.Payload))
+	return formulae, nil
+}
+
+// parseFormulaIndexV3 converts the v3 formula index of a bottle tag to partial formulae, sorted by name.
+// The index only lists the bottle of its tag, so that is the only bottle of the formulae.
+func parseFormulaIndexV3(data []byte, tag string) ([]models.Formula, error) {
+	var index formulaIndexV3
+	if err := decodeJSON(cacheFileFormulaeV3, data, &index); err != nil {
+		return nil, err
+	}
+
+	oldNames := make(map[string][]string)
+	for oldName, name := range index.Renames {
+		oldNames[name] = append(oldNames[name], oldName)
+	}
+
+	if len(index.Formulae) == 0 {
+		return nil, errors.New("no formulae in the v3 formula index")
+	}
+
+	formulae := make([]models.Formula, 0, len(index.Formulae))
+	for name, entry := range index.Formulae {
+		version := entry.Version
+		if version == "" {
+			version = entry.StableVersion
+		}
+		if version == "" { // Neither shape of entry: the format changed
+			return nil, fmt.Errorf("no version for %s in the v3 formula index", name)
+		}
+		var bottle models.Bottle
+		if len(entry.Bottle) > 0 && string(entry.Bottle) != "null" {
+			bottle.Stable.Files = map[string]models.BottleFile{tag: {}}
+		}
+		formulae = append(formulae, models.Formula{
+			Name:              name,
+			FullName:          name,
+			OldNames:          oldNames[name],
+			Tap:               "homebrew/core",
+			Description:       entry.Description,
+			License:           entry.License,
+			Homepage:          entry.Homepage,
+			Versions:          models.Versions{Stable: version, Bottle: bottle.Stable.Files != nil},
+			Revision:          entry.Revision,
+			Bottle:            bottle,
+			Dependencies:      entry.Dependencies,
+			BuildDependencies: entry.BuildDependencies,
+			Deprecated:        entry.Deprecated,
+			Disabled:          entry.Disabled,
+			Partial:           true,
+		})
+	}
+	i18n.SortByName(formulae, func(formula models.Formula) string { return formula.Name })
+	return formulae, nil
+}
+
+// FetchFormula fetches the full JSON of a single formula, at the given version.
+// Used to complete the partial formulae of the v3 index and the summaries when they are shown:
+// summaries are read back from formula.json first. The result is cached until the index reports a new version.
+func (d *DataProvider) FetchFormula(name, version string) (*models.Formula, error) {
+	var summarized models.Formula
+	if d.readDetails(cacheFileFormulae, name, &summarized) && summarized.Name == name && summarized.Versions.Stable == version {
+		return &summarized, nil
+	}
+
+	if err := ensureCacheDir(); err != nil {
+		return nil, err
+	}
+
+	cacheFile := "formula-info-" + url.PathEscape(name) + ".json"
+	if data := readCacheFile(cacheFile, formulaDetailsMinSize); data != nil {
+		var formula models.Formula
+		if err := json.Unmarshal(data, &formula); err == nil && formula.Name == name && formula.Versions.Stable == version {
+			return &formula, nil
+		}
+	}
+
+	body, err := fetchFromAPI(fmt.Sprintf(formulaDetailsAPIURL, url.PathEscape(name)))
+	if err != nil {
+		return nil, err
+	}
+
+	var formula models.Formula
+	if err := json.Unmarshal(body, &formula); err != nil {
+		return nil, err
+	}
+	if formula.Name != name {
+		return nil, fmt.Errorf("formula %s not found", name)
+	}
+	writeCacheFile(cacheFile, body)
+	return &formula, nil
+}
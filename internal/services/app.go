@@ -1,12 +1,15 @@
 package services
 
 import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
 	"bbrew/internal/models"
 	"bbrew/internal/ui"
 	"bbrew/internal/ui/theme"
 	"context"
 	"fmt"
-	"os"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -18,14 +21,36 @@ var (
 	AppVersion = "0.0.1"
 )
 
+// homebrewInstallCommand is the official Homebrew install command, shown in read-only mode.
+const homebrewInstallCommand = `/bin/bash -c "$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)"`
+
 type AppServiceInterface interface {
 	GetApp() *tview.Application
 	GetLayout() ui.LayoutInterface
 	Boot() (err error)
 	BuildApp()
 	SetBrewfilePath(path string)
+	SetStartupOptions(opts StartupOptions)
 	IsBrewfileMode() bool
-	GetBrewfilePackages() *[]models.Package
+	IsReadOnly() bool
+	GetBrewfilePackages() []models.Package
+	WriteSessionReport(path string) error
+}
+
+// StartupOptions pre-populates the UI state on launch (see the --query, --filter, --select and --only-group flags).
+type StartupOptions struct {
+	Query     string     // Initial search text
+	Filter    FilterType // Initial filter
+	Select    string     // Name of the package to focus
+	OnlyGroup string     // Brewfile section or tag to restrict Brewfile mode to
+	Bundle    bool       // Delegate Brewfile operations to brew bundle, as the bundle_mode setting
+	ReadOnly  bool       // Disable the actions changing packages, as the read_only setting
+	Brewfile  string     // Path or URL of the Brewfile given with -f, as reported by the webhook
+	Profile   string     // Name of the open profile (see Profile), empty without one
+	Profiles  bool       // Open the profile picker at startup
+
+	InstalledOnly bool // Load only the installed packages, skipping the API catalogs (--installed-only)
+	AllowIPC      bool // Serve the control socket (--allow-ipc, see ipc.go)
 }
 
 // AppService manages the application state, Homebrew integration, and UI components.
@@ -33,41 +58,98 @@ type AppService struct {
 	app    *tview.Application
 	theme  *theme.Theme
 	layout ui.LayoutInterface
+	config *Config
+	events *events.Bus
+
+	store          *PackageStore // Package lists, shared with background refreshes
+	activeFilter   FilterType
+	licenseFilter  string // License shown by FilterLicense
+	highlight      string // Search text highlighted in the table, set by setResults
+	brewMissing    bool   // Homebrew is not available: browse the API data, without actions
+	brewVersion    string
+	latestVersion  string // Newer Bold Brew release, if any
+	startupOptions StartupOptions
+	profileCleanup func()              // Removes the Brewfile of the open profile when downloaded, nil otherwise
+	sizeCache      map[string]int64    // Installed package sizes, reset on refresh (see installedSize)
+	sizeQueue      map[string]string   // Paths of the packages waiting to be measured, by name
+	sizeEpoch      int                 // Incremented when sizes are invalidated, to measure again those in progress
+	sizeColumn     int                 // Index of the size column in the table, -1 when hidden
+	watchChanges   int                 // Watched packages with a new version since last seen
+	session        *sessionLog         // Operations of this session, summarized on quit
+	marked         map[packageKey]bool // Packages marked with space, to act on at once (see marks.go)
+
+	// Brew operation running, described by its activity, "" when none is (see operation.go)
+	operationMu sync.Mutex
+	operation   string
 
-	packages         *[]models.Package
-	filteredPackages *[]models.Package
-	activeFilter     FilterType
-	brewVersion      string
+	// Icon of the selected cask, drawn in the details where the terminal supports images (see graphics.go)
+	icons     *iconRenderer
+	iconName  string            // Package whose icon is shown
+	iconCache map[string][]byte // Icons by package, nil for those without one
 
 	// Brewfile support
-	brewfilePath     string
-	brewfilePackages *[]models.Package
-	brewfileTaps     []string // Taps required by the Brewfile
+	brewfilePath    string
+	brewfileTaps    []string          // Taps required by the Brewfile
+	brewfileRenames map[string]string // Old name → new name of the renamed packages of the Brewfile
+
+	// Grouped view of the Brewfile sections (see groups.go)
+	brewfileGroups  []string        // Section headers, in Brewfile order
+	groupedView     bool            // Show packages under their section headers
+	collapsedGroups map[string]bool // Sections whose packages are hidden
+	tableRows       []tableRow      // What each table row shows, rebuilt by setResults
+
+	// View grouped by package type, outside Brewfile mode
+	typeView       bool                        // Show packages under their type headers
+	collapsedTypes map[models.PackageType]bool // Types whose packages are hidden
 
 	brewService       BrewServiceInterface
 	dataProvider      DataProviderInterface // Direct access for Brewfile operations
 	selfUpdateService SelfUpdateServiceInterface
+	changelogService  ChangelogServiceInterface
+	github            GitHubServiceInterface
+	advisories        AdvisoryServiceInterface
+	notes             NotesServiceInterface
+	favorites         FavoritesServiceInterface
+	watchlist         WatchlistServiceInterface
+	removed           RemovedServiceInterface
 	inputService      InputServiceInterface
+	terminal          TerminalServiceInterface
 }
 
 // NewAppService creates a new instance of AppService with initialized components.
 var NewAppService = func() AppServiceInterface {
+	// Load the user config and select the UI language before any component is built
+	config := LoadConfig()
+	i18n.Init(config.Language)
+
 	app := tview.NewApplication()
-	themeService := theme.NewTheme()
+	themeService := theme.NewTheme(theme.Options{
+		HighContrast: config.HighContrast, ASCII: config.ASCII,
+		RowSpacing: config.RowSpacing, ReducedMotion: config.ReducedMotion,
+	})
 	layout := ui.NewLayout(themeService)
 
 	s := &AppService{
 		app:    app,
 		theme:  themeService,
 		layout: layout,
+		config: config,
+		events: events.NewBus(),
 
-		packages:         new([]models.Package),
-		filteredPackages: new([]models.Package),
-		activeFilter:     FilterNone,
-		brewVersion:      "-",
+		store:        NewPackageStore(),
+		activeFilter: FilterNone,
+		brewVersion:  "-",
+		sizeCache:    make(map[string]int64),
+		sizeQueue:    make(map[string]string),
+		sizeColumn:   -1,
+		session:      newSessionLog(),
+		marked:       make(map[packageKey]bool),
+		icons:        &iconRenderer{protocol: detectGraphicsProtocol()},
+		iconCache:    make(map[string][]byte),
 
-		brewfilePath:     "",
-		brewfilePackages: new([]models.Package),
+		brewfilePath:    "",
+		collapsedGroups: make(map[string]bool),
+		collapsedTypes:  make(map[models.PackageType]bool),
 	}
 
 	// Initialize services
@@ -75,37 +157,67 @@ var NewAppService = func() AppServiceInterface {
 	s.brewService = NewBrewService()
 	s.inputService = NewInputService(s, s.brewService)
 	s.selfUpdateService = NewSelfUpdateService()
+	s.changelogService = NewChangelogService()
+	s.github = NewGitHubService()
+	s.advisories = NewAdvisoryService()
+	s.notes = NewNotesService()
+	s.favorites = NewFavoritesService()
+	s.watchlist = NewWatchlistService()
+	s.removed = NewRemovedService()
+	s.terminal = NewTerminalService(app)
+	s.terminal.SetCompletionAlert(config.CompletionAlert)
+	s.terminal.SetReducedMotion(config.ReducedMotion)
+
+	// The spinner and the expiry of notifications change the status line outside of the event loop
+	layout.GetNotifier().SetRedraw(func() { app.QueueUpdateDraw(func() {}) })
+	if config.NotificationExpiry != 0 {
+		layout.GetNotifier().SetSuccessExpiry(time.Duration(max(config.NotificationExpiry, 0)) * time.Second)
+	}
 
 	return s
 }
 
-func (s *AppService) GetApp() *tview.Application             { return s.app }
-func (s *AppService) GetLayout() ui.LayoutInterface          { return s.layout }
-func (s *AppService) SetBrewfilePath(path string)            { s.brewfilePath = path }
-func (s *AppService) IsBrewfileMode() bool                   { return s.brewfilePath != "" }
-func (s *AppService) GetBrewfilePackages() *[]models.Package { return s.brewfilePackages }
+func (s *AppService) GetApp() *tview.Application            { return s.app }
+func (s *AppService) GetLayout() ui.LayoutInterface         { return s.layout }
+func (s *AppService) SetBrewfilePath(path string)           { s.brewfilePath = path }
+func (s *AppService) SetStartupOptions(opts StartupOptions) { s.startupOptions = opts }
+func (s *AppService) IsBrewfileMode() bool                  { return s.brewfilePath != "" }
+func (s *AppService) GetBrewfilePackages() []models.Package { return s.store.Brewfile() }
 
 // Boot initializes the application by setting up Homebrew and loading formulae data.
 func (s *AppService) Boot() (err error) {
 	if s.brewVersion, err = s.brewService.GetBrewVersion(); err != nil {
-		// This error is critical, as we need Homebrew to function
-		return fmt.Errorf("failed to get Homebrew version: %v", err)
+		// Without Homebrew, fall back to browsing the formulae.brew.sh API data
+		s.brewMissing = true
+		s.brewVersion = i18n.T("Homebrew not installed")
+		s.dataProvider.SetReadOnly(true)
+	}
+	s.dataProvider.SetFormulaAPIv3(s.config.FormulaAPIv3)
+	if s.startupOptions.InstalledOnly {
+		if s.brewMissing {
+			return fmt.Errorf("--installed-only requires Homebrew")
+		}
+		s.dataProvider.SetInstalledOnly(true)
 	}
 
 	// Load Homebrew data from cache for fast startup
-	// Installation status might be stale but will be refreshed in background by updateHomeBrew()
+	// Installation status might be stale but will be refreshed in background by forceRefreshResults()
 	if err = s.dataProvider.SetupData(false); err != nil {
 		// Log error but don't fail - app can work with empty/partial data
-		fmt.Fprintf(os.Stderr, "Warning: failed to load Homebrew data (will retry in background): %v\n", err)
+		appLog.Warn("failed to load Homebrew data, will retry in background", "err", err)
 	}
 
-	// Initialize packages and filteredPackages
-	s.packages = s.dataProvider.GetPackages()
-	*s.filteredPackages = *s.packages
+	// Initialize packages and filtered packages
+	packages := s.dataProvider.GetPackages()
+	s.store.SetAll(*packages)
+	s.store.SetFiltered(*packages)
 
 	// If Brewfile is specified, parse it and filter packages
 	if s.IsBrewfileMode() {
-		if err = s.loadBrewfilePackages(); err != nil {
+		endStage := startup.begin("load Brewfile")
+		err = s.loadBrewfilePackages()
+		endStage()
+		if err != nil {
 			return fmt.Errorf("failed to load Brewfile: %v", err)
 		}
 	}
@@ -113,66 +225,389 @@ func (s *AppService) Boot() (err error) {
 	return nil
 }
 
-// updateHomeBrew updates the Homebrew formulae and refreshes the results in the UI.
-func (s *AppService) updateHomeBrew() {
-	s.app.QueueUpdateDraw(func() {
-		s.layout.GetNotifier().ShowWarning("Updating Homebrew formulae...")
-	})
-	if err := s.brewService.UpdateHomebrew(); err != nil {
+// updateHeader refreshes the header with the app and Homebrew versions,
+// including the new version badge when an update is available.
+func (s *AppService) updateHeader() {
+	headerName := AppName
+	if s.IsBrewfileMode() {
+		headerName = fmt.Sprintf("%s [Brewfile Mode]", AppName)
+		if s.startupOptions.OnlyGroup != "" {
+			headerName = fmt.Sprintf("%s [Brewfile Mode: %s]", AppName, s.startupOptions.OnlyGroup)
+		}
+		if s.startupOptions.Profile != "" {
+			headerName = fmt.Sprintf("%s [Profile: %s]", AppName, s.startupOptions.Profile)
+		}
+		if s.IsBundleMode() {
+			headerName += " [brew bundle]"
+		}
+	}
+	if s.startupOptions.InstalledOnly {
+		headerName += " " + i18n.T("[Installed only]")
+	}
+	if s.IsReadOnly() {
+		headerName += " " + i18n.T("[Read-only]")
+	} else if s.config.ExpertMode {
+		headerName += " " + i18n.T("[Expert]")
+	}
+	if s.inputService.IsRecordingMacro() {
+		headerName += " " + i18n.T("[Recording macro]")
+	}
+	if s.watchChanges > 0 {
+		headerName += fmt.Sprintf(" [%s]%s[-]", theme.ColorTag(s.theme.OutdatedColor),
+			tview.Escape(i18n.T("[%d watched updates - press V]", s.watchChanges)))
+	}
+
+	version := AppVersion
+	if s.latestVersion != "" {
+		version = fmt.Sprintf("%s ([%s]%s[-])", AppVersion, theme.ColorTag(s.theme.OutdatedColor),
+			i18n.T("New Version Available: %s - press n", s.latestVersion))
+	}
+	s.layout.GetHeader().Update(headerName, version, s.brewVersion)
+}
+
+// showReadOnlyBanner explains in the output panel why actions are disabled and how to install Homebrew.
+func (s *AppService) showReadOnlyBanner() {
+	if !s.brewMissing {
+		fmt.Fprintf(s.layout.GetOutput().View(), "[%s::b]%s[-:-:-]\n\n%s\n",
+			theme.ColorTag(s.theme.WarningColor), i18n.T("Read-only mode"),
+			i18n.T("Packages can be browsed but not installed, updated or removed, and taps are not added."))
+		return
+	}
+	fmt.Fprintf(s.layout.GetOutput().View(), "[%s::b]%s[-:-:-]\n\n%s\n\n  %s\n\n%s\n",
+		theme.ColorTag(s.theme.WarningColor), i18n.T("Read-only mode"),
+		i18n.T("Homebrew was not found, so packages can be browsed but not installed, updated or removed. To install Homebrew, run:"),
+		tview.Escape(homebrewInstallCommand),
+		i18n.T("Then restart Bold Brew. See https://brew.sh for details."))
+}
+
+// IsReadOnly returns true if the actions changing packages are disabled: Homebrew is not
+// available, or read-only mode was requested with --read-only or the read_only setting.
+func (s *AppService) IsReadOnly() bool {
+	return s.brewMissing || s.config.ReadOnly || s.startupOptions.ReadOnly
+}
+
+// IsBundleMode returns true if Brewfile operations are delegated to `brew bundle`.
+func (s *AppService) IsBundleMode() bool {
+	return s.IsBrewfileMode() && (s.config.BundleMode || s.startupOptions.Bundle)
+}
+
+// isFormulaInstalled returns true if the formula of the given name is installed.
+func (s *AppService) isFormulaInstalled(name string) bool {
+	pkg, exists := s.store.Lookup(name, models.PackageTypeFormula)
+	return exists && pkg.LocallyInstalled
+}
+
+// IsExpertMode returns true if single-package operations run without confirmation.
+func (s *AppService) IsExpertMode() bool {
+	return s.config.ExpertMode
+}
+
+// SetExpertMode toggles the confirmation of single-package operations and persists the choice.
+func (s *AppService) SetExpertMode(enabled bool) error {
+	s.config.ExpertMode = enabled
+	s.updateHeader()
+	return s.config.Save()
+}
+
+// SetUpgradePolicy sets what `bbrew upgrade` does with a package and persists the choice.
+func (s *AppService) SetUpgradePolicy(name string, policy UpgradePolicy) error {
+	if policy == PolicyAsk {
+		delete(s.config.UpgradePolicies, name) // The default
+	} else {
+		if s.config.UpgradePolicies == nil {
+			s.config.UpgradePolicies = make(map[string]UpgradePolicy)
+		}
+		s.config.UpgradePolicies[name] = policy
+	}
+	return s.config.Save()
+}
+
+// SetProtected protects a package from removal, or lifts the protection, and persists the choice.
+func (s *AppService) SetProtected(name string, protected bool) error {
+	s.config.Protected = slices.DeleteFunc(s.config.Protected, func(protected string) bool { return protected == name })
+	if protected {
+		s.config.Protected = append(s.config.Protected, name)
+		i18n.SortByName(s.config.Protected, func(name string) string { return name })
+	}
+	return s.config.Save()
+}
+
+// fetchGitHubMetadata fetches the GitHub metadata of the package at the given row in the background,
+// if enabled and not cached yet, and refreshes the details if the row is still selected.
+func (s *AppService) fetchGitHubMetadata(row int) {
+	pkg, exists := s.packageAtRow(row)
+	if !exists || !s.config.GitHubMetadata || s.github.Lookup(pkg) != nil {
+		return
+	}
+
+	go func() {
+		defer RecoverCrash()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if _, err := s.github.Fetch(ctx, pkg); err != nil {
+			return
+		}
 		s.app.QueueUpdateDraw(func() {
-			s.layout.GetNotifier().ShowError("Could not update Homebrew formulae")
+			selected, _ := s.layout.GetTable().View().GetSelection()
+			if current, exists := s.packageAtRow(row); exists && selected == row && current.Name == pkg.Name {
+				s.layout.GetDetails().SetContent(&current)
+			}
 		})
+	}()
+}
+
+// fetchIcon shows the icon of the cask at the given row, loading it in the background if not cached yet.
+// Nothing is loaded when the terminal can't draw images.
+func (s *AppService) fetchIcon(row int) {
+	pkg, exists := s.packageAtRow(row)
+	if !exists || pkg.Type != models.PackageTypeCask || s.icons.protocol == graphicsNone {
+		s.iconName = ""
+		return
+	}
+	s.iconName = pkg.Name
+	if _, cached := s.iconCache[pkg.Name]; cached {
 		return
 	}
-	// Clear loading message and update results
-	s.app.QueueUpdateDraw(func() {
-		s.layout.GetNotifier().ShowSuccess("Homebrew formulae updated successfully")
+
+	go func() {
+		defer RecoverCrash()
+		data := caskIcon(pkg)
+		s.app.QueueUpdateDraw(func() {
+			s.iconCache[pkg.Name] = data // Redrawn with the icon if still selected
+		})
+	}()
+}
+
+// drawIcons draws the icon of the selected cask in the details after each draw, while the main view is shown:
+// overlays and modals take the focus, and hide the icon.
+func (s *AppService) drawIcons() {
+	afterDraw := s.app.GetAfterDrawFunc()
+	s.app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		if afterDraw != nil {
+			afterDraw(screen)
+		}
+		var data []byte
+		mainView := s.layout.GetTable().View().HasFocus() || s.layout.GetSearch().Field().HasFocus()
+		if mainView && s.iconName != "" {
+			data = s.iconCache[s.iconName]
+		}
+		x, y, width, height, ok := s.layout.GetDetails().IconArea()
+		if !ok {
+			data = nil
+		}
+		s.icons.render(screen, s.iconName, data, x, y, width, height)
 	})
-	s.forceRefreshResults()
+}
+
+// fullPackage returns the package with its full formula or cask, fetching them now if it only has partial data.
+// The package is returned as is if they can't be fetched.
+func (s *AppService) fullPackage(pkg models.Package) models.Package {
+	switch {
+	case pkg.Formula != nil && pkg.Formula.Partial:
+		if formula, err := s.dataProvider.FetchFormula(pkg.Name, pkg.Formula.Versions.Stable); err == nil {
+			pkg.Formula = formula
+		}
+	case pkg.Cask != nil && pkg.Cask.Partial:
+		if cask, err := s.dataProvider.FetchCask(pkg.Name, pkg.Cask.Version); err == nil {
+			pkg.Cask = cask
+		}
+	}
+	return pkg
+}
+
+// completePackage fetches the full formula or cask of the package at the given row in the background
+// when it only has partial data (v3 index or summary), and refreshes the details if the row is still selected.
+func (s *AppService) completePackage(row int) {
+	pkg, exists := s.packageAtRow(row)
+	if !exists || !pkg.IsPartial() {
+		return
+	}
+
+	go func() {
+		defer RecoverCrash()
+		full := s.fullPackage(pkg)
+		if full.IsPartial() {
+			return
+		}
+		s.store.UpdateLists(func(p *models.Package) {
+			if p.Name != pkg.Name || p.Type != pkg.Type || !p.IsPartial() {
+				return
+			}
+			if full.Formula != nil && p.Formula != nil {
+				full.Formula.Analytics90dRank, full.Formula.Analytics90dDownloads = p.Formula.Analytics90dRank, p.Formula.Analytics90dDownloads
+				p.Formula = full.Formula
+			}
+			if full.Cask != nil && p.Cask != nil {
+				full.Cask.Analytics90dRank, full.Cask.Analytics90dDownloads = p.Cask.Analytics90dRank, p.Cask.Analytics90dDownloads
+				p.Cask = full.Cask
+			}
+		})
+		s.app.QueueUpdateDraw(func() {
+			selected, _ := s.layout.GetTable().View().GetSelection()
+			if current, exists := s.packageAtRow(row); exists && selected == row && current.Name == pkg.Name {
+				s.layout.GetDetails().SetContent(&current)
+			}
+		})
+	}()
+}
+
+// checkAdvisories looks up known vulnerabilities of the installed formulae on OSV.dev,
+// and warns if any is affected.
+func (s *AppService) checkAdvisories() {
+	if s.config.DisableSecurityCheck {
+		return
+	}
+
+	installed := make([]models.Package, 0)
+	for _, pkg := range s.store.All() {
+		if pkg.LocallyInstalled {
+			installed = append(installed, pkg)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	if err := s.advisories.Check(ctx, installed); err != nil {
+		return
+	}
+
+	vulnerable := 0
+	for _, pkg := range installed {
+		if len(s.advisories.Lookup(pkg)) > 0 {
+			vulnerable++
+		}
+	}
+
+	s.events.Publish(events.Event{Type: events.PackagesUpdated})
+	if vulnerable > 0 {
+		s.layout.GetNotifier().ShowWarning(i18n.T("%d installed packages have known vulnerabilities (press x)", vulnerable))
+	}
+}
+
+// prefetchGitHubMetadata fetches the GitHub metadata of the installed packages in the background,
+// so that the "Maintained" filter and the Stars column work without browsing each package first.
+func (s *AppService) prefetchGitHubMetadata() {
+	if !s.config.GitHubMetadata {
+		return
+	}
+
+	installed := make([]models.Package, 0)
+	for _, pkg := range s.store.All() {
+		if pkg.LocallyInstalled {
+			installed = append(installed, pkg)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	s.github.Prefetch(ctx, installed)
+	s.events.Publish(events.Event{Type: events.PackagesUpdated})
+}
+
+// updateHomeBrew runs `brew update` on demand, streaming its output, then refreshes the results
+// through the OperationCompleted event. Other brew commands never auto-update (see brewCommand).
+// It runs in the background, within an operation begun by the caller (see beginOperation).
+func (s *AppService) updateHomeBrew() {
+	s.layout.GetNotifier().ShowProgress(i18n.T("Updating Homebrew formulae..."))
+
+	err := s.brewService.UpdateHomebrew(s.app, s.layout.GetOutput().View())
+	if err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Could not update Homebrew formulae"))
+		s.terminal.Done(i18n.T("Could not update Homebrew formulae"))
+	} else {
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Homebrew formulae updated successfully"))
+		s.terminal.Done(i18n.T("Homebrew formulae updated successfully"))
+	}
+	s.events.Publish(events.Event{Type: events.OperationCompleted, Operation: events.OperationBrewUpdate, Err: err})
 }
 
 // BuildApp builds the application layout, sets up event handlers, and initializes the UI components.
 func (s *AppService) BuildApp() {
+	watchCrashes(s.terminal, s.brewVersion)
+	if err := startAskpass(s.inputService.PromptPassword); err != nil {
+		appLog.Warn("password prompt unavailable, installers asking for sudo will fail", "err", err)
+	}
+	if s.startupOptions.AllowIPC {
+		if err := startIPC(s); err != nil {
+			appLog.Warn("control socket unavailable", "err", err)
+		}
+	}
+
 	// Build the layout
 	s.layout.Setup()
+	platform := GetPlatform()
+	s.layout.GetDetails().SetBottleTags(platform.BottleTag(), platform.ArchLabel(), platform.BottleTags())
+	s.layout.GetDetails().SetMacOSVersion(platform.MacOSVersion)
+	s.layout.GetDetails().SetGitHubLookup(func(pkg *models.Package) *models.GitHubMetadata {
+		return s.github.Lookup(*pkg)
+	})
+	s.layout.GetDetails().SetAdvisoryLookup(func(pkg *models.Package) []models.Vulnerability {
+		return s.advisories.Lookup(*pkg)
+	})
+	s.layout.GetDetails().SetNoteLookup(func(pkg *models.Package) string {
+		return s.notes.Lookup(*pkg)
+	})
+	s.layout.GetDetails().SetPolicyLookup(func(pkg *models.Package) string {
+		return i18n.T(upgradePolicyLabels[s.config.UpgradePolicyOf(pkg.Name)])
+	})
+	s.layout.GetDetails().SetProtectedLookup(func(pkg *models.Package) bool {
+		return s.config.IsProtected(pkg.Name)
+	})
+	s.layout.GetDetails().SetHeldLookup(func(pkg *models.Package) bool {
+		return s.isHeld(*pkg)
+	})
+	s.layout.GetDetails().SetAlternativeLookup(func(pkg *models.Package) string {
+		if alternative, exists := linuxAlternativeOf(*pkg); exists {
+			return alternative.String()
+		}
+		return ""
+	})
+	s.layout.GetDetails().SetInstalledLookup(s.isFormulaInstalled)
 
-	// Update header and enable Brewfile mode features if needed
-	headerName := AppName
+	// Enable Brewfile mode features if needed
 	if s.IsBrewfileMode() {
-		headerName = fmt.Sprintf("%s [Brewfile Mode]", AppName)
-		s.layout.GetSearch().Field().SetLabel("Search (Brewfile): ")
+		s.layout.GetSearch().Field().SetLabel(i18n.T("Search (Brewfile): "))
 		s.inputService.EnableBrewfileMode() // Add Install All action
 	}
-	s.layout.GetHeader().Update(headerName, AppVersion, s.brewVersion)
+	if s.IsReadOnly() {
+		s.inputService.EnableReadOnlyMode() // Remove the actions that change packages
+		s.showReadOnlyBanner()
+	}
+	s.updateHeader()
 
 	// Evaluate if there is a new version available
 	// This is done in a goroutine to avoid blocking the UI during startup
-	// In the future, this could be replaced with a more sophisticated update check, and update
-	// the user if a new version is available instantly instead of waiting for the next app start
+	// The check is throttled by the SelfUpdateService, so most starts don't hit the tap at all
 	go func() {
+		defer RecoverCrash()
 		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 		defer cancel()
 
 		if latestVersion, err := s.selfUpdateService.CheckForUpdates(ctx); err == nil && latestVersion != AppVersion {
 			s.app.QueueUpdateDraw(func() {
-				AppVersion = fmt.Sprintf("%s ([orange]New Version Available: %s[-])", AppVersion, latestVersion)
-				headerName := AppName
-				if s.IsBrewfileMode() {
-					headerName = fmt.Sprintf("%s [Brewfile Mode]", AppName)
-				}
-				s.layout.GetHeader().Update(headerName, AppVersion, s.brewVersion)
+				s.latestVersion = latestVersion
+				s.updateHeader()
 			})
 		}
 	}()
 
 	// Table handler to update the details view when a table row is selected
 	tableSelectionChangedFunc := func(row, _ int) {
-		if row > 0 && row-1 < len(*s.filteredPackages) {
-			s.layout.GetDetails().SetContent(&(*s.filteredPackages)[row-1])
+		if pkg, exists := s.packageAtRow(row); exists {
+			s.layout.GetDetails().SetContent(&pkg)
+			s.fetchGitHubMetadata(row)
+			s.completePackage(row)
+			s.fetchIcon(row)
 		}
 	}
 	s.layout.GetTable().View().SetSelectionChangedFunc(tableSelectionChangedFunc)
 
+	// Enter on a section header of the grouped Brewfile view collapses or expands it
+	s.layout.GetTable().View().SetSelectedFunc(func(row, _ int) {
+		s.toggleGroupAt(row)
+	})
+
 	// Search input handlers
 	inputDoneFunc := func(key tcell.Key) {
 		if key == tcell.KeyEnter || key == tcell.KeyEscape {
@@ -187,25 +622,125 @@ func (s *AppService) BuildApp() {
 	// Add key event handler
 	s.app.SetInputCapture(s.inputService.HandleKeyEventInput)
 
+	s.subscribeEvents()
+
 	// Set the root of the application to the layout's root and focus on the table view
 	s.app.SetRoot(s.layout.Root(), true)
 	s.app.SetFocus(s.layout.GetTable().View())
+	s.profileFirstDraw()
+	s.drawIcons()
 
-	// Start background tasks: install taps first (if Brewfile mode), then update Homebrew
+	// Start background tasks: install taps first (if Brewfile mode), then update Homebrew.
+	// Installing the taps is an operation, begun before the event loop runs: the brew keys wait for it.
+	installTaps := !s.brewMissing && s.IsBrewfileMode() && len(s.brewfileTaps) > 0 && !s.IsReadOnly() &&
+		s.beginOperation(i18n.T("installing taps…"))
 	go func() {
+		defer RecoverCrash()
+		if s.brewMissing {
+			return // Nothing to install, update or check without Homebrew
+		}
+
 		// In Brewfile mode, install missing taps first
-		if s.IsBrewfileMode() && len(s.brewfileTaps) > 0 {
-			s.installBrewfileTapsAtStartup()
+		if installTaps {
+			s.installMissingTaps()
+			s.endOperation()
 		}
-		// Then update Homebrew (which will reload all data including new taps)
-		s.updateHomeBrew()
+		// Then refresh the data from cache to the current state (including new taps).
+		// Homebrew itself is only updated on demand, see updateHomeBrew.
+		s.forceRefreshResults()
+		s.session.setBaseline(s.installedVersions()) // The session summary compares with the refreshed state
+		// Finally check the installed packages for advisories and enrich them with GitHub metadata
+		s.checkAdvisories()
+		s.prefetchGitHubMetadata()
 	}()
 
 	// Set initial results based on mode
 	if s.IsBrewfileMode() {
-		*s.filteredPackages = *s.brewfilePackages // Sync filteredPackages
-		s.setResults(s.brewfilePackages, true)    // Show only Brewfile packages
+		s.store.SetFiltered(s.store.Brewfile()) // Show only Brewfile packages
 	} else {
-		s.setResults(s.packages, true) // Show all packages
+		s.store.SetFiltered(s.store.All()) // Show all packages
+	}
+	s.setResults(s.store.Filtered(), true)
+
+	s.applyStartupOptions()
+	s.notifyBrewfileRenames()
+	s.checkWatchlist() // New versions from the cached data, before the background refresh
+	s.startStatusTicker()
+}
+
+// profileFirstDraw times the first draw for --profile-startup, which ends the startup.
+func (s *AppService) profileFirstDraw() {
+	endStage := startup.begin("first draw")
+	afterDraw := s.app.GetAfterDrawFunc() // Chained: the terminal service captures the screen there
+	s.app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		if afterDraw != nil {
+			afterDraw(screen)
+		}
+		endStage()
+		startup.finish()
+	})
+}
+
+// subscribeEvents wires the UI to the data change events.
+func (s *AppService) subscribeEvents() {
+	// Package data is refreshed in background goroutines, so redraw through the event loop
+	s.events.Subscribe(events.PackagesUpdated, func(_ events.Event) {
+		s.refreshHeaderStats() // Installed and outdated counts
+		s.app.QueueUpdateDraw(func() {
+			s.invalidateSize("") // Installed sizes may have changed
+			s.search(s.layout.GetSearch().Field().GetText(), false)
+			s.checkWatchlist()
+		})
+	})
+
+	// Reload the package data after a successful operation, to pick up the new installed state
+	s.events.Subscribe(events.OperationCompleted, func(event events.Event) {
+		s.session.recordOperation(event)
+		if event.Package != nil {
+			name := event.Package.Name
+			s.app.QueueUpdate(func() { s.invalidateSize(name) }) // Upgraded or removed: measured again
+		}
+		if event.Err == nil {
+			s.forceRefreshResults()
+		}
+	})
+
+	// Batch steps are reported from the batch goroutine
+	s.events.Subscribe(events.BatchProgress, func(event events.Event) {
+		progress := *event.Progress
+		s.session.recordBatchStep(progress)
+		s.terminal.SetProgress(progress.Index, progress.Total)
+		s.app.QueueUpdateDraw(func() {
+			s.layout.GetBatchProgress().Update(progress)
+		})
+	})
+
+	// Filters are changed from key handlers, which already run in the event loop
+	s.events.Subscribe(events.FilterChanged, func(_ events.Event) {
+		s.inputService.UpdateFilterUI()
+		s.search(s.layout.GetSearch().Field().GetText(), true)
+	})
+}
+
+// applyStartupOptions applies the initial filter, search text and package selection from the command line.
+func (s *AppService) applyStartupOptions() {
+	opts := s.startupOptions
+	if opts.Filter != FilterNone {
+		s.activeFilter = opts.Filter
+		s.events.Publish(events.Event{Type: events.FilterChanged})
+	}
+	if opts.Query != "" {
+		s.layout.GetSearch().Field().SetText(opts.Query) // Triggers the search through the changed handler
+	}
+	if opts.Profiles {
+		s.inputService.ShowProfilePicker()
+	}
+	if opts.Select == "" {
+		return
+	}
+
+	if s.selectPackage(opts.Select) {
+		return
 	}
+	s.layout.GetNotifier().ShowWarning(i18n.T("Package %s not found", opts.Select))
 }
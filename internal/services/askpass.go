@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Cask installers may run sudo, which would read the password from the terminal owned by the TUI.
+// Brew commands run without a controlling terminal instead (see brewCommandContext), with SUDO_ASKPASS
+// pointing to a script running `bbrew askpass`: Homebrew then passes -A to sudo, which asks the TUI for
+// the password through a Unix socket of a private directory.
+
+// askpass is the running password server, used by the brew commands.
+var askpass struct {
+	mu     sync.Mutex
+	script string // SUDO_ASKPASS program, "" when the server is not running
+	stop   func()
+
+	prompting sync.Mutex // One prompt at a time, e.g. for casks installed in parallel
+}
+
+// startAskpass serves the password requests of sudo, answered by prompt, which blocks until the user answers
+// (false when canceled). The server runs until StopAskpass.
+func startAskpass(prompt func(prompt string) (string, bool)) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "bbrew-askpass-") // 0700: only this user can connect
+	if err != nil {
+		return err
+	}
+
+	socket := filepath.Join(dir, "askpass.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	script := filepath.Join(dir, "askpass")
+	content := fmt.Sprintf("#!/bin/sh\nexec %s askpass %s \"$@\"\n", shellQuote(executable), shellQuote(socket))
+	if err := os.WriteFile(script, []byte(content), 0700); err != nil { // #nosec G306 -- the script must be executable
+		_ = listener.Close()
+		_ = os.RemoveAll(dir)
+		return err
+	}
+
+	go func() {
+		defer RecoverCrash()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // Closed
+			}
+			go func() {
+				defer RecoverCrash()
+				serveAskpass(conn, prompt)
+			}()
+		}
+	}()
+
+	askpass.mu.Lock()
+	defer askpass.mu.Unlock()
+	askpass.script = script
+	askpass.stop = func() {
+		_ = listener.Close()
+		_ = os.RemoveAll(dir)
+	}
+	return nil
+}
+
+// StopAskpass stops the password server and removes its files, if it is running.
+func StopAskpass() {
+	askpass.mu.Lock()
+	defer askpass.mu.Unlock()
+	if askpass.stop != nil {
+		askpass.stop()
+	}
+	askpass.script, askpass.stop = "", nil
+}
+
+// serveAskpass answers a password request: the prompt of sudo is a line, answered by the password line,
+// or by nothing when canceled.
+func serveAskpass(conn net.Conn, prompt func(prompt string) (string, bool)) {
+	defer conn.Close()
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+	askpass.prompting.Lock()
+	defer askpass.prompting.Unlock()
+	if password, ok := prompt(strings.TrimSpace(line)); ok {
+		_, _ = io.WriteString(conn, password+"\n")
+	}
+}
+
+// passwordMarkers are the messages of sudo failing because it couldn't ask for the password,
+// matched case-insensitively against the command output.
+var passwordMarkers = []string{
+	"a terminal is required to read the password", // sudo: a terminal is required to read the password; ...
+	"no tty present and no askpass program",       // Older sudo versions
+	"a password is required",                      // sudo: a password is required
+	"no password was provided",                    // The askpass program failed
+	"no password given",                           // Password prompt canceled, see RunAskpass
+}
+
+// isPasswordRequired reports whether a brew command failed because an installer needed the password of the user.
+func isPasswordRequired(err error) bool {
+	output := strings.ToLower(failureOutput(err))
+	for _, marker := range passwordMarkers {
+		if strings.Contains(output, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// askpassEnv returns the environment making sudo ask the TUI for passwords, nil when the server is not running.
+func askpassEnv() []string {
+	askpass.mu.Lock()
+	defer askpass.mu.Unlock()
+	if askpass.script == "" {
+		return nil
+	}
+	return []string{"SUDO_ASKPASS=" + askpass.script}
+}
+
+// RunAskpass is the `bbrew askpass <socket> [prompt]` command run by sudo: it forwards the prompt
+// to the TUI and prints the password. It fails when the password is not given, which makes sudo fail.
+func RunAskpass(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: bbrew askpass <socket> [prompt]")
+	}
+	prompt := strings.Join(args[1:], " ")
+	if prompt == "" {
+		prompt = "Password:"
+	}
+
+	conn, err := net.Dial("unix", args[0])
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := io.WriteString(conn, strings.ReplaceAll(prompt, "\n", " ")+"\n"); err != nil {
+		return err
+	}
+
+	password, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("no password given")
+	}
+	_, err = io.WriteString(os.Stdout, password)
+	return err
+}
+
+// shellQuote quotes a string for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"sync"
+)
+
+// batchOperation defines the configuration for a batch package operation.
+type batchOperation struct {
+	actionVerb    string // "Installing" or "Removing"
+	skipCondition func(pkg models.Package) bool
+	skipReason    string
+	execute       func(pkg models.Package) error
+	protected     func(pkg models.Package) bool // Packages left out although they match (removals), optional
+	group         string                        // Brewfile section or tag to limit the operation to, empty for the whole Brewfile
+	lock          bool                          // Records the installed versions in the Brewfile lock afterwards (installs)
+
+	// Packages that may run alongside each other (e.g. casks), at most workers at a time.
+	// The others run first, one by one. Nil or fewer than 2 workers runs everything in order.
+	concurrent func(pkg models.Package) bool
+	workers    int
+}
+
+// skip reports whether the operation leaves a package out, and why.
+func (op batchOperation) skip(pkg models.Package) (bool, string) {
+	if op.skipCondition(pkg) {
+		return true, op.skipReason
+	}
+	if op.protected != nil && op.protected(pkg) {
+		return true, i18n.T("protected")
+	}
+	return false, ""
+}
+
+// batchResult is the outcome of a batch operation.
+type batchResult struct {
+	succeeded []models.Package // Kept in order, for the webhook report
+	skipped   int
+	failed    []models.Package // Kept in order, to retry them
+}
+
+// runBatch applies a batch operation to the packages in order, reporting each step.
+// Concurrent packages run last, in parallel, so report may be called from several goroutines.
+// It has no UI dependencies: the progress is only surfaced through report.
+func runBatch(packages []models.Package, op batchOperation, report func(progress events.Progress)) batchResult {
+	var (
+		result batchResult
+		mu     sync.Mutex
+	)
+	total := len(packages)
+	failed, done := make([]bool, total), make([]bool, total)
+
+	step := func(i int) {
+		pkg := packages[i]
+		progress := events.Progress{Index: i, Total: total, Package: pkg.Name}
+
+		if skip, reason := op.skip(pkg); skip {
+			mu.Lock()
+			result.skipped++
+			mu.Unlock()
+			progress.Phase, progress.Reason = events.PhaseSkipped, reason
+			report(progress)
+			return
+		}
+
+		progress.Phase = events.PhaseRunning
+		report(progress)
+
+		err := op.execute(pkg)
+		mu.Lock()
+		if err != nil {
+			failed[i] = true
+			progress.Phase, progress.Err = events.PhaseFailed, err
+		} else {
+			done[i] = true
+			progress.Phase = events.PhaseDone
+		}
+		mu.Unlock()
+		report(progress)
+	}
+
+	var concurrent []int
+	for i, pkg := range packages {
+		if op.workers > 1 && op.concurrent != nil && op.concurrent(pkg) {
+			concurrent = append(concurrent, i)
+			continue
+		}
+		step(i)
+	}
+	runBounded(len(concurrent), op.workers, func(j int) { step(concurrent[j]) })
+
+	// Keep the failed packages in order, to retry them
+	for i, pkg := range packages {
+		if failed[i] {
+			result.failed = append(result.failed, pkg)
+		} else if done[i] {
+			result.succeeded = append(result.succeeded, pkg)
+		}
+	}
+	return result
+}
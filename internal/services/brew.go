@@ -2,11 +2,16 @@ package services
 
 import (
 	"bbrew/internal/models"
+	"bytes"
+	"context"
 	"fmt"
-	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/rivo/tview"
 )
@@ -19,15 +24,25 @@ type BrewServiceInterface interface {
 	GetBrewVersion() (string, error)
 
 	// Package operations
-	UpdateHomebrew() error
+	UpdateHomebrew(app *tview.Application, outputView *tview.TextView) error
 	UpdateAllPackages(app *tview.Application, outputView *tview.TextView) error
+	UpgradePackages(packages []models.Package, app *tview.Application, outputView *tview.TextView) error
 	UpdatePackage(info models.Package, app *tview.Application, outputView *tview.TextView) error
 	RemovePackage(info models.Package, app *tview.Application, outputView *tview.TextView) error
-	InstallPackage(info models.Package, app *tview.Application, outputView *tview.TextView) error
+	InstallPackage(info models.Package, noQuarantine bool, app *tview.Application, outputView *tview.TextView, options ...string) error
+	InstallPackageTagged(info models.Package, noQuarantine bool, app *tview.Application, outputView *tview.TextView) error
+	AdoptCask(info models.Package, app *tview.Application, outputView *tview.TextView) error
+	Cleanup(name string, app *tview.Application, outputView *tview.TextView) error
+	RunCommands(cmds []*exec.Cmd, app *tview.Application, outputView *tview.TextView) error
+
+	// Brewfile operations delegated to brew bundle
+	BundleInstall(brewfilePath string, app *tview.Application, outputView *tview.TextView) error
+	BundleCleanup(brewfilePath string, force bool, app *tview.Application, outputView *tview.TextView) error
 
 	// Tap support
 	InstallTap(tapName string, app *tview.Application, outputView *tview.TextView) error
 	IsTapInstalled(tapName string) bool
+	GetInstalledTaps() map[string]bool
 }
 
 // BrewService provides methods to execute Homebrew commands.
@@ -36,6 +51,25 @@ type BrewService struct {
 	brewVersion string
 }
 
+// brewCommand returns a brew command with Homebrew's implicit auto-update disabled,
+// which makes installs much faster and predictable. Homebrew is updated on demand instead.
+func brewCommand(args ...string) *exec.Cmd {
+	return brewCommandContext(context.Background(), args...)
+}
+
+// brewCommandContext is like brewCommand, with a context to cancel the command.
+func brewCommandContext(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "brew", args...) // #nosec G204
+	cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1")
+	if env := askpassEnv(); env != nil {
+		// Without a controlling terminal, sudo asks the TUI for the password (see askpass.go)
+		// or fails right away, instead of waiting for input on the terminal owned by the TUI
+		cmd.Env = append(cmd.Env, env...)
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	}
+	return cmd
+}
+
 // NewBrewService creates a new instance of BrewService.
 var NewBrewService = func() BrewServiceInterface {
 	return &BrewService{}
@@ -47,7 +81,8 @@ func (s *BrewService) GetBrewVersion() (string, error) {
 		return s.brewVersion, nil
 	}
 
-	cmd := exec.Command("brew", "--version")
+	defer startup.begin("brew --version")()
+	cmd := brewCommand("--version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -58,14 +93,57 @@ func (s *BrewService) GetBrewVersion() (string, error) {
 }
 
 // UpdateHomebrew updates the Homebrew package manager by running the `brew update` command.
-func (s *BrewService) UpdateHomebrew() error {
-	cmd := exec.Command("brew", "update")
-	return cmd.Run()
+// Spawned commands never auto-update (see brewCommand), so this is the only place Homebrew is updated.
+func (s *BrewService) UpdateHomebrew(app *tview.Application, outputView *tview.TextView) error {
+	cmd := brewCommand("update")
+	return s.executeCommand(app, cmd, outputView)
 }
 
 // UpdateAllPackages upgrades all outdated packages.
 func (s *BrewService) UpdateAllPackages(app *tview.Application, outputView *tview.TextView) error {
-	cmd := exec.Command("brew", "upgrade") // #nosec G204
+	cmd := brewCommand("upgrade") // #nosec G204
+	return s.executeCommand(app, cmd, outputView)
+}
+
+// UpgradePackages upgrades the given packages, with one command for the formulae and one for the casks.
+// Used instead of UpdateAllPackages when some outdated packages must be left alone.
+func (s *BrewService) UpgradePackages(packages []models.Package, app *tview.Application, outputView *tview.TextView) error {
+	var formulae, casks []string
+	for _, pkg := range packages {
+		if pkg.Type == models.PackageTypeCask {
+			casks = append(casks, pkg.Name)
+		} else {
+			formulae = append(formulae, pkg.Name)
+		}
+	}
+
+	if len(formulae) > 0 {
+		cmd := brewCommand(append([]string{"upgrade", "--formula"}, formulae...)...) // #nosec G204
+		if err := s.executeCommand(app, cmd, outputView); err != nil {
+			return err
+		}
+	}
+	if len(casks) > 0 {
+		cmd := brewCommand(append([]string{"upgrade", "--cask"}, casks...)...) // #nosec G204
+		return s.executeCommand(app, cmd, outputView)
+	}
+	return nil
+}
+
+// BundleInstall installs and upgrades everything in a Brewfile, taps and mas entries included,
+// exactly as `brew bundle install` does.
+func (s *BrewService) BundleInstall(brewfilePath string, app *tview.Application, outputView *tview.TextView) error {
+	cmd := brewCommand("bundle", "install", "--file="+brewfilePath, "--verbose") // #nosec G204
+	return s.executeCommand(app, cmd, outputView)
+}
+
+// BundleCleanup lists the installed packages that are not in a Brewfile, or uninstalls them with force.
+func (s *BrewService) BundleCleanup(brewfilePath string, force bool, app *tview.Application, outputView *tview.TextView) error {
+	args := []string{"bundle", "cleanup", "--file=" + brewfilePath}
+	if force {
+		args = append(args, "--force")
+	}
+	cmd := brewCommand(args...) // #nosec G204
 	return s.executeCommand(app, cmd, outputView)
 }
 
@@ -73,9 +151,9 @@ func (s *BrewService) UpdateAllPackages(app *tview.Application, outputView *tvie
 func (s *BrewService) UpdatePackage(info models.Package, app *tview.Application, outputView *tview.TextView) error {
 	var cmd *exec.Cmd
 	if info.Type == models.PackageTypeCask {
-		cmd = exec.Command("brew", "upgrade", "--cask", info.Name) // #nosec G204
+		cmd = brewCommand("upgrade", "--cask", info.Name) // #nosec G204
 	} else {
-		cmd = exec.Command("brew", "upgrade", info.Name) // #nosec G204
+		cmd = brewCommand("upgrade", info.Name) // #nosec G204
 	}
 	return s.executeCommand(app, cmd, outputView)
 }
@@ -84,125 +162,267 @@ func (s *BrewService) UpdatePackage(info models.Package, app *tview.Application,
 func (s *BrewService) RemovePackage(info models.Package, app *tview.Application, outputView *tview.TextView) error {
 	var cmd *exec.Cmd
 	if info.Type == models.PackageTypeCask {
-		cmd = exec.Command("brew", "uninstall", "--cask", info.Name) // #nosec G204
+		cmd = brewCommand("uninstall", "--cask", info.Name) // #nosec G204
 	} else {
-		cmd = exec.Command("brew", "uninstall", info.Name) // #nosec G204
+		cmd = brewCommand("uninstall", info.Name) // #nosec G204
 	}
 	return s.executeCommand(app, cmd, outputView)
 }
 
-// InstallPackage installs a package.
-func (s *BrewService) InstallPackage(info models.Package, app *tview.Application, outputView *tview.TextView) error {
-	var cmd *exec.Cmd
-	if info.Type == models.PackageTypeCask {
-		cmd = exec.Command("brew", "install", "--cask", info.Name) // #nosec G204
-	} else {
-		cmd = exec.Command("brew", "install", info.Name) // #nosec G204
+// InstallPackageTagged installs a package like InstallPackage, prefixing its output lines with
+// the package name. Used when several packages install at the same time.
+func (s *BrewService) InstallPackageTagged(
+	info models.Package, noQuarantine bool, app *tview.Application, outputView *tview.TextView,
+) error {
+	return s.executeTaggedCommand(app, s.installCommand(info, noQuarantine), outputView, info.Name)
+}
+
+// InstallPackage installs a package, by full name so that tap packages don't resolve to core ones.
+// noQuarantine skips the Gatekeeper quarantine of casks (ignored for formulae), options are flags of formulae
+// such as --with-foo, e.g. those of a removed install to restore.
+func (s *BrewService) InstallPackage(
+	info models.Package, noQuarantine bool, app *tview.Application, outputView *tview.TextView, options ...string,
+) error {
+	return s.executeCommand(app, s.installCommand(info, noQuarantine, options...), outputView)
+}
+
+// AdoptCask installs a cask over its app already present in /Applications, which Homebrew then manages.
+func (s *BrewService) AdoptCask(info models.Package, app *tview.Application, outputView *tview.TextView) error {
+	name := info.FullName
+	if name == "" {
+		name = info.Name
 	}
+	cmd := brewCommand("install", "--cask", "--adopt", name) // #nosec G204
+	return s.executeCommand(app, cmd, outputView)
+}
+
+// Cleanup removes the old versions and the old downloads of a package (`brew cleanup <name>`).
+func (s *BrewService) Cleanup(name string, app *tview.Application, outputView *tview.TextView) error {
+	cmd := brewCommand("cleanup", name) // #nosec G204
 	return s.executeCommand(app, cmd, outputView)
 }
 
+// RunCommands runs other commands than brew ones in order, e.g. those installing the Linux alternative
+// of a cask, stopping at the first failure.
+func (s *BrewService) RunCommands(cmds []*exec.Cmd, app *tview.Application, outputView *tview.TextView) error {
+	for _, cmd := range cmds {
+		if err := s.executeCommand(app, cmd, outputView); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installCommand returns the command installing a package, with the given options for formulae.
+func (s *BrewService) installCommand(info models.Package, noQuarantine bool, options ...string) *exec.Cmd {
+	name := info.FullName
+	if name == "" {
+		name = info.Name
+	}
+
+	if info.Type == models.PackageTypeCask {
+		if noQuarantine {
+			return brewCommand("install", "--cask", "--no-quarantine", name) // #nosec G204
+		}
+		return brewCommand("install", "--cask", name) // #nosec G204
+	}
+	return brewCommand(append(append([]string{"install"}, options...), name)...) // #nosec G204
+}
+
 // InstallTap installs a Homebrew tap.
 func (s *BrewService) InstallTap(tapName string, app *tview.Application, outputView *tview.TextView) error {
-	cmd := exec.Command("brew", "tap", tapName) // #nosec G204
+	cmd := brewCommand("tap", tapName) // #nosec G204
 	return s.executeCommand(app, cmd, outputView)
 }
 
 // IsTapInstalled checks if a tap is already installed.
 func (s *BrewService) IsTapInstalled(tapName string) bool {
-	cmd := exec.Command("brew", "tap")
-	output, err := cmd.Output()
+	return s.GetInstalledTaps()[strings.ToLower(tapName)]
+}
+
+// GetInstalledTaps returns the installed taps, lowercased as tap names are case-insensitive.
+func (s *BrewService) GetInstalledTaps() map[string]bool {
+	taps := make(map[string]bool)
+	output, err := brewCommand("tap").Output()
 	if err != nil {
-		return false
+		return taps
 	}
 
-	taps := strings.Split(strings.TrimSpace(string(output)), "\n")
-	for _, tap := range taps {
-		if strings.TrimSpace(tap) == tapName {
-			return true
+	for _, tap := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if tap = strings.TrimSpace(tap); tap != "" {
+			taps[strings.ToLower(tap)] = true
 		}
 	}
-	return false
+	return taps
 }
 
+// Output streaming limits: command output is flushed to the output view at most every
+// outputFlushInterval, or as soon as outputFlushSize bytes are buffered.
+const (
+	outputFlushInterval = 75 * time.Millisecond
+	outputFlushSize     = 8 * 1024
+)
+
+// outputTailSize is how much of the end of the output of a failed command is kept, to diagnose the failure.
+const outputTailSize = 16 * 1024
+
+// commandError is the error of a failed brew command, with the end of its output.
+type commandError struct {
+	err    error
+	args   []string // Command line, to run it again e.g. in a terminal
+	output string
+}
+
+func (e *commandError) Error() string { return e.err.Error() }
+
+func (e *commandError) Unwrap() error { return e.err }
+
 // executeCommand runs a command and captures its output, updating the provided TextView.
 func (s *BrewService) executeCommand(
 	app *tview.Application,
 	cmd *exec.Cmd,
 	outputView *tview.TextView,
 ) error {
-	stdoutPipe, stdoutWriter := io.Pipe()
-	stderrPipe, stderrWriter := io.Pipe()
-	cmd.Stdout = stdoutWriter
-	cmd.Stderr = stderrWriter
+	return s.executeTaggedCommand(app, cmd, outputView, "")
+}
+
+// executeTaggedCommand runs a command like executeCommand, prefixing each output line with
+// the given tag, so that commands running at the same time share the output view legibly.
+func (s *BrewService) executeTaggedCommand(
+	app *tview.Application,
+	cmd *exec.Cmd,
+	outputView *tview.TextView,
+	tag string,
+) error {
+	brewLog.Debug("running command", "args", cmd.Args[1:])
+	started := time.Now()
+
+	// stdout and stderr share the streamer, so their lines stay in order
+	streamer := &outputStreamer{app: app, view: outputView, tag: tag, lineStart: true}
+	cmd.Stdout = streamer
+	cmd.Stderr = streamer
+	streamer.stamp(cmd.Args)
 
 	if err := cmd.Start(); err != nil {
+		brewLog.Error("failed to start command", "args", cmd.Args[1:], "err", err)
 		return err
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(3)
-
-	cmdErrCh := make(chan error, 1)
-
-	go func() {
-		defer wg.Done()
-		defer stdoutWriter.Close()
-		defer stderrWriter.Close()
-		cmdErrCh <- cmd.Wait()
-	}()
-
+	done := make(chan struct{})
 	go func() {
-		defer wg.Done()
-		defer stdoutPipe.Close()
-		buf := make([]byte, 1024)
+		defer RecoverCrash()
+		ticker := time.NewTicker(outputFlushInterval)
+		defer ticker.Stop()
 		for {
-			n, err := stdoutPipe.Read(buf)
-			if n > 0 {
-				output := make([]byte, n)
-				copy(output, buf[:n])
-				app.QueueUpdateDraw(func() {
-					_, _ = outputView.Write(output) // #nosec G104
-					outputView.ScrollToEnd()
-				})
-			}
-			if err != nil {
-				if err != io.EOF {
-					app.QueueUpdateDraw(func() {
-						fmt.Fprintf(outputView, "\nError: %v\n", err)
-					})
-				}
-				break
+			select {
+			case <-ticker.C:
+				streamer.flush()
+			case <-done:
+				return
 			}
 		}
 	}()
 
-	go func() {
-		defer wg.Done()
-		defer stderrPipe.Close()
-		buf := make([]byte, 1024)
-		for {
-			n, err := stderrPipe.Read(buf)
-			if n > 0 {
-				output := make([]byte, n)
-				copy(output, buf[:n])
-				app.QueueUpdateDraw(func() {
-					_, _ = outputView.Write(output) // #nosec G104
-					outputView.ScrollToEnd()
-				})
-			}
-			if err != nil {
-				if err != io.EOF {
-					app.QueueUpdateDraw(func() {
-						fmt.Fprintf(outputView, "\nError: %v\n", err)
-					})
-				}
-				break
+	err := cmd.Wait()
+	close(done)
+	streamer.flushAll()
+	if err != nil {
+		brewLog.Warn("command failed", "args", cmd.Args[1:], "err", err, "duration", time.Since(started))
+		return &commandError{err: err, args: cmd.Args, output: streamer.tail.String()}
+	}
+	brewLog.Debug("command completed", "args", cmd.Args[1:], "duration", time.Since(started))
+	return nil
+}
+
+// outputStreamer buffers command output and writes it to the output view in batches,
+// so that verbose commands don't flood the tview event loop with a redraw per read.
+type outputStreamer struct {
+	app  *tview.Application
+	view *tview.TextView
+	mu   sync.Mutex
+	buf  bytes.Buffer
+
+	// Tagged output: each line is prefixed with the tag, and only whole lines are flushed
+	// so that they don't mix with the output of other commands
+	tag       string
+	lineStart bool
+
+	tail bytes.Buffer // End of the raw output, up to outputTailSize
+}
+
+// stamp starts the output of a command with the time and its command line, dimmed,
+// e.g. "14:02:11 $ brew install wget", so that a long output can be followed over time.
+func (o *outputStreamer) stamp(args []string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.tag != "" {
+		o.buf.WriteString(tview.Escape("["+o.tag+"]") + " ")
+	}
+	commandLine := append([]string{filepath.Base(args[0])}, args[1:]...)
+	fmt.Fprintf(&o.buf, "[::d]%s $ %s[::-]\n", time.Now().Format("15:04:05"), tview.Escape(strings.Join(commandLine, " ")))
+}
+
+// Write buffers the output, flushing it right away once the buffer is large enough.
+func (o *outputStreamer) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	o.tail.Write(p)
+	if excess := o.tail.Len() - outputTailSize; excess > 0 {
+		o.tail.Next(excess)
+	}
+	if o.tag == "" {
+		o.buf.Write(p)
+	} else {
+		prefix := tview.Escape("["+o.tag+"]") + " "
+		for _, c := range p {
+			if o.lineStart {
+				o.buf.WriteString(prefix)
 			}
+			o.buf.WriteByte(c)
+			o.lineStart = c == '\n'
 		}
-	}()
+	}
+	full := o.buf.Len() >= outputFlushSize
+	o.mu.Unlock()
+
+	if full {
+		o.flush()
+	}
+	return len(p), nil
+}
+
+// flush queues the buffered output for display. The lock is held while queueing,
+// so that concurrent flushes keep the output in order. Tagged output keeps its last,
+// incomplete line in the buffer.
+func (o *outputStreamer) flush() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.flushLocked(o.tag != "")
+}
+
+// flushAll queues all the buffered output, completing the last line of tagged output.
+func (o *outputStreamer) flushAll() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.tag != "" && !o.lineStart {
+		o.buf.WriteByte('\n')
+		o.lineStart = true
+	}
+	o.flushLocked(false)
+}
 
-	wg.Wait()
+// flushLocked queues the buffered output, up to the last newline if wholeLines is set. Callers hold the lock.
+func (o *outputStreamer) flushLocked(wholeLines bool) {
+	end := o.buf.Len()
+	if wholeLines {
+		end = bytes.LastIndexByte(o.buf.Bytes(), '\n') + 1
+	}
+	if end == 0 {
+		return
+	}
 
-	return <-cmdErrCh
+	output := bytes.Clone(o.buf.Next(end))
+	o.app.QueueUpdateDraw(func() {
+		_, _ = o.view.Write(output) // #nosec G104
+		o.view.ScrollToEnd()
+	})
 }
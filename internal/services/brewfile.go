@@ -13,9 +13,9 @@
 //     Initial load using cached tap data for fast startup.
 //
 //  2. BuildApp() → goroutine:
-//     a) installBrewfileTapsAtStartup()
-//     Installs any missing taps from the Brewfile.
-//     b) updateHomeBrew() → forceRefreshResults()
+//     a) installMissingTaps()
+//     Installs any missing taps from the Brewfile (also available on demand, see InstallMissingTaps).
+//     b) forceRefreshResults()
 //     Refreshes Homebrew data and reloads packages.
 //
 //  3. forceRefreshResults() → fetchTapPackages() + loadBrewfilePackages()
@@ -23,13 +23,15 @@
 package services
 
 import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
 	"bbrew/internal/models"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
+	"slices"
 	"strings"
 )
 
@@ -61,7 +63,7 @@ func ResolveBrewfilePath(pathOrURL string) (localPath string, cleanup func(), er
 
 // downloadBrewfile downloads a remote Brewfile to a temporary file.
 func downloadBrewfile(url string) (string, error) {
-	fmt.Fprintf(os.Stderr, "Downloading Brewfile from %s...\n", url)
+	brewfileLog.Info("downloading Brewfile", "url", url)
 
 	resp, err := http.Get(url) // #nosec G107 - URL is user-provided, HTTPS enforced
 	if err != nil {
@@ -103,52 +105,93 @@ func parseBrewfileWithTaps(filepath string) (*models.BrewfileResult, error) {
 	}
 	lines := strings.Split(string(data), "\n")
 
+	group := ""
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
+		// Section headers group the entries that follow them
+		if title, ok := parseBrewfileSection(line); ok {
+			group = title
+			result.Groups = append(result.Groups, title)
+			continue
+		}
+
 		// Skip empty lines and comments
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
 
-		// Parse tap entries: tap "user/repo"
-		if strings.HasPrefix(line, "tap ") {
-			start := strings.Index(line, "\"")
-			end := strings.LastIndex(line, "\"")
-			if start != -1 && end != -1 && start < end {
-				tapName := line[start+1 : end]
-				result.Taps = append(result.Taps, tapName)
-			}
+		keyword, _, _ := strings.Cut(line, " ")
+		name, comment, ok := parseBrewfileLine(line)
+		if !ok {
+			continue
 		}
 
-		// Parse brew entries: brew "package-name"
-		if strings.HasPrefix(line, "brew ") {
-			start := strings.Index(line, "\"")
-			end := strings.LastIndex(line, "\"")
-			if start != -1 && end != -1 && start < end {
-				packageName := line[start+1 : end]
-				result.Packages = append(result.Packages, models.BrewfileEntry{
-					Name:   packageName,
-					IsCask: false,
-				})
-			}
+		switch keyword {
+		case "tap": // tap "user/repo"
+			result.Taps = append(result.Taps, name)
+		case "brew": // brew "package-name"
+			result.Packages = append(result.Packages, models.BrewfileEntry{Name: name, IsCask: false, Comment: comment, Group: group, Tags: parseBrewfileTags(comment)})
+		case "cask": // cask "package-name"
+			result.Packages = append(result.Packages, models.BrewfileEntry{Name: name, IsCask: true, Comment: comment, Group: group, Tags: parseBrewfileTags(comment)})
 		}
+	}
 
-		// Parse cask entries: cask "package-name"
-		if strings.HasPrefix(line, "cask ") {
-			start := strings.Index(line, "\"")
-			end := strings.LastIndex(line, "\"")
-			if start != -1 && end != -1 && start < end {
-				packageName := line[start+1 : end]
-				result.Packages = append(result.Packages, models.BrewfileEntry{
-					Name:   packageName,
-					IsCask: true,
-				})
-			}
+	return result, nil
+}
+
+// parseBrewfileLine extracts the quoted name of a Brewfile entry and its trailing comment,
+// e.g. `brew "jq", args: ["HEAD"] # used by deploy scripts` → "jq", "used by deploy scripts".
+func parseBrewfileLine(line string) (name, comment string, ok bool) {
+	start := strings.Index(line, "\"")
+	if start == -1 {
+		return "", "", false
+	}
+	length := strings.Index(line[start+1:], "\"")
+	if length <= 0 {
+		return "", "", false
+	}
+	name = line[start+1 : start+1+length]
+
+	// The comment starts at the first "#" after the name, outside of quoted options
+	rest := line[start+length+2:]
+	inQuotes := false
+	for i, r := range rest {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == '#' && !inQuotes:
+			return name, strings.TrimSpace(rest[i+1:]), true
 		}
 	}
+	return name, "", true
+}
 
-	return result, nil
+// parseBrewfileSection returns the title of a section header comment,
+// e.g. "# --- Dev tools ---" or "# === Media ===" → "Dev tools", "Media".
+func parseBrewfileSection(line string) (string, bool) {
+	const rules = "-=*#"
+	text := strings.TrimSpace(strings.TrimPrefix(line, "#"))
+	if !strings.HasPrefix(line, "#") || len(text) < 2 || text[0] != text[1] || !strings.ContainsRune(rules, rune(text[0])) {
+		return "", false
+	}
+	title := strings.Trim(text, rules+" ")
+	return title, title != ""
+}
+
+// parseBrewfileTags extracts the tags of a "tags: work,media" comment.
+func parseBrewfileTags(comment string) []string {
+	_, list, found := strings.Cut(comment, "tags:")
+	if !found {
+		return nil
+	}
+	var tags []string
+	for _, tag := range strings.Split(list, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
 }
 
 // loadBrewfilePackages parses the Brewfile and creates a filtered package list.
@@ -159,88 +202,125 @@ func (s *AppService) loadBrewfilePackages() error {
 		return err
 	}
 
-	// Store taps for later installation
+	// Store taps for later installation, and the sections for the grouped view
 	s.brewfileTaps = result.Taps
-
-	// Create a map for quick lookup of Brewfile entries
-	packageMap := make(map[string]models.PackageType)
-	for _, entry := range result.Packages {
-		if entry.IsCask {
-			packageMap[entry.Name] = models.PackageTypeCask
-		} else {
-			packageMap[entry.Name] = models.PackageTypeFormula
+	s.brewfileGroups = result.Groups
+
+	// Keep only the packages of the requested section or tag (see the --only-group flag)
+	if group := s.startupOptions.OnlyGroup; group != "" {
+		selected := make([]models.BrewfileEntry, 0, len(result.Packages))
+		for _, entry := range result.Packages {
+			if entry.InGroup(group) {
+				selected = append(selected, entry)
+			}
 		}
+		if len(selected) == 0 {
+			return fmt.Errorf("no packages in group %q", group)
+		}
+		result.Packages = selected
 	}
 
-	// Track which packages were found (to avoid duplicates)
-	foundPackages := make(map[string]bool)
-
 	// Get actual installed packages (2 calls total, much faster than per-package checks)
 	installedCasks := s.dataProvider.FetchInstalledCaskNames()
 	installedFormulae := s.dataProvider.FetchInstalledFormulaNames()
 
-	// Filter packages to only include those in the Brewfile
-	*s.brewfilePackages = []models.Package{}
-	for _, pkg := range *s.packages {
-		if pkgType, exists := packageMap[pkg.Name]; exists && pkgType == pkg.Type {
-			// Skip if already added (prevent duplicates)
-			if foundPackages[pkg.Name] {
-				continue
-			}
-			// Verify installation status against actual installed lists
-			if pkgType == models.PackageTypeCask {
-				pkg.LocallyInstalled = installedCasks[pkg.Name]
-			} else {
-				pkg.LocallyInstalled = installedFormulae[pkg.Name]
-			}
-			*s.brewfilePackages = append(*s.brewfilePackages, pkg)
-			foundPackages[pkg.Name] = true
+	// Track which packages were found (to avoid duplicates)
+	foundPackages := make(map[packageKey]bool)
+	brewfilePackages := []models.Package{}
+	renames := make(map[string]string)
+	addPackage := func(entry models.BrewfileEntry, pkg models.Package) {
+		if foundPackages[packageKeyOf(pkg)] {
+			return
+		}
+		foundPackages[packageKeyOf(pkg)] = true
+
+		// Verify installation status against actual installed lists
+		if pkg.Type == models.PackageTypeCask {
+			pkg.LocallyInstalled = isInstalledAs(pkg, installedCasks)
+		} else {
+			pkg.LocallyInstalled = isInstalledAs(pkg, installedFormulae)
+		}
+
+		// The entry may use an old name of the package: operations use the new one
+		if oldName := models.ShortName(entry.Name); oldName != pkg.Name && slices.Contains(pkg.OldNames(), oldName) {
+			pkg.RenamedFrom = entry.Name
+			renames[entry.Name] = pkg.Name
+			brewfileLog.Info("Brewfile entry renamed", "old", entry.Name, "new", pkg.Name)
 		}
+
+		// Carry the Brewfile annotations over to the package
+		pkg.BrewfileComment = entry.Comment
+		pkg.BrewfileGroup = entry.Group
+		pkg.BrewfileTags = entry.Tags
+		brewfilePackages = append(brewfilePackages, pkg)
 	}
 
-	// Collect entries not found in main list (tap packages)
+	// Resolve the entries against the known packages, by short or full name ("user/repo/tool")
+	packages := s.store.All()
+	known := newPackageIndex(packages)
 	var tapEntries []models.BrewfileEntry
 	for _, entry := range result.Packages {
-		if !foundPackages[entry.Name] {
-			tapEntries = append(tapEntries, entry)
+		if pkg, exists := known.lookup(entry.Name, entry.IsCask); exists {
+			addPackage(entry, pkg)
+		} else {
+			tapEntries = append(tapEntries, entry) // Not in main list (tap packages)
 		}
 	}
 
 	// Load tap packages from cache (fast startup)
 	if len(tapEntries) > 0 {
-		// Build existing packages map
-		existingPackages := make(map[string]models.Package)
-		for _, pkg := range *s.packages {
-			existingPackages[pkg.Name] = pkg
-		}
-
 		// Use DataProvider to load tap packages (from cache only at startup, no fetch)
-		tapPackages, _ := s.dataProvider.GetTapPackages(tapEntries, existingPackages, false)
-
-		// Add tap packages to brewfilePackages, updating installed status (avoid duplicates)
-		for _, pkg := range tapPackages {
-			if foundPackages[pkg.Name] {
-				continue // Already added
-			}
-			if pkg.Type == models.PackageTypeCask {
-				pkg.LocallyInstalled = installedCasks[pkg.Name]
-			} else {
-				pkg.LocallyInstalled = installedFormulae[pkg.Name]
+		tapPackages, _ := s.dataProvider.GetTapPackages(tapEntries, packages, false)
+		tapIndex := newPackageIndex(tapPackages)
+		for _, entry := range tapEntries {
+			if pkg, exists := tapIndex.lookup(entry.Name, entry.IsCask); exists {
+				addPackage(entry, pkg)
 			}
-			*s.brewfilePackages = append(*s.brewfilePackages, pkg)
-			foundPackages[pkg.Name] = true
 		}
 	}
 
 	// Sort by name for consistent display
-	sort.Slice(*s.brewfilePackages, func(i, j int) bool {
-		return (*s.brewfilePackages)[i].Name < (*s.brewfilePackages)[j].Name
-	})
+	i18n.SortByName(brewfilePackages, func(pkg models.Package) string { return pkg.Name })
+	s.store.SetBrewfile(brewfilePackages)
+	s.brewfileRenames = renames
 
 	return nil
 }
 
-// fetchTapPackages fetches info for packages from third-party taps and adds them to s.packages.
+// isInstalledAs reports whether a package is in a list of installed names, under its name or an old one
+// (a keg brew hasn't migrated yet).
+func isInstalledAs(pkg models.Package, installed map[string]bool) bool {
+	if installed[pkg.Name] {
+		return true
+	}
+	for _, oldName := range pkg.OldNames() {
+		if installed[oldName] {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyBrewfileRenames tells which entries of the Brewfile use an old name, e.g. "x was renamed to y".
+// They are shown and handled under the new name. Runs in the event loop.
+func (s *AppService) notifyBrewfileRenames() {
+	if len(s.brewfileRenames) == 0 {
+		return
+	}
+	oldNames := make([]string, 0, len(s.brewfileRenames))
+	for oldName := range s.brewfileRenames {
+		oldNames = append(oldNames, oldName)
+	}
+	i18n.SortByName(oldNames, func(name string) string { return name })
+
+	message := i18n.T("%s was renamed to %s", oldNames[0], s.brewfileRenames[oldNames[0]])
+	if len(oldNames) > 1 {
+		message += " " + i18n.T("(and %d more renamed entries of the Brewfile)", len(oldNames)-1)
+	}
+	s.layout.GetNotifier().ShowWarning(message)
+}
+
+// fetchTapPackages fetches info for packages from third-party taps and adds them to the known packages.
 // This is called after taps are installed so that loadBrewfilePackages can find them.
 // Uses the DataProvider to fetch and cache tap package data.
 func (s *AppService) fetchTapPackages() {
@@ -250,63 +330,95 @@ func (s *AppService) fetchTapPackages() {
 
 	result, err := parseBrewfileWithTaps(s.brewfilePath)
 	if err != nil {
+		brewfileLog.Warn("failed to parse the Brewfile", "path", s.brewfilePath, "err", err)
 		return
 	}
 
-	// Build a map of existing packages for quick lookup
-	existingPackages := make(map[string]models.Package)
-	for _, pkg := range *s.packages {
-		existingPackages[pkg.Name] = pkg
-	}
-
 	// Use DataProvider to fetch all tap packages (force download to get fresh data)
-	tapPackages, _ := s.dataProvider.GetTapPackages(result.Packages, existingPackages, true)
+	packages := s.store.All()
+	tapPackages, err := s.dataProvider.GetTapPackages(result.Packages, packages, true)
+	if err != nil {
+		brewfileLog.Warn("failed to fetch some tap packages", "err", err) // The others are still added
+	}
 
-	// Add tap packages to s.packages (avoiding duplicates)
+	// Add tap packages to the known packages (avoiding duplicates, by full name)
+	existingPackages := make(map[packageKey]bool, len(packages))
+	for _, pkg := range packages {
+		existingPackages[packageKeyOf(pkg)] = true
+	}
 	for _, pkg := range tapPackages {
-		if _, exists := existingPackages[pkg.Name]; !exists {
-			*s.packages = append(*s.packages, pkg)
+		if !existingPackages[packageKeyOf(pkg)] {
+			existingPackages[packageKeyOf(pkg)] = true
+			packages = append(packages, pkg)
 		}
 	}
+	s.store.SetAll(packages)
 }
 
-// installBrewfileTapsAtStartup installs any missing taps from the Brewfile at app startup.
-// This runs before updateHomeBrew, which will then reload all data including the new taps.
-func (s *AppService) installBrewfileTapsAtStartup() {
-	// Check which taps need to be installed
+// installMissingTaps installs the taps of the Brewfile that aren't installed yet, re-reading the
+// Brewfile first to pick up taps added since startup. It returns the number of taps it tried to install.
+// At startup, this runs before forceRefreshResults, which will then reload all data including the new taps.
+func (s *AppService) installMissingTaps() int {
+	if result, err := parseBrewfileWithTaps(s.brewfilePath); err == nil {
+		s.brewfileTaps = result.Taps
+	}
+
+	// Check which taps need to be installed (a single `brew tap` call)
+	installedTaps := s.brewService.GetInstalledTaps()
 	var tapsToInstall []string
 	for _, tap := range s.brewfileTaps {
-		if !s.brewService.IsTapInstalled(tap) {
+		if !installedTaps[strings.ToLower(tap)] {
 			tapsToInstall = append(tapsToInstall, tap)
 		}
 	}
 
 	if len(tapsToInstall) == 0 {
-		return // All taps already installed
+		return 0 // All taps already installed
 	}
 
 	// Install missing taps
 	for _, tap := range tapsToInstall {
 		tap := tap // Create local copy for closures
 		s.app.QueueUpdateDraw(func() {
-			s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Installing tap %s...", tap))
+			s.layout.GetNotifier().ShowProgress(i18n.T("Installing tap %s...", tap))
 			fmt.Fprintf(s.layout.GetOutput().View(), "[TAP] Installing %s...\n", tap)
 		})
 
 		if err := s.brewService.InstallTap(tap, s.app, s.layout.GetOutput().View()); err != nil {
 			s.app.QueueUpdateDraw(func() {
-				s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to install tap %s", tap))
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to install tap %s", tap))
 				fmt.Fprintf(s.layout.GetOutput().View(), "[ERROR] Failed to install tap %s\n", tap)
 			})
 		} else {
 			s.app.QueueUpdateDraw(func() {
-				s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Tap %s installed", tap))
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Tap %s installed", tap))
 				fmt.Fprintf(s.layout.GetOutput().View(), "[SUCCESS] tap %s installed\n", tap)
 			})
 		}
 	}
 
 	s.app.QueueUpdateDraw(func() {
-		s.layout.GetNotifier().ShowSuccess("All taps installed")
+		s.layout.GetNotifier().ShowSuccess(i18n.T("All taps installed"))
 	})
+	return len(tapsToInstall)
+}
+
+// InstallMissingTaps installs the missing taps of the Brewfile on demand, then fetches
+// their packages and reloads the Brewfile, so that no restart is needed.
+// It runs in the background, within an operation begun by the caller (see beginOperation).
+func (s *AppService) InstallMissingTaps() {
+	if s.installMissingTaps() == 0 {
+		s.terminal.ClearActivity()
+		s.app.QueueUpdateDraw(func() {
+			s.layout.GetNotifier().ShowSuccess(i18n.T("All taps are already installed"))
+		})
+		return
+	}
+
+	s.fetchTapPackages()
+	if err := s.loadBrewfilePackages(); err != nil {
+		brewfileLog.Warn("failed to reload the Brewfile", "path", s.brewfilePath, "err", err)
+	}
+	s.terminal.Done(i18n.T("All taps installed"))
+	s.events.Publish(events.Event{Type: events.PackagesUpdated})
 }
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+)
+
+// lockKinds are the entry kinds of the lockfile bbrew records, as in brew bundle's Brewfile.lock.json.
+var lockKinds = []string{"brew", "cask"}
+
+// brewfileLock is the subset of brew bundle's Brewfile.lock.json bbrew uses: the exact version of each
+// formula and cask of the Brewfile. The other fields of an existing lockfile (bottles, system) are kept.
+type brewfileLock struct {
+	raw     map[string]json.RawMessage           // Top-level fields, kept as they are
+	entries map[string]map[string]map[string]any // Kind ("brew", "cask") → name → fields, e.g. "version"
+}
+
+// brewfileLockPath returns the lockfile of a Brewfile: Brewfile.lock.json next to it.
+func brewfileLockPath(brewfilePath string) string {
+	return brewfilePath + ".lock.json"
+}
+
+// readBrewfileLock reads a lockfile, returning an empty one if it doesn't exist.
+func readBrewfileLock(path string) (*brewfileLock, error) {
+	lock := &brewfileLock{raw: make(map[string]json.RawMessage), entries: make(map[string]map[string]map[string]any)}
+
+	// #nosec G304 -- path is next to the Brewfile given on the command line
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lock, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &lock.raw); err != nil {
+		return nil, fmt.Errorf("invalid lockfile %s: %w", path, err)
+	}
+	if entries, exists := lock.raw["entries"]; exists {
+		if err := json.Unmarshal(entries, &lock.entries); err != nil {
+			return nil, fmt.Errorf("invalid entries in lockfile %s: %w", path, err)
+		}
+	}
+	return lock, nil
+}
+
+// version returns the locked version of a package.
+func (l *brewfileLock) version(kind, name string) (string, bool) {
+	entry, exists := l.entries[kind][name]
+	if !exists {
+		return "", false
+	}
+	version, _ := entry["version"].(string)
+	return version, true
+}
+
+// set locks a package at a version.
+func (l *brewfileLock) set(kind, name, version string) {
+	if l.entries[kind] == nil {
+		l.entries[kind] = make(map[string]map[string]any)
+	}
+	if l.entries[kind][name] == nil {
+		l.entries[kind][name] = make(map[string]any)
+	}
+	l.entries[kind][name]["version"] = version
+}
+
+// write saves the lockfile, with its entries sorted by name as encoding/json does for maps.
+func (l *brewfileLock) write(path string) error {
+	entries, err := json.Marshal(l.entries)
+	if err != nil {
+		return err
+	}
+	l.raw["entries"] = entries
+	data, err := json.MarshalIndent(l.raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// lockKind returns the lockfile kind of a Brewfile entry.
+func lockKind(isCask bool) string {
+	if isCask {
+		return "cask"
+	}
+	return "brew"
+}
+
+// installedVersions returns the installed version of each formula and cask, by lockfile kind and name.
+// Formulae with several versions installed report the last one, the linked one in most cases.
+func installedVersions() (map[string]map[string]string, error) {
+	versions := make(map[string]map[string]string)
+	for _, isCask := range []bool{false, true} {
+		flag := "--formula"
+		if isCask {
+			flag = "--cask"
+		}
+		output, err := brewCommand("list", flag, "--versions").Output()
+		if err != nil {
+			return nil, fmt.Errorf("brew list %s --versions failed: %w", flag, err)
+		}
+		kind := lockKind(isCask)
+		versions[kind] = make(map[string]string)
+		for _, line := range strings.Split(string(output), "\n") {
+			if fields := strings.Fields(line); len(fields) > 1 {
+				versions[kind][fields[0]] = fields[len(fields)-1]
+			}
+		}
+	}
+	return versions, nil
+}
+
+// lockBrewfile records the installed versions of the packages of a Brewfile in its lockfile, returning
+// the locked and the not installed packages. Packages no longer installed keep their locked version.
+func lockBrewfile(brewfilePath string) (locked int, missing []string, err error) {
+	brewfile, err := parseBrewfileWithTaps(brewfilePath)
+	if err != nil {
+		return 0, nil, err
+	}
+	lockPath := brewfileLockPath(brewfilePath)
+	lock, err := readBrewfileLock(lockPath)
+	if err != nil {
+		return 0, nil, err
+	}
+	installed, err := installedVersions()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, entry := range brewfile.Packages {
+		kind, name := lockKind(entry.IsCask), entry.Name
+		version, exists := installed[kind][models.ShortName(name)]
+		if !exists {
+			missing = append(missing, name)
+			continue
+		}
+		lock.set(kind, name, version)
+		locked++
+	}
+	brewfileLog.Info("locking Brewfile", "lockfile", lockPath, "locked", locked, "missing", len(missing))
+	return locked, missing, lock.write(lockPath)
+}
+
+// updateBrewfileLock records the installed versions in the lockfile of the Brewfile after an install,
+// if it has one or brewfile_lock is set. Remote Brewfiles are left alone, having no place for it.
+func (s *AppService) updateBrewfileLock() {
+	if !s.IsBrewfileMode() || strings.HasPrefix(s.startupOptions.Brewfile, "https://") {
+		return
+	}
+	if _, err := os.Stat(brewfileLockPath(s.brewfilePath)); err != nil && !s.config.BrewfileLock {
+		return
+	}
+	if _, _, err := lockBrewfile(s.brewfilePath); err != nil {
+		brewfileLog.Error("failed to update the Brewfile lock", "err", err)
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to update the Brewfile lock: %v", err))
+	}
+}
+
+// LockBrewfile handles `bbrew lock`: it writes Brewfile.lock.json next to a Brewfile with the installed versions.
+func LockBrewfile(brewfilePath string, out io.Writer) error {
+	locked, missing, err := lockBrewfile(brewfilePath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Locked %d packages in %s\n", locked, brewfileLockPath(brewfilePath))
+	if len(missing) > 0 {
+		fmt.Fprintf(out, "Not installed, not locked: %s\n", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// lockDrift is a package whose installed version differs from its lockfile.
+type lockDrift struct {
+	Kind      string
+	Name      string
+	Locked    string // "" if the package is not in the lockfile
+	Installed string // "" if the package is not installed
+}
+
+// verifyBrewfileLock compares the lockfile of a Brewfile with the installed packages.
+// Every package of the Brewfile and of the lockfile is checked, sorted by kind and name.
+func verifyBrewfileLock(brewfilePath string) ([]lockDrift, error) {
+	lockPath := brewfileLockPath(brewfilePath)
+	if _, err := os.Stat(lockPath); err != nil {
+		return nil, fmt.Errorf("no lockfile for %s, create it with bbrew lock: %w", brewfilePath, err)
+	}
+	lock, err := readBrewfileLock(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	brewfile, err := parseBrewfileWithTaps(brewfilePath)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := installedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]map[string]bool)
+	for _, kind := range lockKinds {
+		names[kind] = make(map[string]bool)
+		for name := range lock.entries[kind] {
+			names[kind][name] = true
+		}
+	}
+	for _, entry := range brewfile.Packages {
+		names[lockKind(entry.IsCask)][entry.Name] = true
+	}
+
+	var drifts []lockDrift
+	for _, kind := range lockKinds {
+		for name := range names[kind] {
+			locked, isLocked := lock.version(kind, name)
+			version, isInstalled := installed[kind][models.ShortName(name)]
+			if !isLocked || !isInstalled || locked != version {
+				drifts = append(drifts, lockDrift{Kind: kind, Name: name, Locked: locked, Installed: version})
+			}
+		}
+	}
+	sort.SliceStable(drifts, func(i, j int) bool {
+		if drifts[i].Kind != drifts[j].Kind {
+			return drifts[i].Kind < drifts[j].Kind
+		}
+		return i18n.Compare(drifts[i].Name, drifts[j].Name) < 0
+	})
+	return drifts, nil
+}
+
+// VerifyBrewfileLock handles `bbrew verify`: it reports the drift between the lockfile of a Brewfile and
+// the installed packages, returning the number of drifted packages.
+func VerifyBrewfileLock(brewfilePath string, out io.Writer) (int, error) {
+	drifts, err := verifyBrewfileLock(brewfilePath)
+	if err != nil {
+		return 0, err
+	}
+	if len(drifts) == 0 {
+		fmt.Fprintf(out, "No drift: the installed packages match %s\n", brewfileLockPath(brewfilePath))
+		return 0, nil
+	}
+
+	for _, drift := range drifts {
+		switch {
+		case drift.Installed == "" && drift.Locked == "":
+			fmt.Fprintf(out, "%s %s: not installed, not locked\n", drift.Kind, drift.Name)
+		case drift.Installed == "":
+			fmt.Fprintf(out, "%s %s: locked at %s, not installed\n", drift.Kind, drift.Name, drift.Locked)
+		case drift.Locked == "":
+			fmt.Fprintf(out, "%s %s: %s installed, not locked\n", drift.Kind, drift.Name, drift.Installed)
+		default:
+			fmt.Fprintf(out, "%s %s: locked at %s, %s installed\n", drift.Kind, drift.Name, drift.Locked, drift.Installed)
+		}
+	}
+	fmt.Fprintf(out, "\n%d packages drifted from %s\n", len(drifts), brewfileLockPath(brewfilePath))
+	return len(drifts), nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// brewLockMarkers are the messages of brew commands failing because another brew process holds a lock,
+// matched case-insensitively against the command output.
+var brewLockMarkers = []string{
+	"another active homebrew",    // Another active Homebrew update process is already in progress.
+	"process has already locked", // A `brew install wget` process has already locked /opt/homebrew/var/...
+	"process is already running", // Another `brew update` process is already running.
+}
+
+const (
+	brewLockFirstCheck = 2 * time.Second  // First check of the lock, doubling after each one
+	brewLockMaxCheck   = 30 * time.Second // Longest interval between checks
+	brewLockMaxWait    = 10 * time.Minute // Giving up after this, e.g. on a stuck process
+)
+
+// isBrewLocked reports whether a brew command failed because another brew process holds a lock.
+func isBrewLocked(err error) bool {
+	output := strings.ToLower(failureOutput(err))
+	for _, marker := range brewLockMarkers {
+		if strings.Contains(output, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// brewLockBackoff returns the delay before the given check of the lock, counting from 0.
+func brewLockBackoff(check int) time.Duration {
+	delay := brewLockFirstCheck
+	for i := 0; i < check && delay < brewLockMaxCheck; i++ {
+		delay *= 2
+	}
+	return min(delay, brewLockMaxCheck)
+}
+
+// brewProcess is a running brew command, e.g. the one holding the lock.
+type brewProcess struct {
+	PID     int
+	Command string // e.g. "brew upgrade node"
+}
+
+// brewProcesses lists the running brew commands of other processes, from the entry scripts of Homebrew
+// in their command line. Returns nil if ps is not available.
+func brewProcesses() []brewProcess {
+	output, err := exec.Command("ps", "-Ao", "pid=,args=").Output()
+	if err != nil {
+		return nil
+	}
+
+	var processes []brewProcess
+	for _, line := range strings.Split(string(output), "\n") {
+		pidField, args, found := strings.Cut(strings.TrimSpace(line), " ")
+		pid, err := strconv.Atoi(pidField)
+		if !found || err != nil || pid == os.Getpid() {
+			continue
+		}
+		for _, script := range []string{"/Homebrew/brew.rb", "/Homebrew/brew.sh"} {
+			if _, command, found := strings.Cut(args, script); found {
+				processes = append(processes, brewProcess{PID: pid, Command: strings.TrimSpace("brew " + strings.TrimSpace(command))})
+				break
+			}
+		}
+	}
+	return processes
+}
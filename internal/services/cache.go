@@ -24,6 +24,7 @@ func ensureCacheDir() error {
 // readCacheFile reads a cached file if it exists and meets minimum size requirements.
 // Returns nil if cache should not be used.
 func readCacheFile(filename string, minSize int64) []byte {
+	defer startup.begin("cache read " + filename)()
 	cacheFile := filepath.Join(getCacheDir(), filename)
 	fileInfo, err := os.Stat(cacheFile)
 	if err != nil || fileInfo.Size() < minSize {
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"path/filepath"
+	"strings"
+)
+
+// Placeholders of the Homebrew API for the paths of the installing machine.
+const (
+	caveatsPrefixPlaceholder = "$HOMEBREW_PREFIX"
+	caveatsCellarPlaceholder = "$HOMEBREW_CELLAR"
+)
+
+// packageCaveats returns the caveats of a package (PATH additions, services, ...), with the
+// Homebrew path placeholders of the API resolved for this machine. Partial formulae and casks
+// are completed first, as the v3 index and the summaries have no caveats.
+func (s *AppService) packageCaveats(pkg models.Package) string {
+	pkg = s.fullPackage(pkg)
+	var caveats string
+	switch {
+	case pkg.Formula != nil:
+		caveats, _ = pkg.Formula.Caveats.(string)
+	case pkg.Cask != nil:
+		caveats = pkg.Cask.Caveats
+	}
+	caveats = strings.TrimSpace(caveats)
+	if caveats == "" {
+		return ""
+	}
+
+	prefix := s.dataProvider.GetPrefixPath()
+	return strings.NewReplacer(
+		caveatsCellarPlaceholder, filepath.Join(prefix, "Cellar"),
+		caveatsPrefixPlaceholder, prefix,
+	).Replace(caveats)
+}
+
+// caveatsSnippet extracts the shell lines the caveats suggest running or adding to a shell profile:
+// Homebrew indents them under the sentence introducing them.
+func caveatsSnippet(caveats string) string {
+	var lines []string
+	for _, line := range strings.Split(caveats, "\n") {
+		if strings.HasPrefix(line, "  ") && strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// releasesAPIURL lists the most recent GitHub releases of a repository.
+	releasesAPIURL = "https://api.github.com/repos/%s/%s/releases?per_page=20"
+
+	// maxChangelogReleases caps the releases shown when the installed one can't be found.
+	maxChangelogReleases = 5
+)
+
+// githubRepoPattern extracts the owner and repository from a github.com URL.
+var githubRepoPattern = regexp.MustCompile(`github\.com/([\w.-]+)/([\w.-]+)`)
+
+// ChangelogServiceInterface defines the contract for fetching what an upgrade brings.
+type ChangelogServiceInterface interface {
+	GetReleaseNotes(ctx context.Context, pkg models.Package) (string, error)
+}
+
+// ChangelogService fetches upstream release notes, falling back to the tap history.
+type ChangelogService struct{}
+
+type githubRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	PublishedAt string `json:"published_at"`
+}
+
+// NewChangelogService creates a new instance of ChangelogService.
+var NewChangelogService = func() ChangelogServiceInterface {
+	return &ChangelogService{}
+}
+
+// GetReleaseNotes returns the changes between the installed and the latest version of a package.
+// It is best-effort: GitHub releases when the project lives on GitHub, otherwise the commit log
+// of the package definition in its tap.
+func (c *ChangelogService) GetReleaseNotes(ctx context.Context, pkg models.Package) (string, error) {
+	if owner, repo := githubRepo(pkg); owner != "" {
+		if notes, err := c.githubReleaseNotes(ctx, owner, repo, pkg); err == nil && notes != "" {
+			return notes, nil
+		}
+	}
+	return c.tapCommitLog(ctx, pkg)
+}
+
+// githubRepo returns the GitHub owner and repository of a package, looking at its homepage
+// and download URL, or empty strings if it isn't hosted on GitHub.
+func githubRepo(pkg models.Package) (string, string) {
+	candidates := []string{pkg.Homepage}
+	if pkg.Formula != nil {
+		candidates = append(candidates, pkg.Formula.Urls.Stable.URL)
+	} else if pkg.Cask != nil {
+		candidates = append(candidates, pkg.Cask.URL)
+	}
+
+	for _, url := range candidates {
+		if match := githubRepoPattern.FindStringSubmatch(url); match != nil {
+			return match[1], strings.TrimSuffix(match[2], ".git")
+		}
+	}
+	return "", ""
+}
+
+// githubReleaseNotes collects the GitHub releases newer than the installed version.
+func (c *ChangelogService) githubReleaseNotes(ctx context.Context, owner, repo string, pkg models.Package) (string, error) {
+	var releases []githubRelease
+	if err := fetchGitHubJSON(ctx, fmt.Sprintf(releasesAPIURL, owner, repo), &releases); err != nil {
+		return "", err
+	}
+
+	installed := pkg.InstalledVersion()
+	var sb strings.Builder
+	for i, release := range releases {
+		if installed != "" && normalizeReleaseTag(release.TagName, pkg.Name) == installed {
+			break
+		}
+		if installed == "" && i >= maxChangelogReleases {
+			break
+		}
+
+		title := release.TagName
+		if release.Name != "" && release.Name != release.TagName {
+			title = fmt.Sprintf("%s - %s", release.TagName, release.Name)
+		}
+		if date, _, found := strings.Cut(release.PublishedAt, "T"); found {
+			title = fmt.Sprintf("%s (%s)", title, date)
+		}
+		fmt.Fprintf(&sb, "## %s\n\n%s\n\n", title, strings.TrimSpace(release.Body))
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// normalizeReleaseTag turns a release tag into a plain version ("v1.2.3", "jq-1.2.3" → "1.2.3").
+func normalizeReleaseTag(tag, name string) string {
+	tag = strings.TrimPrefix(tag, name+"-")
+	return strings.TrimPrefix(tag, "v")
+}
+
+// tapCommitLog returns the recent commits touching the package definition in its local tap.
+func (c *ChangelogService) tapCommitLog(ctx context.Context, pkg models.Package) (string, error) {
+	var tap, sourcePath string
+	if pkg.Formula != nil {
+		tap, sourcePath = pkg.Formula.Tap, pkg.Formula.RubySourcePath
+	} else if pkg.Cask != nil {
+		tap, sourcePath = pkg.Cask.Tap, pkg.Cask.RubySourcePath
+	}
+	if tap == "" || sourcePath == "" {
+		return "", fmt.Errorf("no release notes source for %s", pkg.Name)
+	}
+
+	output, err := brewCommandContext(ctx, "--repository", tap).Output() // #nosec G204
+	if err != nil {
+		return "", fmt.Errorf("failed to locate tap %s: %v", tap, err)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "-C", strings.TrimSpace(string(output)), "log", // #nosec G204
+		"-n", "20", "--date=short", "--format=%ad  %s", "--", sourcePath)
+	output, err = cmd.Output()
+	if err != nil || len(strings.TrimSpace(string(output))) == 0 {
+		return "", fmt.Errorf("no history for %s in tap %s", pkg.Name, tap)
+	}
+	return fmt.Sprintf("%s (%s)\n\n%s", tap, sourcePath, strings.TrimSpace(string(output))), nil
+}
+
+// githubRateLimitError is returned when the GitHub API rate limit is exhausted.
+type githubRateLimitError struct {
+	reset time.Time
+}
+
+func (e *githubRateLimitError) Error() string {
+	return fmt.Sprintf("GitHub rate limit exceeded until %s", e.reset.Format("15:04"))
+}
+
+// fetchGitHubJSON performs a GitHub API request and decodes the JSON response into v.
+// The token from GITHUB_TOKEN or HOMEBREW_GITHUB_API_TOKEN is used if set, for a higher rate limit.
+func fetchGitHubJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	for _, name := range []string{"GITHUB_TOKEN", "HOMEBREW_GITHUB_API_TOKEN"} {
+		if token := os.Getenv(name); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+			break
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach GitHub: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+		resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset := time.Now().Add(time.Hour)
+		if epoch, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			reset = time.Unix(epoch, 0)
+		}
+		return &githubRateLimitError{reset: reset}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub returned HTTP %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse GitHub response: %v", err)
+	}
+	return nil
+}
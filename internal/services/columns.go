@@ -0,0 +1,333 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"path/filepath"
+
+	"github.com/rivo/tview"
+)
+
+// ColumnID identifies a table column in the config and the column picker.
+type ColumnID string
+
+const (
+	ColumnType             ColumnID = "type"
+	ColumnName             ColumnID = "name"
+	ColumnVersion          ColumnID = "version"
+	ColumnInstalledVersion ColumnID = "installed_version"
+	ColumnDescription      ColumnID = "description"
+	ColumnDownloads        ColumnID = "downloads"
+	ColumnSize             ColumnID = "size"
+	ColumnTap              ColumnID = "tap"
+	ColumnLicense          ColumnID = "license"
+	ColumnStars            ColumnID = "stars"
+	ColumnNote             ColumnID = "note"
+	ColumnFavorite         ColumnID = "favorite"
+	ColumnPolicy           ColumnID = "policy"
+)
+
+// defaultColumns is the column layout used when the config doesn't specify one.
+var defaultColumns = []ColumnID{ColumnType, ColumnName, ColumnVersion, ColumnDescription, ColumnDownloads}
+
+// columnSpec describes how a single table column is rendered.
+type columnSpec struct {
+	id        ColumnID
+	header    string
+	expansion int
+	render    func(s *AppService, info models.Package) *tview.TableCell
+}
+
+// columnSpecs lists every available column, in the order offered by the column picker.
+var columnSpecs = []columnSpec{
+	{id: ColumnType, header: "Type", render: renderTypeCell},
+	{id: ColumnName, header: "Name", render: renderNameCell},
+	{id: ColumnVersion, header: "Version", render: renderVersionCell},
+	{id: ColumnInstalledVersion, header: "Installed", render: renderInstalledVersionCell},
+	{id: ColumnDescription, header: "Description", expansion: 1, render: renderDescriptionCell},
+	{id: ColumnDownloads, header: "Downloads", render: renderDownloadsCell},
+	{id: ColumnSize, header: "Size", render: renderSizeCell},
+	{id: ColumnTap, header: "Tap", render: renderTapCell},
+	{id: ColumnLicense, header: "License", render: renderLicenseCell},
+	{id: ColumnStars, header: "Stars", render: renderStarsCell},
+	{id: ColumnNote, header: "Note", render: renderNoteCell},
+	{id: ColumnFavorite, header: "Fav", render: renderFavoriteCell},
+	{id: ColumnPolicy, header: "Policy", render: renderPolicyCell},
+}
+
+// getColumnSpec returns the spec for the given column ID, or nil if unknown.
+func getColumnSpec(id ColumnID) *columnSpec {
+	for i := range columnSpecs {
+		if columnSpecs[i].id == id {
+			return &columnSpecs[i]
+		}
+	}
+	return nil
+}
+
+// visibleColumns returns the specs of the configured columns, skipping unknown IDs.
+func (s *AppService) visibleColumns() []*columnSpec {
+	ids := s.config.Columns
+	if len(ids) == 0 {
+		ids = defaultColumns
+	}
+
+	columns := make([]*columnSpec, 0, len(ids))
+	for _, id := range ids {
+		if spec := getColumnSpec(id); spec != nil {
+			columns = append(columns, spec)
+		}
+	}
+	if len(columns) == 0 {
+		return s.defaultColumnSpecs()
+	}
+	return columns
+}
+
+// defaultColumnSpecs returns the specs of the default column layout.
+func (s *AppService) defaultColumnSpecs() []*columnSpec {
+	columns := make([]*columnSpec, 0, len(defaultColumns))
+	for _, id := range defaultColumns {
+		columns = append(columns, getColumnSpec(id))
+	}
+	return columns
+}
+
+// SetColumns updates the visible columns, persists the choice and redraws the table.
+func (s *AppService) SetColumns(ids []ColumnID) error {
+	s.config.Columns = ids
+	s.setResults(s.store.Filtered(), false)
+	return s.config.Save()
+}
+
+func renderTypeCell(_ *AppService, info models.Package) *tview.TableCell {
+	typeTag := tview.Escape("[F]") // Formula
+	if info.Type == models.PackageTypeCask {
+		typeTag = tview.Escape("[C]") // Cask
+	}
+	return tview.NewTableCell(typeTag).SetAlign(tview.AlignLeft)
+}
+
+func renderNameCell(s *AppService, info models.Package) *tview.TableCell {
+	name := components.HighlightMatches(s.theme, info.Name, s.highlight)
+	cell := tview.NewTableCell(name)
+	if s.isMarked(info) {
+		cell.SetText(s.theme.Symbols.Check + " " + name)
+	}
+	if info.LocallyInstalled {
+		cell.SetTextColor(s.theme.SuccessColor)
+	}
+	return cell
+}
+
+func renderVersionCell(s *AppService, info models.Package) *tview.TableCell {
+	// Held packages stay at their installed version, whatever the latest one
+	installed, latest := info.InstalledVersion(), info.LatestVersion()
+	if info.Outdated && s.isHeld(info) && installed != "" {
+		return tview.NewTableCell(tview.Escape(s.truncateVersion(installed) + " " + i18n.T("(held)"))).
+			SetTextColor(s.theme.WarningColor)
+	}
+
+	// For outdated packages, show what an update would change: "installed → latest"
+	if info.LocallyInstalled && info.Outdated && installed != "" && installed != latest {
+		return tview.NewTableCell(components.FormatVersionDelta(s.theme, s.truncateVersion(installed), s.truncateVersion(latest)))
+	}
+
+	cell := tview.NewTableCell(tview.Escape(s.truncateVersion(info.Version)))
+	if info.LocallyInstalled && info.Outdated {
+		cell.SetTextColor(s.theme.OutdatedColor)
+	}
+	return cell
+}
+
+func renderInstalledVersionCell(s *AppService, info models.Package) *tview.TableCell {
+	return tview.NewTableCell(tview.Escape(s.truncateVersion(info.InstalledVersion())))
+}
+
+func renderDescriptionCell(_ *AppService, info models.Package) *tview.TableCell {
+	return tview.NewTableCell(info.Description)
+}
+
+// renderDownloadsCell shows the 90 days downloads in a compact form (12.4k), or exact if configured.
+func renderDownloadsCell(s *AppService, info models.Package) *tview.TableCell {
+	downloads := i18n.CompactNumber(info.Analytics90dDownloads)
+	if s.config.ExactDownloads {
+		downloads = i18n.Number(info.Analytics90dDownloads)
+	}
+	return tview.NewTableCell(downloads).SetAlign(tview.AlignRight)
+}
+
+// renderSizeCell shows the disk usage of installed packages, or an ellipsis while it is measured.
+func renderSizeCell(s *AppService, info models.Package) *tview.TableCell {
+	size := ""
+	if info.LocallyInstalled {
+		size = s.theme.Symbols.Ellipsis
+		if bytes, known := s.installedSize(info); known {
+			size = formatBytes(bytes)
+		}
+	}
+	return tview.NewTableCell(size).SetAlign(tview.AlignRight)
+}
+
+func renderTapCell(s *AppService, info models.Package) *tview.TableCell {
+	cell := tview.NewTableCell(info.Tap())
+	if info.IsThirdPartyTap() {
+		cell.SetTextColor(s.theme.ThirdPartyColor)
+	}
+	return cell
+}
+
+func renderLicenseCell(_ *AppService, info models.Package) *tview.TableCell {
+	license := ""
+	if info.Formula != nil {
+		license = info.Formula.License
+	}
+	return tview.NewTableCell(license)
+}
+
+func renderStarsCell(s *AppService, info models.Package) *tview.TableCell {
+	stars := ""
+	if meta := s.github.Lookup(info); meta != nil {
+		stars = i18n.CompactNumber(meta.Stars)
+	}
+	return tview.NewTableCell(stars).SetAlign(tview.AlignRight)
+}
+
+// renderNoteCell shows the Brewfile comment of the entry, empty outside Brewfile mode.
+func renderNoteCell(_ *AppService, info models.Package) *tview.TableCell {
+	return tview.NewTableCell(tview.Escape(info.BrewfileComment))
+}
+
+// renderFavoriteCell shows a star for starred packages.
+func renderFavoriteCell(s *AppService, info models.Package) *tview.TableCell {
+	star := ""
+	if s.favorites.IsFavorite(info) {
+		star = s.theme.Symbols.Star
+	}
+	return tview.NewTableCell(star).SetTextColor(s.theme.WarningColor).SetAlign(tview.AlignCenter)
+}
+
+// renderPolicyCell shows the upgrade policy of the package, empty for the default one.
+func renderPolicyCell(s *AppService, info models.Package) *tview.TableCell {
+	policy := s.config.UpgradePolicyOf(info.Name)
+	cell := tview.NewTableCell(i18n.T(upgradePolicyTags[policy]))
+	if policy == PolicyHold {
+		cell.SetTextColor(s.theme.WarningColor)
+	}
+	return cell
+}
+
+// truncateVersion shortens long version strings so they don't dominate the table width.
+func (s *AppService) truncateVersion(version string) string {
+	const maxVersionLen = 15
+	if len(version) > maxVersionLen {
+		return version[:maxVersionLen-1] + s.theme.Symbols.Ellipsis
+	}
+	return version
+}
+
+// sizeMeasuring marks the packages of sizeCache being measured.
+const sizeMeasuring = -1
+
+// installedSize returns the disk usage of an installed package, and whether it is known yet.
+// Formulae are measured in the Cellar, casks in the Caskroom, in the background (see measureSizes).
+func (s *AppService) installedSize(info models.Package) (int64, bool) {
+	if size, exists := s.sizeCache[info.Name]; exists {
+		return size, size != sizeMeasuring
+	}
+
+	var path string
+	if info.Formula != nil && info.Formula.LocalPath != "" {
+		path = info.Formula.LocalPath
+	} else if info.Type == models.PackageTypeCask {
+		path = filepath.Join(s.dataProvider.GetPrefixPath(), "Caskroom", info.Name)
+	}
+	if path == "" {
+		s.sizeCache[info.Name] = 0
+		return 0, true
+	}
+
+	s.sizeCache[info.Name] = sizeMeasuring
+	s.queueSize(info.Name, path)
+	return 0, false
+}
+
+// queueSize queues a package to measure, starting a measure if none is waiting.
+func (s *AppService) queueSize(name, path string) {
+	if len(s.sizeQueue) == 0 {
+		go s.measureSizes()
+	}
+	s.sizeQueue[name] = path
+}
+
+// measureSizes measures the queued packages once the event loop is free (the table rendered),
+// then shows their sizes. The sizes invalidated meanwhile are measured again.
+func (s *AppService) measureSizes() {
+	defer RecoverCrash()
+	var queue map[string]string
+	var epoch int
+	s.app.QueueUpdate(func() {
+		queue, s.sizeQueue = s.sizeQueue, make(map[string]string)
+		epoch = s.sizeEpoch
+	})
+
+	sizes := make(map[string]int64, len(queue))
+	for name, path := range queue {
+		sizes[name] = diskUsage(path)
+	}
+
+	s.app.QueueUpdateDraw(func() {
+		for name, size := range sizes {
+			switch {
+			case s.sizeCache[name] != sizeMeasuring: // Invalidated, no longer shown
+			case epoch != s.sizeEpoch:
+				s.queueSize(name, queue[name])
+			default:
+				s.sizeCache[name] = size
+			}
+		}
+		s.updateSizeCells()
+	})
+}
+
+// invalidateSize forgets the size of a package, or of all packages when name is "", to measure it again.
+func (s *AppService) invalidateSize(name string) {
+	if name == "" {
+		s.sizeCache = make(map[string]int64)
+	} else {
+		delete(s.sizeCache, name)
+	}
+	s.sizeEpoch++
+}
+
+// updateSizeCells shows the measured sizes in the size column of the table, if shown.
+func (s *AppService) updateSizeCells() {
+	column := s.sizeColumn
+	if column < 0 {
+		return
+	}
+	for row := 1; row <= len(s.tableRows); row++ {
+		info, exists := s.packageAtRow(row)
+		if !exists || !info.LocallyInstalled {
+			continue
+		}
+		if size, known := s.installedSize(info); known {
+			s.layout.GetTable().View().GetCell(row, column).SetText(formatBytes(size))
+		}
+	}
+}
+
+// formatBytes formats a byte count as a human-readable size, with the decimal separator of the locale.
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return i18n.Printer().Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return i18n.Printer().Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
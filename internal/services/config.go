@@ -0,0 +1,165 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/adrg/xdg"
+)
+
+// Config file name
+const configFileName = "config.json"
+
+// Config holds the user preferences persisted across sessions.
+// Missing fields fall back to their defaults, so older config files keep working.
+type Config struct {
+	// Columns lists the table columns to display, in order (see ColumnID).
+	Columns []ColumnID `json:"columns,omitempty"`
+
+	// Language overrides the UI language detected from the environment (e.g. "it").
+	Language string `json:"language,omitempty"`
+
+	// HighContrast switches to a high-contrast color theme.
+	HighContrast bool `json:"high_contrast,omitempty"`
+
+	// ASCII replaces unicode symbols (bullets, arrows, separators) with plain ASCII.
+	ASCII bool `json:"ascii,omitempty"`
+
+	// RowSpacing makes the package table denser ("compact": no margins around the table and the search
+	// field) or more spaced out ("padded": a blank line between rows). One line per row by default.
+	RowSpacing string `json:"row_spacing,omitempty"`
+
+	// ReducedMotion replaces the moving progress bars with static counts, and turns off screen flashes,
+	// the progress of the terminal tab and desktop notifications, for users who find movement
+	// distracting or use a screen reader. The status line still reports each outcome.
+	ReducedMotion bool `json:"reduced_motion,omitempty"`
+
+	// DisableSecurityCheck turns off the OSV.dev advisory check of installed formulae.
+	DisableSecurityCheck bool `json:"disable_security_check,omitempty"`
+
+	// GitHubMetadata enables fetching stars, last push and archived status from GitHub.
+	GitHubMetadata bool `json:"github_metadata,omitempty"`
+
+	// ExpertMode skips the confirmation of single-package operations. Batch operations are still confirmed.
+	ExpertMode bool `json:"expert_mode,omitempty"`
+
+	// UpgradePolicies sets what Update All and `bbrew upgrade` do with each outdated package,
+	// by name (see UpgradePolicy). Packages without a policy are asked about.
+	UpgradePolicies map[string]UpgradePolicy `json:"upgrade_policies,omitempty"`
+
+	// Profiles are named package sets ("work", "gamedev", "minimal"), each backed by a Brewfile,
+	// opened with --profile or the profile picker (O).
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+
+	// Protected lists the packages that must not be removed by accident (e.g. git, openssl used by other
+	// tooling), by name: Remove asks to type their name, Remove All leaves them out.
+	Protected []string `json:"protected,omitempty"`
+
+	// Macros bind a key to a sequence of actions, by the keys that run them, e.g. {"Y": ["o", "ctrl+u"]}
+	// to list the outdated packages and update them all. Recorded with Q.
+	Macros map[string][]string `json:"macros,omitempty"`
+
+	// BundleMode delegates Install All and the Brewfile cleanup to `brew bundle`,
+	// for exact parity with it (mas entries, cask args, ...).
+	BundleMode bool `json:"bundle_mode,omitempty"`
+
+	// BrewfileLock writes Brewfile.lock.json next to the Brewfile after Install All and Install Group,
+	// with the installed versions. An existing lockfile is updated even without it. See `bbrew verify`.
+	BrewfileLock bool `json:"brewfile_lock,omitempty"`
+
+	// FormulaAPIv3 loads the formulae from Homebrew's smaller v3 index, fetching the details of
+	// each formula when it is shown. The v2 formula.json is used when the index is not available.
+	FormulaAPIv3 bool `json:"formula_api_v3,omitempty"`
+
+	// ExactDownloads shows the exact download counts in the table instead of the compact form (12.4k).
+	ExactDownloads bool `json:"exact_downloads,omitempty"`
+
+	// ParallelCasks installs up to this many casks at a time in Install All and Install Group,
+	// after the formulae. Casks don't depend on each other, unlike formulae. 0 or 1 installs one by one.
+	ParallelCasks int `json:"parallel_casks,omitempty"`
+
+	// CaskNoQuarantine installs casks with --no-quarantine, skipping the Gatekeeper check of their apps.
+	// It is the default of the install confirmation, where it can be changed for each install.
+	CaskNoQuarantine bool `json:"cask_no_quarantine,omitempty"`
+
+	// CompletionAlert rings the terminal bell ("bell") or flashes the screen ("flash") when an operation
+	// that ran for a while finishes, for users who switch windows meanwhile.
+	CompletionAlert string `json:"completion_alert,omitempty"`
+
+	// NotificationExpiry is how many seconds success messages stay in the status line, 5 by default.
+	// A negative value keeps them until replaced. Errors always stay until dismissed with Esc.
+	NotificationExpiry int `json:"notification_expiry,omitempty"`
+
+	// LogLevel sets the level of the log file in the state directory: debug, info, warn (default), error
+	// or off. --log-level overrides it.
+	LogLevel string `json:"log_level,omitempty"`
+
+	// LogJSON writes the log file as JSON lines instead of text.
+	LogJSON bool `json:"log_json,omitempty"`
+
+	// ReadOnly disables installing, updating and removing packages, to hand bbrew over
+	// for browsing an inventory without risk. Same as --read-only.
+	ReadOnly bool `json:"read_only,omitempty"`
+
+	// Telemetry is the consent to usage reporting by bbrew itself, off by default. bbrew reports nothing:
+	// the field is always written so that the choice is explicit in the config file. Homebrew's own
+	// analytics are a setting of brew, shown and toggled in the settings screen.
+	Telemetry bool `json:"telemetry"`
+
+	// Webhook reports the Brewfile batches and the scheduled upgrades to a URL, e.g. a Slack or Discord channel.
+	Webhook *WebhookConfig `json:"webhook,omitempty"`
+
+	// Hooks are shell commands run before and after installs and after Update All (see HooksConfig).
+	Hooks *HooksConfig `json:"hooks,omitempty"`
+}
+
+// getConfigDir returns the config directory following XDG Base Directory Specification.
+func getConfigDir() string {
+	return filepath.Join(xdg.ConfigHome, "bbrew")
+}
+
+// LoadConfig reads the user config, returning defaults if it doesn't exist or can't be parsed.
+func LoadConfig() *Config {
+	config := &Config{}
+
+	// #nosec G304 -- config path is safely constructed from getConfigDir
+	data, err := os.ReadFile(filepath.Join(getConfigDir(), configFileName))
+	if err != nil {
+		return config
+	}
+	_ = json.Unmarshal(data, config)
+	return config
+}
+
+// UpgradePolicyOf returns the upgrade policy of a package, PolicyAsk if none is set
+// or the value is unknown. The aliases "notify" and "never" are accepted.
+func (c *Config) UpgradePolicyOf(name string) UpgradePolicy {
+	policy := c.UpgradePolicies[name]
+	if alias, exists := upgradePolicyAliases[policy]; exists {
+		return alias
+	}
+	if _, known := upgradePolicyLabels[policy]; known {
+		return policy
+	}
+	return PolicyAsk
+}
+
+// IsProtected reports whether a package is protected from removal.
+func (c *Config) IsProtected(name string) bool {
+	return slices.Contains(c.Protected, name)
+}
+
+// Save writes the config to disk, creating the config directory if needed.
+func (c *Config) Save() error {
+	if err := os.MkdirAll(getConfigDir(), 0750); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(getConfigDir(), configFileName), data, 0600)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"bbrew/internal/logging"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// crashLogLines is the number of recent log lines included in crash reports.
+const crashLogLines = 50
+
+var (
+	crashMu          sync.Mutex
+	crashTerminal    TerminalServiceInterface // Restored on a crash, nil before the TUI is built
+	crashBrewVersion string
+)
+
+// watchCrashes registers the terminal to restore on a crash, and the Homebrew version to report.
+func watchCrashes(terminal TerminalServiceInterface, brewVersion string) {
+	crashMu.Lock()
+	defer crashMu.Unlock()
+	crashTerminal, crashBrewVersion = terminal, brewVersion
+}
+
+// RecoverCrash handles a panic of the calling goroutine: it restores the terminal, writes a crash report
+// to the state directory and exits, printing its path. It must be deferred at the top of main and of
+// every goroutine, since a panic in any of them would leave the terminal in raw mode.
+func RecoverCrash() {
+	value := recover()
+	if value == nil {
+		return
+	}
+	stack := debug.Stack()
+
+	// Never unlocked: the first crash is reported, other panicking goroutines wait for the exit
+	crashMu.Lock()
+	if crashTerminal != nil {
+		crashTerminal.Restore()
+	}
+
+	appLog.Error("crash", "panic", fmt.Sprint(value))
+	fmt.Fprintf(os.Stderr, "Bold Brew crashed: %v\n", value)
+	if path, err := writeCrashReport(value, stack); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write the crash report: %v\n\n%s", err, stack)
+	} else {
+		fmt.Fprintf(os.Stderr, "A crash report was written to %s\n", path)
+		fmt.Fprintf(os.Stderr, "Please attach it to an issue: https://github.com/Valkyrie00/bold-brew/issues\n")
+	}
+	os.Exit(2)
+}
+
+// writeCrashReport saves the panic, its stack, the versions and the recent log lines to the state directory.
+func writeCrashReport(value any, stack []byte) (string, error) {
+	now := time.Now()
+	var report strings.Builder
+	fmt.Fprintf(&report, "%s crash report, %s\n\n", AppName, now.Format(time.RFC3339))
+	fmt.Fprintf(&report, "Version:  %s\n", AppVersion)
+	if crashBrewVersion != "" {
+		fmt.Fprintf(&report, "Homebrew: %s\n", crashBrewVersion)
+	}
+	fmt.Fprintf(&report, "Go:       %s %s/%s\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&report, "panic: %v\n\n%s\n", value, stack)
+
+	if lines := logging.Tail(crashLogLines); len(lines) > 0 {
+		fmt.Fprintf(&report, "Recent log lines:\n%s\n", strings.Join(lines, "\n"))
+	}
+
+	filename := fmt.Sprintf("crash-%s.txt", now.Format("20060102-150405"))
+	if err := writeStateFile(filename, []byte(report.String())); err != nil {
+		return "", err
+	}
+	return filepath.Join(getStateDir(), filename), nil
+}
@@ -1,16 +1,20 @@
 package services
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/models"
+	"bbrew/internal/version"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // API URLs for Homebrew data
@@ -37,14 +41,22 @@ const (
 type DataProviderInterface interface {
 	// Setup and retrieval
 	SetupData(forceRefresh bool) error
+	SetReadOnly(readOnly bool)
+	SetInstalledOnly(installedOnly bool)
 	GetPackages() *[]models.Package
+	GetPrefixPath() string
 
 	// Installation status checks (runs brew list command)
 	FetchInstalledCaskNames() map[string]bool
 	FetchInstalledFormulaNames() map[string]bool
 
 	// Tap packages - gets from cache or fetches via brew info
-	GetTapPackages(entries []models.BrewfileEntry, existingPackages map[string]models.Package, forceRefresh bool) ([]models.Package, error)
+	GetTapPackages(entries []models.BrewfileEntry, existingPackages []models.Package, forceRefresh bool) ([]models.Package, error)
+
+	// Partial packages (v3 index, summaries) are completed on demand
+	SetFormulaAPIv3(enabled bool)
+	FetchFormula(name, version string) (*models.Formula, error)
+	FetchCask(token, version string) (*models.Cask, error)
 }
 
 // DataProvider implements DataProviderInterface.
@@ -60,10 +72,20 @@ type DataProvider struct {
 	remoteCasks    *[]models.Cask
 	caskAnalytics  map[string]models.AnalyticsItem
 
-	// Unified package list
-	allPackages *[]models.Package
+	// Unified package list, rebuilt only when its inputs change
+	allPackages        *[]models.Package
+	sources            map[string]uint64         // Hash of the data last loaded for each input, by cache file
+	generation         dataGeneration            // Changes of the inputs
+	packagesGeneration dataGeneration            // Inputs allPackages was built from
+	displaced          map[string]models.Package // Remote packages replaced by their installed version, by name
 
 	prefixPath string
+	readOnly   bool // Homebrew is not available: only the API data is loaded
+
+	installedOnly bool // Only the installed packages are loaded, without the API data (--installed-only)
+
+	formulaAPIv3 bool        // Load the formulae from the v3 index, falling back to formula.json
+	details      detailIndex // Where the full remote packages are in the cache, see decodeEntries
 }
 
 // NewDataProvider creates a new DataProvider instance with initialized data structures.
@@ -74,11 +96,14 @@ func NewDataProvider() *DataProvider {
 		installedCasks:    new([]models.Cask),
 		remoteCasks:       new([]models.Cask),
 		allPackages:       new([]models.Package),
+		sources:           make(map[string]uint64),
+		displaced:         make(map[string]models.Package),
 	}
 }
 
 // fetchFromAPI downloads data from a URL.
 func fetchFromAPI(url string) ([]byte, error) {
+	defer startup.begin("API fetch " + strings.TrimPrefix(url, "https://formulae.brew.sh/api/"))()
 	resp, err := http.Get(url) // #nosec G107 - URLs are internal constants
 	if err != nil {
 		return nil, err
@@ -87,12 +112,12 @@ func fetchFromAPI(url string) ([]byte, error) {
 	return io.ReadAll(resp.Body)
 }
 
-// getPrefixPath returns the Homebrew prefix path, caching it.
-func (d *DataProvider) getPrefixPath() string {
+// GetPrefixPath returns the Homebrew prefix path, caching it.
+func (d *DataProvider) GetPrefixPath() string {
 	if d.prefixPath != "" {
 		return d.prefixPath
 	}
-	cmd := exec.Command("brew", "--prefix")
+	cmd := brewCommand("--prefix")
 	output, err := cmd.Output()
 	if err != nil {
 		d.prefixPath = "Unknown"
@@ -111,32 +136,36 @@ func (d *DataProvider) GetInstalledFormulae(forceRefresh bool) ([]models.Formula
 	if !forceRefresh {
 		if data := readCacheFile(cacheFileInstalled, 10); data != nil {
 			var formulae []models.Formula
-			if err := json.Unmarshal(data, &formulae); err == nil {
+			if err := decodeJSON(cacheFileInstalled, data, &formulae); err == nil {
 				d.markFormulaeAsInstalled(&formulae)
+				d.track(cacheFileInstalled, data)
 				return formulae, nil
 			}
 		}
 	}
 
-	cmd := exec.Command("brew", "info", "--json=v1", "--installed")
+	endStage := startup.begin("brew info --installed")
+	cmd := brewCommand("info", "--json=v1", "--installed")
 	output, err := cmd.Output()
+	endStage()
 	if err != nil {
 		return nil, err
 	}
 
 	var formulae []models.Formula
-	if err := json.Unmarshal(output, &formulae); err != nil {
+	if err := decodeJSON(cacheFileInstalled, output, &formulae); err != nil {
 		return nil, err
 	}
 
 	d.markFormulaeAsInstalled(&formulae)
+	d.track(cacheFileInstalled, output)
 	writeCacheFile(cacheFileInstalled, output)
 	return formulae, nil
 }
 
 // markFormulaeAsInstalled sets LocallyInstalled and LocalPath for formulae.
 func (d *DataProvider) markFormulaeAsInstalled(formulae *[]models.Formula) {
-	prefix := d.getPrefixPath()
+	prefix := d.GetPrefixPath()
 	for i := range *formulae {
 		(*formulae)[i].LocallyInstalled = true
 		(*formulae)[i].LocalPath = filepath.Join(prefix, "Cellar", (*formulae)[i].Name)
@@ -154,41 +183,49 @@ func (d *DataProvider) GetInstalledCasks(forceRefresh bool) ([]models.Cask, erro
 			var response struct {
 				Casks []models.Cask `json:"casks"`
 			}
-			if err := json.Unmarshal(data, &response); err == nil {
+			if err := decodeJSON(cacheFileInstalledCasks, data, &response); err == nil {
 				d.markCasksAsInstalled(&response.Casks)
+				d.track(cacheFileInstalledCasks, data)
 				return response.Casks, nil
 			}
 		}
 	}
 
 	// Get list of installed cask names
-	listCmd := exec.Command("brew", "list", "--cask")
+	endStage := startup.begin("brew list/info --cask")
+	defer endStage()
+	listCmd := brewCommand("list", "--cask")
 	listOutput, err := listCmd.Output()
 	if err != nil {
+		d.track(cacheFileInstalledCasks, nil)
 		return []models.Cask{}, nil // No casks installed
 	}
 
 	caskNames := strings.Split(strings.TrimSpace(string(listOutput)), "\n")
 	if len(caskNames) == 0 || (len(caskNames) == 1 && caskNames[0] == "") {
+		d.track(cacheFileInstalledCasks, nil)
 		return []models.Cask{}, nil
 	}
 
 	// Get info for each installed cask
 	args := append([]string{"info", "--json=v2", "--cask"}, caskNames...)
-	infoCmd := exec.Command("brew", args...)
+	infoCmd := brewCommand(args...)
 	infoOutput, err := infoCmd.Output()
 	if err != nil {
+		d.track(cacheFileInstalledCasks, nil)
 		return []models.Cask{}, nil
 	}
+	endStage()
 
 	var response struct {
 		Casks []models.Cask `json:"casks"`
 	}
-	if err := json.Unmarshal(infoOutput, &response); err != nil {
+	if err := decodeJSON(cacheFileInstalledCasks, infoOutput, &response); err != nil {
 		return nil, err
 	}
 
 	d.markCasksAsInstalled(&response.Casks)
+	d.track(cacheFileInstalledCasks, infoOutput)
 	writeCacheFile(cacheFileInstalledCasks, infoOutput)
 	return response.Casks, nil
 }
@@ -201,7 +238,7 @@ func (d *DataProvider) markCasksAsInstalled(casks *[]models.Cask) {
 	}
 }
 
-// GetRemoteFormulae retrieves remote formulae from API, optionally using cache.
+// GetRemoteFormulae retrieves remote formulae from API as summaries, optionally using cache.
 func (d *DataProvider) GetRemoteFormulae(forceRefresh bool) ([]models.Formula, error) {
 	if err := ensureCacheDir(); err != nil {
 		return nil, err
@@ -209,8 +246,8 @@ func (d *DataProvider) GetRemoteFormulae(forceRefresh bool) ([]models.Formula, e
 
 	if !forceRefresh {
 		if data := readCacheFile(cacheFileFormulae, 1000); data != nil {
-			var formulae []models.Formula
-			if err := json.Unmarshal(data, &formulae); err == nil && len(formulae) > 0 {
+			if formulae, err := d.decodeFormulaSummaries(data); err == nil && len(formulae) > 0 {
+				d.track(cacheFileFormulae, data)
 				return formulae, nil
 			}
 		}
@@ -221,16 +258,17 @@ func (d *DataProvider) GetRemoteFormulae(forceRefresh bool) ([]models.Formula, e
 		return nil, err
 	}
 
-	var formulae []models.Formula
-	if err := json.Unmarshal(body, &formulae); err != nil {
+	formulae, err := d.decodeFormulaSummaries(body)
+	if err != nil {
 		return nil, err
 	}
 
+	d.track(cacheFileFormulae, body)
 	writeCacheFile(cacheFileFormulae, body)
 	return formulae, nil
 }
 
-// GetRemoteCasks retrieves remote casks from API, optionally using cache.
+// GetRemoteCasks retrieves remote casks from API as summaries, optionally using cache.
 func (d *DataProvider) GetRemoteCasks(forceRefresh bool) ([]models.Cask, error) {
 	if err := ensureCacheDir(); err != nil {
 		return nil, err
@@ -238,8 +276,8 @@ func (d *DataProvider) GetRemoteCasks(forceRefresh bool) ([]models.Cask, error)
 
 	if !forceRefresh {
 		if data := readCacheFile(cacheFileCasks, 1000); data != nil {
-			var casks []models.Cask
-			if err := json.Unmarshal(data, &casks); err == nil && len(casks) > 0 {
+			if casks, err := d.decodeCaskSummaries(data); err == nil && len(casks) > 0 {
+				d.track(cacheFileCasks, data)
 				return casks, nil
 			}
 		}
@@ -250,11 +288,12 @@ func (d *DataProvider) GetRemoteCasks(forceRefresh bool) ([]models.Cask, error)
 		return nil, err
 	}
 
-	var casks []models.Cask
-	if err := json.Unmarshal(body, &casks); err != nil {
+	casks, err := d.decodeCaskSummaries(body)
+	if err != nil {
 		return nil, err
 	}
 
+	d.track(cacheFileCasks, body)
 	writeCacheFile(cacheFileCasks, body)
 	return casks, nil
 }
@@ -268,11 +307,12 @@ func (d *DataProvider) GetFormulaeAnalytics(forceRefresh bool) (map[string]model
 	if !forceRefresh {
 		if data := readCacheFile(cacheFileAnalytics, 100); data != nil {
 			analytics := models.Analytics{}
-			if err := json.Unmarshal(data, &analytics); err == nil && len(analytics.Items) > 0 {
+			if err := decodeJSON(cacheFileAnalytics, data, &analytics); err == nil && len(analytics.Items) > 0 {
 				result := make(map[string]models.AnalyticsItem)
 				for _, f := range analytics.Items {
 					result[f.Formula] = f
 				}
+				d.track(cacheFileAnalytics, data)
 				return result, nil
 			}
 		}
@@ -284,7 +324,7 @@ func (d *DataProvider) GetFormulaeAnalytics(forceRefresh bool) (map[string]model
 	}
 
 	analytics := models.Analytics{}
-	if err := json.Unmarshal(body, &analytics); err != nil {
+	if err := decodeJSON(cacheFileAnalytics, body, &analytics); err != nil {
 		return nil, err
 	}
 
@@ -293,6 +333,7 @@ func (d *DataProvider) GetFormulaeAnalytics(forceRefresh bool) (map[string]model
 		result[f.Formula] = f
 	}
 
+	d.track(cacheFileAnalytics, body)
 	writeCacheFile(cacheFileAnalytics, body)
 	return result, nil
 }
@@ -306,13 +347,14 @@ func (d *DataProvider) GetCaskAnalytics(forceRefresh bool) (map[string]models.An
 	if !forceRefresh {
 		if data := readCacheFile(cacheFileCaskAnalytics, 100); data != nil {
 			analytics := models.Analytics{}
-			if err := json.Unmarshal(data, &analytics); err == nil && len(analytics.Items) > 0 {
+			if err := decodeJSON(cacheFileCaskAnalytics, data, &analytics); err == nil && len(analytics.Items) > 0 {
 				result := make(map[string]models.AnalyticsItem)
 				for _, c := range analytics.Items {
 					if c.Cask != "" {
 						result[c.Cask] = c
 					}
 				}
+				d.track(cacheFileCaskAnalytics, data)
 				return result, nil
 			}
 		}
@@ -324,7 +366,7 @@ func (d *DataProvider) GetCaskAnalytics(forceRefresh bool) (map[string]models.An
 	}
 
 	analytics := models.Analytics{}
-	if err := json.Unmarshal(body, &analytics); err != nil {
+	if err := decodeJSON(cacheFileCaskAnalytics, body, &analytics); err != nil {
 		return nil, err
 	}
 
@@ -335,51 +377,48 @@ func (d *DataProvider) GetCaskAnalytics(forceRefresh bool) (map[string]models.An
 		}
 	}
 
+	d.track(cacheFileCaskAnalytics, body)
 	writeCacheFile(cacheFileCaskAnalytics, body)
 	return result, nil
 }
 
 // GetTapPackages retrieves package info for third-party tap entries.
 // It checks cache first, then fetches missing packages via `brew info`.
-// Results are cached for faster subsequent lookups.
-func (d *DataProvider) GetTapPackages(entries []models.BrewfileEntry, existingPackages map[string]models.Package, forceRefresh bool) ([]models.Package, error) {
+// Results are cached for faster subsequent lookups. Packages that can't be fetched
+// are returned as placeholders, and the fetch errors are joined in the returned error.
+func (d *DataProvider) GetTapPackages(entries []models.BrewfileEntry, existingPackages []models.Package, forceRefresh bool) ([]models.Package, error) {
 	if len(entries) == 0 {
 		return nil, nil
 	}
 
 	result := make([]models.Package, 0)
-	foundPackages := make(map[string]bool)
 
 	// 1. Get from cache (if not forceRefresh)
-	cachedPackages := make(map[string]models.Package)
+	var cachedPackages []models.Package
 	if !forceRefresh {
 		if data := readCacheFile(cacheFileTapPackages, 10); data != nil {
-			var packages []models.Package
-			if err := json.Unmarshal(data, &packages); err == nil {
-				for _, pkg := range packages {
-					cachedPackages[pkg.Name] = pkg
-				}
-			}
+			_ = decodeJSON(cacheFileTapPackages, data, &cachedPackages)
 		}
 	}
 
 	// 2. Collect packages from existingPackages (already loaded from APIs)
-	// and packages from cache, tracking what we still need to fetch
+	// and packages from cache, tracking what we still need to fetch.
+	// Entries may use the short or the full name ("user/repo/tool").
+	existing := newPackageIndex(existingPackages)
+	cached := newPackageIndex(cachedPackages)
 	var missingCasks []string
 	var missingFormulae []string
 
 	for _, entry := range entries {
 		// Check if already in existingPackages (from API)
-		if pkg, exists := existingPackages[entry.Name]; exists {
+		if pkg, exists := existing.lookup(entry.Name, entry.IsCask); exists {
 			result = append(result, pkg)
-			foundPackages[entry.Name] = true
 			continue
 		}
 
 		// Check if in cache
-		if pkg, exists := cachedPackages[entry.Name]; exists {
+		if pkg, exists := cached.lookup(entry.Name, entry.IsCask); exists {
 			result = append(result, pkg)
-			foundPackages[entry.Name] = true
 			continue
 		}
 
@@ -391,39 +430,13 @@ func (d *DataProvider) GetTapPackages(entries []models.BrewfileEntry, existingPa
 		}
 	}
 
-	// 3. Fetch missing packages via brew info
-	if len(missingCasks) > 0 {
-		fetched := d.fetchPackagesInfo(missingCasks, true)
-		for _, name := range missingCasks {
-			if pkg, exists := fetched[name]; exists {
-				result = append(result, pkg)
-			} else {
-				// Fallback for packages that couldn't be fetched
-				result = append(result, models.Package{
-					Name:        name,
-					DisplayName: name,
-					Description: "(unable to load package info)",
-					Type:        models.PackageTypeCask,
-				})
-			}
-		}
+	// 3. Fetch missing packages via brew info, concurrently
+	fetched, err := d.fetchPackagesInfo(missingCasks, missingFormulae)
+	for _, name := range missingCasks {
+		result = append(result, tapPackageOrPlaceholder(fetched, name, models.PackageTypeCask))
 	}
-
-	if len(missingFormulae) > 0 {
-		fetched := d.fetchPackagesInfo(missingFormulae, false)
-		for _, name := range missingFormulae {
-			if pkg, exists := fetched[name]; exists {
-				result = append(result, pkg)
-			} else {
-				// Fallback for packages that couldn't be fetched
-				result = append(result, models.Package{
-					Name:        name,
-					DisplayName: name,
-					Description: "(unable to load package info)",
-					Type:        models.PackageTypeFormula,
-				})
-			}
-		}
+	for _, name := range missingFormulae {
+		result = append(result, tapPackageOrPlaceholder(fetched, name, models.PackageTypeFormula))
 	}
 
 	// 4. Save all tap packages to cache
@@ -435,109 +448,195 @@ func (d *DataProvider) GetTapPackages(entries []models.BrewfileEntry, existingPa
 		}
 	}
 
-	return result, nil
+	return result, err
 }
 
-// fetchPackagesInfo retrieves package info via brew info command.
-func (d *DataProvider) fetchPackagesInfo(names []string, isCask bool) map[string]models.Package {
-	result := make(map[string]models.Package)
-	if len(names) == 0 {
-		return result
+// tapPackageOrPlaceholder returns the fetched package, or a placeholder for packages that couldn't be fetched.
+func tapPackageOrPlaceholder(fetched packageIndex, name string, packageType models.PackageType) models.Package {
+	if pkg, exists := fetched.lookup(name, packageType == models.PackageTypeCask); exists {
+		return pkg
 	}
-
-	var cmd *exec.Cmd
-	if isCask {
-		args := append([]string{"info", "--json=v2", "--cask"}, names...)
-		cmd = exec.Command("brew", args...)
-	} else {
-		args := append([]string{"info", "--json=v1"}, names...)
-		cmd = exec.Command("brew", args...)
+	return models.Package{
+		Name:        models.ShortName(name),
+		FullName:    name,
+		DisplayName: name,
+		Description: "(unable to load package info)",
+		Type:        packageType,
 	}
+}
 
-	output, err := cmd.Output()
-	if err != nil {
-		// Try individual fetches as fallback
-		for _, name := range names {
-			if pkg := d.fetchSinglePackageInfo(name, isCask); pkg != nil {
-				result[name] = *pkg
-			}
+// tapFetchJob is a single `brew info` call for one or more packages of the same type.
+type tapFetchJob struct {
+	names  []string
+	isCask bool
+}
+
+const (
+	tapFetchWorkers   = 4  // Concurrent `brew info` processes
+	tapFetchBatchSize = 10 // Packages requested per `brew info` call
+)
+
+// fetchPackagesInfo retrieves package info via brew info commands, run concurrently by a bounded worker pool.
+// Batches that fail (e.g. because of a single unknown package) are retried one package at a time.
+func (d *DataProvider) fetchPackagesInfo(casks, formulae []string) (packageIndex, error) {
+	var batches []tapFetchJob
+	for _, group := range []tapFetchJob{{names: casks, isCask: true}, {names: formulae, isCask: false}} {
+		for start := 0; start < len(group.names); start += tapFetchBatchSize {
+			end := min(start+tapFetchBatchSize, len(group.names))
+			batches = append(batches, tapFetchJob{names: group.names[start:end], isCask: group.isCask})
 		}
-		return result
 	}
 
-	if isCask {
-		var response struct {
-			Casks []models.Cask `json:"casks"`
-		}
-		if err := json.Unmarshal(output, &response); err == nil {
-			for _, cask := range response.Casks {
-				c := cask
-				pkg := models.NewPackageFromCask(&c)
-				result[c.Token] = pkg
+	var mu sync.Mutex
+	var fetched []models.Package
+	var retries []tapFetchJob
+	runBounded(len(batches), tapFetchWorkers, func(i int) {
+		job := batches[i]
+		packages, err := fetchPackagesBatch(job.names, job.isCask)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			for _, name := range job.names {
+				retries = append(retries, tapFetchJob{names: []string{name}, isCask: job.isCask})
 			}
+			return
 		}
-	} else {
-		var formulae []models.Formula
-		if err := json.Unmarshal(output, &formulae); err == nil {
-			for _, formula := range formulae {
-				f := formula
-				pkg := models.NewPackageFromFormula(&f)
-				result[f.Name] = pkg
-			}
+		fetched = append(fetched, packages...)
+	})
+
+	var errs []error
+	runBounded(len(retries), tapFetchWorkers, func(i int) {
+		job := retries[i]
+		packages, err := fetchPackagesBatch(job.names, job.isCask)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil && len(packages) == 0 {
+			err = fmt.Errorf("no package info returned")
 		}
-	}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", job.names[0], err))
+			return
+		}
+		fetched = append(fetched, packages[0])
+	})
 
-	return result
+	return newPackageIndex(fetched), errors.Join(errs...)
 }
 
-// fetchSinglePackageInfo fetches info for a single package.
-func (d *DataProvider) fetchSinglePackageInfo(name string, isCask bool) *models.Package {
+// fetchPackagesBatch fetches info for the given packages with a single brew info call.
+func fetchPackagesBatch(names []string, isCask bool) ([]models.Package, error) {
 	var cmd *exec.Cmd
 	if isCask {
-		cmd = exec.Command("brew", "info", "--json=v2", "--cask", name)
+		args := append([]string{"info", "--json=v2", "--cask"}, names...)
+		cmd = brewCommand(args...)
 	} else {
-		cmd = exec.Command("brew", "info", "--json=v1", name)
+		args := append([]string{"info", "--json=v1"}, names...)
+		cmd = brewCommand(args...)
 	}
 
 	output, err := cmd.Output()
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
+	var packages []models.Package
 	if isCask {
 		var response struct {
 			Casks []models.Cask `json:"casks"`
 		}
-		if err := json.Unmarshal(output, &response); err != nil || len(response.Casks) == 0 {
-			return nil
+		if err := json.Unmarshal(output, &response); err != nil {
+			return nil, err
+		}
+		for i := range response.Casks {
+			packages = append(packages, models.NewPackageFromCask(&response.Casks[i]))
 		}
-		pkg := models.NewPackageFromCask(&response.Casks[0])
-		return &pkg
+		return packages, nil
 	}
 
 	var formulae []models.Formula
-	if err := json.Unmarshal(output, &formulae); err != nil || len(formulae) == 0 {
-		return nil
+	if err := json.Unmarshal(output, &formulae); err != nil {
+		return nil, err
+	}
+	for i := range formulae {
+		packages = append(packages, models.NewPackageFromFormula(&formulae[i]))
 	}
-	pkg := models.NewPackageFromFormula(&formulae[0])
-	return &pkg
+	return packages, nil
+}
+
+// runBounded calls fn for each index in [0, n), with at most workers calls running at a time.
+func runBounded(n, workers int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer RecoverCrash()
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// SetReadOnly switches to read-only mode, for machines without Homebrew:
+// installed packages are not queried, and only the formulae.brew.sh API data is loaded.
+func (d *DataProvider) SetReadOnly(readOnly bool) {
+	d.readOnly = readOnly
+}
+
+// SetInstalledOnly switches to installed-only mode, for a fast startup: the formulae.brew.sh API data
+// (catalogs and analytics) is neither downloaded nor parsed, only the installed packages are loaded.
+// Outdated packages are the ones `brew info` reports.
+func (d *DataProvider) SetInstalledOnly(installedOnly bool) {
+	d.installedOnly = installedOnly
 }
 
 // SetupData initializes the DataProvider by loading all package data.
 func (d *DataProvider) SetupData(forceRefresh bool) error {
 	// Get installed formulae
-	installed, err := d.GetInstalledFormulae(forceRefresh)
-	if err != nil {
-		return fmt.Errorf("failed to get installed formulae: %w", err)
+	if !d.readOnly {
+		installed, err := d.GetInstalledFormulae(forceRefresh)
+		if err != nil {
+			return fmt.Errorf("failed to get installed formulae: %w", err)
+		}
+		*d.installedFormulae = installed
 	}
-	*d.installedFormulae = installed
 
-	// Get remote formulae
-	remote, err := d.GetRemoteFormulae(forceRefresh)
-	if err != nil {
-		return fmt.Errorf("failed to get remote formulae: %w", err)
+	// Installed-only mode: the installed casks are all that is left to load
+	if d.installedOnly {
+		installedCasks, err := d.GetInstalledCasks(forceRefresh)
+		if err != nil {
+			return fmt.Errorf("failed to get installed casks: %w", err)
+		}
+		*d.installedCasks = installedCasks
+		return nil
+	}
+
+	// Get remote formulae, from the smaller v3 index if enabled
+	loadedV3 := false
+	if d.formulaAPIv3 {
+		if remote, err := d.getRemoteFormulaeV3(forceRefresh); err != nil {
+			appLog.Warn("v3 formula index unavailable, falling back to formula.json", "err", err)
+		} else {
+			*d.remoteFormulae = remote
+			loadedV3 = true
+		}
+	}
+	if !loadedV3 {
+		remote, err := d.GetRemoteFormulae(forceRefresh)
+		if err != nil {
+			return fmt.Errorf("failed to get remote formulae: %w", err)
+		}
+		*d.remoteFormulae = remote
 	}
-	*d.remoteFormulae = remote
 
 	// Get formulae analytics
 	analytics, err := d.GetFormulaeAnalytics(forceRefresh)
@@ -547,11 +646,13 @@ func (d *DataProvider) SetupData(forceRefresh bool) error {
 	d.formulaeAnalytics = analytics
 
 	// Get installed casks
-	installedCasks, err := d.GetInstalledCasks(forceRefresh)
-	if err != nil {
-		return fmt.Errorf("failed to get installed casks: %w", err)
+	if !d.readOnly {
+		installedCasks, err := d.GetInstalledCasks(forceRefresh)
+		if err != nil {
+			return fmt.Errorf("failed to get installed casks: %w", err)
+		}
+		*d.installedCasks = installedCasks
 	}
-	*d.installedCasks = installedCasks
 
 	// Get remote casks
 	remoteCasks, err := d.GetRemoteCasks(forceRefresh)
@@ -570,56 +671,67 @@ func (d *DataProvider) SetupData(forceRefresh bool) error {
 	return nil
 }
 
+// dataGeneration counts the changes of the inputs of the package list: the remote data
+// (formulae, casks and their analytics) and the installed packages.
+type dataGeneration struct {
+	remote, installed int
+}
+
+// track records the data loaded for an input of the package list, counting a change if it differs from the last one.
+func (d *DataProvider) track(source string, data []byte) {
+	hash := fnv.New64a()
+	_, _ = hash.Write(data)
+	sum := hash.Sum64()
+	if previous, loaded := d.sources[source]; loaded && previous == sum {
+		return
+	}
+
+	d.sources[source] = sum
+	if source == cacheFileInstalled || source == cacheFileInstalledCasks {
+		d.generation.installed++
+	} else {
+		d.generation.remote++
+	}
+}
+
 // GetPackages retrieves all packages (formulae + casks), merging remote and installed.
+// The list is cached: it is returned as is while its inputs don't change, and when only the installed
+// packages changed, they are updated in place instead of merging and sorting everything again.
 func (d *DataProvider) GetPackages() *[]models.Package {
+	switch {
+	case d.packagesGeneration == d.generation:
+	case d.packagesGeneration.remote == d.generation.remote && d.updateInstalledPackages():
+	default:
+		d.buildPackages()
+	}
+	d.packagesGeneration = d.generation
+	return d.allPackages
+}
+
+// buildPackages merges the remote and installed packages into a new list, sorted by name.
+func (d *DataProvider) buildPackages() {
 	packageMap := make(map[string]models.Package)
+	d.displaced = make(map[string]models.Package)
 
 	for _, formula := range *d.remoteFormulae {
 		if _, exists := packageMap[formula.Name]; !exists {
 			f := formula
-			pkg := models.NewPackageFromFormula(&f)
-			if a, exists := d.formulaeAnalytics[formula.Name]; exists && a.Number > 0 {
-				downloads, _ := strconv.Atoi(strings.ReplaceAll(a.Count, ",", ""))
-				pkg.Analytics90dRank = a.Number
-				pkg.Analytics90dDownloads = downloads
-			}
-			packageMap[formula.Name] = pkg
+			packageMap[formula.Name] = d.withAnalytics(models.NewPackageFromFormula(&f))
 		}
 	}
-
-	for _, formula := range *d.installedFormulae {
-		f := formula
-		pkg := models.NewPackageFromFormula(&f)
-		if a, exists := d.formulaeAnalytics[formula.Name]; exists && a.Number > 0 {
-			downloads, _ := strconv.Atoi(strings.ReplaceAll(a.Count, ",", ""))
-			pkg.Analytics90dRank = a.Number
-			pkg.Analytics90dDownloads = downloads
-		}
-		packageMap[formula.Name] = pkg
-	}
-
 	for _, cask := range *d.remoteCasks {
 		if _, exists := packageMap[cask.Token]; !exists {
 			c := cask
-			pkg := models.NewPackageFromCask(&c)
-			if a, exists := d.caskAnalytics[cask.Token]; exists && a.Number > 0 {
-				downloads, _ := strconv.Atoi(strings.ReplaceAll(a.Count, ",", ""))
-				pkg.Analytics90dRank = a.Number
-				pkg.Analytics90dDownloads = downloads
-			}
-			packageMap[cask.Token] = pkg
+			packageMap[cask.Token] = d.withAnalytics(models.NewPackageFromCask(&c))
 		}
 	}
 
-	for _, cask := range *d.installedCasks {
-		c := cask
-		pkg := models.NewPackageFromCask(&c)
-		if a, exists := d.caskAnalytics[cask.Token]; exists && a.Number > 0 {
-			downloads, _ := strconv.Atoi(strings.ReplaceAll(a.Count, ",", ""))
-			pkg.Analytics90dRank = a.Number
-			pkg.Analytics90dDownloads = downloads
+	for name, pkg := range d.installedPackages() {
+		if remote, exists := packageMap[name]; exists {
+			d.displaced[name] = remote
+			pkg = withRemoteVersion(pkg, remote)
 		}
-		packageMap[cask.Token] = pkg
+		packageMap[name] = pkg
 	}
 
 	*d.allPackages = make([]models.Package, 0, len(packageMap))
@@ -627,17 +739,93 @@ func (d *DataProvider) GetPackages() *[]models.Package {
 		*d.allPackages = append(*d.allPackages, pkg)
 	}
 
-	sort.Slice(*d.allPackages, func(i, j int) bool {
-		return (*d.allPackages)[i].Name < (*d.allPackages)[j].Name
-	})
+	i18n.SortByName(*d.allPackages, func(pkg models.Package) string { return pkg.Name })
+}
 
-	return d.allPackages
+// updateInstalledPackages replaces the installed packages of the list in place, restoring the remote
+// version of the packages no longer installed. Returns false if the list needs a rebuild because
+// packages appeared or disappeared (e.g. installed from or removed with a tap).
+func (d *DataProvider) updateInstalledPackages() bool {
+	installed := d.installedPackages()
+	packages := *d.allPackages
+	for i := range packages {
+		name := packages[i].Name
+		pkg, isInstalled := installed[name]
+		switch {
+		case isInstalled:
+			if !packages[i].LocallyInstalled {
+				d.displaced[name] = packages[i]
+			}
+			if remote, exists := d.displaced[name]; exists {
+				pkg = withRemoteVersion(pkg, remote)
+			}
+			packages[i] = pkg
+			delete(installed, name)
+		case packages[i].LocallyInstalled:
+			remote, exists := d.displaced[name]
+			if !exists {
+				return false
+			}
+			packages[i] = remote
+			delete(d.displaced, name)
+		}
+	}
+	return len(installed) == 0
+}
+
+// installedPackages returns the installed formulae and casks by name, casks replacing formulae of the same name.
+func (d *DataProvider) installedPackages() map[string]models.Package {
+	installed := make(map[string]models.Package, len(*d.installedFormulae)+len(*d.installedCasks))
+	for _, formula := range *d.installedFormulae {
+		f := formula
+		installed[formula.Name] = d.withAnalytics(models.NewPackageFromFormula(&f))
+	}
+	for _, cask := range *d.installedCasks {
+		c := cask
+		installed[cask.Token] = d.withAnalytics(models.NewPackageFromCask(&c))
+	}
+	return installed
+}
+
+// withRemoteVersion marks an installed package as outdated when the API has a newer version than its
+// `brew info`, which only knows the local taps and may be cached. The package takes the remote version,
+// revision included. Casks that update themselves are left alone, as brew does without --greedy.
+func withRemoteVersion(pkg, remote models.Package) models.Package {
+	if pkg.Outdated || pkg.Type != remote.Type || (pkg.Cask != nil && pkg.Cask.AutoUpdates) {
+		return pkg
+	}
+	if !version.Newer(pkg.InstalledVersion(), remote.LatestVersion()) {
+		return pkg
+	}
+
+	pkg.Outdated = true
+	pkg.Version = remote.Version
+	if pkg.Formula != nil && remote.Formula != nil {
+		formula := *pkg.Formula // Shared with the installed list
+		formula.Versions.Stable, formula.Revision = remote.Version, remote.Formula.Revision
+		pkg.Formula = &formula
+	}
+	return pkg
+}
+
+// withAnalytics sets the 90 days install rank and count of a package.
+func (d *DataProvider) withAnalytics(pkg models.Package) models.Package {
+	analytics := d.formulaeAnalytics
+	if pkg.Type == models.PackageTypeCask {
+		analytics = d.caskAnalytics
+	}
+	if a, exists := analytics[pkg.Name]; exists && a.Number > 0 {
+		downloads, _ := strconv.Atoi(strings.ReplaceAll(a.Count, ",", ""))
+		pkg.Analytics90dRank = a.Number
+		pkg.Analytics90dDownloads = downloads
+	}
+	return pkg
 }
 
 // fetchInstalledNames returns a map of installed package names for the given type.
 func (d *DataProvider) fetchInstalledNames(packageType string) map[string]bool {
 	result := make(map[string]bool)
-	cmd := exec.Command("brew", "list", packageType)
+	cmd := brewCommand("list", packageType)
 	output, err := cmd.Output()
 	if err != nil {
 		return result
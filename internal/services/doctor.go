@@ -0,0 +1,177 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/components"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pathConflict is a command of a Homebrew bin directory shadowed by another one of the same name found
+// earlier in the PATH, e.g. the system git in /usr/bin shadowing brew's git.
+type pathConflict struct {
+	Command   string
+	Package   string // Formula or cask linking the command, "" if not found
+	BrewPath  string // The command of the Homebrew prefix
+	Shadowing string // The command `which` resolves
+}
+
+// pathReport is the result of the PATH check: the Homebrew bin directories missing from the PATH,
+// and the commands shadowed in those that are in it.
+type pathReport struct {
+	NotInPath []string
+	Conflicts []pathConflict
+}
+
+// checkPathShadowing compares the commands of the bin directories of the prefix with what the PATH
+// resolves first, as `which` does. Commands resolving to the same file (e.g. /usr/local/bin on an
+// Intel Mac, or a symlink to the keg) are not conflicts.
+func checkPathShadowing(prefix, pathList string) pathReport {
+	var pathDirs []string
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir = filepath.Clean(dir); dir != "" && !seen[dir] {
+			seen[dir] = true
+			pathDirs = append(pathDirs, dir)
+		}
+	}
+
+	// The commands of each directory of the PATH, listed once
+	commands := make(map[string]map[string]bool)
+	listCommands := func(dir string) map[string]bool {
+		if names, exists := commands[dir]; exists {
+			return names
+		}
+		names := make(map[string]bool)
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				names[entry.Name()] = true
+			}
+		}
+		commands[dir] = names
+		return names
+	}
+
+	var report pathReport
+	for _, brewDir := range []string{filepath.Join(prefix, "bin"), filepath.Join(prefix, "sbin")} {
+		if _, err := os.Stat(brewDir); err != nil {
+			continue
+		}
+		position := -1
+		for i, dir := range pathDirs {
+			if resolvePath(dir) == resolvePath(brewDir) {
+				position = i
+				break
+			}
+		}
+		if position < 0 {
+			report.NotInPath = append(report.NotInPath, brewDir)
+			continue
+		}
+
+		names := make([]string, 0, len(listCommands(brewDir)))
+		for name := range listCommands(brewDir) {
+			names = append(names, name)
+		}
+		i18n.SortByName(names, func(name string) string { return name })
+		for _, name := range names {
+			brewPath := filepath.Join(brewDir, name)
+			for _, dir := range pathDirs[:position] {
+				if !listCommands(dir)[name] {
+					continue
+				}
+				found := filepath.Join(dir, name)
+				if !isExecutable(found) {
+					continue
+				}
+				if resolvePath(found) != resolvePath(brewPath) {
+					report.Conflicts = append(report.Conflicts, pathConflict{
+						Command: name, Package: linkingPackage(brewPath, prefix), BrewPath: brewPath, Shadowing: found,
+					})
+				}
+				break
+			}
+		}
+	}
+	return report
+}
+
+// isExecutable returns true if the path is an executable file, following symlinks.
+func isExecutable(path string) bool {
+	fileInfo, err := os.Stat(path)
+	return err == nil && !fileInfo.IsDir() && fileInfo.Mode()&0111 != 0
+}
+
+// linkingPackage returns the formula or cask a command of the prefix links to, from the keg of the
+// Cellar or the Caskroom its symlink points to.
+func linkingPackage(path, prefix string) string {
+	target := resolvePath(path)
+	for _, root := range []string{"Cellar", "Caskroom"} {
+		dir := resolvePath(filepath.Join(prefix, root)) + string(filepath.Separator)
+		if rest, found := strings.CutPrefix(target, dir); found {
+			return strings.SplitN(rest, string(filepath.Separator), 2)[0]
+		}
+	}
+	return ""
+}
+
+// pathFix returns the line putting a directory first in the PATH, for the shell of the user.
+func pathFix(dir string) string {
+	if filepath.Base(os.Getenv("SHELL")) == "fish" {
+		return fmt.Sprintf("fish_add_path --move %s", dir)
+	}
+	return fmt.Sprintf(`export PATH="%s:$PATH"`, dir)
+}
+
+// pathHealthCheck runs the PATH check for the Health screen: each shadowing directory is a problem,
+// with the commands it shadows and the line of the shell profile putting Homebrew first.
+func pathHealthCheck(prefix string) components.HealthCheck {
+	report := checkPathShadowing(prefix, os.Getenv("PATH"))
+	check := components.HealthCheck{
+		Name:   i18n.T("Commands shadowed in the PATH"),
+		Passed: i18n.T("The commands of Homebrew are the ones the PATH resolves"),
+	}
+
+	profile := i18n.T("your shell profile")
+	if rcFile, err := shellRCFile(); err == nil {
+		profile = displayPath(rcFile)
+	}
+	for _, dir := range report.NotInPath {
+		check.Problems = append(check.Problems, components.HealthProblem{
+			Summary: i18n.T("%s is not in the PATH", dir),
+			Fix:     i18n.T("Add to %s: %s", profile, pathFix(dir)),
+		})
+	}
+
+	// One problem per shadowing directory, in PATH order, as a single line of the profile fixes it
+	var dirs []string
+	byDir := make(map[string][]pathConflict)
+	for _, conflict := range report.Conflicts {
+		dir := filepath.Dir(conflict.Shadowing)
+		if _, exists := byDir[dir]; !exists {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], conflict)
+	}
+	for _, dir := range dirs {
+		conflicts := byDir[dir]
+		problem := components.HealthProblem{
+			Summary: i18n.T("%s comes before Homebrew in the PATH, shadowing %d commands", dir, len(conflicts)),
+			Fix: i18n.T("Add to the end of %s: %s", profile, pathFix(filepath.Dir(conflicts[0].BrewPath))) + "\n" +
+				i18n.T("Or keep these commands of %s if you use them on purpose", dir),
+		}
+		for _, conflict := range conflicts {
+			detail := i18n.T("%s: %s runs instead of %s", conflict.Command, conflict.Shadowing, conflict.BrewPath)
+			if conflict.Package != "" {
+				detail += " (" + conflict.Package + ")"
+			}
+			problem.Details = append(problem.Details, detail)
+		}
+		check.Problems = append(check.Problems, problem)
+	}
+	appLog.Info("checked PATH shadowing", "not_in_path", len(report.NotInPath), "conflicts", len(report.Conflicts))
+	return check
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ghcrAnonymousToken is the bearer token Homebrew uses to pull bottles from ghcr.io without an account.
+const ghcrAnonymousToken = "QQ=="
+
+// packageDownload is the file a package downloads on install: the bottle of a formula
+// for this platform, or the cask artifact. Useful to mirror packages to air-gapped machines.
+type packageDownload struct {
+	URL      string
+	Sha256   string // Expected checksum, "" or "no_check" when it can't be verified
+	FileName string
+}
+
+// packageDownloadFor returns the download of a package for the given bottle tags, in order of preference.
+// Formulae without a bottle for them have none.
+func packageDownloadFor(pkg models.Package, tags []string) (packageDownload, bool) {
+	switch {
+	case pkg.Formula != nil:
+		tag := pkg.Formula.BottleTag(tags)
+		file := pkg.Formula.Bottle.Stable.Files[tag]
+		if tag == "" || file.URL == "" {
+			return packageDownload{}, false // No bottle, or not loaded yet (v3 index)
+		}
+		return packageDownload{URL: file.URL, Sha256: file.Sha256, FileName: pkg.Formula.BottleFileName(tag)}, true
+	case pkg.Cask != nil && pkg.Cask.URL != "":
+		name := pkg.Cask.Token
+		if parsed, err := url.Parse(pkg.Cask.URL); err == nil && path.Base(parsed.Path) != "/" && path.Base(parsed.Path) != "." {
+			name = path.Base(parsed.Path)
+		}
+		return packageDownload{URL: pkg.Cask.URL, Sha256: pkg.Cask.SHA256, FileName: name}, true
+	}
+	return packageDownload{}, false
+}
+
+// downloadPackageFile downloads a package file to a directory, verifying its checksum when known.
+// The file is written under a temporary name first, so that failed downloads leave nothing behind.
+func downloadPackageFile(ctx context.Context, download packageDownload, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, download.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	if req.URL.Host == "ghcr.io" {
+		req.Header.Set("Authorization", "Bearer "+ghcrAnonymousToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req) // #nosec G107 -- URL comes from the Homebrew API
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("download returned HTTP %d", resp.StatusCode)
+	}
+
+	temp, err := os.CreateTemp(dir, ".bbrew-download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(temp.Name()) // No-op once renamed
+
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(temp, hash), resp.Body)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if expected := strings.ToLower(download.Sha256); expected != "" && expected != "no_check" {
+		if actual := hex.EncodeToString(hash.Sum(nil)); actual != expected {
+			return "", fmt.Errorf("checksum mismatch: expected %s, got %s", expected, actual)
+		}
+	}
+
+	target := filepath.Join(dir, filepath.Base(download.FileName))
+	if err := os.Rename(temp.Name(), target); err != nil {
+		return "", err
+	}
+	return target, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"context"
+	"strings"
+)
+
+// upgradeSection is a group of packages `brew upgrade --dry-run` reports, e.g. the dependents
+// of the upgraded formulae, under its header.
+type upgradeSection struct {
+	Title    string   // e.g. "Would upgrade 2 dependents of upgraded formula:"
+	Packages []string // e.g. "openssl@3 3.1.0 -> 3.1.1"
+}
+
+// previewUpgrade runs `brew upgrade --dry-run` on a package, returning what else the upgrade would
+// upgrade or install: outdated dependencies, dependents, new dependencies.
+func previewUpgrade(ctx context.Context, info models.Package) ([]upgradeSection, error) {
+	args := []string{"upgrade", "--dry-run", info.Name}
+	if info.Type == models.PackageTypeCask {
+		args = []string{"upgrade", "--dry-run", "--cask", info.Name}
+	}
+	output, err := brewCommandContext(ctx, args...).CombinedOutput()
+	if err != nil {
+		return nil, commandFailure("brew upgrade --dry-run", err, output)
+	}
+	return parseUpgradeDryRun(string(output)), nil
+}
+
+// parseUpgradeDryRun parses the output of `brew upgrade --dry-run`: "==> Would ..." headers followed
+// by their packages, one per line or separated by commas. Other headers (e.g. "==> Fetching") end a section.
+func parseUpgradeDryRun(output string) []upgradeSection {
+	var sections []upgradeSection
+	var current *upgradeSection
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "==> "):
+			current = nil
+			if title := strings.TrimPrefix(line, "==> "); strings.HasPrefix(title, "Would ") && strings.HasSuffix(title, ":") {
+				sections = append(sections, upgradeSection{Title: title})
+				current = &sections[len(sections)-1]
+			}
+		case current != nil && !strings.HasPrefix(line, "Warning:") && !strings.HasPrefix(line, "Error:"):
+			for _, pkg := range strings.Split(line, ", ") {
+				if pkg = strings.TrimSpace(pkg); pkg != "" {
+					current.Packages = append(current.Packages, pkg)
+				}
+			}
+		}
+	}
+	return sections
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// brewFailure is a known cause of brew command failures, recognized in the command output.
+type brewFailure struct {
+	Title   string
+	Cause   string
+	Command string // Suggested fix, %s being the package name
+	markers []string
+}
+
+// brewFailures are the known causes of failures, in order of precedence: the first matching one is reported.
+// Markers are matched case-insensitively against each output line.
+var brewFailures = []brewFailure{
+	{
+		Title:   "Checksum mismatch",
+		Cause:   "The downloaded file doesn't match the checksum Homebrew expects: the download is corrupted, or the file changed upstream.",
+		Command: `rm -f "$(brew --cache %s)" && brew install %[1]s`,
+		markers: []string{"sha256 mismatch", "checksum mismatch", "reports different checksum"},
+	},
+	{
+		Title:   "Command Line Tools missing",
+		Cause:   "Building this package requires the Xcode Command Line Tools, which are missing or outdated.",
+		Command: "xcode-select --install",
+		markers: []string{
+			"xcode-select --install", "no developer tools were found", "invalid active developer path",
+			"command line tools are too outdated", "xcode alone is not sufficient",
+		},
+	},
+	{
+		Title:   "Sandbox error",
+		Cause:   "The build sandbox denied access to a file. Another app or a privacy setting of macOS may be blocking it.",
+		Command: "brew doctor",
+		markers: []string{"sandbox-exec", "sandbox: ", "deny(1)"},
+	},
+	{
+		Title:   "Permission denied",
+		Cause:   "Your user can't write to the Homebrew prefix, often after running brew or an installer with sudo.",
+		Command: `sudo chown -R "$(whoami)" "$(brew --prefix)"`,
+		markers: []string{"permission denied @", "not writable by your user", "is not writable"},
+	},
+}
+
+// brewDiagnosis is the cause of a failed brew command, with the output line revealing it.
+type brewDiagnosis struct {
+	Failure brewFailure
+	Line    string
+	Command string
+}
+
+// diagnoseFailure recognizes the cause of a failed brew command on a package from its output.
+// Returns false for failures without output or with an unknown cause.
+func diagnoseFailure(err error, name string) (brewDiagnosis, bool) {
+	var cmdErr *commandError
+	if !errors.As(err, &cmdErr) {
+		return brewDiagnosis{}, false
+	}
+
+	lines := strings.Split(cmdErr.output, "\n")
+	for _, failure := range brewFailures {
+		for _, line := range lines {
+			lower := strings.ToLower(line)
+			for _, marker := range failure.markers {
+				if strings.Contains(lower, marker) {
+					command := failure.Command
+					if strings.Contains(command, "%s") {
+						command = fmt.Sprintf(command, name)
+					}
+					return brewDiagnosis{Failure: failure, Line: strings.TrimSpace(line), Command: command}, true
+				}
+			}
+		}
+	}
+	return brewDiagnosis{}, false
+}
+
+// failureOutput returns the end of the output of a failed brew command, "" if it has none.
+func failureOutput(err error) string {
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.output
+	}
+	return ""
+}
+
+// failureCommand returns the command line of a failed brew command, nil if it didn't run.
+func failureCommand(err error) []string {
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.args
+	}
+	return nil
+}
+
+// exitCode returns the exit code of a failed command, false if it didn't run to completion.
+func exitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
+// issueSearchURL returns the GitHub issue search of the tap of a package for the error of a failed command.
+func issueSearchURL(pkg models.Package, err error) string {
+	repo := "Homebrew/homebrew-core"
+	if pkg.Type == models.PackageTypeCask {
+		repo = "Homebrew/homebrew-cask"
+	}
+	if user, name, found := strings.Cut(pkg.Tap(), "/"); found && pkg.IsThirdPartyTap() {
+		repo = user + "/homebrew-" + name
+	}
+
+	query := "is:issue " + pkg.Name
+	lines := strings.Split(failureOutput(err), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if message, found := strings.CutPrefix(strings.TrimSpace(lines[i]), "Error: "); found {
+			if len(message) > 100 {
+				message = message[:100]
+			}
+			query += ` "` + strings.ReplaceAll(message, `"`, "") + `"`
+			break
+		}
+	}
+	return fmt.Sprintf("https://github.com/%s/issues?q=%s", repo, url.QueryEscape(query))
+}
+
+// openURL opens a URL in the default browser, without waiting for it.
+func openURL(target string) error {
+	opener := "xdg-open"
+	if runtime.GOOS == "darwin" {
+		opener = "open"
+	}
+	cmd := exec.Command(opener, target) // #nosec G204 -- opener is fixed, target is a URL we built
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
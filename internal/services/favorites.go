@@ -0,0 +1,165 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// favoritesFileName stores the starred packages, in the state directory.
+const favoritesFileName = "favorites.json"
+
+// FavoritesServiceInterface defines the contract for starred packages.
+type FavoritesServiceInterface interface {
+	IsFavorite(pkg models.Package) bool
+	Toggle(pkg models.Package) (bool, error)
+}
+
+// FavoritesService keeps a short list of starred packages, e.g. the tools installed on every machine.
+type FavoritesService struct {
+	mu        sync.Mutex
+	favorites map[string]bool // Keyed by "type/name", e.g. "cask/firefox"
+}
+
+// NewFavoritesService creates a new instance of FavoritesService, loading the saved favorites.
+var NewFavoritesService = func() FavoritesServiceInterface {
+	f := &FavoritesService{favorites: make(map[string]bool)}
+	if data := readStateFile(favoritesFileName); data != nil {
+		var keys []string
+		if json.Unmarshal(data, &keys) == nil {
+			for _, key := range keys {
+				f.favorites[key] = true
+			}
+		}
+	}
+	return f
+}
+
+// IsFavorite returns true if the package is starred.
+func (f *FavoritesService) IsFavorite(pkg models.Package) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.favorites[userDataKey(pkg)]
+}
+
+// Toggle stars or unstars a package and saves the favorites, returning whether it is now starred.
+func (f *FavoritesService) Toggle(pkg models.Package) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := userDataKey(pkg)
+	starred := !f.favorites[key]
+	if starred {
+		f.favorites[key] = true
+	} else {
+		delete(f.favorites, key)
+	}
+
+	keys := make([]string, 0, len(f.favorites))
+	for key := range f.favorites {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return starred, err
+	}
+	return starred, writeStateFile(favoritesFileName, data)
+}
+
+// favoritePackages returns the starred packages, in the order of the package list.
+func (s *AppService) favoritePackages() []models.Package {
+	var favorites []models.Package
+	for _, pkg := range s.store.All() {
+		if s.favorites.IsFavorite(pkg) {
+			favorites = append(favorites, pkg)
+		}
+	}
+	return favorites
+}
+
+// expandHome expands a leading "~/" in a path typed by the user.
+func expandHome(path string) string {
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// appendToBrewfile appends Brewfile lines for the packages to the file at path, creating it if needed.
+// Third-party taps are added before the packages that need them; taps and packages already
+// in the file are skipped. Returns the number of packages added.
+func appendToBrewfile(path string, packages []models.Package) (int, error) {
+	existingTaps := make(map[string]bool)
+	existingNames := make(map[string]bool)
+	needsNewline := false // The file doesn't end with a line break
+	// #nosec G304 -- path is chosen by the user in the export prompt
+	if data, err := os.ReadFile(path); err == nil {
+		needsNewline = len(data) > 0 && !strings.HasSuffix(string(data), "\n")
+		result, err := parseBrewfileWithTaps(path)
+		if err != nil {
+			return 0, err
+		}
+		for _, tap := range result.Taps {
+			existingTaps[strings.ToLower(tap)] = true
+		}
+		for _, entry := range result.Packages {
+			existingNames[strings.ToLower(models.ShortName(entry.Name))] = true
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+
+	var taps, entries []string
+	for _, pkg := range packages {
+		if existingNames[strings.ToLower(pkg.Name)] {
+			continue
+		}
+		existingNames[strings.ToLower(pkg.Name)] = true
+
+		// Third-party packages are referenced by their full name, and need their tap
+		name := pkg.Name
+		if pkg.IsThirdPartyTap() {
+			tap := pkg.Tap()
+			if !existingTaps[strings.ToLower(tap)] {
+				existingTaps[strings.ToLower(tap)] = true
+				taps = append(taps, fmt.Sprintf("tap %q", tap))
+			}
+			name = tap + "/" + pkg.Name
+		}
+
+		keyword := "brew"
+		if pkg.Type == models.PackageTypeCask {
+			keyword = "cask"
+		}
+		entries = append(entries, fmt.Sprintf("%s %q", keyword, name))
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	// #nosec G304 -- path is chosen by the user in the export prompt
+	file, err := os.OpenFile(filepath.Clean(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	lines := strings.Join(append(taps, entries...), "\n") + "\n"
+	if needsNewline {
+		lines = "\n" + lines
+	}
+	if _, err := file.WriteString(lines); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// packageFile is a file an installed package put on disk, or a whole app bundle of a cask.
+type packageFile struct {
+	Path string
+	Size int64
+
+	// Commands only: the command of the same name found first in the PATH, or inPath false if the
+	// directory isn't in the PATH (e.g. keg-only formulae)
+	command  bool
+	inPath   bool
+	shadowed string
+}
+
+// packageFiles lists what an installed package put on disk: the files of the keg of a formula
+// (`brew list --verbose`), or the Caskroom, apps and binaries of a cask. The cask must have its artifacts.
+func packageFiles(info models.Package, prefix string) ([]packageFile, error) {
+	var files []packageFile
+	if info.Type == models.PackageTypeCask {
+		files = caskFiles(info, prefix)
+	} else {
+		output, err := brewCommand("list", "--verbose", "--formula", info.Name).Output()
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if path := strings.TrimSpace(line); path != "" {
+				files = append(files, packageFile{Path: path, Size: diskUsage(path)})
+			}
+		}
+	}
+
+	i18n.SortByName(files, func(file packageFile) string { return file.Path })
+	for i := range files {
+		checkCommand(&files[i])
+	}
+	return files, nil
+}
+
+// caskFiles lists the files of the Caskroom directory of a cask, its apps and the commands it links.
+func caskFiles(info models.Package, prefix string) []packageFile {
+	var files []packageFile
+	_ = filepath.WalkDir(filepath.Join(prefix, "Caskroom", info.Name), func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, packageFile{Path: path, Size: diskUsage(path)})
+		}
+		return nil
+	})
+	if info.Cask == nil {
+		return files
+	}
+
+	for _, app := range info.Cask.Apps() {
+		if path, ok := caskAppPath(app); ok {
+			if _, err := os.Stat(path); err == nil {
+				files = append(files, packageFile{Path: path, Size: diskUsage(path)})
+			}
+		}
+	}
+	for _, binary := range info.Cask.Binaries() {
+		path := filepath.Join(prefix, "bin", binary)
+		if _, err := os.Lstat(path); err == nil {
+			files = append(files, packageFile{Path: path})
+		}
+	}
+	return files
+}
+
+// checkCommand looks up a file of a bin directory in the PATH, to reveal the commands shadowed
+// by another one of the same name, e.g. from the system or another package manager.
+func checkCommand(file *packageFile) {
+	dir := filepath.Base(filepath.Dir(file.Path))
+	if dir != "bin" && dir != "sbin" {
+		return
+	}
+	file.command = true
+
+	found, err := exec.LookPath(filepath.Base(file.Path))
+	if err != nil {
+		return
+	}
+	file.inPath = true
+	if resolvePath(found) != resolvePath(file.Path) {
+		file.shadowed = found
+	}
+}
+
+// resolvePath returns the path with its symlinks resolved, the path itself if it can't be resolved.
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// diskUsage returns the size of a file, or of the files of a directory (e.g. an app bundle).
+// Symlinks are not followed.
+func diskUsage(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if fileInfo, err := d.Info(); err == nil {
+			size += fileInfo.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// repoAPIURL returns the metadata of a GitHub repository.
+	repoAPIURL = "https://api.github.com/repos/%s/%s"
+
+	// cacheFileGitHub stores the GitHub metadata of packages, keyed by repository.
+	cacheFileGitHub = "github-metadata.json"
+
+	// githubMetadataTTL is how long cached GitHub metadata stays valid.
+	githubMetadataTTL = 7 * 24 * time.Hour
+)
+
+// GitHubServiceInterface defines the contract for GitHub metadata enrichment.
+type GitHubServiceInterface interface {
+	Lookup(pkg models.Package) *models.GitHubMetadata
+	Fetch(ctx context.Context, pkg models.Package) (*models.GitHubMetadata, error)
+	Prefetch(ctx context.Context, packages []models.Package)
+}
+
+// GitHubService fetches repository metadata from the GitHub API.
+// Results are cached on disk, and requests are paused while the API rate limit is exhausted.
+type GitHubService struct {
+	mu           sync.Mutex
+	metadata     map[string]*models.GitHubMetadata // Keyed by "owner/repo"
+	limitedUntil time.Time
+}
+
+// NewGitHubService creates a new instance of GitHubService, loading the cached metadata.
+var NewGitHubService = func() GitHubServiceInterface {
+	g := &GitHubService{metadata: make(map[string]*models.GitHubMetadata)}
+	if data := readCacheFile(cacheFileGitHub, 2); data != nil {
+		_ = json.Unmarshal(data, &g.metadata)
+	}
+	return g
+}
+
+// Lookup returns the cached metadata of a package, or nil if it isn't known (or not on GitHub).
+func (g *GitHubService) Lookup(pkg models.Package) *models.GitHubMetadata {
+	owner, repo := githubRepo(pkg)
+	if owner == "" {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.metadata[owner+"/"+repo]
+}
+
+// Fetch returns the metadata of a package, querying GitHub if the cached one is missing or stale.
+func (g *GitHubService) Fetch(ctx context.Context, pkg models.Package) (*models.GitHubMetadata, error) {
+	meta, err := g.fetch(ctx, pkg)
+	if err == nil {
+		g.save()
+	}
+	return meta, err
+}
+
+// Prefetch fetches the metadata of the given packages, stopping early if the rate limit is hit.
+func (g *GitHubService) Prefetch(ctx context.Context, packages []models.Package) {
+	for _, pkg := range packages {
+		if _, err := g.fetch(ctx, pkg); err != nil {
+			var rateLimit *githubRateLimitError
+			if errors.As(err, &rateLimit) || ctx.Err() != nil {
+				break
+			}
+		}
+	}
+	g.save()
+}
+
+// fetch returns the metadata of a package without persisting the cache.
+func (g *GitHubService) fetch(ctx context.Context, pkg models.Package) (*models.GitHubMetadata, error) {
+	owner, repo := githubRepo(pkg)
+	if owner == "" {
+		return nil, fmt.Errorf("%s is not hosted on GitHub", pkg.Name)
+	}
+	key := owner + "/" + repo
+
+	g.mu.Lock()
+	cached := g.metadata[key]
+	limitedUntil := g.limitedUntil
+	g.mu.Unlock()
+
+	if cached != nil && time.Since(cached.FetchedAt) < githubMetadataTTL {
+		return cached, nil
+	}
+	if time.Now().Before(limitedUntil) {
+		return cached, &githubRateLimitError{reset: limitedUntil}
+	}
+
+	meta := &models.GitHubMetadata{}
+	if err := fetchGitHubJSON(ctx, fmt.Sprintf(repoAPIURL, owner, repo), meta); err != nil {
+		var rateLimit *githubRateLimitError
+		if errors.As(err, &rateLimit) {
+			g.mu.Lock()
+			g.limitedUntil = rateLimit.reset
+			g.mu.Unlock()
+		}
+		return cached, err
+	}
+	meta.FetchedAt = time.Now()
+
+	g.mu.Lock()
+	g.metadata[key] = meta
+	g.mu.Unlock()
+	return meta, nil
+}
+
+// save persists the metadata cache.
+func (g *GitHubService) save() {
+	g.mu.Lock()
+	data, err := json.Marshal(g.metadata)
+	g.mu.Unlock()
+
+	if err == nil && ensureCacheDir() == nil {
+		writeCacheFile(cacheFileGitHub, data)
+	}
+}
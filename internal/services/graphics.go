@@ -0,0 +1,237 @@
+package services
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// graphicsProtocol is an escape sequence protocol drawing images in the terminal.
+type graphicsProtocol int
+
+const (
+	graphicsNone  graphicsProtocol = iota
+	graphicsKitty                  // kitty graphics protocol (kitty, Ghostty)
+	graphicsITerm                  // iTerm2 inline images (iTerm2, WezTerm)
+	graphicsSixel                  // DEC sixel (foot, mlterm, contour)
+)
+
+// detectGraphicsProtocol guesses the supported image protocol from the environment.
+// BBREW_GRAPHICS overrides it: kitty, iterm, sixel or none. Disabled in tmux, which doesn't pass images through reliably.
+func detectGraphicsProtocol() graphicsProtocol {
+	switch os.Getenv("BBREW_GRAPHICS") {
+	case "kitty":
+		return graphicsKitty
+	case "iterm":
+		return graphicsITerm
+	case "sixel":
+		return graphicsSixel
+	case "none":
+		return graphicsNone
+	}
+
+	termProgram := os.Getenv("TERM_PROGRAM")
+	term := os.Getenv("TERM")
+	switch {
+	case os.Getenv("TMUX") != "":
+		return graphicsNone
+	case strings.Contains(term, "kitty"), os.Getenv("KITTY_WINDOW_ID") != "", termProgram == "ghostty":
+		return graphicsKitty
+	case termProgram == "iTerm.app", termProgram == "WezTerm", os.Getenv("LC_TERMINAL") == "iTerm2":
+		return graphicsITerm
+	case strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"), strings.HasPrefix(term, "contour"):
+		return graphicsSixel
+	}
+	return graphicsNone
+}
+
+// kittyImageID identifies the image of bbrew in kitty, replaced by each new image.
+const kittyImageID = 4242
+
+// encodeImage returns the sequence drawing a PNG image over a box of cells at the cursor position,
+// keeping its aspect ratio. Sixel images are scaled to the pixel size of the cells.
+func encodeImage(protocol graphicsProtocol, data []byte, cols, rows, cellWidth, cellHeight int) ([]byte, error) {
+	var b bytes.Buffer
+	switch protocol {
+	case graphicsKitty:
+		// Sent in chunks of 4096 base64 bytes; q=2 silences the replies, C=1 keeps the cursor in place
+		encoded := base64.StdEncoding.EncodeToString(data)
+		for i := 0; i < len(encoded); i += 4096 {
+			chunk := encoded[i:min(i+4096, len(encoded))]
+			more := 0
+			if i+4096 < len(encoded) {
+				more = 1
+			}
+			if i == 0 {
+				fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,i=%d,c=%d,r=%d,m=%d;%s\x1b\\", kittyImageID, cols, rows, more, chunk)
+			} else {
+				fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+			}
+		}
+	case graphicsITerm:
+		fmt.Fprintf(&b, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\x07",
+			len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+	case graphicsSixel:
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		encodeSixel(&b, img, cols*cellWidth, rows*cellHeight)
+	default:
+		return nil, fmt.Errorf("no graphics protocol")
+	}
+	return b.Bytes(), nil
+}
+
+// clearImageSequence returns the sequence deleting the image drawn with the protocol, for protocols
+// drawing images above the text (kitty). Other protocols draw in the cells, erased by writing over them.
+func clearImageSequence(protocol graphicsProtocol) []byte {
+	if protocol == graphicsKitty {
+		return []byte(fmt.Sprintf("\x1b_Ga=d,d=I,q=2,i=%d\x1b\\", kittyImageID))
+	}
+	return nil
+}
+
+// encodeSixel writes an image as sixel, scaled to fit a box of pixels, quantized to a 6x6x6 color cube.
+// Transparent pixels are left out, showing the background.
+func encodeSixel(b *bytes.Buffer, img image.Image, maxWidth, maxHeight int) {
+	bounds := img.Bounds()
+	scale := min(float64(maxWidth)/float64(bounds.Dx()), float64(maxHeight)/float64(bounds.Dy()))
+	width, height := max(1, int(float64(bounds.Dx())*scale)), max(1, int(float64(bounds.Dy())*scale))
+
+	// Nearest neighbor scaling, -1 for transparent pixels
+	pixels := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, bl, a := img.At(bounds.Min.X+int(float64(x)/scale), bounds.Min.Y+int(float64(y)/scale)).RGBA()
+			if a < 0x8000 {
+				pixels[y*width+x] = -1
+				continue
+			}
+			// Colors are alpha-premultiplied
+			r, g, bl = r*0xffff/a, g*0xffff/a, bl*0xffff/a
+			pixels[y*width+x] = int((r*5+0x7fff)/0xffff)*36 + int((g*5+0x7fff)/0xffff)*6 + int((bl*5+0x7fff)/0xffff)
+		}
+	}
+
+	fmt.Fprintf(b, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for c := 0; c < 216; c++ {
+		fmt.Fprintf(b, "#%d;2;%d;%d;%d", c, c/36*20, c/6%6*20, c%6*20)
+	}
+	for top := 0; top < height; top += 6 {
+		// Each band of 6 rows is drawn once per color, returning to its start ($) between colors
+		used := make(map[int]bool)
+		for y := top; y < min(top+6, height); y++ {
+			for x := 0; x < width; x++ {
+				if c := pixels[y*width+x]; c >= 0 {
+					used[c] = true
+				}
+			}
+		}
+		for c := range used {
+			fmt.Fprintf(b, "#%d", c)
+			run, last := 0, byte(0)
+			for x := 0; x <= width; x++ {
+				var bits byte
+				if x < width {
+					for bit := 0; bit < 6 && top+bit < height; bit++ {
+						if pixels[(top+bit)*width+x] == c {
+							bits |= 1 << bit
+						}
+					}
+				}
+				sixel := 63 + bits // Printable form of the 6 vertical pixels
+				if x == width || (run > 0 && sixel != last) {
+					writeSixelRun(b, last, run)
+					run = 0
+				}
+				last = sixel
+				run++
+			}
+			b.WriteByte('$')
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+}
+
+// writeSixelRun writes a sixel repeated count times, compressed when worth it.
+func writeSixelRun(b *bytes.Buffer, sixel byte, count int) {
+	if count > 3 {
+		fmt.Fprintf(b, "!%d%c", count, sixel)
+		return
+	}
+	for i := 0; i < count; i++ {
+		b.WriteByte(sixel)
+	}
+}
+
+// iconRenderer draws an image over a box of cells, straight to the tty. The cells are locked so that tcell
+// doesn't draw over the image, until it changes or is hidden.
+type iconRenderer struct {
+	protocol graphicsProtocol
+	drawn    iconPlacement // Zero when nothing is drawn
+	failed   string        // Key of the last image that couldn't be encoded, not retried
+}
+
+// iconPlacement is an image drawn at a box of cells, for a screen size.
+type iconPlacement struct {
+	key                 string
+	x, y, width, height int
+	screenW, screenH    int
+}
+
+// render draws the image identified by key in the box, or erases the drawn image when data is nil.
+// Call it after the screen is drawn, before it is shown (tview's after draw function).
+func (r *iconRenderer) render(screen tcell.Screen, key string, data []byte, x, y, width, height int) {
+	tty, ok := screen.Tty()
+	if !ok || r.protocol == graphicsNone {
+		return
+	}
+	screenW, screenH := screen.Size()
+	placement := iconPlacement{key: key, x: x, y: y, width: width, height: height, screenW: screenW, screenH: screenH}
+	if data == nil || width <= 0 || height <= 0 || key == r.failed {
+		placement = iconPlacement{}
+	}
+	if placement == r.drawn {
+		return // Still on screen
+	}
+
+	var b bytes.Buffer
+	if old := r.drawn; old.key != "" {
+		// Write over the cells of the old image, then let tcell draw them again
+		b.Write(clearImageSequence(r.protocol))
+		b.WriteString("\x1b[0m")
+		for row := old.y; row < old.y+old.height; row++ {
+			fmt.Fprintf(&b, "\x1b[%d;%dH%s", row+1, old.x+1, strings.Repeat(" ", old.width))
+		}
+		screen.LockRegion(old.x, old.y, old.width, old.height, false)
+	}
+	r.drawn = iconPlacement{}
+
+	if placement.key != "" {
+		cellWidth, cellHeight := 10, 20 // Usual cell size, if the terminal doesn't report it
+		if size, err := tty.WindowSize(); err == nil {
+			if w, h := size.CellDimensions(); w > 0 && h > 0 {
+				cellWidth, cellHeight = w, h
+			}
+		}
+		sequence, err := encodeImage(r.protocol, data, width, height, cellWidth, cellHeight)
+		if err != nil {
+			appLog.Debug("failed to encode the icon", "key", key, "err", err)
+			r.failed = key
+		} else {
+			fmt.Fprintf(&b, "\x1b[%d;%dH", y+1, x+1)
+			b.Write(sequence)
+			screen.LockRegion(x, y, width, height, true)
+			r.drawn = placement
+		}
+	}
+	_, _ = tty.Write(b.Bytes())
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tableRow describes a table row below the header: a package, or a Brewfile section or package type header.
+type tableRow struct {
+	index int                // Index in the filtered list, -1 for section headers
+	group string             // Section of the header row
+	kind  models.PackageType // Package type of the header row in the view grouped by type, "" otherwise
+	count int                // Packages in the section of the header row
+
+	spacer bool // Blank row between two rows, with the padded row spacing
+}
+
+// typeGroups are the package types of the view grouped by type, in order.
+var typeGroups = []models.PackageType{models.PackageTypeFormula, models.PackageTypeCask}
+
+// buildTableRows lays out the filtered packages, under their section headers in the grouped view.
+// Sections keep the Brewfile order; packages outside any section are listed last.
+// Outside Brewfile mode, the grouped view puts them under their package type instead.
+func (s *AppService) buildTableRows(data []models.Package) []tableRow {
+	if !s.IsBrewfileMode() && s.typeView {
+		return s.buildTypeRows(data)
+	}
+
+	rows := make([]tableRow, 0, len(data))
+	if !s.IsBrewfileMode() || !s.groupedView {
+		for i := range data {
+			rows = append(rows, tableRow{index: i})
+		}
+		return rows
+	}
+
+	members := make(map[string][]int)
+	for i, info := range data {
+		members[info.BrewfileGroup] = append(members[info.BrewfileGroup], i)
+	}
+
+	for _, group := range append(s.brewfileGroups, "") {
+		indexes, exists := members[group]
+		if !exists {
+			continue
+		}
+		delete(members, group) // Sections may be repeated in the Brewfile
+		rows = append(rows, tableRow{index: -1, group: group, count: len(indexes)})
+		if s.collapsedGroups[group] {
+			continue
+		}
+		for _, i := range indexes {
+			rows = append(rows, tableRow{index: i})
+		}
+	}
+	return rows
+}
+
+// spaceRows puts a blank row between the rows with the padded row spacing. Blank rows can't be selected,
+// so the selection moves from one row to the next as usual.
+func (s *AppService) spaceRows(rows []tableRow) []tableRow {
+	if s.theme.RowSpacing != theme.RowSpacingPadded || len(rows) < 2 {
+		return rows
+	}
+	spaced := make([]tableRow, 0, 2*len(rows)-1)
+	for i, row := range rows {
+		if i > 0 {
+			spaced = append(spaced, tableRow{index: -1, spacer: true})
+		}
+		spaced = append(spaced, row)
+	}
+	return spaced
+}
+
+// buildTypeRows lays out the filtered packages under a header for each package type, e.g. "Formulae (5,900)".
+// Types without packages have no header.
+func (s *AppService) buildTypeRows(data []models.Package) []tableRow {
+	members := make(map[models.PackageType][]int)
+	for i, info := range data {
+		members[info.Type] = append(members[info.Type], i)
+	}
+
+	rows := make([]tableRow, 0, len(data)+len(typeGroups))
+	for _, kind := range typeGroups {
+		indexes := members[kind]
+		if len(indexes) == 0 {
+			continue
+		}
+		rows = append(rows, tableRow{index: -1, kind: kind, count: len(indexes)})
+		if s.collapsedTypes[kind] {
+			continue
+		}
+		for _, i := range indexes {
+			rows = append(rows, tableRow{index: i})
+		}
+	}
+	return rows
+}
+
+// packageAtRow returns the package shown at the given table row, if the row isn't a section header.
+func (s *AppService) packageAtRow(row int) (models.Package, bool) {
+	if row < 1 || row > len(s.tableRows) || s.tableRows[row-1].index < 0 {
+		return models.Package{}, false
+	}
+	return s.store.FilteredAt(s.tableRows[row-1].index)
+}
+
+// rowOfPackage returns the table row showing the named package, or 0 if it isn't shown.
+func (s *AppService) rowOfPackage(name string) int {
+	for i := range s.tableRows {
+		if pkg, exists := s.packageAtRow(i + 1); exists && pkg.Name == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// selectPackage selects the row of a package in the table and shows its details.
+// Returns false if the package isn't shown.
+func (s *AppService) selectPackage(name string) bool {
+	row := s.rowOfPackage(name)
+	if row == 0 {
+		return false
+	}
+	pkg, _ := s.packageAtRow(row)
+	s.layout.GetTable().View().Select(row, 0)
+	s.layout.GetDetails().SetContent(&pkg)
+	return true
+}
+
+// jumpToInitial selects the first package of the table whose name starts with the given character.
+// In the default name order, this jumps through the alphabet without paging.
+func (s *AppService) jumpToInitial(initial rune) {
+	prefix := strings.ToLower(string(initial))
+	for i := range s.tableRows {
+		if pkg, exists := s.packageAtRow(i + 1); exists && strings.HasPrefix(strings.ToLower(pkg.Name), prefix) {
+			s.layout.GetTable().View().Select(i+1, 0)
+			return
+		}
+	}
+	s.layout.GetNotifier().ShowWarning(i18n.T("No package starting with %s", string(initial)))
+}
+
+// renderGroupHeader renders a section header row, e.g. "▾ Dev tools (5)", or a package type header.
+func (s *AppService) renderGroupHeader(row tableRow) *tview.TableCell {
+	group := row.group
+	collapsed := s.collapsedGroups[group]
+	title := group
+	switch {
+	case row.kind == models.PackageTypeFormula:
+		collapsed, title = s.collapsedTypes[row.kind], i18n.T("Formulae")
+	case row.kind == models.PackageTypeCask:
+		collapsed, title = s.collapsedTypes[row.kind], i18n.T("Casks")
+	case title == "":
+		title = i18n.T("Other")
+	}
+	symbol := s.theme.Symbols.Expanded
+	if collapsed {
+		symbol = s.theme.Symbols.Collapsed
+	}
+	return tview.NewTableCell(fmt.Sprintf("%s %s (%s)", symbol, tview.Escape(title), i18n.Number(row.count))).
+		SetTextColor(s.theme.SectionTitleColor).
+		SetAttributes(tcell.AttrBold)
+}
+
+// ToggleGroupedView switches the Brewfile table between the flat and the grouped view.
+func (s *AppService) ToggleGroupedView() {
+	s.groupedView = !s.groupedView
+	s.setResults(s.store.Filtered(), true)
+}
+
+// ToggleTypeView switches the table between the flat view and the view grouped by package type,
+// outside Brewfile mode.
+func (s *AppService) ToggleTypeView() {
+	s.typeView = !s.typeView
+	s.setResults(s.store.Filtered(), true)
+}
+
+// toggleGroupAt collapses or expands the section or package type whose header is at the given row.
+func (s *AppService) toggleGroupAt(row int) {
+	if row < 1 || row > len(s.tableRows) || s.tableRows[row-1].index >= 0 || s.tableRows[row-1].spacer {
+		return
+	}
+	if kind := s.tableRows[row-1].kind; kind != "" {
+		s.collapsedTypes[kind] = !s.collapsedTypes[kind]
+	} else {
+		group := s.tableRows[row-1].group
+		s.collapsedGroups[group] = !s.collapsedGroups[group]
+	}
+	s.setResults(s.store.Filtered(), false)
+	s.layout.GetTable().View().Select(row, 0)
+}
+
+// selectedGroup returns the Brewfile section of the selected row: the section of a header row,
+// or the section of the selected package.
+func (s *AppService) selectedGroup() (string, bool) {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if row >= 1 && row <= len(s.tableRows) && s.tableRows[row-1].index < 0 && s.tableRows[row-1].kind == "" &&
+		!s.tableRows[row-1].spacer {
+		return s.tableRows[row-1].group, true
+	}
+	if pkg, exists := s.packageAtRow(row); exists && pkg.BrewfileGroup != "" {
+		return pkg.BrewfileGroup, true
+	}
+	return "", false
+}
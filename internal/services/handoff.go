@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bbrew/internal/events"
+	"bbrew/internal/models"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Some installers don't behave under the captured output of the TUI, e.g. when they ask questions.
+// Their brew command can be handed off to a new terminal window instead: the command writes its exit status
+// to a file when it finishes, which is watched to report the result and refresh the packages.
+
+const (
+	handoffCheckInterval = 2 * time.Second
+	handoffMaxWait       = 12 * time.Hour // Giving up after this, e.g. when the terminal was closed
+)
+
+// handoffCommand returns the brew command of a package run in an external terminal, with its operation:
+// install when it's not installed, upgrade when outdated, reinstall otherwise.
+func handoffCommand(info models.Package) (string, []string) {
+	name := info.FullName
+	if name == "" {
+		name = info.Name
+	}
+
+	operation, args := events.OperationInstall, []string{"brew", "install"}
+	switch {
+	case info.LocallyInstalled && info.Outdated:
+		operation, args = events.OperationUpdate, []string{"brew", "upgrade"}
+	case info.LocallyInstalled:
+		args = []string{"brew", "reinstall"}
+	}
+	if info.Type == models.PackageTypeCask {
+		args = append(args, "--cask")
+	}
+	return operation, append(args, name)
+}
+
+// handoffOperation returns the operation of a brew command line, for the OperationCompleted event.
+func handoffOperation(args []string) string {
+	if len(args) > 1 {
+		switch args[1] {
+		case "upgrade":
+			return events.OperationUpdate
+		case "uninstall":
+			return events.OperationRemove
+		}
+	}
+	return events.OperationInstall
+}
+
+// runInTerminal runs a command in a new terminal window, where it can ask for input, then calls done
+// from a background goroutine with its failure, nil on success. done isn't called if the command doesn't finish
+// within handoffMaxWait. Fails where no terminal is available, e.g. over SSH.
+func runInTerminal(args []string, done func(err error)) error {
+	dir, err := os.MkdirTemp("", "bbrew-handoff-")
+	if err != nil {
+		return err
+	}
+	status := filepath.Join(dir, "status")
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	script := fmt.Sprintf("HOMEBREW_NO_AUTO_UPDATE=1 %s; echo $? > %s; printf '\\nPress enter to close'; read _",
+		strings.Join(quoted, " "), shellQuote(status))
+
+	cmd, err := terminalCommand(script)
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return err
+	}
+	brewLog.Info("command handed off to a terminal", "args", args[1:], "terminal", cmd.Args[0])
+
+	go func() {
+		defer RecoverCrash()
+		_ = cmd.Wait() // Returns right away for most terminals, the command keeps running in the window
+	}()
+	go func() {
+		defer RecoverCrash()
+		defer os.RemoveAll(dir)
+		for waited := time.Duration(0); waited < handoffMaxWait; waited += handoffCheckInterval {
+			time.Sleep(handoffCheckInterval)
+			data, err := os.ReadFile(status) // #nosec G304 -- status is in our temporary directory
+			if err != nil || !strings.HasSuffix(string(data), "\n") {
+				continue // Not finished, or still writing
+			}
+			if code, _ := strconv.Atoi(strings.TrimSpace(string(data))); code != 0 {
+				done(fmt.Errorf("exit status %d", code))
+			} else {
+				done(nil)
+			}
+			return
+		}
+		brewLog.Warn("handed off command didn't finish", "args", args[1:])
+	}()
+	return nil
+}
+
+// terminalCommand returns the command opening a terminal window running a sh script:
+// iTerm or Terminal on macOS, $TERMINAL or x-terminal-emulator on Linux.
+func terminalCommand(script string) (*exec.Cmd, error) {
+	shell := "sh -c " + shellQuote(script) // The shell of the user may not be sh-compatible, e.g. fish
+
+	if runtime.GOOS == "darwin" {
+		escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(shell)
+		if os.Getenv("TERM_PROGRAM") == "iTerm.app" {
+			return exec.Command("osascript", // #nosec G204 -- the script runs a brew command we built
+				"-e", `tell application "iTerm" to create window with default profile command "`+escaped+`"`,
+				"-e", `tell application "iTerm" to activate`), nil
+		}
+		return exec.Command("osascript", // #nosec G204 -- the script runs a brew command we built
+			"-e", `tell application "Terminal" to do script "`+escaped+`"`,
+			"-e", `tell application "Terminal" to activate`), nil
+	}
+
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return nil, errors.New("no graphical session")
+	}
+	terminal := strings.Fields(os.Getenv("TERMINAL")) // May include options, e.g. "kitty --single-instance"
+	if len(terminal) == 0 {
+		terminal = []string{"x-terminal-emulator"}
+	}
+	args := append(terminal[1:], "-e", "sh", "-c", script)
+	return exec.Command(terminal[0], args...), nil // #nosec G204 -- the terminal of the user, running a command we built
+}
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"runtime"
+	"testing"
+
+	"bbrew/internal/models"
+)
+
+func TestHandOffWithoutTerminal(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("macOS always has a terminal to hand off to")
+	}
+	t.Setenv("DISPLAY", "")
+	t.Setenv("WAYLAND_DISPLAY", "")
+
+	s, app := newTestAppService(notifyOSC9)
+	go app.Run()
+	defer app.Stop()
+
+	info := models.Package{Name: "wget", Type: models.PackageTypeFormula}
+	operation, args := handoffCommand(info)
+	// The command is copied to the clipboard instead, from the event loop
+	runOnEventLoop(t, app, func() { s.inputService.(*InputService).handOff(info, operation, args) })
+	if got := s.runningOperation(); got != "" {
+		t.Fatalf("runningOperation() = %q after the fallback, want none", got)
+	}
+	waitFlushed(t, s.terminal.(*TerminalService))
+}
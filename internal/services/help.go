@@ -0,0 +1,79 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/components"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// helpCategoryTitles are the help screen sections, in order.
+var helpCategoryTitles = []struct {
+	category ActionCategory
+	title    string
+}{
+	{CategoryNavigation, "NAVIGATION"},
+	{CategoryFilters, "FILTERS"},
+	{CategoryActions, "ACTIONS"},
+	{CategoryBrewfile, "BREWFILE"},
+}
+
+// helpSections generates the help screen from the registered actions, so it always matches
+// the current bindings: Brewfile actions only appear in Brewfile mode, disabled ones never do.
+func (s *InputService) helpSections() []components.HelpSection {
+	sections := make([]components.HelpSection, 0, len(helpCategoryTitles))
+	for _, category := range helpCategoryTitles {
+		section := components.HelpSection{Title: i18n.T(category.title)}
+
+		// Keys handled outside of the action registry
+		switch category.category {
+		case CategoryNavigation:
+			section.Entries = append(section.Entries, components.HelpEntry{
+				Key: s.appService.theme.Symbols.UpDown + ", j/k", Description: i18n.T("Navigate list"),
+			})
+			if !s.appService.IsBrewfileMode() {
+				section.Entries = append(section.Entries, components.HelpEntry{
+					Key: "Enter", Description: i18n.T("Collapse/expand type (grouped by type)"),
+				})
+			}
+		case CategoryBrewfile:
+			if s.appService.IsBrewfileMode() {
+				section.Entries = append(section.Entries, components.HelpEntry{
+					Key: "Enter", Description: i18n.T("Collapse/expand section"),
+				})
+			}
+		}
+
+		for _, action := range s.keyActions {
+			if action.Category != category.category {
+				continue
+			}
+			description := action.Help
+			if description == "" {
+				description = action.Name
+			}
+			section.Entries = append(section.Entries, components.HelpEntry{Key: helpKeyLabel(action), Description: description})
+		}
+
+		if len(section.Entries) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// helpKeyLabel returns the key of an action as shown on the help screen: "Ctrl+U", "Esc", "i".
+func helpKeyLabel(action *InputAction) string {
+	if action.Key == tcell.KeyRune {
+		return string(action.Rune)
+	}
+	parts := strings.Split(action.KeySlug, "+")
+	for i, part := range parts {
+		if r, size := utf8.DecodeRuneInString(part); size > 0 {
+			parts[i] = strings.ToUpper(string(r)) + part[size:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bbrew/internal/logging"
+	"bbrew/internal/models"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Hooks are commands of the user run around operations, e.g. to refresh dotfiles once a package is
+// installed or to notify a chat channel after provisioning a machine. They run with sh -c, their output in
+// the output view, with BBREW_* variables describing the package and the outcome in their environment.
+
+var hooksLog = logging.For("hooks")
+
+// hookTimeout stops a hook that hangs, since the operation it follows is not finished until it returns.
+const hookTimeout = 5 * time.Minute
+
+// HooksConfig sets the commands run around operations.
+type HooksConfig struct {
+	// PreInstall runs before each install, with BBREW_PACKAGE, BBREW_PACKAGE_TYPE and BBREW_VERSION set.
+	// When it fails, the package is not installed.
+	PreInstall string `json:"pre_install,omitempty"`
+
+	// PostInstall runs after each install, successful or not, with the variables of pre_install, plus
+	// BBREW_RESULT (success or failure) and BBREW_ERROR.
+	PostInstall string `json:"post_install,omitempty"`
+
+	// PostUpdateAll runs after Update All and the runs of `bbrew upgrade` that upgraded something, with
+	// BBREW_RESULT, BBREW_ERROR, BBREW_PACKAGES (the packages upgraded or tried, space-separated) and,
+	// from `bbrew upgrade`, BBREW_FAILED.
+	PostUpdateAll string `json:"post_update_all,omitempty"`
+}
+
+// hookError is the failure of a hook.
+type hookError struct {
+	hook string
+	err  error
+}
+
+func (e *hookError) Error() string { return fmt.Sprintf("%s hook: %v", e.hook, e.err) }
+
+func (e *hookError) Unwrap() error { return e.err }
+
+// packageHookEnv returns the variables describing the package of a hook.
+func packageHookEnv(pkg models.Package) []string {
+	return []string{
+		"BBREW_PACKAGE=" + pkg.Name,
+		"BBREW_PACKAGE_TYPE=" + string(pkg.Type),
+		"BBREW_VERSION=" + pkg.Version,
+	}
+}
+
+// resultHookEnv returns the variables describing the outcome of the operation before a hook.
+func resultHookEnv(err error) []string {
+	if err != nil {
+		return []string{"BBREW_RESULT=failure", "BBREW_ERROR=" + err.Error()}
+	}
+	return []string{"BBREW_RESULT=success", "BBREW_ERROR="}
+}
+
+// hookCommand returns the command running a hook, with its variables added to the environment.
+func hookCommand(ctx context.Context, hook, command string, env []string) *exec.Cmd {
+	// #nosec G204 -- the command is set by the user in their own config
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(append(os.Environ(), "BBREW_HOOK="+hook), env...)
+	return cmd
+}
+
+// runHook runs a hook in the TUI, its output in the output view. Does nothing when command is empty.
+func (s *InputService) runHook(hook, command string, env []string) error {
+	if command == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	hooksLog.Info("running hook", "hook", hook)
+	output := s.layout.GetOutput().View()
+	s.appService.app.QueueUpdateDraw(func() { fmt.Fprintf(output, "==> Running the %s hook\n", hook) })
+	err := s.brewService.RunCommands([]*exec.Cmd{hookCommand(ctx, hook, command, env)}, s.appService.app, output)
+	if err != nil {
+		hooksLog.Warn("hook failed", "hook", hook, "err", err)
+		s.appService.app.QueueUpdateDraw(func() { fmt.Fprintf(output, "==> The %s hook failed: %v\n", hook, err) })
+		return &hookError{hook: hook, err: err}
+	}
+	return nil
+}
+
+// installWithHooks runs an install between the pre_install and post_install hooks. A failed pre_install
+// hook cancels the install. A failed post_install hook is only reported, the package being installed.
+func (s *InputService) installWithHooks(pkg models.Package, install func() error) error {
+	hooks := s.appService.config.Hooks
+	if hooks == nil {
+		return install()
+	}
+	if err := s.runHook("pre_install", hooks.PreInstall, packageHookEnv(pkg)); err != nil {
+		return err
+	}
+	err := install()
+	_ = s.runHook("post_install", hooks.PostInstall, append(packageHookEnv(pkg), resultHookEnv(err)...))
+	return err
+}
+
+// runUpdateAllHook runs the post_update_all hook after Update All, on the packages it upgraded.
+func (s *InputService) runUpdateAllHook(packages []models.Package, err error) {
+	hooks := s.appService.config.Hooks
+	if hooks == nil {
+		return
+	}
+	names := make([]string, len(packages))
+	for i, pkg := range packages {
+		names[i] = pkg.Name
+	}
+	env := append(resultHookEnv(err), "BBREW_PACKAGES="+strings.Join(names, " "))
+	_ = s.runHook("post_update_all", hooks.PostUpdateAll, env)
+}
+
+// runScheduledHook runs a hook of `bbrew upgrade`, which has no TUI: its output is written to out.
+func runScheduledHook(out io.Writer, hook, command string, env []string) {
+	if command == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	fmt.Fprintf(out, "Running the %s hook...\n", hook)
+	cmd := hookCommand(ctx, hook, command, env)
+	cmd.Stdout, cmd.Stderr = out, out
+	if err := cmd.Run(); err != nil {
+		hooksLog.Warn("hook failed", "hook", hook, "err", err)
+		fmt.Fprintf(out, "The %s hook failed: %v\n", hook, err)
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// The icon of a cask comes from the bundle of its app when installed, or else from the apple-touch-icon of its
+// homepage: Homebrew publishes no icons, and the artifacts of a cask only name its app, not an image.
+
+const (
+	cacheDirIcons = "icons"
+
+	// iconMaxSize bounds the icons read from the network or from an icns file.
+	iconMaxSize = 2 << 20
+)
+
+// iconFilePattern matches the icon file of an app in its Info.plist (XML format).
+var iconFilePattern = regexp.MustCompile(`<key>CFBundleIconFile</key>\s*<string>([^<]+)</string>`)
+
+// caskIcon returns the icon of a cask as PNG, from its installed app or its homepage, or nil if it has none.
+// Icons of homepages are cached, as well as their absence.
+func caskIcon(pkg models.Package) []byte {
+	if pkg.Type != models.PackageTypeCask {
+		return nil
+	}
+	if pkg.LocallyInstalled && pkg.Cask != nil {
+		for _, app := range pkg.Cask.Apps() {
+			if path, ok := caskAppPath(app); ok {
+				if data := appBundleIcon(path); data != nil {
+					return data
+				}
+			}
+		}
+	}
+	return homepageIcon(pkg.Name, pkg.Homepage)
+}
+
+// appBundleIcon returns the largest PNG image of the icns icon of an app bundle, named by its Info.plist
+// or else the first icns file of its resources.
+func appBundleIcon(app string) []byte {
+	resources := filepath.Join(app, "Contents", "Resources")
+	var candidates []string
+	// #nosec G304 -- path of an installed app bundle
+	if plist, err := os.ReadFile(filepath.Join(app, "Contents", "Info.plist")); err == nil {
+		if match := iconFilePattern.FindSubmatch(plist); match != nil {
+			name := strings.TrimSpace(string(match[1]))
+			if filepath.Ext(name) == "" {
+				name += ".icns"
+			}
+			candidates = append(candidates, filepath.Join(resources, filepath.Base(name)))
+		}
+	}
+	candidates = append(candidates, filepath.Join(resources, "AppIcon.icns"))
+	if matches, err := filepath.Glob(filepath.Join(resources, "*.icns")); err == nil && len(matches) > 0 {
+		candidates = append(candidates, matches[0])
+	}
+
+	for _, candidate := range candidates {
+		// #nosec G304 -- path of an installed app bundle
+		data, err := os.ReadFile(candidate)
+		if err != nil || len(data) > 8*iconMaxSize {
+			continue
+		}
+		if icon := icnsPNG(data); icon != nil {
+			return icon
+		}
+	}
+	return nil
+}
+
+// icnsPNG returns the largest PNG image of an icns file, or nil if it only has the legacy image formats.
+// An icns file is a header ("icns", size) followed by entries (type, size including the 8 byte entry header).
+func icnsPNG(data []byte) []byte {
+	if len(data) < 8 || string(data[:4]) != "icns" {
+		return nil
+	}
+	var best []byte
+	for offset := 8; offset+8 <= len(data); {
+		size := int(binary.BigEndian.Uint32(data[offset+4 : offset+8]))
+		if size < 8 || offset+size > len(data) {
+			break
+		}
+		entry := data[offset+8 : offset+size]
+		if bytes.HasPrefix(entry, []byte("\x89PNG")) && len(entry) > len(best) && len(entry) <= iconMaxSize {
+			best = entry
+		}
+		offset += size
+	}
+	return best
+}
+
+// homepageIcon returns the apple-touch-icon of a homepage, the usual square icon of a site, cached by cask.
+// An empty file in the cache records that the homepage has none.
+func homepageIcon(name, homepage string) []byte {
+	if homepage == "" || ensureCacheDir() != nil {
+		return nil
+	}
+	dir := filepath.Join(getCacheDir(), cacheDirIcons)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil
+	}
+	cacheFile := filepath.Join(dir, name+".png")
+	// #nosec G304 -- cacheFile path is safely constructed from getCacheDir
+	if data, err := os.ReadFile(cacheFile); err == nil {
+		if len(data) == 0 {
+			return nil
+		}
+		return data
+	}
+
+	data, err := fetchHomepageIcon(homepage)
+	if err != nil {
+		appLog.Debug("no icon on the homepage", "cask", name, "err", err)
+		data = nil
+	}
+	_ = os.WriteFile(cacheFile, data, 0600)
+	return data
+}
+
+// fetchHomepageIcon downloads /apple-touch-icon.png from the site of a homepage, checking that it is a PNG.
+func fetchHomepageIcon(homepage string) ([]byte, error) {
+	site, err := url.Parse(homepage)
+	if err != nil || site.Host == "" {
+		return nil, fmt.Errorf("invalid homepage %q", homepage)
+	}
+	iconURL := url.URL{Scheme: site.Scheme, Host: site.Host, Path: "/apple-touch-icon.png"}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(iconURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", iconURL.String(), resp.Status)
+	}
+
+	var b bytes.Buffer
+	if _, err := b.ReadFrom(http.MaxBytesReader(nil, resp.Body, iconMaxSize)); err != nil {
+		return nil, err
+	}
+	if _, err := png.DecodeConfig(bytes.NewReader(b.Bytes())); err != nil {
+		return nil, fmt.Errorf("%s: not a PNG image", iconURL.String())
+	}
+	return b.Bytes(), nil
+}
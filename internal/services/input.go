@@ -1,11 +1,22 @@
 package services
 
 import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
 	"bbrew/internal/models"
 	"bbrew/internal/ui"
+	"bbrew/internal/ui/components"
+	"context"
+	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 )
 
 // FilterType represents the active package filter state.
@@ -17,6 +28,52 @@ const (
 	FilterOutdated
 	FilterLeaves
 	FilterCasks
+	FilterNoBottle
+	FilterMaintained
+	FilterVulnerable
+	FilterFavorites
+	FilterLicense // Installed packages with the license chosen in the license audit
+)
+
+// filterNames maps the filter names accepted on the command line to filter types.
+var filterNames = map[string]FilterType{
+	"all":        FilterNone,
+	"installed":  FilterInstalled,
+	"outdated":   FilterOutdated,
+	"leaves":     FilterLeaves,
+	"casks":      FilterCasks,
+	"source":     FilterNoBottle,
+	"maintained": FilterMaintained,
+	"vulnerable": FilterVulnerable,
+	"favorites":  FilterFavorites,
+}
+
+// quickFilters are the filters applied by the number keys, from 1, and shown as tabs above the table,
+// with their untranslated labels.
+var quickFilters = []struct {
+	filter FilterType
+	label  string
+}{
+	{FilterNone, "All"}, {FilterInstalled, "Installed"}, {FilterOutdated, "Outdated"},
+	{FilterLeaves, "Leaves"}, {FilterCasks, "Casks"},
+}
+
+// ParseFilterType converts a filter name (e.g. "outdated") to a FilterType.
+func ParseFilterType(name string) (FilterType, error) {
+	if filter, exists := filterNames[strings.ToLower(name)]; exists {
+		return filter, nil
+	}
+	return FilterNone, fmt.Errorf("unknown filter %q (valid: all, installed, outdated, leaves, casks, source, maintained, vulnerable, favorites)", name)
+}
+
+// ActionCategory groups the actions on the help screen.
+type ActionCategory int
+
+const (
+	CategoryNavigation ActionCategory = iota
+	CategoryFilters
+	CategoryActions
+	CategoryBrewfile
 )
 
 // InputAction represents a user action that can be triggered by a key event.
@@ -26,13 +83,22 @@ type InputAction struct {
 	Name           string
 	KeySlug        string
 	Action         func()
-	HideFromLegend bool // If true, this action won't appear in the legend bar
+	HideFromLegend bool           // If true, this action won't appear in the legend bar
+	Category       ActionCategory // Section of the help screen
+	Help           string         // Description on the help screen, Name if empty
 }
 
 // InputServiceInterface defines the interface for handling user input actions.
 type InputServiceInterface interface {
 	HandleKeyEventInput(event *tcell.EventKey) *tcell.EventKey
 	EnableBrewfileMode()
+	EnableReadOnlyMode()
+	UpdateFilterUI()
+	UpdateOperationUI()
+	RequestInstall(pkg models.Package)
+	PromptPassword(prompt string) (string, bool)
+	ShowProfilePicker()
+	IsRecordingMacro() bool
 }
 
 // InputService implements the InputServiceInterface and handles key events for the application.
@@ -43,21 +109,86 @@ type InputService struct {
 	keyActions    []*InputAction
 	legendEntries []struct{ KeySlug, Name string }
 
+	// The goto key was pressed: the next letter jumps to the first package starting with it
+	gotoPending bool
+
+	// Actions removed by read-only mode: their keys show a notice instead
+	disabledActions []*InputAction
+
+	// Macros of the config, bound to their keys, and the keys of the macro being recorded (nil when not recording)
+	macros        []*InputAction
+	recordedSteps []string
+
+	// Last directory packages were downloaded to (d)
+	downloadDir string
+
+	// Last batch operation and the packages that failed, to retry them
+	lastBatch   batchOperation
+	failedBatch []models.Package
+
 	// Actions for each key input
-	ActionSearch          *InputAction
-	ActionFilterInstalled *InputAction
-	ActionFilterOutdated  *InputAction
-	ActionFilterLeaves    *InputAction
-	ActionFilterCasks     *InputAction
-	ActionInstall         *InputAction
-	ActionUpdate          *InputAction
-	ActionRemove          *InputAction
-	ActionUpdateAll       *InputAction
-	ActionInstallAll      *InputAction
-	ActionRemoveAll       *InputAction
-	ActionHelp            *InputAction
-	ActionBack            *InputAction
-	ActionQuit            *InputAction
+	ActionSearch           *InputAction
+	ActionGoto             *InputAction
+	ActionFilterInstalled  *InputAction
+	ActionFilterOutdated   *InputAction
+	ActionFilterLeaves     *InputAction
+	ActionFilterCasks      *InputAction
+	ActionFilterNoBottle   *InputAction
+	ActionFilterMaintained *InputAction
+	ActionFilterVulnerable *InputAction
+	ActionFilterFavorites  *InputAction
+	ActionQuickFilters     []*InputAction // 1 to 5, applying quickFilters
+	ActionStar             *InputAction
+	ActionExportFavorites  *InputAction
+	ActionWatch            *InputAction
+	ActionWatchlist        *InputAction
+	ActionExportInventory  *InputAction
+	ActionCompareInventory *InputAction
+	ActionCopyURL          *InputAction
+	ActionDownload         *InputAction
+	ActionUpgradePolicy    *InputAction
+	ActionProtect          *InputAction
+	ActionRestore          *InputAction
+	ActionProfiles         *InputAction
+	ActionHealth           *InputAction
+	ActionSettings         *InputAction
+	ActionStorage          *InputAction
+	ActionServices         *InputAction
+	ActionRecordMacro      *InputAction
+	ActionMark             *InputAction
+	ActionInstall          *InputAction
+	ActionUpdate           *InputAction
+	ActionRemove           *InputAction
+	ActionAdopt            *InputAction
+	ActionMigrate          *InputAction
+	ActionTerminal         *InputAction
+	ActionUpdateAll        *InputAction
+	ActionBrewUpdate       *InputAction
+	ActionInstallAll       *InputAction
+	ActionRemoveAll        *InputAction
+	ActionGroupView        *InputAction
+	ActionTypeView         *InputAction
+	ActionInstallGroup     *InputAction
+	ActionInstallTaps      *InputAction
+	ActionRetryFailed      *InputAction
+	ActionFailureActions   *InputAction
+	ActionBundleCleanup    *InputAction
+	ActionExpertMode       *InputAction
+	ActionNote             *InputAction
+	ActionFiles            *InputAction
+	ActionProvides         *InputAction
+	ActionTapAudit         *InputAction
+	ActionColumns          *InputAction
+	ActionLicenses         *InputAction
+	ActionInspect          *InputAction
+	ActionPrevOperation    *InputAction
+	ActionNextOperation    *InputAction
+	ActionDescription      *InputAction
+	ActionReleaseNotes     *InputAction
+	ActionChangelog        *InputAction
+	ActionHelp             *InputAction
+	ActionBack             *InputAction
+	ActionQuit             *InputAction
 }
 
 var NewInputService = func(appService *AppService, brewService BrewServiceInterface) InputServiceInterface {
@@ -69,72 +200,342 @@ var NewInputService = func(appService *AppService, brewService BrewServiceInterf
 
 	// Initialize actions with key bindings and handlers
 	s.ActionSearch = &InputAction{
-		Key: tcell.KeyRune, Rune: '/', KeySlug: "/", Name: "Search",
-		Action: s.handleSearchFieldEvent,
+		Key: tcell.KeyRune, Rune: '/', KeySlug: "/", Name: i18n.T("Search"),
+		Action: s.handleSearchFieldEvent, Category: CategoryNavigation,
+		Help: i18n.T("Focus search"),
+	}
+	s.ActionGoto = &InputAction{
+		Key: tcell.KeyRune, Rune: '\'', KeySlug: "'", Name: i18n.T("Go to"),
+		Action: s.handleGotoEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Jump to a letter (then press it)"),
 	}
 	s.ActionFilterInstalled = &InputAction{
-		Key: tcell.KeyRune, Rune: 'f', KeySlug: "f", Name: "Installed",
-		Action: s.handleFilterPackagesEvent,
+		Key: tcell.KeyRune, Rune: 'f', KeySlug: "f", Name: i18n.T("Installed"),
+		Action: s.handleFilterPackagesEvent, Category: CategoryFilters,
+		Help: i18n.T("Toggle installed"),
 	}
 	s.ActionFilterOutdated = &InputAction{
-		Key: tcell.KeyRune, Rune: 'o', KeySlug: "o", Name: "Outdated",
+		Key: tcell.KeyRune, Rune: 'o', KeySlug: "o", Name: i18n.T("Outdated"),
 		Action: s.handleFilterOutdatedPackagesEvent, HideFromLegend: true,
+		Category: CategoryFilters, Help: i18n.T("Toggle outdated"),
 	}
 	s.ActionFilterLeaves = &InputAction{
-		Key: tcell.KeyRune, Rune: 'l', KeySlug: "l", Name: "Leaves",
+		Key: tcell.KeyRune, Rune: 'l', KeySlug: "l", Name: i18n.T("Leaves"),
 		Action: s.handleFilterLeavesEvent, HideFromLegend: true,
+		Category: CategoryFilters, Help: i18n.T("Toggle leaves"),
 	}
 	s.ActionFilterCasks = &InputAction{
-		Key: tcell.KeyRune, Rune: 'c', KeySlug: "c", Name: "Casks",
+		Key: tcell.KeyRune, Rune: 'c', KeySlug: "c", Name: i18n.T("Casks"),
 		Action: s.handleFilterCasksEvent, HideFromLegend: true,
+		Category: CategoryFilters, Help: i18n.T("Toggle casks"),
+	}
+	s.ActionFilterNoBottle = &InputAction{
+		Key: tcell.KeyRune, Rune: 'b', KeySlug: "b", Name: i18n.T("Source Builds"),
+		Action: s.handleFilterNoBottleEvent, HideFromLegend: true,
+		Category: CategoryFilters, Help: i18n.T("Toggle source builds (no bottle)"),
+	}
+	s.ActionFilterMaintained = &InputAction{
+		Key: tcell.KeyRune, Rune: 'm', KeySlug: "m", Name: i18n.T("Maintained"),
+		Action: s.handleFilterMaintainedEvent, HideFromLegend: true,
+		Category: CategoryFilters, Help: i18n.T("Toggle maintained (GitHub)"),
+	}
+	s.ActionFilterVulnerable = &InputAction{
+		Key: tcell.KeyRune, Rune: 'x', KeySlug: "x", Name: i18n.T("Vulnerable"),
+		Action: s.handleFilterVulnerableEvent, HideFromLegend: true,
+		Category: CategoryFilters, Help: i18n.T("Toggle vulnerable (OSV.dev)"),
+	}
+	s.ActionFilterFavorites = &InputAction{
+		Key: tcell.KeyRune, Rune: 'S', KeySlug: "S", Name: i18n.T("Favorites"),
+		Action: s.handleFilterFavoritesEvent, HideFromLegend: true,
+		Category: CategoryFilters, Help: i18n.T("Toggle favorites"),
+	}
+
+	// Number keys apply the common filters directly, instead of toggling them
+	for i, quick := range quickFilters {
+		filter, key := quick.filter, strconv.Itoa(i+1)
+		s.ActionQuickFilters = append(s.ActionQuickFilters, &InputAction{
+			Key: tcell.KeyRune, Rune: rune('1' + i), KeySlug: key, Name: i18n.T(quick.label),
+			Action: func() { s.setFilter(filter) }, HideFromLegend: true,
+			Category: CategoryFilters, Help: i18n.T("Show %s", i18n.T(quick.label)),
+		})
+	}
+	s.ActionStar = &InputAction{
+		Key: tcell.KeyRune, Rune: 's', KeySlug: "s", Name: i18n.T("Star"),
+		Action: s.handleStarEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Star/unstar selected"),
+	}
+	s.ActionExportFavorites = &InputAction{
+		Key: tcell.KeyRune, Rune: 'B', KeySlug: "B", Name: i18n.T("Favorites to Brewfile"),
+		Action: s.handleExportFavoritesEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Append favorites to Brewfile"),
+	}
+	s.ActionWatch = &InputAction{
+		Key: tcell.KeyRune, Rune: 'W', KeySlug: "W", Name: i18n.T("Watch"),
+		Action: s.handleWatchEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Watch/unwatch selected"),
+	}
+	s.ActionWatchlist = &InputAction{
+		Key: tcell.KeyRune, Rune: 'V', KeySlug: "V", Name: i18n.T("Watchlist"),
+		Action: s.handleWatchlistEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Show watchlist"),
+	}
+	s.ActionProfiles = &InputAction{
+		Key: tcell.KeyRune, Rune: 'O', KeySlug: "O", Name: i18n.T("Profiles"),
+		Action: s.ShowProfilePicker, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Switch, compare or save profiles"),
+	}
+	s.ActionHealth = &InputAction{
+		Key: tcell.KeyRune, Rune: 'H', KeySlug: "H", Name: i18n.T("Health"),
+		Action: s.handleHealthEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Check the environment, e.g. commands shadowed in the PATH"),
+	}
+	s.ActionSettings = &InputAction{
+		Key: tcell.KeyRune, Rune: ',', KeySlug: ",", Name: i18n.T("Settings"),
+		Action: s.handleSettingsEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Homebrew analytics and bbrew telemetry"),
+	}
+	s.ActionStorage = &InputAction{
+		Key: tcell.KeyRune, Rune: 'z', KeySlug: "z", Name: i18n.T("Disk Usage"),
+		Action: s.handleStorageEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Disk usage of Homebrew's cache and old versions, to clean them up"),
+	}
+	s.ActionServices = &InputAction{
+		Key: tcell.KeyRune, Rune: 'J', KeySlug: "J", Name: i18n.T("Services"),
+		Action: s.handleServicesEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Start, stop or enable the services of formulae (systemd, on Linux)"),
+	}
+	s.ActionRecordMacro = &InputAction{
+		Key: tcell.KeyRune, Rune: 'Q', KeySlug: "Q", Name: i18n.T("Record Macro"),
+		Action: s.handleRecordMacroEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Record a macro, then bind it to a key"),
+	}
+	s.ActionExportInventory = &InputAction{
+		Key: tcell.KeyRune, Rune: 'X', KeySlug: "X", Name: i18n.T("Export Inventory"),
+		Action: s.handleExportInventoryEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Export installed packages, to compare machines"),
+	}
+	s.ActionCompareInventory = &InputAction{
+		Key: tcell.KeyRune, Rune: 'D', KeySlug: "D", Name: i18n.T("Compare Inventory"),
+		Action: s.handleCompareInventoryEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Compare with another machine's inventory or Brewfile"),
+	}
+	s.ActionCopyURL = &InputAction{
+		Key: tcell.KeyRune, Rune: 'y', KeySlug: "y", Name: i18n.T("Copy URL"),
+		Action: s.handleCopyURLEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Copy the bottle or download URL"),
+	}
+	s.ActionDownload = &InputAction{
+		Key: tcell.KeyRune, Rune: 'd', KeySlug: "d", Name: i18n.T("Download"),
+		Action: s.handleDownloadEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Download the bottle or cask to a directory"),
+	}
+	s.ActionUpgradePolicy = &InputAction{
+		Key: tcell.KeyRune, Rune: 'p', KeySlug: "p", Name: i18n.T("Upgrade Policy"),
+		Action: s.handleUpgradePolicyEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Cycle upgrade policy (ask/auto/hold)"),
+	}
+	s.ActionProtect = &InputAction{
+		Key: tcell.KeyRune, Rune: 'K', KeySlug: "K", Name: i18n.T("Protect"),
+		Action: s.handleProtectEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Protect from removal (toggle)"),
+	}
+	s.ActionRestore = &InputAction{
+		Key: tcell.KeyRune, Rune: 'Z', KeySlug: "Z", Name: i18n.T("Restore"),
+		Action: s.handleRestoreEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Reinstall a recently removed package"),
+	}
+	s.ActionMark = &InputAction{
+		Key: tcell.KeyRune, Rune: ' ', KeySlug: "space", Name: i18n.T("Mark"),
+		Action: s.handleMarkEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Mark or unmark selected, for i, u and r"),
 	}
 	s.ActionInstall = &InputAction{
-		Key: tcell.KeyRune, Rune: 'i', KeySlug: "i", Name: "Install",
-		Action: s.handleInstallPackageEvent,
+		Key: tcell.KeyRune, Rune: 'i', KeySlug: "i", Name: i18n.T("Install"),
+		Action: s.handleInstallPackageEvent, Category: CategoryActions,
+		Help: i18n.T("Install selected, or the marked packages"),
 	}
 	s.ActionUpdate = &InputAction{
-		Key: tcell.KeyRune, Rune: 'u', KeySlug: "u", Name: "Update",
-		Action: s.handleUpdatePackageEvent,
+		Key: tcell.KeyRune, Rune: 'u', KeySlug: "u", Name: i18n.T("Update"),
+		Action: s.handleUpdatePackageEvent, Category: CategoryActions,
+		Help: i18n.T("Update selected, or the marked packages"),
 	}
 	s.ActionRemove = &InputAction{
-		Key: tcell.KeyRune, Rune: 'r', KeySlug: "r", Name: "Remove",
-		Action: s.handleRemovePackageEvent,
+		Key: tcell.KeyRune, Rune: 'r', KeySlug: "r", Name: i18n.T("Remove"),
+		Action: s.handleRemovePackageEvent, Category: CategoryActions,
+		Help: i18n.T("Remove selected, or the marked packages"),
+	}
+	s.ActionAdopt = &InputAction{
+		Key: tcell.KeyRune, Rune: 'A', KeySlug: "A", Name: i18n.T("Adopt"),
+		Action: s.handleAdoptEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Adopt the existing app as a cask"),
+	}
+	s.ActionMigrate = &InputAction{
+		Key: tcell.KeyRune, Rune: 'M', KeySlug: "M", Name: i18n.T("Migrate"),
+		Action: s.handleMigrateEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Migrate apps to Homebrew (macOS)"),
+	}
+	s.ActionTerminal = &InputAction{
+		Key: tcell.KeyRune, Rune: 't', KeySlug: "t", Name: i18n.T("Terminal"),
+		Action: s.handleTerminalEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Install or update in an external terminal"),
 	}
 	s.ActionUpdateAll = &InputAction{
-		Key: tcell.KeyCtrlU, Rune: 0, KeySlug: "ctrl+u", Name: "Update All",
+		Key: tcell.KeyCtrlU, Rune: 0, KeySlug: "ctrl+u", Name: i18n.T("Update All"),
 		Action: s.handleUpdateAllPackagesEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Update all"),
+	}
+	s.ActionBrewUpdate = &InputAction{
+		Key: tcell.KeyRune, Rune: 'U', KeySlug: "U", Name: i18n.T("Update Homebrew"),
+		Action: s.handleBrewUpdateEvent, Category: CategoryActions,
+		Help: i18n.T("Update Homebrew (brew update)"),
 	}
 	s.ActionInstallAll = &InputAction{
-		Key: tcell.KeyCtrlA, Rune: 0, KeySlug: "ctrl+a", Name: "Install All (Brewfile)",
-		Action: s.handleInstallAllPackagesEvent,
+		Key: tcell.KeyCtrlA, Rune: 0, KeySlug: "ctrl+a", Name: i18n.T("Install All (Brewfile)"),
+		Action: s.handleInstallAllPackagesEvent, Category: CategoryBrewfile,
+		Help: i18n.T("Install all"),
 	}
 	s.ActionRemoveAll = &InputAction{
-		Key: tcell.KeyCtrlR, Rune: 0, KeySlug: "ctrl+r", Name: "Remove All (Brewfile)",
-		Action: s.handleRemoveAllPackagesEvent,
+		Key: tcell.KeyCtrlR, Rune: 0, KeySlug: "ctrl+r", Name: i18n.T("Remove All (Brewfile)"),
+		Action: s.handleRemoveAllPackagesEvent, Category: CategoryBrewfile,
+		Help: i18n.T("Remove all"),
+	}
+	s.ActionGroupView = &InputAction{
+		Key: tcell.KeyRune, Rune: 'g', KeySlug: "g", Name: i18n.T("Group"),
+		Action: s.handleGroupViewEvent, HideFromLegend: true,
+		Category: CategoryBrewfile, Help: i18n.T("Group by section"),
+	}
+	s.ActionInstallGroup = &InputAction{
+		Key: tcell.KeyRune, Rune: 'G', KeySlug: "G", Name: i18n.T("Install Group"),
+		Action: s.handleInstallGroupEvent, HideFromLegend: true,
+		Category: CategoryBrewfile, Help: i18n.T("Install section"),
+	}
+	s.ActionInstallTaps = &InputAction{
+		Key: tcell.KeyRune, Rune: 'T', KeySlug: "T", Name: i18n.T("Install Taps"),
+		Action: s.handleInstallTapsEvent, HideFromLegend: true,
+		Category: CategoryBrewfile, Help: i18n.T("Install missing taps"),
+	}
+	s.ActionRetryFailed = &InputAction{
+		Key: tcell.KeyRune, Rune: 'F', KeySlug: "F", Name: i18n.T("Retry failed"),
+		Action: s.handleRetryFailedEvent, HideFromLegend: true,
+		Category: CategoryBrewfile, Help: i18n.T("Retry failed"),
+	}
+	s.ActionFailureActions = &InputAction{
+		Key: tcell.KeyRune, Rune: '!', KeySlug: "!", Name: i18n.T("Failure Actions"),
+		Action: s.handleFailureActionsEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Act on the last failure (log, retry, issues)"),
+	}
+	s.ActionBundleCleanup = &InputAction{
+		Key: tcell.KeyRune, Rune: 'C', KeySlug: "C", Name: i18n.T("Bundle Cleanup"),
+		Action: s.handleBundleCleanupEvent, Category: CategoryBrewfile,
+		Help: i18n.T("Uninstall what's not in the Brewfile"),
+	}
+	s.ActionExpertMode = &InputAction{
+		Key: tcell.KeyRune, Rune: 'E', KeySlug: "E", Name: i18n.T("Expert Mode"),
+		Action: s.handleExpertModeEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Toggle expert mode (no confirmation)"),
+	}
+	s.ActionNote = &InputAction{
+		Key: tcell.KeyRune, Rune: 'N', KeySlug: "N", Name: i18n.T("Note"),
+		Action: s.handleNoteEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Edit note"),
+	}
+	s.ActionFiles = &InputAction{
+		Key: tcell.KeyRune, Rune: 'L', KeySlug: "L", Name: i18n.T("Files"),
+		Action: s.handleFilesEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("List the files of the installed package"),
+	}
+	s.ActionProvides = &InputAction{
+		Key: tcell.KeyRune, Rune: 'P', KeySlug: "P", Name: i18n.T("Provides"),
+		Action: s.handleProvidesEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Find the package providing a command"),
+	}
+	s.ActionTapAudit = &InputAction{
+		Key: tcell.KeyRune, Rune: 'R', KeySlug: "R", Name: i18n.T("Tap Audit"),
+		Action: s.handleTapAuditEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("Audit a local tap (for tap maintainers)"),
+	}
+	s.ActionTypeView = &InputAction{
+		Key: tcell.KeyRune, Rune: 'g', KeySlug: "g", Name: i18n.T("Group"),
+		Action: s.handleTypeViewEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Group by type"),
+	}
+	s.ActionColumns = &InputAction{
+		Key: tcell.KeyRune, Rune: 'v', KeySlug: "v", Name: i18n.T("Columns"),
+		Action: s.handleColumnsEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Choose columns"),
+	}
+	s.ActionLicenses = &InputAction{
+		Key: tcell.KeyRune, Rune: 'a', KeySlug: "a", Name: i18n.T("Licenses"),
+		Action: s.handleLicenseAuditEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("License audit"),
+	}
+	s.ActionInspect = &InputAction{
+		Key: tcell.KeyRune, Rune: 'I', KeySlug: "I", Name: i18n.T("Raw Info"),
+		Action: s.handleInspectEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Inspect raw JSON"),
+	}
+	s.ActionPrevOperation = &InputAction{
+		Key: tcell.KeyRune, Rune: '[', KeySlug: "[", Name: i18n.T("Previous Operation"),
+		Action: func() { s.jumpToOperation(-1) }, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Scroll the output to the previous operation"),
+	}
+	s.ActionNextOperation = &InputAction{
+		Key: tcell.KeyRune, Rune: ']', KeySlug: "]", Name: i18n.T("Next Operation"),
+		Action: func() { s.jumpToOperation(1) }, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Scroll the output to the next operation"),
+	}
+	s.ActionDescription = &InputAction{
+		Key: tcell.KeyRune, Rune: 'e', KeySlug: "e", Name: i18n.T("Description"),
+		Action: s.handleDescriptionEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Show the full description"),
+	}
+	s.ActionChangelog = &InputAction{
+		Key: tcell.KeyRune, Rune: 'w', KeySlug: "w", Name: i18n.T("What's New"),
+		Action: s.handleChangelogEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("What's new in the update"),
+	}
+	s.ActionReleaseNotes = &InputAction{
+		Key: tcell.KeyRune, Rune: 'n', KeySlug: "n", Name: i18n.T("Release Notes"),
+		Action: s.handleReleaseNotesEvent, HideFromLegend: true,
+		Category: CategoryActions, Help: i18n.T("New version notes"),
 	}
 	s.ActionHelp = &InputAction{
-		Key: tcell.KeyRune, Rune: '?', KeySlug: "?", Name: "Help",
-		Action: s.handleHelpEvent,
+		Key: tcell.KeyRune, Rune: '?', KeySlug: "?", Name: i18n.T("Help"),
+		Action: s.handleHelpEvent, Category: CategoryNavigation,
+		Help: i18n.T("Show this help"),
 	}
 	s.ActionBack = &InputAction{
-		Key: tcell.KeyEsc, Rune: 0, KeySlug: "esc", Name: "Back to Table",
-		Action: s.handleBack, HideFromLegend: true,
+		Key: tcell.KeyEsc, Rune: 0, KeySlug: "esc", Name: i18n.T("Back to Table"),
+		Action: s.handleBackEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Back to table, dismiss an error or unmark"),
 	}
 	s.ActionQuit = &InputAction{
-		Key: tcell.KeyRune, Rune: 'q', KeySlug: "q", Name: "Quit",
+		Key: tcell.KeyRune, Rune: 'q', KeySlug: "q", Name: i18n.T("Quit"),
 		Action: s.handleQuitEvent, HideFromLegend: true,
+		Category: CategoryNavigation, Help: i18n.T("Quit"),
 	}
 
 	// Build keyActions slice (InstallAll/RemoveAll added dynamically in Brewfile mode)
 	s.keyActions = []*InputAction{
-		s.ActionSearch, s.ActionFilterInstalled, s.ActionFilterOutdated,
-		s.ActionFilterLeaves, s.ActionFilterCasks, s.ActionInstall,
-		s.ActionUpdate, s.ActionRemove, s.ActionUpdateAll,
-		s.ActionHelp, s.ActionBack, s.ActionQuit,
+		s.ActionSearch, s.ActionGoto, s.ActionFilterInstalled, s.ActionFilterOutdated,
+		s.ActionFilterLeaves, s.ActionFilterCasks, s.ActionFilterNoBottle,
+		s.ActionFilterMaintained, s.ActionFilterVulnerable, s.ActionFilterFavorites,
+		s.ActionStar, s.ActionExportFavorites, s.ActionWatch, s.ActionWatchlist, s.ActionProfiles, s.ActionHealth, s.ActionSettings, s.ActionStorage, s.ActionServices, s.ActionRecordMacro, s.ActionExportInventory,
+		s.ActionCompareInventory, s.ActionCopyURL, s.ActionDownload, s.ActionMark, s.ActionInstall,
+		s.ActionUpdate, s.ActionRemove, s.ActionRestore, s.ActionAdopt, s.ActionMigrate, s.ActionTerminal, s.ActionUpgradePolicy, s.ActionProtect, s.ActionUpdateAll, s.ActionBrewUpdate,
+		s.ActionFailureActions, s.ActionExpertMode, s.ActionChangelog, s.ActionTypeView, s.ActionColumns, s.ActionLicenses, s.ActionInspect, s.ActionDescription,
+		s.ActionPrevOperation, s.ActionNextOperation,
+		s.ActionFiles, s.ActionProvides, s.ActionTapAudit, s.ActionNote, s.ActionReleaseNotes, s.ActionHelp, s.ActionBack, s.ActionQuit,
 	}
+	s.keyActions = append(s.keyActions, s.ActionQuickFilters...)
+
+	// Bind the macros of the config to their keys
+	s.setMacroActions()
 
 	// Convert keyActions to legend entries
 	s.updateLegendEntries()
+	s.updateFilterTabs()
+	s.layout.GetDetails().SetActionsLookup(s.quickActions)
 	return s
 }
 
@@ -149,14 +550,41 @@ func (s *InputService) updateLegendEntries() {
 	s.layout.GetLegend().SetLegend(s.legendEntries, "")
 }
 
-// EnableBrewfileMode enables Brewfile mode, adding Install All and Remove All actions to the legend
+// EnableBrewfileMode enables Brewfile mode, adding Install All, Remove All, the group and the tap actions,
+// and the bundle cleanup in bundle mode
 func (s *InputService) EnableBrewfileMode() {
-	// Add Install All and Remove All actions after Update All
+	// Add Install All, Remove All, the group and the tap actions after Update All
 	newActions := []*InputAction{}
 	for _, action := range s.keyActions {
+		if action == s.ActionTypeView {
+			continue // g groups by section instead
+		}
 		newActions = append(newActions, action)
 		if action == s.ActionUpdateAll {
-			newActions = append(newActions, s.ActionInstallAll, s.ActionRemoveAll)
+			newActions = append(newActions, s.ActionInstallAll, s.ActionRemoveAll, s.ActionGroupView, s.ActionInstallGroup, s.ActionInstallTaps, s.ActionRetryFailed)
+			if s.appService.IsBundleMode() {
+				newActions = append(newActions, s.ActionBundleCleanup)
+			}
+		}
+	}
+	s.keyActions = newActions
+	s.updateLegendEntries()
+}
+
+// EnableReadOnlyMode removes the actions that change packages (install, update, remove, taps),
+// used when bbrew runs on a machine without Homebrew or with --read-only.
+func (s *InputService) EnableReadOnlyMode() {
+	disabled := s.brewActions()
+	for _, action := range []*InputAction{s.ActionExpertMode, s.ActionUpgradePolicy, s.ActionProtect, s.ActionStorage, s.ActionServices} {
+		disabled[action] = true
+	}
+
+	newActions := []*InputAction{}
+	for _, action := range s.keyActions {
+		if disabled[action] {
+			s.disabledActions = append(s.disabledActions, action)
+		} else {
+			newActions = append(newActions, action)
 		}
 	}
 	s.keyActions = newActions
@@ -165,47 +593,142 @@ func (s *InputService) EnableBrewfileMode() {
 
 // HandleKeyEventInput processes key events and triggers the corresponding actions.
 func (s *InputService) HandleKeyEventInput(event *tcell.EventKey) *tcell.EventKey {
-	if s.layout.GetSearch().Field().HasFocus() {
+	if s.layout.GetSearch().Field().HasFocus() || s.layout.GetColumnPicker().HasFocus() ||
+		s.layout.GetReleaseNotes().HasFocus() || s.layout.GetLicenseAudit().HasFocus() ||
+		s.layout.GetInspector().HasFocus() || s.layout.GetBatchProgress().HasFocus() ||
+		s.layout.GetModal().HasFocus() || s.layout.GetPrompt().HasFocus() ||
+		s.layout.GetWatchlist().HasFocus() || s.layout.GetHealth().HasFocus() || s.layout.GetSettings().HasFocus() || s.layout.GetStorage().HasFocus() || s.layout.GetServices().HasFocus() || s.layout.GetInventoryDiff().HasFocus() ||
+		s.layout.GetCaveats().HasFocus() || s.layout.GetLog().HasFocus() || s.layout.GetNotifier().HasFocus() ||
+		s.layout.GetMigrate().HasFocus() || s.layout.GetFiles().HasFocus() ||
+		s.layout.GetTapAudit().HasFocus() || s.layout.GetRemoved().HasFocus() ||
+		s.layout.GetProfiles().HasFocus() || s.layout.GetTooltip().HasFocus() {
 		return event
 	}
 
+	if s.gotoPending {
+		s.gotoPending = false
+		s.layout.GetNotifier().Clear()
+		if event.Key() == tcell.KeyRune {
+			s.appService.jumpToInitial(event.Rune())
+		}
+		return nil // Any other key cancels the goto
+	}
+
 	for _, input := range s.keyActions {
-		if event.Modifiers() == tcell.ModNone && input.Key == event.Key() && input.Rune == event.Rune() { // Check Rune
-			if input.Action != nil {
-				input.Action()
-				return nil
-			}
-		} else if event.Modifiers() != tcell.ModNone && input.Key == event.Key() { // Check Key only
-			if input.Action != nil {
-				input.Action()
+		if input.matches(event) && input.Action != nil {
+			if s.denyDuringOperation(input) {
 				return nil
 			}
+			s.recordStep(input)
+			input.Action()
+			return nil
+		}
+	}
+
+	for _, input := range s.disabledActions {
+		if input.matches(event) {
+			s.layout.GetNotifier().ShowWarning(i18n.T("Read-only mode: %s is disabled", input.Name))
+			return nil
 		}
 	}
 
 	return event
 }
 
+// matches returns true if the key event triggers the action: the rune for plain keys, the key only with modifiers.
+func (a *InputAction) matches(event *tcell.EventKey) bool {
+	if event.Modifiers() == tcell.ModNone {
+		return a.Key == event.Key() && a.Rune == event.Rune()
+	}
+	return a.Key == event.Key()
+}
+
 // handleBack is called when the user presses the back key (Esc).
 func (s *InputService) handleBack() {
 	s.appService.GetApp().SetRoot(s.layout.Root(), true)
 	s.appService.GetApp().SetFocus(s.layout.GetTable().View())
 }
 
+// handleBackEvent is called when the user presses the back key (Esc) in the table.
+// It dismisses a pinned error first, then unmarks the marked packages.
+func (s *InputService) handleBackEvent() {
+	if s.layout.GetNotifier().IsPinned() {
+		s.layout.GetNotifier().Dismiss()
+		return
+	}
+	if len(s.appService.marked) > 0 {
+		s.appService.clearMarks()
+		return
+	}
+	s.handleBack()
+}
+
 // handleSearchFieldEvent is called when the user presses the search key (/).
 func (s *InputService) handleSearchFieldEvent() {
 	s.appService.GetApp().SetFocus(s.layout.GetSearch().Field())
 }
 
+// handleGotoEvent is called when the user presses the goto key (').
+// The next letter or digit jumps to the first package starting with it.
+func (s *InputService) handleGotoEvent() {
+	s.gotoPending = true
+	s.layout.GetNotifier().ShowWarning(i18n.T("Go to: press a letter"))
+}
+
 // handleQuitEvent is called when the user presses the quit key (q).
+// After package operations, it shows what the session changed before quitting.
 func (s *InputService) handleQuitEvent() {
-	s.appService.GetApp().Stop()
+	summary, ok := s.appService.sessionSummary()
+	if !ok {
+		s.appService.GetApp().Stop()
+		return
+	}
+
+	ellipsis := s.appService.theme.Symbols.Ellipsis
+	text := i18n.T("Session summary (%s)", summary.Duration.Round(time.Second)) + "\n\n" +
+		i18n.T("Installed: %s", sessionChangeNames(summary.Installed, ellipsis)) + "\n" +
+		i18n.T("Updated: %s", sessionChangeNames(summary.Updated, ellipsis)) + "\n" +
+		i18n.T("Removed: %s", sessionChangeNames(summary.Removed, ellipsis))
+	if len(summary.Failures) > 0 {
+		names := make([]string, len(summary.Failures))
+		for i, failure := range summary.Failures {
+			names[i] = failure.Package
+		}
+		text += "\n" + i18n.T("Failed: %s", summaryList(names, ellipsis))
+	}
+	text += "\n\n" + i18n.T("Quit Bold Brew?")
+
+	s.showModal(components.ModalOptions{Text: text, Cancel: s.closeModal, Confirm: func() {
+		s.appService.GetApp().Stop()
+	}})
+}
+
+// sessionChangeNames lists the packages of session changes for the quit summary.
+func sessionChangeNames(changes []sessionChange, ellipsis string) string {
+	names := make([]string, len(changes))
+	for i, change := range changes {
+		names[i] = change.Name
+	}
+	return summaryList(names, ellipsis)
+}
+
+// summaryList formats a count and the first few names: "7 (git, node, wget, …)".
+func summaryList(names []string, ellipsis string) string {
+	const shown = 5
+	if len(names) == 0 {
+		return "0"
+	}
+	list := names
+	if len(list) > shown {
+		list = append(list[:shown:shown], ellipsis)
+	}
+	return fmt.Sprintf("%d (%s)", len(names), tview.Escape(strings.Join(list, ", ")))
 }
 
 // handleHelpEvent shows the help screen with all keyboard shortcuts.
 func (s *InputService) handleHelpEvent() {
 	helpScreen := s.layout.GetHelpScreen()
-	helpScreen.SetBrewfileMode(s.appService.IsBrewfileMode())
+	helpScreen.SetSections(s.helpSections())
 	helpPages := helpScreen.Build(s.layout.Root())
 
 	// Set up key handler to close help on any key press
@@ -219,6 +742,440 @@ func (s *InputService) handleHelpEvent() {
 	s.appService.GetApp().SetRoot(helpPages, true)
 }
 
+// handleGroupViewEvent is called when the user presses the group key (g) in Brewfile mode.
+func (s *InputService) handleGroupViewEvent() {
+	s.appService.ToggleGroupedView()
+}
+
+// handleTypeViewEvent is called when the user presses the group key (g) outside Brewfile mode.
+func (s *InputService) handleTypeViewEvent() {
+	s.appService.ToggleTypeView()
+}
+
+// handleColumnsEvent shows the column picker to choose and reorder the table columns.
+func (s *InputService) handleColumnsEvent() {
+	// Visible columns first (in their current order), followed by the hidden ones
+	visible := make(map[ColumnID]bool)
+	items := make([]components.ColumnItem, 0, len(columnSpecs))
+	for _, col := range s.appService.visibleColumns() {
+		visible[col.id] = true
+		items = append(items, components.ColumnItem{ID: string(col.id), Label: i18n.T(col.header), Visible: true})
+	}
+	for _, col := range columnSpecs {
+		if !visible[col.id] {
+			items = append(items, components.ColumnItem{ID: string(col.id), Label: i18n.T(col.header), Visible: false})
+		}
+	}
+
+	pickerPages := s.layout.GetColumnPicker().Build(s.layout.Root(), items, func(items []components.ColumnItem) {
+		ids := make([]ColumnID, 0, len(items))
+		for _, item := range items {
+			if item.Visible {
+				ids = append(ids, ColumnID(item.ID))
+			}
+		}
+
+		s.appService.GetApp().SetRoot(s.layout.Root(), true)
+		s.appService.GetApp().SetFocus(s.layout.GetTable().View())
+
+		if len(ids) == 0 {
+			s.layout.GetNotifier().ShowError(i18n.T("At least one column must be visible"))
+			return
+		}
+		if err := s.appService.SetColumns(ids); err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("Failed to save column settings: %v", err))
+			return
+		}
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Column settings saved"))
+	})
+
+	s.appService.GetApp().SetRoot(pickerPages, true)
+}
+
+// handleLicenseAuditEvent shows the installed packages grouped by license (a).
+// Selecting a license filters the table, and the audit can be exported to CSV.
+func (s *InputService) handleLicenseAuditEvent() {
+	groups := groupByLicense(s.appService.store.All())
+	items := make([]components.LicenseAuditItem, 0, len(groups))
+	for _, group := range groups {
+		names := make([]string, 0, len(group.Packages))
+		for _, pkg := range group.Packages {
+			names = append(names, pkg.Name)
+		}
+		items = append(items, components.LicenseAuditItem{License: group.License, Packages: names})
+	}
+
+	auditPages := s.layout.GetLicenseAudit().Build(s.layout.Root(), items, func(license string) {
+		s.handleBack()
+		s.appService.licenseFilter = license
+		s.appService.activeFilter = FilterLicense
+		s.appService.events.Publish(events.Event{Type: events.FilterChanged})
+	}, func() {
+		path, err := exportLicenseCSV(groups)
+		if err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("Failed to export licenses: %v", err))
+			return
+		}
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Licenses exported to %s", path))
+	}, s.handleBack)
+
+	s.appService.GetApp().SetRoot(auditPages, true)
+}
+
+// handleFilesEvent is called when the user presses the files key (L).
+// It lists the files the selected installed package put on disk, with their size, and the commands
+// shadowed by another one in the PATH.
+func (s *InputService) handleFilesEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+	if !info.LocallyInstalled {
+		s.layout.GetNotifier().ShowWarning(i18n.T("%s is not installed", info.Name))
+		return
+	}
+
+	s.layout.GetNotifier().ShowProgress(i18n.T("Listing the files of %s...", info.Name))
+	go func() {
+		defer RecoverCrash()
+		files, err := packageFiles(s.appService.fullPackage(info), s.appService.dataProvider.GetPrefixPath())
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to list the files of %s: %v", info.Name, err))
+				return
+			}
+			s.layout.GetNotifier().Clear()
+
+			var total int64
+			items := make([]components.FileItem, 0, len(files))
+			for _, file := range files {
+				total += file.Size
+				item := components.FileItem{Path: file.Path, Size: formatBytes(file.Size)}
+				switch {
+				case file.shadowed != "":
+					item.Note = i18n.T("shadowed by %s", file.shadowed)
+				case file.command && !file.inPath:
+					item.Note = i18n.T("not in PATH")
+				}
+				items = append(items, item)
+			}
+
+			title := i18n.T("Files of %s (%d, %s)", info.Name, len(files), formatBytes(total))
+			filesPages := s.layout.GetFiles().Build(s.layout.Root(), title, items, s.handleBack)
+			s.appService.GetApp().SetRoot(filesPages, true)
+		})
+	}()
+}
+
+// handleProvidesEvent is called when the user presses the provides key (P).
+// It asks for a command name and looks up the package providing it: installed ones first, then
+// the formulae of the index of executables, offering to install the most popular one.
+func (s *InputService) handleProvidesEvent() {
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Which package provides a command?"),
+		i18n.T("enter: look up | esc: cancel"), "", func(command string) {
+			s.handleBack()
+			if command = strings.TrimSpace(command); command != "" {
+				s.lookupCommand(command)
+			}
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// lookupCommand looks up the packages providing a command in the background, then selects the installed one,
+// or asks to install the most popular one.
+func (s *InputService) lookupCommand(command string) {
+	s.layout.GetNotifier().ShowProgress(i18n.T("Looking up %s...", command))
+	go func() {
+		defer RecoverCrash()
+		var installedCasks []models.Package
+		for _, pkg := range s.appService.store.All() {
+			if pkg.LocallyInstalled && pkg.Type == models.PackageTypeCask {
+				installedCasks = append(installedCasks, pkg)
+			}
+		}
+		installed := installedCommandProviders(command, s.appService.dataProvider.GetPrefixPath(), installedCasks)
+		known, err := knownCommandProviders(command)
+
+		// Known formulae not installed, the most popular first, and those installed but not linked (e.g. keg-only)
+		var candidates []models.Package
+		var unlinked []string
+		for _, name := range known {
+			if pkg, exists := s.appService.store.Lookup(name, models.PackageTypeFormula); exists && pkg.LocallyInstalled {
+				unlinked = append(unlinked, name)
+			} else if exists {
+				candidates = append(candidates, pkg)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].Analytics90dRank == 0 {
+				return false
+			}
+			if candidates[j].Analytics90dRank == 0 {
+				return true
+			}
+			return candidates[i].Analytics90dRank < candidates[j].Analytics90dRank
+		})
+
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			switch {
+			case len(installed) > 0:
+				provider := installed[0]
+				s.appService.selectPackage(provider.Name)
+				s.layout.GetNotifier().ShowSuccess(i18n.T("%s is provided by %s (installed)", command, provider.Name))
+			case len(unlinked) > 0:
+				s.appService.selectPackage(unlinked[0])
+				s.layout.GetNotifier().ShowWarning(i18n.T("%s is provided by %s (installed, not linked in the PATH)", command, unlinked[0]))
+			case len(candidates) > 0:
+				names := make([]string, 0, len(candidates))
+				for _, pkg := range candidates {
+					names = append(names, pkg.Name)
+				}
+				s.appService.selectPackage(candidates[0].Name)
+				s.layout.GetNotifier().ShowWarning(i18n.T("%s is provided by %s (not installed)", command, strings.Join(names, ", ")))
+				if !s.appService.IsReadOnly() {
+					s.installPackage(candidates[0])
+				}
+			case err != nil:
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to look up %s: %v", command, err))
+			default:
+				s.layout.GetNotifier().ShowWarning(i18n.T("No package provides %s", command))
+			}
+		})
+	}()
+}
+
+// handleTapAuditEvent is called when the user presses the tap audit key (R).
+// It asks for a local tap, the one of the selected package by default, and audits it for its maintainers.
+func (s *InputService) handleTapAuditEvent() {
+	tap := ""
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if info, exists := s.appService.packageAtRow(row); exists && info.IsThirdPartyTap() {
+		tap = info.Tap()
+	} else {
+		var thirdParty []string
+		for name := range s.brewService.GetInstalledTaps() {
+			if !strings.HasPrefix(name, "homebrew/") {
+				thirdParty = append(thirdParty, name)
+			}
+		}
+		if len(thirdParty) == 1 {
+			tap = thirdParty[0]
+		}
+	}
+
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Audit a tap (brew audit --strict, brew style)"),
+		i18n.T("enter: audit | esc: cancel"), tap, func(tap string) {
+			s.handleBack()
+			tap = strings.ToLower(strings.TrimSpace(tap))
+			if !s.brewService.IsTapInstalled(tap) {
+				s.layout.GetNotifier().ShowError(i18n.T("%s is not a local tap", tap))
+				return
+			}
+			s.auditTap(tap)
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// auditTap audits a tap in the background, then shows the problems found per package.
+func (s *InputService) auditTap(tap string) {
+	s.layout.GetNotifier().ShowProgress(i18n.T("Auditing %s...", tap))
+	go func() {
+		defer RecoverCrash()
+		result, err := auditTap(tap)
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			if err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to audit %s: %v", tap, err))
+				return
+			}
+			s.layout.GetNotifier().Clear()
+
+			items := make([]components.TapAuditItem, 0, len(result.Packages))
+			for _, name := range result.Packages {
+				item := components.TapAuditItem{Package: name}
+				for _, finding := range result.Findings[name] {
+					item.Findings = append(item.Findings, components.TapAuditFinding{Source: finding.Source, Message: finding.Message})
+				}
+				items = append(items, item)
+			}
+
+			auditPages := s.layout.GetTapAudit().Build(s.layout.Root(), tap, items, func(name string) {
+				s.handleBack()
+				if !s.appService.selectPackage(name) {
+					s.layout.GetNotifier().ShowWarning(i18n.T("%s is not shown in the table", name))
+				}
+			}, func() {
+				s.handleBack()
+				s.auditTap(tap)
+			}, s.handleBack)
+			s.appService.GetApp().SetRoot(auditPages, true)
+		})
+	}()
+}
+
+// handleNoteEvent is called when the user presses the note key (N).
+// It edits the personal note of the selected package, shown in the details and matched by the search.
+func (s *InputService) handleNoteEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	note := s.appService.notes.Lookup(info)
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Note for %s", info.Name),
+		i18n.T("enter: save (empty to remove) | esc: cancel"), note, func(note string) {
+			s.handleBack()
+			if err := s.appService.notes.Set(info, note); err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to save the note: %v", err))
+				return
+			}
+			s.layout.GetDetails().SetContent(&info)
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Note saved"))
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// handleInspectEvent shows the raw Formula/Cask JSON of the selected package (I).
+func (s *InputService) handleInspectEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	var raw any = info.Formula
+	if info.Cask != nil {
+		raw = info.Cask
+	}
+	data, err := json.Marshal(raw)
+	if err == nil {
+		var inspectorPages *tview.Pages
+		if inspectorPages, err = s.layout.GetInspector().Build(s.layout.Root(), info.Name, data, s.handleBack); err == nil {
+			s.appService.GetApp().SetRoot(inspectorPages, true)
+			return
+		}
+	}
+	s.layout.GetNotifier().ShowError(i18n.T("Failed to inspect %s: %v", info.Name, err))
+}
+
+// jumpToOperation scrolls the output to the previous (-1) or next (1) operation of the session.
+func (s *InputService) jumpToOperation(direction int) {
+	if !s.layout.GetOutput().JumpToOperation(direction) {
+		s.layout.GetNotifier().ShowWarning(i18n.T("No other operation in the output"))
+	}
+}
+
+// handleDescriptionEvent is called when the user presses the description key (e).
+// It shows the full description of the selected package under its row, the table cutting it to fit.
+func (s *InputService) handleDescriptionEvent() {
+	table := s.layout.GetTable()
+	row, _ := table.View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists || info.Description == "" {
+		return
+	}
+
+	x, y, width, fitted := table.FittedCellPosition(row)
+	if !fitted { // Description column hidden: under the whole row
+		x, y, _ = table.View().GetCell(row, 0).GetLastPosition()
+		_, _, width, _ = table.View().GetInnerRect()
+	}
+	tooltipPages := s.layout.GetTooltip().Build(s.layout.Root(), info.Name, info.Description, x, y, width, s.handleBack)
+	s.appService.GetApp().SetRoot(tooltipPages, true)
+}
+
+// handleReleaseNotesEvent shows the release notes of the new version, if one is available (n).
+func (s *InputService) handleReleaseNotesEvent() {
+	version := s.appService.latestVersion
+	if version == "" {
+		return
+	}
+
+	s.showReleaseNotes(i18n.T("Bold Brew %s", version), func(ctx context.Context) (string, error) {
+		return s.appService.selfUpdateService.GetReleaseNotes(ctx, version)
+	}, func() {
+		s.handleSelfUpdate(version)
+	})
+}
+
+// handleChangelogEvent shows what an upgrade of the selected outdated package brings (w).
+func (s *InputService) handleChangelogEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	if !info.LocallyInstalled || !info.Outdated {
+		s.layout.GetNotifier().ShowWarning(i18n.T("%s is up to date", info.Name))
+		return
+	}
+
+	title := fmt.Sprintf("%s %s %s %s", info.Name, info.InstalledVersion(), s.appService.theme.Symbols.Arrow, info.Version)
+	s.showReleaseNotes(title, func(ctx context.Context) (string, error) {
+		return s.appService.changelogService.GetReleaseNotes(ctx, info)
+	}, s.handleUpdatePackageEvent)
+}
+
+// showReleaseNotes opens the release notes overlay and loads its content in the background.
+// onUpdate is called after the overlay is closed, when the user asks to update now.
+func (s *InputService) showReleaseNotes(title string, fetch func(ctx context.Context) (string, error), onUpdate func()) {
+	releaseNotes := s.layout.GetReleaseNotes()
+	notesPages := releaseNotes.Build(s.layout.Root(), title, func() {
+		s.handleBack()
+		onUpdate()
+	}, s.handleBack)
+	s.appService.GetApp().SetRoot(notesPages, true)
+
+	go func() {
+		defer RecoverCrash()
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		notes, err := fetch(ctx)
+		if err != nil {
+			notes = i18n.T("Could not load the release notes: %v", err)
+		} else if notes == "" {
+			notes = i18n.T("No release notes for this version.")
+		}
+		s.appService.app.QueueUpdateDraw(func() {
+			releaseNotes.SetNotes(notes)
+		})
+	}()
+}
+
+// handleSelfUpdate upgrades Bold Brew itself through Homebrew.
+func (s *InputService) handleSelfUpdate(version string) {
+	if s.appService.brewMissing {
+		s.layout.GetNotifier().ShowError(i18n.T("Homebrew is required to update %s", AppName))
+		return
+	}
+	if s.appService.IsReadOnly() {
+		s.layout.GetNotifier().ShowWarning(i18n.T("Read-only mode: %s is disabled", i18n.T("Update")))
+		return
+	}
+
+	self := models.Package{Name: selfUpdateFormula, Type: models.PackageTypeFormula}
+
+	if !s.appService.beginOperation(i18n.T("updating %s…", AppName)) {
+		return
+	}
+	s.layout.GetOutput().BeginOperation(i18n.T("updating %s…", AppName))
+	go func() {
+		defer RecoverCrash()
+		defer s.appService.endOperation()
+		s.layout.GetNotifier().ShowProgress(i18n.T("Updating %s...", AppName))
+		if err := s.brewService.UpdatePackage(self, s.appService.app, s.layout.GetOutput().View()); err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("Failed to update %s", AppName))
+			s.appService.terminal.Done(i18n.T("Failed to update %s", AppName))
+			return
+		}
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Updated to %s, restart Bold Brew to use it", version))
+		s.appService.terminal.Done(i18n.T("Updated to %s, restart Bold Brew to use it", version))
+	}()
+}
+
 // handleFilterEvent toggles the filter for packages based on the provided filter type.
 func (s *InputService) handleFilterEvent(filterType FilterType) {
 	// Toggle: if same filter is active, turn it off; otherwise switch to new filter
@@ -228,46 +1185,65 @@ func (s *InputService) handleFilterEvent(filterType FilterType) {
 		s.appService.activeFilter = filterType
 	}
 
-	// Update UI based on active filter
-	s.updateFilterUI()
-	s.appService.search(s.layout.GetSearch().Field().GetText(), true)
+	s.appService.events.Publish(events.Event{Type: events.FilterChanged})
 }
 
-// updateFilterUI updates the search label and legend based on the current filter state.
-func (s *InputService) updateFilterUI() {
-	s.layout.GetLegend().SetLegend(s.legendEntries, "")
+// setFilter applies a filter of the number keys, which unlike the letter keys don't toggle it off.
+func (s *InputService) setFilter(filterType FilterType) {
+	if s.appService.activeFilter == filterType {
+		return
+	}
+	s.appService.activeFilter = filterType
+	s.appService.events.Publish(events.Event{Type: events.FilterChanged})
+}
 
-	// Map filter types to their display config
-	filterConfig := map[FilterType]struct {
-		suffix  string
-		keySlug string
-	}{
-		FilterInstalled: {"Installed", s.ActionFilterInstalled.KeySlug},
-		FilterOutdated:  {"Outdated", s.ActionFilterOutdated.KeySlug},
-		FilterLeaves:    {"Leaves", s.ActionFilterLeaves.KeySlug},
-		FilterCasks:     {"Casks", s.ActionFilterCasks.KeySlug},
+// updateFilterTabs shows the quick filters above the table, highlighting the active one.
+func (s *InputService) updateFilterTabs() {
+	tabs := make([]components.FilterTab, len(quickFilters))
+	active := -1
+	for i, quick := range quickFilters {
+		tabs[i] = components.FilterTab{Key: s.ActionQuickFilters[i].KeySlug, Label: i18n.T(quick.label)}
+		if quick.filter == s.appService.activeFilter {
+			active = i
+		}
 	}
+	s.layout.GetFilterBar().SetTabs(tabs, active)
+}
 
-	baseLabel := "Search"
-	if s.appService.IsBrewfileMode() {
-		baseLabel = "Search (Brewfile"
+// UpdateFilterUI updates the search label, the legend and the filter tabs based on the current filter state.
+func (s *InputService) UpdateFilterUI() {
+	s.layout.GetLegend().SetLegend(s.legendEntries, "")
+	s.updateFilterTabs()
+
+	// Map filter types to the keys that toggle them
+	filterKeys := map[FilterType]string{
+		FilterInstalled:  s.ActionFilterInstalled.KeySlug,
+		FilterOutdated:   s.ActionFilterOutdated.KeySlug,
+		FilterLeaves:     s.ActionFilterLeaves.KeySlug,
+		FilterCasks:      s.ActionFilterCasks.KeySlug,
+		FilterNoBottle:   s.ActionFilterNoBottle.KeySlug,
+		FilterMaintained: s.ActionFilterMaintained.KeySlug,
+		FilterVulnerable: s.ActionFilterVulnerable.KeySlug,
+		FilterFavorites:  s.ActionFilterFavorites.KeySlug,
+		FilterLicense:    s.ActionLicenses.KeySlug,
 	}
 
-	if cfg, exists := filterConfig[s.appService.activeFilter]; exists {
+	if keySlug, exists := filterKeys[s.appService.activeFilter]; exists {
+		label := s.appService.filterLabel()
 		if s.appService.IsBrewfileMode() {
-			s.layout.GetSearch().Field().SetLabel(baseLabel + " - " + cfg.suffix + "): ")
+			s.layout.GetSearch().Field().SetLabel(i18n.T("Search (Brewfile - %s): ", label))
 		} else {
-			s.layout.GetSearch().Field().SetLabel("Search (" + cfg.suffix + "): ")
+			s.layout.GetSearch().Field().SetLabel(i18n.T("Search (%s): ", label))
 		}
-		s.layout.GetLegend().SetLegend(s.legendEntries, cfg.keySlug)
+		s.layout.GetLegend().SetLegend(s.legendEntries, keySlug)
 		return
 	}
 
 	// No filter active (FilterNone)
 	if s.appService.IsBrewfileMode() {
-		s.layout.GetSearch().Field().SetLabel(baseLabel + "): ")
+		s.layout.GetSearch().Field().SetLabel(i18n.T("Search (Brewfile): "))
 	} else {
-		s.layout.GetSearch().Field().SetLabel("Search (All): ")
+		s.layout.GetSearch().Field().SetLabel(i18n.T("Search (All): "))
 	}
 }
 
@@ -291,11 +1267,504 @@ func (s *InputService) handleFilterCasksEvent() {
 	s.handleFilterEvent(FilterCasks)
 }
 
-// showModal displays a modal dialog with the specified text and confirmation/cancellation actions.
-// This is used for actions like installing, removing, or updating packages, invoking user confirmation.
-func (s *InputService) showModal(text string, confirmFunc func(), cancelFunc func()) {
-	modal := s.layout.GetModal().Build(text, confirmFunc, cancelFunc)
-	s.appService.app.SetRoot(modal, true)
+// handleFilterNoBottleEvent toggles the filter for formulae without a bottle for the host
+func (s *InputService) handleFilterNoBottleEvent() {
+	s.handleFilterEvent(FilterNoBottle)
+}
+
+// handleFilterMaintainedEvent toggles the filter for packages with a recently maintained GitHub repository
+func (s *InputService) handleFilterMaintainedEvent() {
+	s.handleFilterEvent(FilterMaintained)
+}
+
+// handleFilterVulnerableEvent toggles the filter for installed packages with known vulnerabilities
+func (s *InputService) handleFilterVulnerableEvent() {
+	s.handleFilterEvent(FilterVulnerable)
+}
+
+// handleFilterFavoritesEvent toggles the filter for starred packages
+func (s *InputService) handleFilterFavoritesEvent() {
+	s.handleFilterEvent(FilterFavorites)
+}
+
+// handleStarEvent is called when the user presses the star key (s).
+// It stars or unstars the selected package.
+func (s *InputService) handleStarEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	starred, err := s.appService.favorites.Toggle(info)
+	if err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to save favorites: %v", err))
+		return
+	}
+	if starred {
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Starred %s", info.Name))
+	} else {
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Unstarred %s", info.Name))
+	}
+	s.appService.search(s.layout.GetSearch().Field().GetText(), false) // Redraw the star column and the favorites filter
+}
+
+// handleExportFavoritesEvent is called when the user presses the export favorites key (B).
+// It appends the starred packages to a Brewfile, chosen in a prompt.
+func (s *InputService) handleExportFavoritesEvent() {
+	favorites := s.appService.favoritePackages()
+	if len(favorites) == 0 {
+		s.layout.GetNotifier().ShowWarning(i18n.T("No favorites to export (press s to star packages)"))
+		return
+	}
+
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Append favorites to Brewfile"),
+		i18n.T("enter: append | esc: cancel"), "Brewfile", func(path string) {
+			s.handleBack()
+			path = expandHome(strings.TrimSpace(path))
+			added, err := appendToBrewfile(path, favorites)
+			switch {
+			case err != nil:
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to export favorites: %v", err))
+				return
+			case added == 0:
+				s.layout.GetNotifier().ShowWarning(i18n.T("All favorites are already in %s", path))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Added %d packages to %s", added, path))
+
+			// Pick up the new entries if this is the Brewfile being browsed
+			if s.appService.IsBrewfileMode() && filepath.Clean(path) == filepath.Clean(s.appService.brewfilePath) {
+				go func() {
+					defer RecoverCrash()
+					_ = s.appService.loadBrewfilePackages()
+					s.appService.events.Publish(events.Event{Type: events.PackagesUpdated})
+				}()
+			}
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// handleWatchEvent is called when the user presses the watch key (W).
+// It adds the selected package to the watchlist, installed or not, or removes it.
+func (s *InputService) handleWatchEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	watched, err := s.appService.watchlist.Toggle(info)
+	if err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to save the watchlist: %v", err))
+		return
+	}
+	if watched {
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Watching %s", info.Name))
+	} else {
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Stopped watching %s", info.Name))
+	}
+	s.appService.checkWatchlist()
+}
+
+// handleWatchlistEvent is called when the user presses the watchlist key (V).
+// It shows the watched packages and their new versions, which are marked as seen on close.
+func (s *InputService) handleWatchlistEvent() {
+	watchPages := s.layout.GetWatchlist().Build(s.layout.Root(), s.appService.watchItems(), func() {
+		s.handleBack()
+		if err := s.appService.markWatchlistSeen(); err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("Failed to save the watchlist: %v", err))
+		}
+	})
+	s.appService.GetApp().SetRoot(watchPages, true)
+}
+
+// handleHealthEvent is called when the user presses the health key (H).
+// It checks the environment in the background: the commands of Homebrew shadowed by others earlier in the PATH.
+func (s *InputService) handleHealthEvent() {
+	s.layout.GetNotifier().ShowProgress(i18n.T("Checking the environment..."))
+	go func() {
+		defer RecoverCrash()
+		checks := []components.HealthCheck{pathHealthCheck(s.appService.dataProvider.GetPrefixPath())}
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			s.layout.GetNotifier().Clear()
+			healthPages := s.layout.GetHealth().Build(s.layout.Root(), checks, s.handleHealthEvent, s.handleBack)
+			s.appService.GetApp().SetRoot(healthPages, true)
+		})
+	}()
+}
+
+// handleSettingsEvent is called when the user presses the settings key (,).
+// It shows whether Homebrew's analytics are enabled, to toggle them, and bbrew's own telemetry setting.
+func (s *InputService) handleSettingsEvent() {
+	s.showSettings(0)
+}
+
+// showSettings reads the state of Homebrew's analytics in the background, then shows the settings
+// with the given row selected.
+func (s *InputService) showSettings(selected int) {
+	go func() {
+		defer RecoverCrash()
+		analytics, err := brewAnalyticsState()
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			brewItem := components.SettingItem{Name: i18n.T("Homebrew analytics"), Toggle: true,
+				Description: i18n.T("Anonymous install events brew sends to Homebrew (brew analytics)")}
+			switch {
+			case brewAnalyticsForcedOff():
+				brewItem.Value, brewItem.Toggle = i18n.T("off"), false
+				brewItem.Description = i18n.T("Disabled by HOMEBREW_NO_ANALYTICS")
+			case err != nil:
+				brewItem.Value, brewItem.Toggle = i18n.T("unknown"), false
+				brewItem.Description = err.Error()
+			case analytics:
+				brewItem.Value = i18n.T("on")
+			default:
+				brewItem.Value = i18n.T("off")
+			}
+			if s.appService.IsReadOnly() && brewItem.Toggle {
+				brewItem.Toggle = false
+				brewItem.Description = i18n.T("Read-only mode: %s is disabled", brewItem.Name)
+			}
+
+			telemetryItem := components.SettingItem{Name: i18n.T("bbrew telemetry"), Value: i18n.T("off"),
+				Description: i18n.T("bbrew sends no usage data (telemetry in config.json)")}
+			if s.appService.config.Telemetry {
+				telemetryItem.Value = i18n.T("on")
+			}
+
+			items := []components.SettingItem{brewItem, telemetryItem}
+			settingsPages := s.layout.GetSettings().Build(s.layout.Root(), items, selected, func(row int) {
+				if row == 0 {
+					s.toggleBrewAnalytics(!analytics)
+				}
+			}, s.handleBack)
+			s.appService.GetApp().SetRoot(settingsPages, true)
+		})
+	}()
+}
+
+// toggleBrewAnalytics turns Homebrew's analytics on or off in the background, then refreshes the settings.
+func (s *InputService) toggleBrewAnalytics(enabled bool) {
+	if s.appService.IsReadOnly() { // brew analytics changes Homebrew's settings
+		s.layout.GetNotifier().ShowWarning(i18n.T("Read-only mode: %s is disabled", i18n.T("Homebrew analytics")))
+		return
+	}
+	s.layout.GetNotifier().ShowProgress(i18n.T("Updating Homebrew analytics..."))
+	go func() {
+		defer RecoverCrash()
+		err := setBrewAnalytics(enabled)
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			switch {
+			case err != nil:
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to update Homebrew analytics: %v", err))
+			case enabled:
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Homebrew analytics enabled"))
+			default:
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Homebrew analytics disabled"))
+			}
+			if s.layout.GetSettings().HasFocus() {
+				s.showSettings(0) // Show the new state
+			}
+		})
+	}()
+}
+
+// ShowProfilePicker is called when the user presses the profiles key (O), and at startup with --profiles.
+// It lists the profiles of the config, to open one, compare it with this machine or save the packages shown as one.
+func (s *InputService) ShowProfilePicker() {
+	config := s.appService.config
+	items := make([]components.ProfileItem, 0, len(config.Profiles))
+	for _, name := range config.ProfileNames() {
+		profile := config.Profiles[name]
+		items = append(items, components.ProfileItem{
+			Name: name, Brewfile: profile.Brewfile, Filter: profile.Filter, Group: profile.Group,
+			Current: name == s.appService.startupOptions.Profile,
+		})
+	}
+
+	profilePages := s.layout.GetProfiles().Build(s.layout.Root(), items, func(item components.ProfileItem) {
+		s.handleBack()
+		if err := s.appService.openProfile(item.Name); err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("Failed to open the profile %s: %v", item.Name, err))
+			return
+		}
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Opened the profile %s", item.Name))
+	}, s.compareProfile, s.saveProfile, s.handleBack)
+	s.appService.GetApp().SetRoot(profilePages, true)
+}
+
+// compareProfile shows how this machine differs from the Brewfile of a profile.
+func (s *InputService) compareProfile(item components.ProfileItem) {
+	profile, err := s.appService.config.LookupProfile(item.Name)
+	if err != nil {
+		s.layout.GetNotifier().ShowError(err.Error())
+		return
+	}
+	path, cleanup, err := ResolveBrewfilePath(profile.Brewfile)
+	if err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to read the inventory: %v", err))
+		return
+	}
+	defer cleanup()
+	there, err := readInventory(path)
+	if err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to read the inventory: %v", err))
+		return
+	}
+	there.Host = item.Name
+	s.showInventoryDiff(there)
+}
+
+// saveProfile asks for a name, then saves the packages shown in the table as a new profile.
+func (s *InputService) saveProfile() {
+	packages := s.appService.store.Filtered()
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Save the %d packages shown as the profile", len(packages)),
+		i18n.T("enter: save | esc: cancel"), "", func(name string) {
+			s.handleBack()
+			name = strings.TrimSpace(name)
+			path, err := s.appService.saveProfile(name, packages)
+			if err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to save the profile: %v", err))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Saved the profile %s to %s", name, displayPath(path)))
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// withSelectedDownload calls then with the selected package and the file it downloads on install,
+// warning when there is none (no bottle for this platform). Summaries may lack the bottle URLs: their full
+// package is fetched in the background, then is called from the event loop once it's there.
+func (s *InputService) withSelectedDownload(then func(info models.Package, download packageDownload)) {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	resolved := func(info models.Package) {
+		download, ok := packageDownloadFor(info, GetPlatform().BottleTags())
+		if !ok {
+			s.layout.GetNotifier().ShowWarning(i18n.T("No bottle or download URL for %s on %s", info.Name, GetPlatform().BottleTag()))
+			return
+		}
+		then(info, download)
+	}
+	if !info.IsPartial() {
+		resolved(info)
+		return
+	}
+
+	s.layout.GetNotifier().ShowProgress(i18n.T("Loading the details of %s...", info.Name))
+	go func() {
+		defer RecoverCrash()
+		full := s.appService.fullPackage(info)
+		s.appService.GetApp().QueueUpdateDraw(func() { resolved(full) })
+	}()
+}
+
+// handleCopyURLEvent is called when the user presses the copy URL key (y).
+// It copies the bottle URL of the selected formula, or the download URL of the selected cask.
+func (s *InputService) handleCopyURLEvent() {
+	s.withSelectedDownload(func(info models.Package, download packageDownload) {
+		s.appService.terminal.Copy(download.URL)
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Copied the URL of %s", info.Name))
+	})
+}
+
+// handleDownloadEvent is called when the user presses the download key (d).
+// It downloads the bottle of the selected formula, or the selected cask, to a directory chosen in a prompt,
+// verifying its checksum: useful to mirror packages to air-gapped machines.
+func (s *InputService) handleDownloadEvent() {
+	s.withSelectedDownload(s.promptDownload)
+}
+
+// promptDownload asks for the directory to download a package to, then downloads it in the background.
+func (s *InputService) promptDownload(info models.Package, download packageDownload) {
+	if s.downloadDir == "" {
+		s.downloadDir = "."
+	}
+
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Download %s to", download.FileName),
+		i18n.T("enter: download | esc: cancel"), s.downloadDir, func(dir string) {
+			s.handleBack()
+			dir = expandHome(strings.TrimSpace(dir))
+			s.downloadDir = dir
+			go func() {
+				defer RecoverCrash()
+				s.appService.terminal.SetActivity(i18n.T("downloading %s…", info.Name))
+				s.layout.GetNotifier().ShowProgress(i18n.T("Downloading %s...", info.Name))
+				path, err := downloadPackageFile(context.Background(), download, dir)
+				if err != nil {
+					s.layout.GetNotifier().ShowError(i18n.T("Failed to download %s: %v", info.Name, err))
+					s.appService.terminal.Done(i18n.T("Failed to download %s: %v", info.Name, err))
+					return
+				}
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Downloaded %s", path))
+				s.appService.terminal.Done(i18n.T("Downloaded %s", path))
+			}()
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// handleExportInventoryEvent is called when the user presses the export inventory key (X).
+// It saves the installed packages and their versions, to compare this machine with others (D).
+func (s *InputService) handleExportInventoryEvent() {
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Export inventory to"),
+		i18n.T("enter: export | esc: cancel"), defaultInventoryPath(), func(path string) {
+			s.handleBack()
+			path = expandHome(strings.TrimSpace(path))
+			snapshot := s.appService.localInventory()
+			if err := writeInventory(path, snapshot); err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to export the inventory: %v", err))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Exported %d packages to %s", len(snapshot.Packages), path))
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// handleCompareInventoryEvent is called when the user presses the compare inventory key (D).
+// It loads the inventory or the Brewfile of another machine and shows how this one differs.
+func (s *InputService) handleCompareInventoryEvent() {
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Compare with inventory or Brewfile"),
+		i18n.T("enter: compare | esc: cancel"), "", func(path string) {
+			s.handleBack()
+			path = expandHome(strings.TrimSpace(path))
+			there, err := readInventory(path)
+			if err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to read the inventory: %v", err))
+				return
+			}
+			s.showInventoryDiff(there)
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// showInventoryDiff opens the comparison of this machine with another one.
+// Enter brings this machine in line for the selected package; e writes a Brewfile
+// with the packages the other machine is missing, to apply there.
+func (s *InputService) showInventoryDiff(there inventorySnapshot) {
+	items := compareInventories(s.appService.localInventory(), there)
+	diffPages := s.layout.GetInventoryDiff().Build(s.layout.Root(), there.Host, items, func(item components.InventoryItem) {
+		action, apply := s.ActionUpdate, s.updatePackage
+		switch item.Category {
+		case components.InventoryOnlyHere:
+			action, apply = s.ActionRemove, s.removePackage
+		case components.InventoryOnlyThere:
+			action, apply = s.ActionInstall, s.installPackage
+		}
+		if s.appService.IsReadOnly() {
+			s.layout.GetNotifier().ShowWarning(i18n.T("Read-only mode: %s is disabled", action.Name))
+			return
+		}
+		apply(s.appService.inventoryPackage(item))
+	}, func() {
+		s.exportMissingThere(there, items)
+	}, s.handleBack)
+	s.appService.GetApp().SetRoot(diffPages, true)
+}
+
+// exportMissingThere appends the packages only installed here to a Brewfile, to install them on the other machine.
+func (s *InputService) exportMissingThere(there inventorySnapshot, items []components.InventoryItem) {
+	var missing []models.Package
+	for _, item := range items {
+		if item.Category == components.InventoryOnlyHere {
+			missing = append(missing, s.appService.inventoryPackage(item))
+		}
+	}
+	if len(missing) == 0 {
+		s.layout.GetNotifier().ShowWarning(i18n.T("%s has every package installed here", there.Host))
+		return
+	}
+
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Brewfile for %s", there.Host),
+		i18n.T("enter: append | esc: cancel"), "Brewfile.missing", func(path string) {
+			s.handleBack()
+			path = expandHome(strings.TrimSpace(path))
+			added, err := appendToBrewfile(path, missing)
+			if err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to write the Brewfile: %v", err))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Added %d packages to %s", added, path))
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// showModal displays a confirmation modal dialog with the specified options.
+// This is used for actions like installing, removing, or updating packages, invoking user confirmation.
+// Actions the user chose not to confirm again in this session run right away.
+func (s *InputService) showModal(options components.ModalOptions) {
+	if s.layout.GetModal().Skipped(options.ActionType) {
+		options.Confirm()
+		return
+	}
+	s.appService.app.SetRoot(s.layout.GetModal().Build(options), true)
+}
+
+// confirmPackageOperation asks to confirm an operation on a single package, unless expert mode is on.
+func (s *InputService) confirmPackageOperation(options components.ModalOptions) {
+	if s.appService.IsExpertMode() {
+		options.Confirm()
+		return
+	}
+	s.showModal(options)
+}
+
+// handleUpgradePolicyEvent is called when the user presses the upgrade policy key (p).
+// It cycles the policy of the selected package for Update All and `bbrew upgrade`: ask, auto, hold.
+func (s *InputService) handleUpgradePolicyEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	policy := nextUpgradePolicy(s.appService.config.UpgradePolicyOf(info.Name))
+	if err := s.appService.SetUpgradePolicy(info.Name, policy); err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to save settings: %v", err))
+		return
+	}
+	s.layout.GetNotifier().ShowSuccess(i18n.T("Upgrade policy of %s: %s", info.Name, i18n.T(upgradePolicyLabels[policy])))
+	s.appService.search(s.layout.GetSearch().Field().GetText(), false) // Redraw the policy column and the details
+}
+
+// handleProtectEvent is called when the user presses the protect key (K).
+// It toggles the protection of the selected package from removal.
+func (s *InputService) handleProtectEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	protected := !s.appService.config.IsProtected(info.Name)
+	if err := s.appService.SetProtected(info.Name, protected); err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to save settings: %v", err))
+		return
+	}
+	if protected {
+		s.layout.GetNotifier().ShowSuccess(i18n.T("%s is protected from removal", info.Name))
+	} else {
+		s.layout.GetNotifier().ShowWarning(i18n.T("%s is no longer protected from removal", info.Name))
+	}
+	s.appService.search(s.layout.GetSearch().Field().GetText(), false) // Redraw the details
+}
+
+// handleExpertModeEvent is called when the user presses the expert mode key (E).
+// It toggles the confirmation of single-package operations.
+func (s *InputService) handleExpertModeEvent() {
+	enabled := !s.appService.IsExpertMode()
+	if err := s.appService.SetExpertMode(enabled); err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to save settings: %v", err))
+		return
+	}
+	if enabled {
+		s.layout.GetNotifier().ShowWarning(i18n.T("Expert mode on: installs, updates and removals run without confirmation"))
+	} else {
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Expert mode off: operations are confirmed again"))
+	}
 }
 
 // closeModal closes the currently displayed modal dialog and returns focus to the main table view.
@@ -306,97 +1775,757 @@ func (s *InputService) closeModal() {
 
 // handleInstallPackageEvent is called when the user presses the installation key (i).
 func (s *InputService) handleInstallPackageEvent() {
+	if s.runOnMarked(s.installOperation()) {
+		return
+	}
 	row, _ := s.layout.GetTable().View().GetSelection()
-	if row > 0 {
-		info := (*s.appService.filteredPackages)[row-1]
-		s.showModal(
-			fmt.Sprintf("Are you sure you want to install the package: %s?", info.Name),
-			func() {
+	if info, exists := s.appService.packageAtRow(row); exists {
+		s.installPackage(info)
+	}
+}
+
+// RequestInstall installs a package asked for through the control socket, selecting it first when shown.
+func (s *InputService) RequestInstall(pkg models.Package) {
+	if row := s.appService.rowOfPackage(pkg.Name); row > 0 {
+		s.layout.GetTable().View().Select(row, 0)
+	}
+	s.installPackage(pkg)
+}
+
+// installPackage asks to confirm, then installs a package in the background.
+// It covers every install path (key, control socket, command lookup), so it checks the read-only mode itself.
+func (s *InputService) installPackage(info models.Package) {
+	if s.appService.IsReadOnly() {
+		s.layout.GetNotifier().ShowWarning(i18n.T("Read-only mode: %s is disabled", i18n.T("Install")))
+		return
+	}
+	if alternative, exists := linuxAlternativeOf(info); exists {
+		s.installLinuxAlternative(info, alternative)
+		return
+	}
+	if info.Cask != nil && !info.Cask.SupportsMacOS(GetPlatform().MacOSVersion) {
+		s.layout.GetNotifier().ShowError(i18n.T("%s requires macOS %s (this Mac runs %s)",
+			info.Name, info.Cask.MacOSRequirement(), GetPlatform().MacOSVersion))
+		return
+	}
+
+	message := i18n.T("Are you sure you want to install the package: %s?", info.Name)
+	if info.Type == models.PackageTypeFormula && info.Formula != nil {
+		platform := GetPlatform()
+		if info.Formula.BottleTag(platform.BottleTags()) == "" {
+			message += "\n\n" + i18n.T("No bottle is available for %s: it will be built from source (~%d min).",
+				platform.BottleTag(), info.Formula.EstimatedInstallMinutes(false))
+			if missing := info.Formula.MissingBuildDependencies(s.appService.isFormulaInstalled); len(missing) > 0 {
+				message += "\n" + i18n.T("The build first installs its build dependencies: %s.", strings.Join(missing, ", "))
+			}
+		}
+	}
+	if apps := existingCaskApps(info); len(apps) > 0 {
+		message += "\n\n" + i18n.T("%s already exists, which makes the install fail: adopt it instead (A).",
+			strings.Join(apps, ", "))
+	}
+
+	// Casks are quarantined by Gatekeeper on macOS, unless skipped by default (cask_no_quarantine) or for this install
+	noQuarantine := s.appService.config.CaskNoQuarantine
+	var quarantineOption *components.ModalOption
+	if info.Type == models.PackageTypeCask && GetPlatform().OS == "darwin" {
+		quarantineOption = &components.ModalOption{
+			Label: i18n.T("Skip Gatekeeper quarantine (--no-quarantine)"), Key: 'g', Value: &noQuarantine,
+		}
+	}
+	s.confirmPackageOperation(components.ModalOptions{
+		Text:       message,
+		ActionType: string(events.OperationInstall),
+		Option:     quarantineOption,
+		Cancel:     s.closeModal,
+		Confirm: func() {
+			s.closeModal()
+			if !s.appService.beginOperation(i18n.T("installing %s…", info.Name)) {
+				return
+			}
+			s.layout.GetOutput().BeginOperation(i18n.T("installing %s…", info.Name))
+			go func() {
+				defer RecoverCrash()
+				defer s.appService.endOperation()
+				s.layout.GetNotifier().ShowProgress(i18n.T("Installing %s...", info.Name))
+				err := s.installWithHooks(info, func() error {
+					return s.brewService.InstallPackage(info, noQuarantine, s.appService.app, s.layout.GetOutput().View())
+				})
+				if err != nil {
+					s.showOperationFailure(i18n.T("Failed to install %s", info.Name), info, err, s.installPackage)
+					s.appService.terminal.Done(i18n.T("Failed to install %s", info.Name))
+					if diagnosis, ok := diagnoseFailure(err, info.Name); ok {
+						s.appService.app.QueueUpdateDraw(func() { s.showFailure(info.Name, diagnosis) })
+					}
+				} else {
+					s.layout.GetNotifier().ShowSuccess(i18n.T("Installed %s", info.Name))
+					s.appService.terminal.Done(i18n.T("Installed %s", info.Name))
+					if caveats := s.appService.packageCaveats(info); caveats != "" {
+						s.appService.app.QueueUpdateDraw(func() { s.showCaveats(info.Name, caveats) })
+					}
+				}
+				s.appService.events.Publish(events.Event{
+					Type: events.OperationCompleted, Operation: events.OperationInstall, Package: &info, Err: err,
+				})
+			}()
+		},
+	})
+}
+
+// handleAdoptEvent is called when the user presses the adopt key (A).
+// It installs the selected cask over its app already in /Applications, instead of failing on it.
+func (s *InputService) handleAdoptEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	if info, exists := s.appService.packageAtRow(row); exists {
+		s.adoptPackage(info)
+	}
+}
+
+// adoptPackage asks to confirm, then adopts the existing app of a cask in the background.
+func (s *InputService) adoptPackage(info models.Package) {
+	apps := existingCaskApps(info)
+	switch {
+	case info.Cask == nil:
+		s.layout.GetNotifier().ShowWarning(i18n.T("Only casks can adopt an existing app"))
+		return
+	case info.LocallyInstalled:
+		s.layout.GetNotifier().ShowWarning(i18n.T("%s is already installed", info.Name))
+		return
+	case len(apps) == 0:
+		s.layout.GetNotifier().ShowWarning(i18n.T("No app of %s found in %s", info.Name, caskAppDir))
+		return
+	}
+
+	s.showModal(components.ModalOptions{
+		Text: i18n.T("Adopt %s as the cask %s? Homebrew will manage and update it from now on.",
+			strings.Join(apps, ", "), info.Name),
+		Cancel: s.closeModal,
+		Confirm: func() {
+			s.closeModal()
+			if !s.appService.beginOperation(i18n.T("adopting %s…", info.Name)) {
+				return
+			}
+			s.layout.GetOutput().BeginOperation(i18n.T("adopting %s…", info.Name))
+			go func() {
+				defer RecoverCrash()
+				defer s.appService.endOperation()
+				s.layout.GetNotifier().ShowProgress(i18n.T("Adopting %s...", info.Name))
+				err := s.brewService.AdoptCask(info, s.appService.app, s.layout.GetOutput().View())
+				if err != nil {
+					s.showOperationFailure(i18n.T("Failed to adopt %s", info.Name), info, err, s.adoptPackage)
+					s.appService.terminal.Done(i18n.T("Failed to adopt %s", info.Name))
+				} else {
+					s.layout.GetNotifier().ShowSuccess(i18n.T("Adopted %s", info.Name))
+					s.appService.terminal.Done(i18n.T("Adopted %s", info.Name))
+				}
+				s.appService.events.Publish(events.Event{
+					Type: events.OperationCompleted, Operation: events.OperationInstall, Package: &info, Err: err,
+				})
+			}()
+		},
+	})
+}
+
+// handleMigrateEvent is called when the user presses the migrate key (M).
+// It lists the apps of /Applications installed outside Homebrew that a cask can adopt, to adopt them one by one or all at once.
+func (s *InputService) handleMigrateEvent() {
+	if GetPlatform().OS != "darwin" {
+		s.layout.GetNotifier().ShowWarning(i18n.T("Migrating apps to Homebrew is only available on macOS"))
+		return
+	}
+	apps, err := s.appService.unmanagedApps()
+	if err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("Failed to scan %s: %v", caskAppDir, err))
+		return
+	}
+
+	items := make([]components.MigrateItem, len(apps))
+	casks := make([]models.Package, len(apps))
+	for i, app := range apps {
+		items[i] = components.MigrateItem{App: app.Path, Cask: app.Cask.Name, Version: app.Cask.Version}
+		casks[i] = app.Cask
+	}
+
+	migratePages := s.layout.GetMigrate().Build(s.layout.Root(), items, func(item components.MigrateItem) {
+		for _, app := range apps {
+			if app.Path == item.App {
+				s.adoptPackage(app.Cask)
+			}
+		}
+	}, func() {
+		s.showModal(components.ModalOptions{
+			Text:   i18n.T("Adopt %d apps as casks? Homebrew will manage and update them from now on.", len(casks)),
+			Cancel: s.closeModal,
+			Confirm: func() {
+				s.startBatch(s.adoptOperation(), casks, len(casks))
+			},
+		})
+	}, s.handleBack)
+	s.appService.GetApp().SetRoot(migratePages, true)
+}
+
+// adoptOperation returns the batch operation adopting the existing apps of casks.
+func (s *InputService) adoptOperation() batchOperation {
+	return batchOperation{
+		actionVerb:    i18n.T("Adopting"),
+		skipCondition: func(pkg models.Package) bool { return pkg.LocallyInstalled },
+		skipReason:    i18n.T("already installed"),
+		execute: func(pkg models.Package) error {
+			return s.brewService.AdoptCask(pkg, s.appService.app, s.layout.GetOutput().View())
+		},
+	}
+}
+
+// showOperationFailure notifies that an operation on a package failed, with its exit code, offering to view
+// its output, to retry it and to search the issues of its tap for the error (!).
+// Failures because another brew process holds the lock offer to wait for it instead.
+func (s *InputService) showOperationFailure(message string, info models.Package, err error, retry func(models.Package)) {
+	if isBrewLocked(err) {
+		s.waitForBrewLock(message, info, err, retry)
+		return
+	}
+	s.showFailureActions(message, info, err, retry)
+}
+
+// showFailureActions reports a failed operation with its exit code, and the actions to view its log,
+// retry it or search the issues of its tap.
+func (s *InputService) showFailureActions(message string, info models.Package, err error, retry func(models.Package)) {
+	if code, ok := exitCode(err); ok {
+		message += " " + i18n.T("(exit code %d)", code)
+	}
+
+	var actions []components.NotifierAction
+	if args := failureCommand(err); isPasswordRequired(err) && args != nil {
+		// The installer asked for the password with sudo, but the prompt was canceled or couldn't be shown
+		message += ": " + i18n.T("the installer needs your password (sudo)")
+		actions = append(actions, components.NotifierAction{Label: i18n.T("Run in terminal"), Run: func() {
+			s.handOff(info, handoffOperation(args), args)
+		}})
+	}
+	if output := failureOutput(err); output != "" {
+		actions = append(actions, components.NotifierAction{Label: i18n.T("View log"), Run: func() {
+			logPages := s.layout.GetLog().Build(s.layout.Root(), message, output, s.handleBack)
+			s.appService.GetApp().SetRoot(logPages, true)
+		}})
+	}
+	actions = append(actions,
+		components.NotifierAction{Label: i18n.T("Retry"), Run: func() { retry(info) }},
+		components.NotifierAction{Label: i18n.T("Search issues"), Run: func() {
+			target := issueSearchURL(info, err)
+			if openURL(target) != nil { // No browser, e.g. over SSH
+				s.appService.terminal.Copy(target)
+				s.layout.GetNotifier().ShowWarning(i18n.T("No browser available, issue search URL copied to the clipboard"))
+			}
+		}},
+	)
+	s.layout.GetNotifier().ShowErrorActions(message, actions)
+}
+
+// handleTerminalEvent is called when the user presses the terminal key (t).
+// It runs the install or upgrade of the selected package in an external terminal, for interactive installers.
+func (s *InputService) handleTerminalEvent() {
+	row, _ := s.layout.GetTable().View().GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	operation, args := handoffCommand(info)
+	s.confirmPackageOperation(components.ModalOptions{
+		Text:       i18n.T("Run `%s` in an external terminal?", strings.Join(args, " ")),
+		ActionType: operation,
+		Cancel:     s.closeModal,
+		Confirm: func() {
+			s.closeModal()
+			s.handOff(info, operation, args)
+		},
+	})
+}
+
+// handOff runs a brew command on a package in an external terminal, reporting its result once it finishes.
+// Without a terminal, e.g. over SSH, the command is copied to the clipboard instead.
+func (s *InputService) handOff(info models.Package, operation string, args []string) {
+	command := strings.Join(args, " ")
+	if !s.appService.beginOperation(i18n.T("running %s in a terminal…", command)) {
+		return
+	}
+	err := runInTerminal(args, func(err error) {
+		s.appService.endOperation()
+		if err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("`%s` failed in the terminal: %v", command, err))
+		} else {
+			s.layout.GetNotifier().ShowSuccess(i18n.T("`%s` finished in the terminal", command))
+		}
+		s.appService.events.Publish(events.Event{
+			Type: events.OperationCompleted, Operation: operation, Package: &info, Err: err,
+		})
+	})
+	if err != nil {
+		s.appService.endOperation()
+		s.appService.terminal.Copy(command)
+		s.layout.GetNotifier().ShowWarning(i18n.T("No terminal available, command copied to the clipboard"))
+		return
+	}
+	s.layout.GetNotifier().ShowProgress(i18n.T("Running `%s` in an external terminal...", command))
+}
+
+// PromptPassword asks for a password in a masked prompt, e.g. for sudo run by a cask installer,
+// blocking until it is entered. Returns false when canceled. Don't call it from the UI goroutine.
+func (s *InputService) PromptPassword(prompt string) (string, bool) {
+	type answer struct {
+		password string
+		ok       bool
+	}
+	answers := make(chan answer, 1)
+	s.appService.GetApp().QueueUpdateDraw(func() {
+		promptPages := s.layout.GetPrompt().BuildPassword(s.layout.Root(), i18n.T("Password required"),
+			prompt+"  "+i18n.T("enter: submit | esc: cancel"), func(password string) {
+				s.handleBack()
+				answers <- answer{password: password, ok: true}
+			}, func() {
+				s.handleBack()
+				answers <- answer{}
+			})
+		s.appService.GetApp().SetRoot(promptPages, true)
+	})
+	a := <-answers
+	return a.password, a.ok
+}
+
+// waitForBrewLock explains that another brew process holds the lock, listing them, and offers to retry
+// the operation once they finish. Declining reports the failure as usual.
+func (s *InputService) waitForBrewLock(message string, info models.Package, err error, retry func(models.Package)) {
+	text := i18n.T("%s: another Homebrew process holds the lock.", message)
+	if processes := brewProcesses(); len(processes) > 0 {
+		text += "\n"
+		for _, process := range processes {
+			text += fmt.Sprintf("\nPID %d: %s", process.PID, tview.Escape(process.Command))
+		}
+	}
+	text += "\n\n" + i18n.T("Wait for it to finish, then retry automatically?")
+
+	s.appService.app.QueueUpdateDraw(func() {
+		s.showModal(components.ModalOptions{
+			Text: text,
+			Cancel: func() {
+				s.closeModal()
+				s.showFailureActions(message, info, err, retry)
+			},
+			Confirm: func() {
 				s.closeModal()
-				s.layout.GetOutput().Clear()
 				go func() {
-					s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Installing %s...", info.Name))
-					if err := s.brewService.InstallPackage(info, s.appService.app, s.layout.GetOutput().View()); err != nil {
-						s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to install %s", info.Name))
-						return
-					}
-					s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Installed %s", info.Name))
-					s.appService.forceRefreshResults()
+					defer RecoverCrash()
+					s.retryWhenBrewUnlocked(message, info, err, retry)
 				}()
-			}, s.closeModal)
+			},
+		})
+	})
+}
+
+// retryWhenBrewUnlocked checks the brew processes with an increasing interval, and retries the operation
+// once none is left. It gives up after brewLockMaxWait, reporting the failure.
+func (s *InputService) retryWhenBrewUnlocked(message string, info models.Package, err error, retry func(models.Package)) {
+	deadline := time.Now().Add(brewLockMaxWait)
+	for check := 0; ; check++ {
+		delay := brewLockBackoff(check)
+		s.layout.GetNotifier().ShowProgress(i18n.T("Waiting for Homebrew to retry %s (next check in %s)...", info.Name, delay))
+		time.Sleep(delay)
+		if len(brewProcesses()) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			s.appService.app.QueueUpdateDraw(func() { s.showFailureActions(message, info, err, retry) })
+			return
+		}
+	}
+	s.appService.app.QueueUpdateDraw(func() { retry(info) })
+}
+
+// handleFailureActionsEvent is called when the user presses the failure actions key (!).
+// It focuses the actions of the last failure notification.
+func (s *InputService) handleFailureActionsEvent() {
+	notifier := s.layout.GetNotifier()
+	if !notifier.HasActions() {
+		return
+	}
+	notifier.Activate(func() { s.appService.GetApp().SetFocus(s.layout.GetTable().View()) })
+	s.appService.GetApp().SetFocus(notifier.View())
+}
+
+// showFailure explains why installing a package failed, offering to copy the suggested fix.
+func (s *InputService) showFailure(name string, diagnosis brewDiagnosis) {
+	s.showModal(components.ModalOptions{
+		Text: fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s\n%s\n\n%s",
+			i18n.T("Failed to install %s: %s", name, i18n.T(diagnosis.Failure.Title)),
+			i18n.T(diagnosis.Failure.Cause), tview.Escape(diagnosis.Line),
+			i18n.T("Suggested fix:"), tview.Escape(diagnosis.Command), i18n.T("Copy it to the clipboard?")),
+		Cancel: s.closeModal,
+		Confirm: func() {
+			s.closeModal()
+			s.appService.terminal.Copy(diagnosis.Command)
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Command copied to the clipboard"))
+		},
+	})
+}
+
+// showCaveats shows the caveats of a newly installed package, offering to copy the commands they suggest,
+// and to add the lines they ask for to the profile of the user's shell.
+func (s *InputService) showCaveats(name, caveats string) {
+	snippet := caveatsSnippet(caveats)
+	rcLines := caveatsRCLines(caveats)
+	rcFile, err := shellRCFile()
+	if err != nil || s.appService.IsReadOnly() {
+		rcLines = nil
+	}
+	rcLabel := ""
+	if len(rcLines) > 0 {
+		rcLabel = displayPath(rcFile)
 	}
+
+	caveatsPages := s.layout.GetCaveats().Build(s.layout.Root(), name, caveats, snippet, rcLabel, func() {
+		s.appService.terminal.Copy(snippet)
+		s.layout.GetNotifier().ShowSuccess(i18n.T("Commands copied to the clipboard"))
+	}, func() {
+		s.appendToShellRC(name, rcFile, rcLines, func() { s.showCaveats(name, caveats) })
+	}, s.handleBack)
+	s.appService.GetApp().SetRoot(caveatsPages, true)
+}
+
+// appendToShellRC previews the lines to add to a shell profile and appends them once confirmed,
+// backing up the profile first. onCancel returns to where the user came from.
+func (s *InputService) appendToShellRC(name, rcFile string, lines []string, onCancel func()) {
+	s.showModal(components.ModalOptions{
+		Text: i18n.T("Append to %s?", displayPath(rcFile)) + "\n\n" + strings.Join(lines, "\n") + "\n\n" +
+			i18n.T("A backup of the file is saved next to it."),
+		Cancel: onCancel,
+		Confirm: func() {
+			s.closeModal()
+			added, backup, err := appendToShellRC(rcFile, name, lines)
+			switch {
+			case err != nil:
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to update %s: %v", displayPath(rcFile), err))
+			case added == 0:
+				s.layout.GetNotifier().ShowWarning(i18n.T("%s already has these lines", displayPath(rcFile)))
+			case backup != "":
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Updated %s (backup: %s), open a new shell to apply",
+					displayPath(rcFile), displayPath(backup)))
+			default:
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Created %s, open a new shell to apply", displayPath(rcFile)))
+			}
+		},
+	})
 }
 
 // handleRemovePackageEvent is called when the user presses the removal key (r).
 func (s *InputService) handleRemovePackageEvent() {
+	if s.runOnMarked(s.removeOperation()) {
+		return
+	}
 	row, _ := s.layout.GetTable().View().GetSelection()
-	if row > 0 {
-		info := (*s.appService.filteredPackages)[row-1]
-		s.showModal(
-			fmt.Sprintf("Are you sure you want to remove the package: %s?", info.Name),
-			func() {
-				s.closeModal()
-				s.layout.GetOutput().Clear()
-				go func() {
-					s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Removing %s...", info.Name))
-					if err := s.brewService.RemovePackage(info, s.appService.app, s.layout.GetOutput().View()); err != nil {
-						s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to remove %s", info.Name))
-						return
-					}
-					s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Removed %s", info.Name))
-					s.appService.forceRefreshResults()
-				}()
-			}, s.closeModal)
+	if info, exists := s.appService.packageAtRow(row); exists {
+		s.removePackage(info)
+	}
+}
+
+// removePackage asks to confirm, then removes a package in the background.
+// Protected packages are only removed after typing their name, even in expert mode.
+func (s *InputService) removePackage(info models.Package) {
+	remove := func() {
+		s.closeModal()
+		if !s.appService.beginOperation(i18n.T("removing %s…", info.Name)) {
+			return
+		}
+		s.layout.GetOutput().BeginOperation(i18n.T("removing %s…", info.Name))
+		go func() {
+			defer RecoverCrash()
+			defer s.appService.endOperation()
+			s.layout.GetNotifier().ShowProgress(i18n.T("Removing %s...", info.Name))
+			err := s.brewService.RemovePackage(info, s.appService.app, s.layout.GetOutput().View())
+			if err != nil {
+				s.showOperationFailure(i18n.T("Failed to remove %s", info.Name), info, err, s.removePackage)
+				s.appService.terminal.Done(i18n.T("Failed to remove %s", info.Name))
+			} else {
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Removed %s", info.Name))
+				s.appService.terminal.Done(i18n.T("Removed %s", info.Name))
+				if err := s.appService.removed.Record(info); err != nil {
+					appLog.Warn("failed to record the removal", "package", info.Name, "err", err)
+				}
+			}
+			s.appService.events.Publish(events.Event{
+				Type: events.OperationCompleted, Operation: events.OperationRemove, Package: &info, Err: err,
+			})
+		}()
 	}
+
+	if s.appService.config.IsProtected(info.Name) {
+		promptPages := s.layout.GetPrompt().Build(s.layout.Root(),
+			i18n.T("%s is protected, type its name to remove it", info.Name),
+			i18n.T("enter: remove | esc: cancel"), "", func(text string) {
+				if strings.TrimSpace(text) != info.Name {
+					s.handleBack()
+					s.layout.GetNotifier().ShowWarning(i18n.T("The name didn't match, %s was not removed", info.Name))
+					return
+				}
+				remove()
+			}, s.handleBack)
+		s.appService.GetApp().SetRoot(promptPages, true)
+		return
+	}
+
+	s.confirmPackageOperation(components.ModalOptions{
+		Text:    i18n.T("Are you sure you want to remove the package: %s?", info.Name),
+		Default: components.ModalCancel, // Removing is destructive, Enter should not confirm it
+		Cancel:  s.closeModal,
+		Confirm: remove,
+	})
+}
+
+// handleRestoreEvent is called when the user presses the restore key (Z).
+// It lists the packages recently removed with bbrew, to reinstall one with Enter.
+func (s *InputService) handleRestoreEvent() {
+	removed := s.appService.removed.List()
+	items := make([]components.RemovedItem, len(removed))
+	for i, entry := range removed {
+		items[i] = components.RemovedItem{
+			Name: entry.Name, Cask: entry.Cask, Version: entry.Version, Options: entry.Options, RemovedAt: entry.RemovedAt,
+		}
+	}
+	removedPages := s.layout.GetRemoved().Build(s.layout.Root(), items, func(item components.RemovedItem) {
+		for _, entry := range removed {
+			if entry.Name == item.Name && entry.Cask == item.Cask {
+				s.restorePackage(entry)
+				return
+			}
+		}
+	}, s.handleBack)
+	s.appService.GetApp().SetRoot(removedPages, true)
+}
+
+// restorePackage asks to confirm, then reinstalls a removed package in the background with its
+// install options. brew installs the current version, which may be newer than the removed one.
+func (s *InputService) restorePackage(entry RemovedPackage) {
+	info, known := s.appService.store.Lookup(entry.Name, entry.Package().Type)
+	if !known {
+		info = entry.Package() // e.g. from a tap removed since
+	}
+	if info.LocallyInstalled {
+		s.handleBack()
+		s.layout.GetNotifier().ShowWarning(i18n.T("%s is already installed", info.Name))
+		return
+	}
+
+	message := i18n.T("Reinstall %s, removed on %s?", info.Name, entry.RemovedAt.Format("2006-01-02 15:04"))
+	if len(entry.Options) > 0 {
+		message += "\n\n" + i18n.T("Options: %s", tview.Escape(strings.Join(entry.Options, " ")))
+	}
+	if entry.Version != "" && info.Version != "" && entry.Version != info.Version {
+		message += "\n\n" + i18n.T("%s was removed, %s will be installed.", entry.Version, info.Version)
+	}
+	s.confirmPackageOperation(components.ModalOptions{
+		Text:       message,
+		ActionType: string(events.OperationInstall),
+		Cancel:     s.handleRestoreEvent,
+		Confirm: func() {
+			s.closeModal()
+			if !s.appService.beginOperation(i18n.T("installing %s…", info.Name)) {
+				return
+			}
+			s.layout.GetOutput().BeginOperation(i18n.T("installing %s…", info.Name))
+			go func() {
+				defer RecoverCrash()
+				defer s.appService.endOperation()
+				s.layout.GetNotifier().ShowProgress(i18n.T("Installing %s...", info.Name))
+				err := s.installWithHooks(info, func() error {
+					return s.brewService.InstallPackage(info, s.appService.config.CaskNoQuarantine, s.appService.app,
+						s.layout.GetOutput().View(), entry.Options...)
+				})
+				if err != nil {
+					s.showOperationFailure(i18n.T("Failed to install %s", info.Name), info, err, func(models.Package) {
+						s.restorePackage(entry)
+					})
+					s.appService.terminal.Done(i18n.T("Failed to install %s", info.Name))
+				} else {
+					s.layout.GetNotifier().ShowSuccess(i18n.T("Restored %s", info.Name))
+					s.appService.terminal.Done(i18n.T("Restored %s", info.Name))
+					if err := s.appService.removed.Forget(entry.Name, entry.Cask); err != nil {
+						appLog.Warn("failed to update the removed packages", "package", entry.Name, "err", err)
+					}
+				}
+				s.appService.events.Publish(events.Event{
+					Type: events.OperationCompleted, Operation: events.OperationInstall, Package: &info, Err: err,
+				})
+			}()
+		},
+	})
 }
 
 // handleUpdatePackageEvent is called when the user presses the update key (u).
 func (s *InputService) handleUpdatePackageEvent() {
+	if s.runOnMarked(s.updateOperation()) {
+		return
+	}
 	row, _ := s.layout.GetTable().View().GetSelection()
-	if row > 0 {
-		info := (*s.appService.filteredPackages)[row-1]
-		s.showModal(
-			fmt.Sprintf("Are you sure you want to update the package: %s?", info.Name),
-			func() {
-				s.closeModal()
-				s.layout.GetOutput().Clear()
-				go func() {
-					s.layout.GetNotifier().ShowWarning(fmt.Sprintf("Updating %s...", info.Name))
-					if err := s.brewService.UpdatePackage(info, s.appService.app, s.layout.GetOutput().View()); err != nil {
-						s.layout.GetNotifier().ShowError(fmt.Sprintf("Failed to update %s", info.Name))
-						return
-					}
-					s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Updated %s", info.Name))
-					s.appService.forceRefreshResults()
-				}()
-			}, s.closeModal)
+	if info, exists := s.appService.packageAtRow(row); exists {
+		s.updatePackage(info)
+	}
+}
+
+// updatePackage asks to confirm, then updates a package in the background.
+// The confirmation lists what else the upgrade would upgrade, from `brew upgrade --dry-run` run meanwhile.
+func (s *InputService) updatePackage(info models.Package) {
+	text := i18n.T("Are you sure you want to update the package: %s?", info.Name)
+	options := components.ModalOptions{
+		Text:       text,
+		ActionType: string(events.OperationUpdate),
+		Cancel:     s.closeModal,
+		Confirm: func() {
+			s.closeModal()
+			if !s.appService.beginOperation(i18n.T("updating %s…", info.Name)) {
+				return
+			}
+			s.layout.GetOutput().BeginOperation(i18n.T("updating %s…", info.Name))
+			go func() {
+				defer RecoverCrash()
+				defer s.appService.endOperation()
+				s.layout.GetNotifier().ShowProgress(i18n.T("Updating %s...", info.Name))
+				err := s.brewService.UpdatePackage(info, s.appService.app, s.layout.GetOutput().View())
+				if err != nil {
+					s.showOperationFailure(i18n.T("Failed to update %s", info.Name), info, err, s.updatePackage)
+					s.appService.terminal.Done(i18n.T("Failed to update %s", info.Name))
+				} else {
+					s.layout.GetNotifier().ShowSuccess(i18n.T("Updated %s", info.Name))
+					s.appService.terminal.Done(i18n.T("Updated %s", info.Name))
+				}
+				s.appService.events.Publish(events.Event{
+					Type: events.OperationCompleted, Operation: events.OperationUpdate, Package: &info, Err: err,
+				})
+			}()
+		},
+	}
+	if s.appService.IsExpertMode() || s.layout.GetModal().Skipped(options.ActionType) {
+		options.Confirm()
+		return
+	}
+
+	options.Text = text + "\n\n" + i18n.T("Checking what else would be upgraded…")
+	s.showModal(options)
+	go func() {
+		defer RecoverCrash()
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+		sections, err := previewUpgrade(ctx, info)
+		preview := formatUpgradePreview(info, sections, err)
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			s.layout.GetModal().UpdateText(options.Text, text+preview)
+		})
+	}()
+}
+
+// formatUpgradePreview lists the packages an upgrade would also upgrade or install, for the confirmation modal.
+func formatUpgradePreview(info models.Package, sections []upgradeSection, err error) string {
+	if err != nil {
+		message, _, _ := strings.Cut(err.Error(), "\n")
+		return "\n\n" + tview.Escape(i18n.T("Could not check what else would be upgraded: %s", message))
+	}
+
+	const maxListed = 8
+	var b strings.Builder
+	for _, section := range sections {
+		var others []string
+		for _, pkg := range section.Packages {
+			if pkg != info.Name && !strings.HasPrefix(pkg, info.Name+" ") {
+				others = append(others, pkg)
+			}
+		}
+		if len(others) == 0 {
+			continue
+		}
+		b.WriteString("\n\n" + tview.Escape(section.Title))
+		for i, pkg := range others {
+			if i == maxListed {
+				b.WriteString("\n" + i18n.T("…and %d more", len(others)-maxListed))
+				break
+			}
+			b.WriteString("\n" + tview.Escape(pkg))
+		}
+	}
+	if b.Len() == 0 {
+		return "\n\n" + i18n.T("Nothing else would be upgraded.")
+	}
+	return b.String()
+}
+
+// updateOperation returns the batch operation updating the outdated packages, held ones left out.
+func (s *InputService) updateOperation() batchOperation {
+	return batchOperation{
+		actionVerb: i18n.T("Updating"),
+		skipCondition: func(pkg models.Package) bool {
+			return !pkg.LocallyInstalled || !pkg.Outdated || s.appService.isHeld(pkg)
+		},
+		skipReason: i18n.T("up to date or held"),
+		execute: func(pkg models.Package) error {
+			return s.brewService.UpdatePackage(pkg, s.appService.app, s.layout.GetOutput().View())
+		},
 	}
 }
 
 // handleUpdateAllPackagesEvent is called when the user presses the update all key (Ctrl+U).
+// Packages held by their upgrade policy (p) are left out.
 func (s *InputService) handleUpdateAllPackagesEvent() {
-	s.showModal("Are you sure you want to update all Packages?", func() {
+	upgradable, held := s.appService.heldPackages()
+	text := i18n.T("Are you sure you want to update all Packages?")
+	if len(held) > 0 {
+		if len(upgradable) == 0 {
+			s.layout.GetNotifier().ShowWarning(i18n.T("Nothing to update: %d outdated packages are held", len(held)))
+			return
+		}
+		names := make([]string, len(held))
+		for i, pkg := range held {
+			names[i] = pkg.Name
+		}
+		text += "\n\n" + i18n.T("Held by policy: %s", strings.Join(names, ", "))
+	}
+
+	s.showModal(components.ModalOptions{Text: text, Cancel: s.closeModal, Confirm: func() {
 		s.closeModal()
-		s.layout.GetOutput().Clear()
+		if !s.appService.beginOperation(i18n.T("upgrading all packages…")) {
+			return
+		}
+		s.layout.GetOutput().BeginOperation(i18n.T("upgrading all packages…"))
 		go func() {
-			s.layout.GetNotifier().ShowWarning("Updating all Packages...")
-			if err := s.brewService.UpdateAllPackages(s.appService.app, s.layout.GetOutput().View()); err != nil {
-				s.layout.GetNotifier().ShowError("Failed to update all Packages")
-				return
+			defer RecoverCrash()
+			defer s.appService.endOperation()
+			s.layout.GetNotifier().ShowProgress(i18n.T("Updating all Packages..."))
+			var err error
+			if len(held) > 0 {
+				err = s.brewService.UpgradePackages(upgradable, s.appService.app, s.layout.GetOutput().View())
+			} else {
+				err = s.brewService.UpdateAllPackages(s.appService.app, s.layout.GetOutput().View())
+			}
+			s.runUpdateAllHook(upgradable, err)
+			if err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to update all Packages"))
+				s.appService.terminal.Done(i18n.T("Failed to update all Packages"))
+			} else {
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Updated all Packages"))
+				s.appService.terminal.Done(i18n.T("Updated all Packages"))
 			}
-			s.layout.GetNotifier().ShowSuccess("Updated all Packages")
-			s.appService.forceRefreshResults()
+			s.appService.events.Publish(events.Event{Type: events.OperationCompleted, Operation: events.OperationUpdateAll, Err: err})
 		}()
-	}, s.closeModal)
+	}})
 }
 
-// batchOperation defines the configuration for a batch package operation.
-type batchOperation struct {
-	actionVerb    string // "Installing" or "Removing"
-	actionTag     string // "INSTALL" or "REMOVE"
-	skipCondition func(pkg models.Package) bool
-	skipReason    string
-	execute       func(pkg models.Package) error
+// handleBrewUpdateEvent is called when the user presses the Homebrew update key (U).
+func (s *InputService) handleBrewUpdateEvent() {
+	if !s.appService.beginOperation(i18n.T("updating Homebrew…")) {
+		return
+	}
+	s.layout.GetOutput().BeginOperation(i18n.T("updating Homebrew…"))
+	go func() {
+		defer RecoverCrash()
+		defer s.appService.endOperation()
+		s.appService.updateHomeBrew()
+	}()
 }
 
 // handleBatchPackageOperation processes multiple packages with progress notifications.
@@ -405,93 +2534,301 @@ func (s *InputService) handleBatchPackageOperation(op batchOperation) {
 		return
 	}
 
-	packages := *s.appService.GetBrewfilePackages()
+	packages := s.appService.GetBrewfilePackages()
+	if op.group != "" {
+		inGroup := make([]models.Package, 0, len(packages))
+		for _, pkg := range packages {
+			if pkg.InBrewfileGroup(op.group) {
+				inGroup = append(inGroup, pkg)
+			}
+		}
+		packages = inGroup
+	}
 	if len(packages) == 0 {
-		s.layout.GetNotifier().ShowError("No packages found in Brewfile")
+		s.layout.GetNotifier().ShowError(i18n.T("No packages found in Brewfile"))
 		return
 	}
 
 	// Count relevant packages
 	actionable := 0
+	var protected []string
 	for _, pkg := range packages {
-		if !op.skipCondition(pkg) {
+		if skip, _ := op.skip(pkg); !skip {
 			actionable++
+		} else if !op.skipCondition(pkg) {
+			protected = append(protected, pkg.Name)
 		}
 	}
 
+	if actionable == 0 && len(protected) > 0 {
+		s.layout.GetNotifier().ShowWarning(i18n.T("No packages to process (%s)", i18n.T("protected")))
+		return
+	}
 	if actionable == 0 {
-		s.layout.GetNotifier().ShowWarning(fmt.Sprintf("No packages to process (%s)", op.skipReason))
+		s.layout.GetNotifier().ShowWarning(i18n.T("No packages to process (%s)", op.skipReason))
 		return
 	}
 
-	message := fmt.Sprintf("%s all packages from Brewfile?\n\nTotal: %d packages\nTo process: %d",
+	message := i18n.T("%s all packages from Brewfile?\n\nTotal: %d packages\nTo process: %d",
 		op.actionVerb, len(packages), actionable)
+	if op.group != "" {
+		message = i18n.T("%s the packages of %s?\n\nTotal: %d packages\nTo process: %d",
+			op.actionVerb, op.group, len(packages), actionable)
+	}
+	if len(protected) > 0 {
+		message += "\n\n" + i18n.T("Protected, left out: %s", strings.Join(protected, ", "))
+	}
 
-	s.showModal(message, func() {
+	s.showModal(components.ModalOptions{Text: message, Cancel: s.closeModal, Confirm: func() {
+		s.startBatch(op, packages, actionable)
+	}})
+}
+
+// startBatch runs a confirmed batch operation in the background, then shows its summary.
+// The packages that failed are kept, to retry them with the retry failed key (F).
+// It returns false when another operation is running.
+func (s *InputService) startBatch(op batchOperation, packages []models.Package, actionable int) bool {
+	activity := i18n.T("%s %d packages…", op.actionVerb, actionable)
+	if !s.appService.beginOperation(activity) {
 		s.closeModal()
-		s.layout.GetOutput().Clear()
-		go func() {
-			current := 0
-			total := len(packages)
+		return false
+	}
+	s.layout.GetOutput().BeginOperation(activity)
+	s.showBatchProgress(activity, packages)
+	go func() {
+		defer RecoverCrash()
+		defer s.appService.endOperation()
+		result := runBatch(packages, op, func(progress events.Progress) {
+			s.appService.events.Publish(events.Event{Type: events.BatchProgress, Progress: &progress})
+		})
 
-			for _, pkg := range packages {
-				current++
-				pkgName := pkg.Name // Capture for closures
+		total := len(packages)
+		s.appService.app.QueueUpdateDraw(func() {
+			s.lastBatch, s.failedBatch = op, result.failed
+			s.updateRetryAction()
 
-				if op.skipCondition(pkg) {
-					s.layout.GetNotifier().ShowWarning(fmt.Sprintf("[%d/%d] Skipping %s (%s)", current, total, pkgName, op.skipReason))
-					s.appService.app.QueueUpdateDraw(func() {
-						fmt.Fprintf(s.layout.GetOutput().View(), "[SKIP] %s (%s)\n", pkgName, op.skipReason)
-					})
-					continue
-				}
+			// Bring the summary back if the progress was hidden
+			progress := s.layout.GetBatchProgress()
+			progress.Finish(i18n.T("Completed! Processed %d packages", total))
+			s.appService.app.SetRoot(progress.View(), true)
+		})
 
-				s.layout.GetNotifier().ShowWarning(fmt.Sprintf("[%d/%d] %s %s...", current, total, op.actionVerb, pkgName))
-				s.appService.app.QueueUpdateDraw(func() {
-					fmt.Fprintf(s.layout.GetOutput().View(), "\n[%s] %s %s...\n", op.actionTag, op.actionVerb, pkgName)
-				})
+		if len(result.failed) > 0 {
+			s.layout.GetNotifier().ShowError(i18n.T("%d packages failed (press F to retry)", len(result.failed)))
+		} else {
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Completed! Processed %d packages", total))
+		}
+		s.appService.terminal.Done(i18n.T("Completed! Processed %d packages", total))
+		s.appService.events.Publish(events.Event{Type: events.OperationCompleted, Operation: events.OperationBatch})
+		s.reportBatch(op, result, total)
+		if op.lock {
+			s.appService.updateBrewfileLock()
+		}
+	}()
+	return true
+}
 
-				if err := op.execute(pkg); err != nil {
-					s.layout.GetNotifier().ShowError(fmt.Sprintf("[%d/%d] Failed to process %s", current, total, pkgName))
-					s.appService.app.QueueUpdateDraw(func() {
-						fmt.Fprintf(s.layout.GetOutput().View(), "[ERROR] Failed to process %s: %v\n", pkgName, err)
-					})
-					continue
-				}
+// reportBatch sends the outcome of a batch to the webhook of the config, if any.
+func (s *InputService) reportBatch(op batchOperation, result batchResult, total int) {
+	report := newWebhookReport("batch", op.actionVerb)
+	report.Brewfile = s.appService.startupOptions.Brewfile
+	if op.group != "" {
+		report.Brewfile += " (" + op.group + ")"
+	}
+	for _, pkg := range result.succeeded {
+		report.Succeeded = append(report.Succeeded, pkg.Name)
+	}
+	for _, pkg := range result.failed {
+		report.Failed = append(report.Failed, pkg.Name)
+	}
+	report.Skipped = result.skipped
+	report.Summary = fmt.Sprintf("%s: %d of %d packages succeeded, %d failed, %d skipped",
+		op.actionVerb, len(result.succeeded), total, len(result.failed), result.skipped)
+	if err := sendWebhook(s.appService.config.Webhook, report); err != nil {
+		appLog.Warn("failed to send the batch report", "err", err)
+	}
+}
 
-				s.appService.app.QueueUpdateDraw(func() {
-					fmt.Fprintf(s.layout.GetOutput().View(), "[SUCCESS] %s processed successfully\n", pkgName)
-				})
-			}
+// updateRetryAction shows the retry failed key in the legend, with the number of failed packages,
+// only while there is something to retry.
+func (s *InputService) updateRetryAction() {
+	s.ActionRetryFailed.HideFromLegend = len(s.failedBatch) == 0
+	s.ActionRetryFailed.Name = i18n.T("Retry failed (%d)", len(s.failedBatch))
+	s.updateLegendEntries()
+}
 
-			s.layout.GetNotifier().ShowSuccess(fmt.Sprintf("Completed! Processed %d packages", total))
-			s.appService.forceRefreshResults()
-		}()
-	}, s.closeModal)
+// handleRetryFailedEvent is called when the user presses the retry failed key (F) after a batch operation.
+// It runs the last batch operation again, on the packages that failed only.
+func (s *InputService) handleRetryFailedEvent() {
+	if len(s.failedBatch) == 0 {
+		s.layout.GetNotifier().ShowWarning(i18n.T("No failed packages to retry"))
+		return
+	}
+
+	if s.startBatch(s.lastBatch, s.failedBatch, len(s.failedBatch)) {
+		s.failedBatch = nil
+		s.updateRetryAction()
+	}
+}
+
+// showBatchProgress opens the batch progress overlay for the given packages.
+// Closing it only hides the overlay: the batch keeps running and can still be followed in the output panel.
+func (s *InputService) showBatchProgress(title string, packages []models.Package) {
+	names := make([]string, len(packages))
+	for i, pkg := range packages {
+		names[i] = pkg.Name
+	}
+	progress := s.layout.GetBatchProgress()
+	progress.Start(title, names)
+	s.appService.app.SetRoot(progress.Build(s.layout.Root(), s.handleBack, s.handleRetryFailedEvent), true)
 }
 
 // handleInstallAllPackagesEvent is called when the user presses the install all key (Ctrl+A).
+// In bundle mode, the whole Brewfile is handed to `brew bundle install` instead.
 func (s *InputService) handleInstallAllPackagesEvent() {
-	s.handleBatchPackageOperation(batchOperation{
-		actionVerb:    "Installing",
-		actionTag:     "INSTALL",
+	if s.appService.IsBundleMode() {
+		s.handleBundleInstall()
+		return
+	}
+
+	s.handleBatchPackageOperation(s.installOperation())
+}
+
+// installOperation returns the batch operation installing the missing packages.
+// With parallel_casks set, casks install that many at a time, their output tagged by name.
+// Casks are quarantined according to cask_no_quarantine.
+func (s *InputService) installOperation() batchOperation {
+	workers := s.appService.config.ParallelCasks
+	noQuarantine := s.appService.config.CaskNoQuarantine
+	return batchOperation{
+		actionVerb:    i18n.T("Installing"),
 		skipCondition: func(pkg models.Package) bool { return pkg.LocallyInstalled },
-		skipReason:    "already installed",
+		skipReason:    i18n.T("already installed"),
 		execute: func(pkg models.Package) error {
-			return s.brewService.InstallPackage(pkg, s.appService.app, s.layout.GetOutput().View())
+			return s.installWithHooks(pkg, func() error {
+				if workers > 1 && pkg.Type == models.PackageTypeCask {
+					return s.brewService.InstallPackageTagged(pkg, noQuarantine, s.appService.app, s.layout.GetOutput().View())
+				}
+				return s.brewService.InstallPackage(pkg, noQuarantine, s.appService.app, s.layout.GetOutput().View())
+			})
 		},
-	})
+		concurrent: func(pkg models.Package) bool { return pkg.Type == models.PackageTypeCask },
+		workers:    workers,
+		lock:       true,
+	}
+}
+
+// handleInstallGroupEvent is called when the user presses the install group key (G) in Brewfile mode.
+// It installs the missing packages of the selected section.
+func (s *InputService) handleInstallGroupEvent() {
+	group, ok := s.appService.selectedGroup()
+	if !ok {
+		s.layout.GetNotifier().ShowWarning(i18n.T("Select a Brewfile section to install"))
+		return
+	}
+
+	op := s.installOperation()
+	op.group = group
+	s.handleBatchPackageOperation(op)
+}
+
+// handleBundleInstall runs `brew bundle install` on the Brewfile, streaming its output.
+func (s *InputService) handleBundleInstall() {
+	text := i18n.T("Run brew bundle install on the Brewfile?\n\nIt installs and upgrades every entry, taps and mas apps included.")
+	s.showModal(components.ModalOptions{Text: text, Cancel: s.closeModal, Confirm: func() {
+		s.closeModal()
+		s.runBundle(i18n.T("brew bundle install…"), func() error {
+			return s.brewService.BundleInstall(s.appService.brewfilePath, s.appService.app, s.layout.GetOutput().View())
+		})
+	}})
+}
+
+// handleBundleCleanupEvent is called when the user presses the bundle cleanup key (C) in bundle mode.
+// It lists the installed packages missing from the Brewfile, then asks before uninstalling them.
+func (s *InputService) handleBundleCleanupEvent() {
+	activity := i18n.T("listing the packages missing from the Brewfile…")
+	if !s.appService.beginOperation(activity) {
+		return
+	}
+	s.layout.GetOutput().BeginOperation(activity)
+	go func() {
+		defer RecoverCrash()
+		err := s.brewService.BundleCleanup(s.appService.brewfilePath, false, s.appService.app, s.layout.GetOutput().View())
+		s.appService.endOperation() // Before the confirmation, which begins the uninstall
+		if err != nil {
+			s.appService.app.QueueUpdateDraw(func() {
+				s.layout.GetNotifier().ShowError(i18n.T("brew bundle cleanup failed: %v", err))
+			})
+			return
+		}
+		s.appService.app.QueueUpdateDraw(func() {
+			text := i18n.T("Uninstall the packages listed in the output panel?\n\nThey are installed but not in the Brewfile.")
+			s.showModal(components.ModalOptions{Text: text, Default: components.ModalCancel, Cancel: s.closeModal, Confirm: func() {
+				s.closeModal()
+				s.runBundle(i18n.T("brew bundle cleanup…"), func() error {
+					return s.brewService.BundleCleanup(s.appService.brewfilePath, true, s.appService.app, s.layout.GetOutput().View())
+				})
+			}})
+		})
+	}()
+}
+
+// runBundle runs a brew bundle command in the background, its output streaming into the output panel,
+// then reloads the packages.
+func (s *InputService) runBundle(activity string, run func() error) {
+	if !s.appService.beginOperation(activity) {
+		return
+	}
+	s.layout.GetOutput().BeginOperation(activity)
+	go func() {
+		defer RecoverCrash()
+		defer s.appService.endOperation()
+		s.layout.GetNotifier().ShowWarning(activity)
+		err := run()
+		if err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("brew bundle failed: %v", err))
+			s.appService.terminal.Done(i18n.T("brew bundle failed: %v", err))
+		} else {
+			s.layout.GetNotifier().ShowSuccess(i18n.T("brew bundle completed"))
+			s.appService.terminal.Done(i18n.T("brew bundle completed"))
+		}
+		s.appService.events.Publish(events.Event{Type: events.OperationCompleted, Operation: events.OperationBatch, Err: err})
+	}()
+}
+
+// handleInstallTapsEvent is called when the user presses the install taps key (T) in Brewfile mode.
+func (s *InputService) handleInstallTapsEvent() {
+	if !s.appService.beginOperation(i18n.T("installing taps…")) {
+		return
+	}
+	s.layout.GetOutput().BeginOperation(i18n.T("installing taps…"))
+	go func() {
+		defer RecoverCrash()
+		defer s.appService.endOperation()
+		s.appService.InstallMissingTaps()
+	}()
 }
 
 // handleRemoveAllPackagesEvent is called when the user presses the remove all key (Ctrl+R).
 func (s *InputService) handleRemoveAllPackagesEvent() {
-	s.handleBatchPackageOperation(batchOperation{
-		actionVerb:    "Removing",
-		actionTag:     "REMOVE",
+	s.handleBatchPackageOperation(s.removeOperation())
+}
+
+// removeOperation returns the batch operation removing the installed packages, protected ones left out.
+func (s *InputService) removeOperation() batchOperation {
+	return batchOperation{
+		actionVerb:    i18n.T("Removing"),
 		skipCondition: func(pkg models.Package) bool { return !pkg.LocallyInstalled },
-		skipReason:    "not installed",
+		skipReason:    i18n.T("not installed"),
 		execute: func(pkg models.Package) error {
-			return s.brewService.RemovePackage(pkg, s.appService.app, s.layout.GetOutput().View())
+			if err := s.brewService.RemovePackage(pkg, s.appService.app, s.layout.GetOutput().View()); err != nil {
+				return err
+			}
+			if err := s.appService.removed.Record(pkg); err != nil {
+				appLog.Warn("failed to record the removal", "package", pkg.Name, "err", err)
+			}
+			return nil
 		},
-	})
+		protected: func(pkg models.Package) bool { return s.appService.config.IsProtected(pkg.Name) },
+	}
 }
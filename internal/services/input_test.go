@@ -0,0 +1,54 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"bbrew/internal/models"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestCopyFailureFixFromModal(t *testing.T) {
+	s, app := newTestAppService(notifyOSC9)
+	recorder := recordCopies(s)
+	go app.Run()
+	defer app.Stop()
+
+	diagnosis := brewDiagnosis{Line: "xcrun: error: invalid active developer path", Command: "xcode-select --install"}
+	runOnEventLoop(t, app, func() { s.inputService.(*InputService).showFailure("wget", diagnosis) })
+	pressKeys(app, tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModNone))
+	waitCopied(t, app, recorder, diagnosis.Command)
+}
+
+func TestCopyCaveatsCommands(t *testing.T) {
+	s, app := newTestAppService(notifyOSC9)
+	recorder := recordCopies(s)
+	go app.Run()
+	defer app.Stop()
+
+	caveats := "To start postgresql now and restart at login:\n  brew services start postgresql"
+	runOnEventLoop(t, app, func() { s.inputService.(*InputService).showCaveats("postgresql", caveats) })
+	pressKeys(app, tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModNone))
+	waitCopied(t, app, recorder, "brew services start postgresql")
+}
+
+func TestCopyIssueSearchWithoutBrowser(t *testing.T) {
+	t.Setenv("PATH", "") // No browser to open the URL with
+	s, app := newTestAppService(notifyOSC9)
+	recorder := recordCopies(s)
+	go app.Run()
+	defer app.Stop()
+
+	info := models.Package{Name: "wget", Type: models.PackageTypeFormula}
+	err := errors.New("exit status 1")
+	runOnEventLoop(t, app, func() {
+		app.SetRoot(s.layout.GetNotifier().View(), true) // Keys only reach the primitives of the root
+		input := s.inputService.(*InputService)
+		input.showFailureActions("Failed to install wget", info, err, func(models.Package) {})
+		input.handleFailureActionsEvent()
+	})
+	// Actions: Retry, Search issues
+	pressKeys(app, tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone), tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	waitCopied(t, app, recorder, issueSearchURL(info, err))
+}
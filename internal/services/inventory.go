@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// inventoryEntry is a package of an inventory snapshot.
+type inventoryEntry struct {
+	Name      string             `json:"name"`
+	FullName  string             `json:"full_name,omitempty"`
+	Type      models.PackageType `json:"type"`
+	Version   string             `json:"version,omitempty"`
+	OnRequest bool               `json:"on_request"` // Installed explicitly, not as a dependency
+}
+
+// inventorySnapshot lists the packages installed on a machine, to compare machines with each other.
+type inventorySnapshot struct {
+	Host     string           `json:"host"`
+	Created  time.Time        `json:"created"`
+	Packages []inventoryEntry `json:"packages"`
+}
+
+// inventoryKey identifies a package across machines: formulae and casks may share a name.
+func inventoryKey(packageType models.PackageType, name string) string {
+	return string(packageType) + ":" + strings.ToLower(models.ShortName(name))
+}
+
+// defaultInventoryPath is the file name suggested for the inventory of this machine.
+func defaultInventoryPath() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("bbrew-inventory-%s.json", strings.Split(host, ".")[0])
+}
+
+// localInventory returns the snapshot of the packages installed on this machine.
+func (s *AppService) localInventory() inventorySnapshot {
+	host, _ := os.Hostname()
+	snapshot := inventorySnapshot{Host: host, Created: time.Now()}
+	for _, pkg := range s.store.All() {
+		if !pkg.LocallyInstalled {
+			continue
+		}
+		snapshot.Packages = append(snapshot.Packages, inventoryEntry{
+			Name:      pkg.Name,
+			FullName:  pkg.FullName,
+			Type:      pkg.Type,
+			Version:   pkg.InstalledVersion(),
+			OnRequest: pkg.InstalledOnRequest,
+		})
+	}
+	return snapshot
+}
+
+// writeInventory saves an inventory snapshot as JSON.
+func writeInventory(path string, snapshot inventorySnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Clean(path), append(data, '\n'), 0600)
+}
+
+// readInventory loads the inventory of another machine: a snapshot written by bbrew, or a Brewfile.
+// Brewfile entries have no version, so they are only compared by presence.
+func readInventory(path string) (inventorySnapshot, error) {
+	// #nosec G304 -- path is chosen by the user in the compare prompt
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return inventorySnapshot{}, err
+	}
+
+	var snapshot inventorySnapshot
+	if json.Unmarshal(data, &snapshot) == nil && snapshot.Packages != nil {
+		return snapshot, nil
+	}
+
+	brewfile, err := parseBrewfileWithTaps(path)
+	if err != nil {
+		return inventorySnapshot{}, err
+	}
+	snapshot = inventorySnapshot{Host: filepath.Base(path)}
+	for _, entry := range brewfile.Packages {
+		packageType := models.PackageTypeFormula
+		if entry.IsCask {
+			packageType = models.PackageTypeCask
+		}
+		snapshot.Packages = append(snapshot.Packages, inventoryEntry{
+			Name:      models.ShortName(entry.Name),
+			FullName:  entry.Name,
+			Type:      packageType,
+			OnRequest: true,
+		})
+	}
+	return snapshot, nil
+}
+
+// compareInventories sorts the packages of two machines into only here, only there and version mismatches.
+// Packages installed as dependencies are only reported as missing on the other side when they
+// were installed on request, like `brew bundle dump` would list them.
+func compareInventories(here, there inventorySnapshot) []components.InventoryItem {
+	index := func(snapshot inventorySnapshot) map[string]inventoryEntry {
+		entries := make(map[string]inventoryEntry, len(snapshot.Packages))
+		for _, entry := range snapshot.Packages {
+			entries[inventoryKey(entry.Type, entry.Name)] = entry
+		}
+		return entries
+	}
+	hereEntries, thereEntries := index(here), index(there)
+
+	var items []components.InventoryItem
+	for key, local := range hereEntries {
+		remote, found := thereEntries[key]
+		switch {
+		case !found && local.OnRequest:
+			items = append(items, inventoryItem(components.InventoryOnlyHere, local, local.Version, ""))
+		case found && local.Version != "" && remote.Version != "" && local.Version != remote.Version:
+			items = append(items, inventoryItem(components.InventoryMismatch, local, local.Version, remote.Version))
+		}
+	}
+	for key, remote := range thereEntries {
+		if _, found := hereEntries[key]; !found && remote.OnRequest {
+			items = append(items, inventoryItem(components.InventoryOnlyThere, remote, "", remote.Version))
+		}
+	}
+
+	i18n.SortByName(items, func(item components.InventoryItem) string { return item.Name })
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Category < items[j].Category })
+	return items
+}
+
+// inventoryItem converts an inventory entry to an item of the comparison view.
+func inventoryItem(category components.InventoryCategory, entry inventoryEntry, here, there string) components.InventoryItem {
+	return components.InventoryItem{
+		Category:     category,
+		Name:         entry.Name,
+		FullName:     entry.FullName,
+		Cask:         entry.Type == models.PackageTypeCask,
+		HereVersion:  here,
+		ThereVersion: there,
+	}
+}
+
+// inventoryPackage finds the package of a comparison item. Packages unknown here (e.g. from
+// a tap that isn't installed) get a placeholder, installed by their full name.
+func (s *AppService) inventoryPackage(item components.InventoryItem) models.Package {
+	packageType := models.PackageTypeFormula
+	if item.Cask {
+		packageType = models.PackageTypeCask
+	}
+	key := inventoryKey(packageType, item.Name)
+	for _, pkg := range s.store.All() {
+		if inventoryKey(pkg.Type, pkg.Name) == key {
+			return pkg
+		}
+	}
+	return models.Package{Name: item.Name, FullName: item.FullName, DisplayName: item.Name, Type: packageType}
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"bbrew/internal/logging"
+	"bbrew/internal/models"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// With --allow-ipc, bbrew serves a local API on a Unix socket, for editors, launcher scripts or status bars
+// to query and drive the running instance. Requests and responses are JSON-RPC 2.0 objects, one per line.
+// The socket is in a private directory of the state directory: only this user can connect.
+// `bbrew ipc <method> [params]` is a client for scripts.
+
+var ipcLog = logging.For("ipc")
+
+// ipcSearchLimit is the number of results of a search, unless the request gives one.
+const ipcSearchLimit = 50
+
+// JSON-RPC error codes.
+const (
+	ipcParseError     = -32700
+	ipcMethodNotFound = -32601
+	ipcInvalidParams  = -32602
+	ipcServerError    = -32000
+)
+
+// ipc is the running control server.
+var ipc struct {
+	mu   sync.Mutex
+	stop func() // nil when the server is not running
+}
+
+type ipcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // Absent for notifications, which get no response
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type ipcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *ipcError       `json:"error,omitempty"`
+}
+
+type ipcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ipcError) Error() string {
+	return e.Message
+}
+
+// ipcPackage is a package in the results of list and search.
+type ipcPackage struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"` // formula or cask
+	Version          string `json:"version"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+	Installed        bool   `json:"installed"`
+	Outdated         bool   `json:"outdated"`
+	Description      string `json:"description,omitempty"`
+}
+
+// ipcStatus is the result of status.
+type ipcStatus struct {
+	Version   string `json:"version"`
+	Operation string `json:"operation,omitempty"` // Running operation, e.g. "installing wget…"
+	Busy      bool   `json:"busy"`
+	ReadOnly  bool   `json:"read_only"`
+	Brewfile  string `json:"brewfile,omitempty"`
+	Installed int    `json:"installed"`
+	Outdated  int    `json:"outdated"`
+}
+
+// ipcSocketPath returns the path of the control socket.
+func ipcSocketPath() string {
+	return filepath.Join(getStateDir(), "ipc", "bbrew.sock")
+}
+
+// startIPC serves the control socket until StopIPC. It fails when another bbrew serves it already.
+func startIPC(s *AppService) error {
+	socket := ipcSocketPath()
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		return err
+	}
+	if err := os.Chmod(filepath.Dir(socket), 0700); err != nil {
+		return err
+	}
+	if conn, err := net.Dial("unix", socket); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("another bbrew serves %s", socket)
+	}
+	_ = os.Remove(socket) // Left by a bbrew that didn't stop cleanly
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(socket, 0600); err != nil {
+		_ = listener.Close()
+		return err
+	}
+
+	go func() {
+		defer RecoverCrash()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return // Closed
+			}
+			go func() {
+				defer RecoverCrash()
+				s.serveIPC(conn)
+			}()
+		}
+	}()
+
+	ipc.mu.Lock()
+	defer ipc.mu.Unlock()
+	ipc.stop = func() {
+		_ = listener.Close()
+		_ = os.Remove(socket)
+	}
+	ipcLog.Info("serving the control socket", "socket", socket)
+	return nil
+}
+
+// StopIPC stops the control server and removes its socket, if it is running.
+func StopIPC() {
+	ipc.mu.Lock()
+	defer ipc.mu.Unlock()
+	if ipc.stop != nil {
+		ipc.stop()
+	}
+	ipc.stop = nil
+}
+
+// serveIPC answers the requests of a connection, one per line, until the client closes it.
+func (s *AppService) serveIPC(conn net.Conn) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var request ipcRequest
+		response := ipcResponse{JSONRPC: "2.0"}
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			response.ID = json.RawMessage("null")
+			response.Error = &ipcError{Code: ipcParseError, Message: err.Error()}
+		} else {
+			result, err := s.handleIPC(request)
+			if request.ID == nil {
+				continue // Notification
+			}
+			response.ID, response.Result = request.ID, result
+			if err != nil {
+				response.Error = err
+			}
+		}
+		if err := encoder.Encode(response); err != nil {
+			return
+		}
+	}
+}
+
+// handleIPC runs a request, returning its result.
+func (s *AppService) handleIPC(request ipcRequest) (interface{}, *ipcError) {
+	ipcLog.Debug("request", "method", request.Method)
+	var params struct {
+		Filter string `json:"filter"` // list: a filter name, as --filter
+		Query  string `json:"query"`  // search: text matched against the names and descriptions
+		Limit  int    `json:"limit"`  // search: number of results, 50 by default
+		Show   bool   `json:"show"`   // search: also search in the TUI
+		Name   string `json:"name"`   // install: the package
+		Cask   bool   `json:"cask"`   // install: the package is a cask
+	}
+	if len(request.Params) > 0 {
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, &ipcError{Code: ipcInvalidParams, Message: err.Error()}
+		}
+	}
+
+	switch request.Method {
+	case "status":
+		return s.ipcStatus(), nil
+	case "list":
+		filter, err := ParseFilterType(params.Filter)
+		if params.Filter == "" {
+			filter, err = FilterNone, nil
+		}
+		if err != nil {
+			return nil, &ipcError{Code: ipcInvalidParams, Message: err.Error()}
+		}
+		return ipcPackages(s.filterPackages(s.ipcSource(), filter), 0), nil
+	case "search":
+		if params.Query == "" {
+			return nil, &ipcError{Code: ipcInvalidParams, Message: "query is required"}
+		}
+		if params.Show {
+			s.app.QueueUpdateDraw(func() { s.layout.GetSearch().Field().SetText(params.Query) })
+		}
+		limit := params.Limit
+		if limit <= 0 {
+			limit = ipcSearchLimit
+		}
+		query := strings.ToLower(params.Query)
+		var matches []models.Package
+		for _, pkg := range s.ipcSource() {
+			if strings.Contains(strings.ToLower(pkg.Name), query) || strings.Contains(strings.ToLower(pkg.Description), query) {
+				matches = append(matches, pkg)
+			}
+		}
+		return ipcPackages(matches, limit), nil
+	case "install":
+		return s.ipcInstall(params.Name, params.Cask)
+	}
+	return nil, &ipcError{Code: ipcMethodNotFound, Message: fmt.Sprintf("unknown method %q (valid: status, list, search, install)", request.Method)}
+}
+
+// ipcSource returns the packages the API works on: those of the Brewfile in Brewfile mode, like the table.
+func (s *AppService) ipcSource() []models.Package {
+	if s.IsBrewfileMode() {
+		return s.store.Brewfile()
+	}
+	return s.store.All()
+}
+
+// ipcStatus reports the state of the instance and its running operation.
+func (s *AppService) ipcStatus() ipcStatus {
+	status := ipcStatus{
+		Version: AppVersion, Operation: s.runningOperation(), ReadOnly: s.IsReadOnly(),
+		Brewfile: s.startupOptions.Brewfile,
+	}
+	status.Busy = status.Operation != ""
+	for _, pkg := range s.store.All() {
+		if pkg.LocallyInstalled {
+			status.Installed++
+			if pkg.Outdated {
+				status.Outdated++
+			}
+		}
+	}
+	return status
+}
+
+// ipcInstall asks to install a package in the TUI, as if the user pressed the install key on it: the
+// install is confirmed there, unless in expert mode.
+func (s *AppService) ipcInstall(name string, cask bool) (interface{}, *ipcError) {
+	if name == "" {
+		return nil, &ipcError{Code: ipcInvalidParams, Message: "name is required"}
+	}
+	if s.IsReadOnly() {
+		return nil, &ipcError{Code: ipcServerError, Message: "bbrew is read-only"}
+	}
+	packageType := models.PackageTypeFormula
+	if cask {
+		packageType = models.PackageTypeCask
+	}
+	pkg, exists := s.store.Lookup(name, packageType)
+	if !exists && !cask {
+		pkg, exists = s.store.Lookup(name, models.PackageTypeCask)
+	}
+	switch {
+	case !exists:
+		return nil, &ipcError{Code: ipcServerError, Message: fmt.Sprintf("package %s not found", name)}
+	case pkg.LocallyInstalled:
+		return nil, &ipcError{Code: ipcServerError, Message: fmt.Sprintf("%s is already installed", name)}
+	case s.runningOperation() != "":
+		return nil, &ipcError{Code: ipcServerError, Message: fmt.Sprintf("busy: %s", s.runningOperation())}
+	}
+
+	s.app.QueueUpdateDraw(func() { s.inputService.RequestInstall(pkg) })
+	return map[string]string{"name": pkg.Name, "type": string(pkg.Type), "state": "requested"}, nil
+}
+
+// ipcPackages converts packages for the results, at most limit of them (0 for all).
+func ipcPackages(packages []models.Package, limit int) []ipcPackage {
+	if limit > 0 && len(packages) > limit {
+		packages = packages[:limit]
+	}
+	results := make([]ipcPackage, len(packages))
+	for i, pkg := range packages {
+		results[i] = ipcPackage{
+			Name: pkg.Name, Type: string(pkg.Type), Version: pkg.Version, InstalledVersion: pkg.InstalledVersion(),
+			Installed: pkg.LocallyInstalled, Outdated: pkg.Outdated, Description: pkg.Description,
+		}
+	}
+	return results
+}
+
+// RunIPC is the `bbrew ipc <method> [params]` command: it sends a request to the running bbrew, params
+// being a JSON object, and prints the result as JSON.
+func RunIPC(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: bbrew ipc <status|list|search|install> [json params]")
+	}
+	request := ipcRequest{JSONRPC: "2.0", ID: json.RawMessage("1"), Method: args[0]}
+	if len(args) > 1 {
+		request.Params = json.RawMessage(args[1])
+		if !json.Valid(request.Params) {
+			return fmt.Errorf("params must be a JSON object, e.g. '{\"query\": \"wget\"}'")
+		}
+	}
+
+	conn, err := net.Dial("unix", ipcSocketPath())
+	if err != nil {
+		return fmt.Errorf("no bbrew running with --allow-ipc: %w", err)
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return err
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *ipcError       `json:"error"`
+	}
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(response.Result))
+	return err
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// unknownLicense groups packages without license information (casks never have one).
+const unknownLicense = "Unknown"
+
+// LicenseGroup lists the packages distributed under the same license expression.
+type LicenseGroup struct {
+	License  string
+	Packages []models.Package
+}
+
+// packageLicense returns the license expression of a package, or unknownLicense.
+func packageLicense(pkg models.Package) string {
+	if pkg.Formula != nil && pkg.Formula.License != "" {
+		return pkg.Formula.License
+	}
+	return unknownLicense
+}
+
+// groupByLicense groups the installed packages by license, largest groups first,
+// with unknown licenses last so they stand out in a compliance review.
+func groupByLicense(packages []models.Package) []LicenseGroup {
+	index := make(map[string]int)
+	var groups []LicenseGroup
+	for _, pkg := range packages {
+		if !pkg.LocallyInstalled {
+			continue
+		}
+		license := packageLicense(pkg)
+		i, exists := index[license]
+		if !exists {
+			i = len(groups)
+			index[license] = i
+			groups = append(groups, LicenseGroup{License: license})
+		}
+		groups[i].Packages = append(groups[i].Packages, pkg)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].License == unknownLicense) != (groups[j].License == unknownLicense) {
+			return groups[j].License == unknownLicense
+		}
+		if len(groups[i].Packages) != len(groups[j].Packages) {
+			return len(groups[i].Packages) > len(groups[j].Packages)
+		}
+		return i18n.Compare(groups[i].License, groups[j].License) < 0
+	})
+	return groups
+}
+
+// exportLicenseCSV writes the license groups to a CSV file in the current directory
+// and returns its absolute path.
+func exportLicenseCSV(groups []LicenseGroup) (string, error) {
+	path, err := filepath.Abs(fmt.Sprintf("bbrew-licenses-%s.csv", time.Now().Format("20060102-150405")))
+	if err != nil {
+		return "", err
+	}
+
+	// #nosec G304 -- path is generated, not user-provided
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	_ = writer.Write([]string{"name", "type", "version", "license", "tap"})
+	for _, group := range groups {
+		for _, pkg := range group.Packages {
+			tap := ""
+			if pkg.Formula != nil {
+				tap = pkg.Formula.Tap
+			} else if pkg.Cask != nil {
+				tap = pkg.Cask.Tap
+			}
+			_ = writer.Write([]string{pkg.Name, string(pkg.Type), pkg.InstalledVersion(), group.License, tap})
+		}
+	}
+	writer.Flush()
+	return path, writer.Error()
+}
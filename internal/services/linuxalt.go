@@ -0,0 +1,157 @@
+package services
+
+import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// Most casks are macOS apps, which Homebrew on Linux can't install. Popular ones are packaged for Linux
+// as a Flatpak or a snap, installed instead: the mapping below ships with bbrew.
+
+// flathubRepo is the Flathub repository, added for the user when missing.
+const flathubRepo = "https://dl.flathub.org/repo/flathub.flatpakrepo"
+
+// linuxAlternative is the Linux package of the app of a cask: a Flatpak of Flathub, or a snap of the Snap Store.
+type linuxAlternative struct {
+	Flatpak     string // Flathub app ID, e.g. "org.mozilla.firefox"
+	Snap        string // Snap name, e.g. "firefox"
+	SnapClassic bool   // The snap needs --classic confinement (IDEs, editors)
+}
+
+// linuxAlternatives maps popular casks to their Linux alternatives.
+var linuxAlternatives = map[string]linuxAlternative{
+	"android-studio":     {Flatpak: "com.google.AndroidStudio", Snap: "android-studio", SnapClassic: true},
+	"audacity":           {Flatpak: "org.audacityteam.Audacity", Snap: "audacity"},
+	"bitwarden":          {Flatpak: "com.bitwarden.desktop", Snap: "bitwarden"},
+	"blender":            {Flatpak: "org.blender.Blender", Snap: "blender", SnapClassic: true},
+	"brave-browser":      {Flatpak: "com.brave.Browser", Snap: "brave"},
+	"chromium":           {Flatpak: "org.chromium.Chromium", Snap: "chromium"},
+	"dbeaver-community":  {Flatpak: "io.dbeaver.DBeaverCommunity", Snap: "dbeaver-ce"},
+	"discord":            {Flatpak: "com.discordapp.Discord", Snap: "discord"},
+	"element":            {Flatpak: "im.riot.Riot", Snap: "element-desktop"},
+	"firefox":            {Flatpak: "org.mozilla.firefox", Snap: "firefox"},
+	"gimp":               {Flatpak: "org.gimp.GIMP", Snap: "gimp"},
+	"google-chrome":      {Flatpak: "com.google.Chrome"},
+	"inkscape":           {Flatpak: "org.inkscape.Inkscape", Snap: "inkscape"},
+	"intellij-idea-ce":   {Flatpak: "com.jetbrains.IntelliJ-IDEA-Community", Snap: "intellij-idea-community", SnapClassic: true},
+	"krita":              {Flatpak: "org.kde.krita", Snap: "krita"},
+	"libreoffice":        {Flatpak: "org.libreoffice.LibreOffice", Snap: "libreoffice"},
+	"obs":                {Flatpak: "com.obsproject.Studio", Snap: "obs-studio"},
+	"obsidian":           {Flatpak: "md.obsidian.Obsidian", Snap: "obsidian", SnapClassic: true},
+	"postman":            {Flatpak: "com.getpostman.Postman", Snap: "postman"},
+	"pycharm-ce":         {Flatpak: "com.jetbrains.PyCharm-Community", Snap: "pycharm-community", SnapClassic: true},
+	"signal":             {Flatpak: "org.signal.Signal", Snap: "signal-desktop"},
+	"slack":              {Flatpak: "com.slack.Slack", Snap: "slack"},
+	"spotify":            {Flatpak: "com.spotify.Client", Snap: "spotify"},
+	"steam":              {Flatpak: "com.valvesoftware.Steam", Snap: "steam"},
+	"sublime-text":       {Flatpak: "com.sublimetext.three", Snap: "sublime-text", SnapClassic: true},
+	"telegram":           {Flatpak: "org.telegram.desktop", Snap: "telegram-desktop"},
+	"thunderbird":        {Flatpak: "org.mozilla.Thunderbird", Snap: "thunderbird"},
+	"visual-studio-code": {Flatpak: "com.visualstudio.code", Snap: "code", SnapClassic: true},
+	"vlc":                {Flatpak: "org.videolan.VLC", Snap: "vlc"},
+	"zoom":               {Flatpak: "us.zoom.Zoom", Snap: "zoom-client"},
+}
+
+// linuxAlternativeOf returns the Linux alternative of a cask, when running on Linux.
+func linuxAlternativeOf(pkg models.Package) (linuxAlternative, bool) {
+	if pkg.Type != models.PackageTypeCask || GetPlatform().OS != "linux" {
+		return linuxAlternative{}, false
+	}
+	alternative, exists := linuxAlternatives[pkg.Name]
+	return alternative, exists
+}
+
+// String describes the alternative for the details, e.g. "Flatpak org.mozilla.firefox, snap firefox".
+func (a linuxAlternative) String() string {
+	var parts []string
+	if a.Flatpak != "" {
+		parts = append(parts, "Flatpak "+a.Flatpak)
+	}
+	if a.Snap != "" {
+		parts = append(parts, "snap "+a.Snap)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// installCommands returns the commands installing the alternative with the tool available on this machine,
+// Flatpak first, and its name for the confirmation. It returns false when neither flatpak nor snap is there.
+// Flatpaks are installed for the user, which needs no password; snaps need sudo, which asks the TUI for it.
+func (a linuxAlternative) installCommands() (string, [][]string, bool) {
+	if _, err := exec.LookPath("flatpak"); err == nil && a.Flatpak != "" {
+		return "Flatpak " + a.Flatpak, [][]string{
+			{"flatpak", "remote-add", "--user", "--if-not-exists", "flathub", flathubRepo},
+			{"flatpak", "install", "--user", "--noninteractive", "-y", "flathub", a.Flatpak},
+		}, true
+	}
+	if _, err := exec.LookPath("snap"); err == nil && a.Snap != "" {
+		install := []string{"sudo", "-A", "snap", "install", a.Snap}
+		if a.SnapClassic {
+			install = append(install, "--classic")
+		}
+		return "snap " + a.Snap, [][]string{install}, true
+	}
+	return "", nil, false
+}
+
+// alternativeCommand returns a command installing a Linux alternative, set up like the brew commands
+// so that sudo asks the TUI for the password.
+func alternativeCommand(args []string) *exec.Cmd {
+	cmd := exec.Command(args[0], args[1:]...) // #nosec G204 -- from the shipped mapping
+	cmd.Env = os.Environ()
+	if env := askpassEnv(); env != nil {
+		cmd.Env = append(cmd.Env, env...)
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	}
+	return cmd
+}
+
+// installLinuxAlternative asks to install the Linux alternative of a cask instead of the cask itself.
+func (s *InputService) installLinuxAlternative(info models.Package, alternative linuxAlternative) {
+	name, commands, ok := alternative.installCommands()
+	if !ok {
+		s.layout.GetNotifier().ShowWarning(i18n.T("%s is a macOS app: install flatpak or snap to get %s", info.Name, alternative))
+		return
+	}
+
+	s.showModal(components.ModalOptions{
+		Text: i18n.T("%s is a macOS app, which Homebrew can't install on Linux. Install the %s instead?",
+			info.Name, name),
+		Cancel: s.closeModal,
+		Confirm: func() {
+			s.closeModal()
+			if !s.appService.beginOperation(i18n.T("installing %s…", name)) {
+				return
+			}
+			s.layout.GetOutput().BeginOperation(i18n.T("installing %s…", name))
+			go func() {
+				defer RecoverCrash()
+				defer s.appService.endOperation()
+				s.layout.GetNotifier().ShowProgress(i18n.T("Installing %s...", name))
+				cmds := make([]*exec.Cmd, len(commands))
+				for i, args := range commands {
+					cmds[i] = alternativeCommand(args)
+				}
+				brewLog.Info("installing a Linux alternative", "cask", info.Name, "alternative", name)
+				err := s.installWithHooks(info, func() error {
+					return s.brewService.RunCommands(cmds, s.appService.app, s.layout.GetOutput().View())
+				})
+				if err != nil {
+					s.layout.GetNotifier().ShowError(i18n.T("Failed to install %s: %v", name, err))
+					s.appService.terminal.Done(i18n.T("Failed to install %s", name))
+				} else {
+					s.layout.GetNotifier().ShowSuccess(i18n.T("Installed %s", name))
+					s.appService.terminal.Done(i18n.T("Installed %s", name))
+				}
+				s.appService.events.Publish(events.Event{
+					Type: events.OperationCompleted, Operation: events.OperationInstall, Package: &info, Err: err,
+				})
+			}()
+		},
+	})
+}
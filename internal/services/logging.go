@@ -0,0 +1,28 @@
+package services
+
+import (
+	"bbrew/internal/logging"
+)
+
+// defaultLogLevel is the log level without --log-level or the log_level setting.
+const defaultLogLevel = "warn"
+
+// Loggers of the services, by component.
+var (
+	appLog      = logging.For("app")
+	brewLog     = logging.For("brew")
+	brewfileLog = logging.For("brewfile")
+)
+
+// SetupLogging starts logging to the state directory, at the given level or else the one of the config.
+// The returned function closes the log file.
+func SetupLogging(level string) (func(), error) {
+	config := LoadConfig()
+	if level == "" {
+		level = config.LogLevel
+	}
+	if level == "" {
+		level = defaultLogLevel
+	}
+	return logging.Setup(getStateDir(), level, config.LogJSON)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// joinMacroSteps joins the steps of a macro for display, e.g. "o → ctrl+u" ("o -> ctrl+u" in ASCII mode).
+func (s *InputService) joinMacroSteps(steps []string) string {
+	return strings.Join(steps, " "+s.appService.theme.Symbols.Arrow+" ")
+}
+
+// macroActions returns an action for each macro of the config, bound to its key, which replays its steps.
+func (s *InputService) macroActions() []*InputAction {
+	keys := make([]string, 0, len(s.appService.config.Macros))
+	for key := range s.appService.config.Macros {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	actions := make([]*InputAction, 0, len(keys))
+	for _, key := range keys {
+		r, size := utf8.DecodeRuneInString(key)
+		if size == 0 || size != len(key) || s.isBoundRune(r) {
+			appLog.Warn("ignoring macro: its key must be a single free character", "key", key)
+			continue
+		}
+		steps := s.appService.config.Macros[key]
+		actions = append(actions, &InputAction{
+			Key: tcell.KeyRune, Rune: r, KeySlug: key, Name: i18n.T("Macro"),
+			Action: func() { s.replayMacro(steps) }, HideFromLegend: true,
+			Category: CategoryActions, Help: i18n.T("Macro: %s", s.joinMacroSteps(steps)),
+		})
+	}
+	return actions
+}
+
+// isBoundRune returns true if a key is taken by an action, including those of Brewfile mode
+// and those disabled by read-only mode.
+func (s *InputService) isBoundRune(r rune) bool {
+	actions := append(slices.Clone(s.keyActions), s.disabledActions...)
+	actions = append(actions, s.ActionInstallAll, s.ActionRemoveAll, s.ActionGroupView, s.ActionInstallGroup,
+		s.ActionInstallTaps, s.ActionRetryFailed, s.ActionBundleCleanup, s.ActionTypeView)
+	for _, action := range actions {
+		if action.Key == tcell.KeyRune && action.Rune == r && !s.isMacroAction(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMacroAction returns true if the action replays a macro.
+func (s *InputService) isMacroAction(action *InputAction) bool {
+	return slices.Contains(s.macros, action)
+}
+
+// setMacroActions replaces the macro actions of the key actions with those of the config.
+func (s *InputService) setMacroActions() {
+	s.keyActions = slices.DeleteFunc(s.keyActions, s.isMacroAction)
+	s.macros = s.macroActions()
+	s.keyActions = append(s.keyActions, s.macros...)
+}
+
+// IsRecordingMacro returns true while the keys pressed are recorded as a macro.
+func (s *InputService) IsRecordingMacro() bool {
+	return s.recordedSteps != nil
+}
+
+// recordStep adds the action of a key pressed in the table to the macro being recorded.
+// The record key itself, the macros and the goto key, whose letter isn't an action, are not recorded.
+func (s *InputService) recordStep(action *InputAction) {
+	if !s.IsRecordingMacro() || action == s.ActionRecordMacro || action == s.ActionGoto || s.isMacroAction(action) {
+		return
+	}
+	s.recordedSteps = append(s.recordedSteps, action.KeySlug)
+}
+
+// handleRecordMacroEvent is called when the user presses the record key (Q).
+// It starts recording the actions run from the table, or stops and asks for the key to bind them to.
+func (s *InputService) handleRecordMacroEvent() {
+	if !s.IsRecordingMacro() {
+		s.recordedSteps = []string{}
+		s.appService.updateHeader()
+		s.layout.GetNotifier().SetStatus(i18n.T("Recording a macro: press Q to stop"))
+		return
+	}
+
+	steps := s.recordedSteps
+	s.recordedSteps = nil
+	s.appService.updateHeader()
+	s.layout.GetNotifier().SetStatus("")
+	if len(steps) == 0 {
+		s.layout.GetNotifier().ShowWarning(i18n.T("Macro canceled: no action recorded"))
+		return
+	}
+
+	promptPages := s.layout.GetPrompt().Build(s.layout.Root(), i18n.T("Bind the macro %s to a key", s.joinMacroSteps(steps)),
+		i18n.T("enter: save | esc: cancel"), "", func(key string) {
+			s.handleBack()
+			if err := s.saveMacro(strings.TrimSpace(key), steps); err != nil {
+				s.layout.GetNotifier().ShowError(i18n.T("Failed to save the macro: %v", err))
+				return
+			}
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Macro saved: press %s to run %s", strings.TrimSpace(key), s.joinMacroSteps(steps)))
+		}, s.handleBack)
+	s.appService.GetApp().SetRoot(promptPages, true)
+}
+
+// saveMacro binds the steps of a macro to a free key, saving it in the config.
+func (s *InputService) saveMacro(key string, steps []string) error {
+	r, size := utf8.DecodeRuneInString(key)
+	if size == 0 || size != len(key) {
+		return fmt.Errorf("the key must be a single character")
+	}
+	if s.isBoundRune(r) {
+		return fmt.Errorf("%s is already the key of an action", key)
+	}
+
+	config := s.appService.config
+	if config.Macros == nil {
+		config.Macros = make(map[string][]string)
+	}
+	config.Macros[key] = steps
+	s.setMacroActions()
+	return config.Save()
+}
+
+// replayMacro runs the actions of the keys of a macro, in order. It stops at the first step leaving the
+// table, e.g. a confirmation, which is usually the last one (Update All), or at a key without an action.
+func (s *InputService) replayMacro(steps []string) {
+	for i, step := range steps {
+		action := s.macroStep(step)
+		if action == nil {
+			s.layout.GetNotifier().ShowError(i18n.T("Macro stopped: no action for the key %s here", step))
+			return
+		}
+		action.Action()
+
+		if i < len(steps)-1 && !s.layout.GetTable().View().HasFocus() {
+			s.layout.GetNotifier().ShowWarning(i18n.T("Macro stopped at %s, which opened a dialog: %s not run",
+				step, s.joinMacroSteps(steps[i+1:])))
+			return
+		}
+	}
+}
+
+// macroStep returns the action of a key of a macro in the current mode, nil if there is none
+// (e.g. a Brewfile key outside Brewfile mode, or a key disabled by read-only mode).
+func (s *InputService) macroStep(slug string) *InputAction {
+	for _, action := range s.keyActions {
+		if action.KeySlug == slug && action.Action != nil && !s.isMacroAction(action) {
+			return action
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"strings"
+)
+
+// Packages are marked with space to install, update or remove several at once. Marks are kept by
+// package, not by row: setResults draws them again on every rebuild of the table, so they survive
+// searches, filters and refreshes.
+
+// toggleMark marks a package, or unmarks it if it was marked.
+func (s *AppService) toggleMark(pkg models.Package) {
+	key := packageKeyOf(pkg)
+	if s.marked[key] {
+		delete(s.marked, key)
+	} else {
+		s.marked[key] = true
+	}
+}
+
+// isMarked returns true if the package is marked.
+func (s *AppService) isMarked(pkg models.Package) bool {
+	return s.marked[packageKeyOf(pkg)]
+}
+
+// markedPackages returns the marked packages, as currently known, sorted by name.
+func (s *AppService) markedPackages() []models.Package {
+	if len(s.marked) == 0 {
+		return nil
+	}
+	var packages []models.Package
+	for _, pkg := range s.store.All() {
+		if s.isMarked(pkg) {
+			packages = append(packages, pkg)
+		}
+	}
+	i18n.SortByName(packages, func(pkg models.Package) string { return pkg.Name })
+	return packages
+}
+
+// clearMarks unmarks every package.
+func (s *AppService) clearMarks() {
+	clear(s.marked)
+	s.layout.GetNotifier().SetStatus("")
+	s.setResults(s.store.Filtered(), false)
+}
+
+// handleMarkEvent is called when the user presses the mark key (space).
+// It marks or unmarks the selected package, then moves to the next row.
+func (s *InputService) handleMarkEvent() {
+	table := s.layout.GetTable().View()
+	row, _ := table.GetSelection()
+	info, exists := s.appService.packageAtRow(row)
+	if !exists {
+		return
+	}
+
+	s.appService.toggleMark(info)
+	s.appService.setResults(s.appService.store.Filtered(), false)
+	if row+1 < table.GetRowCount() {
+		table.Select(row+1, 0)
+	}
+	if count := len(s.appService.marked); count > 0 {
+		s.layout.GetNotifier().SetStatus(i18n.T("%d marked: i, u and r act on them, esc unmarks", count))
+	} else {
+		s.layout.GetNotifier().SetStatus("")
+	}
+}
+
+// runOnMarked asks to confirm, then runs a batch operation on the marked packages, unmarking them.
+// Returns false when no package is marked, for the caller to act on the selected one instead.
+func (s *InputService) runOnMarked(op batchOperation) bool {
+	packages := s.appService.markedPackages()
+	if len(packages) == 0 {
+		return false
+	}
+
+	var names []string
+	for _, pkg := range packages {
+		if skip, _ := op.skip(pkg); !skip {
+			names = append(names, pkg.Name)
+		}
+	}
+	if len(names) == 0 {
+		s.layout.GetNotifier().ShowWarning(i18n.T("No packages to process (%s)", op.skipReason))
+		return true
+	}
+
+	s.showModal(components.ModalOptions{
+		Text:   i18n.T("%s the %d marked packages?\n\n%s", op.actionVerb, len(names), strings.Join(names, ", ")),
+		Cancel: s.closeModal,
+		Confirm: func() {
+			if s.startBatch(op, packages, len(names)) {
+				s.appService.clearMarks()
+			}
+		},
+	})
+	return true
+}
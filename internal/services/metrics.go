@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// upgradeMetrics is the patch state of the machine served by `bbrew upgrade --serve`, as of the last run,
+// in the Prometheus text format.
+type upgradeMetrics struct {
+	mu sync.Mutex
+
+	installed map[string]int // By type: formula, cask
+	outdated  map[string]int
+	run       upgradeRun
+	lastRun   time.Time
+	success   bool
+	runs      int
+	failures  int
+}
+
+// ServeUpgradeMetrics runs the scheduled upgrade now and then every interval, updating Homebrew first,
+// and serves the state of the packages and of the last run on addr (/metrics). It only returns on error.
+func ServeUpgradeMetrics(addr string, interval time.Duration, out io.Writer) error {
+	if interval < time.Hour {
+		return fmt.Errorf("the interval must be at least one hour")
+	}
+	metrics := &upgradeMetrics{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		metrics.write(w)
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	listener, err := net.Listen("tcp", addr) // Before the first run, to fail fast if the address is taken
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer RecoverCrash()
+		for {
+			metrics.update(out)
+			time.Sleep(interval)
+		}
+	}()
+
+	fmt.Fprintf(out, "Serving metrics on http://%s/metrics, upgrading every %s\n", addr, interval)
+	return server.Serve(listener)
+}
+
+// update runs a scheduled upgrade, then counts the installed and still outdated packages.
+// The config is loaded again, to pick up the policies changed meanwhile.
+func (m *upgradeMetrics) update(out io.Writer) {
+	run, err := scheduledUpgrade(LoadConfig(), out)
+	installed, outdated := make(map[string]int), make(map[string]int)
+	if err == nil {
+		err = countInstalledPackages(installed)
+	}
+	if err == nil {
+		var packages []outdatedPackage
+		if packages, err = fetchOutdatedPackages(); err == nil {
+			for _, pkg := range packages {
+				outdated[packageTypeLabel(pkg.isCask)]++
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(out, "Error: %v\n", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runs++
+	m.lastRun = time.Now()
+	m.success = err == nil && len(run.failed) == 0
+	if !m.success {
+		m.failures++
+	}
+	m.run = run
+	if err == nil {
+		m.installed, m.outdated = installed, outdated // Else keep the last known counts
+	}
+}
+
+// countInstalledPackages counts the installed formulae and casks by type.
+func countInstalledPackages(counts map[string]int) error {
+	for _, isCask := range []bool{false, true} {
+		flag := "--formula"
+		if isCask {
+			flag = "--cask"
+		}
+		output, err := brewCommand("list", flag, "-1").Output()
+		if err != nil {
+			return fmt.Errorf("brew list %s failed: %w", flag, err)
+		}
+		counts[packageTypeLabel(isCask)] = len(strings.Fields(string(output)))
+	}
+	return nil
+}
+
+// packageTypeLabel returns the value of the type label of the metrics.
+func packageTypeLabel(isCask bool) string {
+	if isCask {
+		return "cask"
+	}
+	return "formula"
+}
+
+// write writes the metrics in the Prometheus text format. The package counts are left out until known.
+func (m *upgradeMetrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	gauge := func(name, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+	counter := func(name, help string, value int) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, value)
+	}
+
+	gauge("bbrew_info", "Version of Bold Brew.")
+	fmt.Fprintf(w, "bbrew_info{version=%q} 1\n", AppVersion)
+	for _, metric := range []struct {
+		name, help string
+		counts     map[string]int
+	}{
+		{"bbrew_packages_installed", "Installed packages.", m.installed},
+		{"bbrew_packages_outdated", "Outdated packages after the last run, held and pinned ones included.", m.outdated},
+	} {
+		if metric.counts == nil {
+			continue
+		}
+		gauge(metric.name, metric.help)
+		for _, packageType := range []string{"formula", "cask"} {
+			fmt.Fprintf(w, "%s{type=%q} %d\n", metric.name, packageType, metric.counts[packageType])
+		}
+	}
+	if m.runs == 0 {
+		return // Still running the first upgrade
+	}
+
+	gauge("bbrew_last_run_timestamp_seconds", "Time of the end of the last scheduled upgrade.")
+	fmt.Fprintf(w, "bbrew_last_run_timestamp_seconds %d\n", m.lastRun.Unix())
+	gauge("bbrew_last_run_success", "Whether the last scheduled upgrade upgraded every auto package.")
+	success := 0
+	if m.success {
+		success = 1
+	}
+	fmt.Fprintf(w, "bbrew_last_run_success %d\n", success)
+	gauge("bbrew_last_run_upgraded", "Packages upgraded by the last scheduled upgrade.")
+	fmt.Fprintf(w, "bbrew_last_run_upgraded %d\n", len(m.run.upgraded))
+	gauge("bbrew_last_run_failed", "Packages that failed to upgrade in the last scheduled upgrade.")
+	fmt.Fprintf(w, "bbrew_last_run_failed %d\n", len(m.run.failed))
+	gauge("bbrew_last_run_pending", "Outdated packages left to the user by their upgrade policy in the last scheduled upgrade.")
+	fmt.Fprintf(w, "bbrew_last_run_pending %d\n", len(m.run.pending))
+	counter("bbrew_runs_total", "Scheduled upgrades since the start.", m.runs)
+	counter("bbrew_run_failures_total", "Scheduled upgrades that failed since the start.", m.failures)
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+// notesFileName stores the personal notes attached to packages, in the state directory.
+const notesFileName = "notes.json"
+
+// NotesServiceInterface defines the contract for personal package notes.
+type NotesServiceInterface interface {
+	Lookup(pkg models.Package) string
+	Set(pkg models.Package, note string) error
+}
+
+// NotesService keeps free-text notes attached to packages (e.g. "installed for project X").
+// Unlike caches, notes are user data: they live in the XDG state directory.
+type NotesService struct {
+	mu    sync.Mutex
+	notes map[string]string // Keyed by "type/name", e.g. "cask/firefox"
+}
+
+// NewNotesService creates a new instance of NotesService, loading the saved notes.
+var NewNotesService = func() NotesServiceInterface {
+	n := &NotesService{notes: make(map[string]string)}
+	if data := readStateFile(notesFileName); data != nil {
+		_ = json.Unmarshal(data, &n.notes)
+	}
+	return n
+}
+
+// userDataKey returns the key of a package in the notes and favorites files.
+func userDataKey(pkg models.Package) string {
+	return string(pkg.Type) + "/" + pkg.Name
+}
+
+// Lookup returns the note attached to a package, or "" if it has none.
+func (n *NotesService) Lookup(pkg models.Package) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.notes[userDataKey(pkg)]
+}
+
+// Set attaches a note to a package and saves the notes. An empty note removes it.
+func (n *NotesService) Set(pkg models.Package, note string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if note = strings.TrimSpace(note); note == "" {
+		delete(n.notes, userDataKey(pkg))
+	} else {
+		n.notes[userDataKey(pkg)] = note
+	}
+
+	data, err := json.MarshalIndent(n.notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStateFile(notesFileName, data)
+}
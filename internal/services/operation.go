@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+)
+
+// Homebrew locks what a command changes: a brew command started while another one runs fails on the
+// lock. bbrew runs one operation at a time, denying the keys that would start another meanwhile.
+
+// beginOperation marks an operation as running, with its activity shown in the terminal title (e.g.
+// "installing wget…"). When another operation is running, it tells the user and returns false: the
+// caller gives up. Every successful call is followed by endOperation once the operation finishes.
+// It is called from the event loop (key handlers, modal callbacks), which updates the legend right away.
+func (s *AppService) beginOperation(activity string) bool {
+	s.operationMu.Lock()
+	running := s.operation
+	if running == "" {
+		s.operation = activity
+	}
+	s.operationMu.Unlock()
+
+	if running != "" {
+		appLog.Info("operation denied", "running", running, "denied", activity)
+		s.layout.GetNotifier().ShowWarning(i18n.T("Wait for the running operation to finish (%s)", running))
+		return false
+	}
+	s.terminal.SetActivity(activity)
+	s.inputService.UpdateOperationUI()
+	return true
+}
+
+// endOperation marks the running operation as finished, enabling the keys of the others again.
+// The terminal title is reset, for the operations that don't report their end with terminal.Done.
+// It is called from the goroutines of the operations, or the event loop: the reset of the terminal and
+// the legend update are queued without waiting for them, which would never return on the event loop.
+func (s *AppService) endOperation() {
+	s.operationMu.Lock()
+	s.operation = ""
+	s.operationMu.Unlock()
+	s.terminal.ClearActivity()
+	go s.app.QueueUpdateDraw(s.inputService.UpdateOperationUI)
+}
+
+// runningOperation returns the activity of the running operation, "" when none is.
+func (s *AppService) runningOperation() string {
+	s.operationMu.Lock()
+	defer s.operationMu.Unlock()
+	return s.operation
+}
+
+// brewActions returns the actions that run brew commands changing packages, one at a time.
+func (s *InputService) brewActions() map[*InputAction]bool {
+	return map[*InputAction]bool{
+		s.ActionInstall: true, s.ActionUpdate: true, s.ActionRemove: true, s.ActionRestore: true, s.ActionAdopt: true,
+		s.ActionMigrate: true, s.ActionTerminal: true, s.ActionUpdateAll: true, s.ActionBrewUpdate: true,
+		s.ActionInstallAll: true, s.ActionRemoveAll: true, s.ActionInstallGroup: true, s.ActionInstallTaps: true,
+		s.ActionRetryFailed: true, s.ActionBundleCleanup: true,
+	}
+}
+
+// UpdateOperationUI dims the keys of the brew actions in the legend while an operation runs.
+func (s *InputService) UpdateOperationUI() {
+	var disabled []string
+	if s.appService.runningOperation() != "" {
+		for action := range s.brewActions() {
+			disabled = append(disabled, action.KeySlug)
+		}
+	}
+	s.layout.GetLegend().SetDisabled(disabled)
+}
+
+// denyDuringOperation returns true, telling the user why, when the action runs brew while another
+// operation is running.
+func (s *InputService) denyDuringOperation(action *InputAction) bool {
+	running := s.appService.runningOperation()
+	if running == "" || !s.brewActions()[action] {
+		return false
+	}
+	s.layout.GetNotifier().ShowWarning(i18n.T("%s is disabled until the running operation finishes (%s)", action.Name, running))
+	return true
+}
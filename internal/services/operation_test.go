@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"bbrew/internal/ui"
+	"bbrew/internal/ui/theme"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// runOnEventLoop runs fn as a queued update, failing the test when the event loop doesn't get through it.
+func runOnEventLoop(t *testing.T, app *tview.Application, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go app.QueueUpdate(func() {
+		fn()
+		close(done)
+	})
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event loop is blocked")
+	}
+}
+
+// newTestAppService returns an AppService running on a simulation screen, with the terminal
+// notifying through protocol.
+func newTestAppService(protocol notificationProtocol) (*AppService, *tview.Application) {
+	app := tview.NewApplication().SetScreen(tcell.NewSimulationScreen(""))
+	s := &AppService{app: app, layout: ui.NewLayout(theme.NewTheme(theme.Options{})), config: &Config{}}
+	s.inputService = NewInputService(s, nil)
+	terminal := NewTerminalService(app).(*TerminalService)
+	terminal.protocol = protocol
+	s.terminal = terminal
+	return s, app
+}
+
+func TestOperationFromEventLoop(t *testing.T) {
+	s, app := newTestAppService(notifyNone)
+	go app.Run()
+	defer app.Stop()
+
+	var began, denied bool
+	runOnEventLoop(t, app, func() {
+		began = s.beginOperation("installing wget…")
+		denied = !s.beginOperation("installing curl…")
+	})
+	if !began || !denied {
+		t.Fatalf("beginOperation: began = %v, denied = %v, want both", began, denied)
+	}
+	if got := s.runningOperation(); got != "installing wget…" {
+		t.Fatalf("runningOperation() = %q, want %q", got, "installing wget…")
+	}
+
+	runOnEventLoop(t, app, s.endOperation)
+	if got := s.runningOperation(); got != "" {
+		t.Fatalf("runningOperation() = %q after endOperation, want none", got)
+	}
+	// The legend update queued by endOperation is run by the event loop
+	runOnEventLoop(t, app, func() {})
+}
+
+func TestEndOperationFromEventLoopWithOSC9(t *testing.T) {
+	s, app := newTestAppService(notifyOSC9)
+	go app.Run()
+	defer app.Stop()
+
+	runOnEventLoop(t, app, func() {
+		if !s.beginOperation("installing wget…") {
+			t.Error("beginOperation denied with no running operation")
+		}
+		s.endOperation() // Resets the progress with OSC 9;4
+	})
+	waitFlushed(t, s.terminal.(*TerminalService))
+}
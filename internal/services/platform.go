@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/version"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Platform describes the host, as needed to resolve bottle availability.
+type Platform struct {
+	OS           string // "darwin" or "linux"
+	Arch         string // "arm64" or "x86_64"
+	MacOSVersion string // e.g. "14.5", empty on Linux
+	Codename     string // e.g. "sonoma", empty on Linux
+	Rosetta      bool   // Running as x86_64 under Rosetta 2 on Apple Silicon
+}
+
+var (
+	hostPlatform     Platform
+	hostPlatformOnce sync.Once
+)
+
+// GetPlatform returns the host platform, detecting it on first use.
+func GetPlatform() Platform {
+	hostPlatformOnce.Do(func() {
+		hostPlatform = Platform{OS: runtime.GOOS, Arch: "x86_64"}
+		if runtime.GOARCH == "arm64" {
+			hostPlatform.Arch = "arm64"
+		}
+
+		if hostPlatform.OS == "darwin" {
+			if output, err := exec.Command("sw_vers", "-productVersion").Output(); err == nil {
+				hostPlatform.MacOSVersion = strings.TrimSpace(string(output))
+				hostPlatform.Codename = models.MacOSCodename(hostPlatform.MacOSVersion)
+			}
+			// An Intel Homebrew under Rosetta pours x86_64 bottles, even on Apple Silicon
+			if output, err := exec.Command("sysctl", "-n", "sysctl.proc_translated").Output(); err == nil {
+				hostPlatform.Rosetta = strings.TrimSpace(string(output)) == "1"
+			}
+		}
+	})
+	return hostPlatform
+}
+
+// ArchLabel returns a human-readable description of the host architecture.
+func (p Platform) ArchLabel() string {
+	switch {
+	case p.OS == "darwin" && p.Arch == "arm64":
+		return "Apple Silicon"
+	case p.OS == "darwin" && p.Rosetta:
+		return "Intel (Rosetta)"
+	case p.OS == "darwin":
+		return "Intel"
+	}
+	return "Linux " + p.Arch
+}
+
+// BottleTag returns the bottle tag of the host (e.g. "arm64_sonoma", "x86_64_linux").
+func (p Platform) BottleTag() string {
+	if p.OS == "linux" {
+		return p.Arch + "_linux"
+	}
+	if p.Arch == "arm64" {
+		return "arm64_" + p.Codename
+	}
+	return p.Codename
+}
+
+// BottleTags returns the bottle tags usable on the host, in order of preference.
+// Like Homebrew, macOS hosts can pour bottles built for older macOS versions of the same arch.
+func (p Platform) BottleTags() []string {
+	if p.OS == "linux" {
+		return []string{p.BottleTag(), "all"}
+	}
+
+	tags := make([]string, 0, len(models.MacOSReleases)+1)
+	found := p.Codename == ""
+	for _, release := range models.MacOSReleases {
+		if release.Codename == p.Codename {
+			found = true
+		}
+		if !found {
+			continue
+		}
+		if p.Arch == "arm64" {
+			if version.Compare(release.Version, "11") < 0 {
+				continue // No arm64 bottles before Big Sur
+			}
+			tags = append(tags, "arm64_"+release.Codename)
+		} else {
+			tags = append(tags, release.Codename)
+		}
+	}
+	return append(tags, "all")
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// startupStage is a timed step of the startup, e.g. a cache read or an API fetch.
+type startupStage struct {
+	Name     string
+	Duration time.Duration
+}
+
+// startupProfiler times the startup stages for --profile-startup, until the first draw.
+// Stages of background work running meanwhile are recorded too, so the breakdown may exceed the total.
+type startupProfiler struct {
+	mu      sync.Mutex
+	enabled bool
+	done    bool
+	start   time.Time
+	total   time.Duration
+	stages  []startupStage
+}
+
+var startup startupProfiler
+
+// EnableStartupProfile starts timing the startup stages. Call it as early as possible, the total starts there.
+func EnableStartupProfile() {
+	startup.mu.Lock()
+	defer startup.mu.Unlock()
+	startup.enabled, startup.start = true, time.Now()
+}
+
+// begin starts timing a stage, recorded the first time the returned function is called: defer startup.begin("...")().
+func (p *startupProfiler) begin(name string) func() {
+	p.mu.Lock()
+	recording := p.enabled && !p.done
+	p.mu.Unlock()
+	if !recording {
+		return func() {}
+	}
+
+	started := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() { p.record(startupStage{Name: name, Duration: time.Since(started)}) })
+	}
+}
+
+// record adds a finished stage, unless the startup is over.
+func (p *startupProfiler) record(stage startupStage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.done {
+		p.stages = append(p.stages, stage)
+		appLog.Info("startup stage", "stage", stage.Name, "duration", stage.Duration)
+	}
+}
+
+// finish stops recording once the first frame is drawn, logging the total.
+func (p *startupProfiler) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled || p.done {
+		return
+	}
+	p.done, p.total = true, time.Since(p.start)
+	appLog.Info("startup finished", "duration", p.total, "stages", len(p.stages))
+}
+
+// StartupProfile returns the breakdown of the startup stages, "" if profiling is not enabled.
+func StartupProfile() string {
+	startup.mu.Lock()
+	defer startup.mu.Unlock()
+	if !startup.enabled {
+		return ""
+	}
+
+	width := len("Total")
+	for _, stage := range startup.stages {
+		width = max(width, len(stage.Name))
+	}
+	var b strings.Builder
+	b.WriteString("Startup profile:\n")
+	for _, stage := range startup.stages {
+		fmt.Fprintf(&b, "  %-*s  %10s\n", width, stage.Name, stage.Duration.Round(time.Microsecond))
+	}
+	if startup.done {
+		fmt.Fprintf(&b, "  %-*s  %10s\n", width, "Total", startup.total.Round(time.Microsecond))
+	}
+	return b.String()
+}
+
+// decodeJSON decodes JSON data of a cache file or an API response, timed as a startup stage.
+func decodeJSON(source string, data []byte, v any) error {
+	defer startup.begin("decode " + source)()
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Profile is a named package set of the config, e.g. "work" or "gamedev", backed by a Brewfile.
+// Opening it shows the Brewfile, as with -f, to apply it (Install All) or compare it (d in the picker).
+type Profile struct {
+	Brewfile string `json:"brewfile"`         // Path or HTTPS URL of the Brewfile
+	Filter   string `json:"filter,omitempty"` // Filter activated with the profile, as --filter
+	Group    string `json:"group,omitempty"`  // Section or tag of the Brewfile to restrict to, as --only-group
+}
+
+// profilesDir is where the profiles saved from a selection keep their Brewfile.
+func profilesDir() string {
+	return filepath.Join(getConfigDir(), "profiles")
+}
+
+// ProfileNames returns the names of the profiles of the config, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	i18n.SortByName(names, func(name string) string { return name })
+	return names
+}
+
+// LookupProfile returns a profile of the config by name, validating its filter.
+func (c *Config) LookupProfile(name string) (Profile, error) {
+	profile, exists := c.Profiles[name]
+	if !exists {
+		if len(c.Profiles) == 0 {
+			return Profile{}, fmt.Errorf("unknown profile %q: no profiles in the config", name)
+		}
+		return Profile{}, fmt.Errorf("unknown profile %q (profiles: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	if profile.Brewfile == "" {
+		return Profile{}, fmt.Errorf("profile %q has no brewfile", name)
+	}
+	if profile.Filter != "" {
+		if _, err := ParseFilterType(profile.Filter); err != nil {
+			return Profile{}, fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	profile.Brewfile = expandHome(profile.Brewfile)
+	return profile, nil
+}
+
+// LoadProfile returns a profile of the user config by name, for --profile.
+func LoadProfile(name string) (Profile, error) {
+	return LoadConfig().LookupProfile(name)
+}
+
+// openProfile switches to a profile: its Brewfile, section and filter, in Brewfile mode.
+// The current Brewfile stays open if the new one can't be loaded. Runs in the event loop.
+func (s *AppService) openProfile(name string) error {
+	profile, err := s.config.LookupProfile(name)
+	if err != nil {
+		return err
+	}
+	filter := FilterNone
+	if profile.Filter != "" {
+		filter, _ = ParseFilterType(profile.Filter) // Validated by LookupProfile
+	}
+	path, cleanup, err := ResolveBrewfilePath(profile.Brewfile)
+	if err != nil {
+		return err
+	}
+
+	wasBrewfileMode := s.IsBrewfileMode()
+	previousPath, previousOptions := s.brewfilePath, s.startupOptions
+	s.brewfilePath = path
+	s.startupOptions.Brewfile, s.startupOptions.OnlyGroup, s.startupOptions.Profile = profile.Brewfile, profile.Group, name
+	if err := s.loadBrewfilePackages(); err != nil {
+		cleanup()
+		s.brewfilePath, s.startupOptions = previousPath, previousOptions
+		if wasBrewfileMode {
+			_ = s.loadBrewfilePackages() // Back to the taps and sections of the previous Brewfile
+		}
+		return err
+	}
+	if s.profileCleanup != nil {
+		s.profileCleanup() // Remove the previous remote Brewfile
+	}
+	s.profileCleanup = cleanup
+
+	if !wasBrewfileMode {
+		s.layout.GetSearch().Field().SetLabel(i18n.T("Search (Brewfile): "))
+		s.inputService.EnableBrewfileMode()
+	}
+	s.updateHeader()
+	s.activeFilter = filter
+	s.events.Publish(events.Event{Type: events.FilterChanged})
+	s.notifyBrewfileRenames()
+
+	// Like at startup: the taps of the profile may be missing. While another operation runs, they are left
+	// to the install taps key (T), as tapping would fail on the lock of Homebrew.
+	if len(s.brewfileTaps) > 0 && !s.IsReadOnly() && !s.brewMissing && s.beginOperation(i18n.T("installing taps…")) {
+		go func() {
+			defer RecoverCrash()
+			defer s.endOperation()
+			if s.installMissingTaps() == 0 {
+				return
+			}
+			s.fetchTapPackages()
+			if err := s.loadBrewfilePackages(); err != nil {
+				brewfileLog.Warn("failed to reload the Brewfile", "path", s.brewfilePath, "err", err)
+			}
+			s.events.Publish(events.Event{Type: events.PackagesUpdated})
+		}()
+	}
+	return nil
+}
+
+// saveProfile saves packages as a new profile: a Brewfile in the profiles directory of the config.
+// Returns the path of the Brewfile.
+func (s *AppService) saveProfile(name string, packages []models.Package) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid profile name %q", name)
+	}
+	if _, exists := s.config.Profiles[name]; exists {
+		return "", fmt.Errorf("profile %q already exists", name)
+	}
+	if err := os.MkdirAll(profilesDir(), 0750); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(profilesDir(), name+".Brewfile")
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) { // Left over by a profile removed from the config
+		return "", err
+	}
+	if _, err := appendToBrewfile(path, packages); err != nil {
+		return "", err
+	}
+	if s.config.Profiles == nil {
+		s.config.Profiles = make(map[string]Profile)
+	}
+	s.config.Profiles[name] = Profile{Brewfile: path}
+	return path, s.config.Save()
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The reverse lookup of a command ("which package provides convert?") reads the links of the installed packages
+// in the bin directories of the prefix, then the index of the executables of all formulae that Homebrew publishes
+// for `brew which-formula`.
+
+const (
+	executablesAPIURL    = "https://formulae.brew.sh/api/internal/executables.txt"
+	cacheFileExecutables = "executables.txt"
+
+	// executablesTTL is how long the cached index of executables stays valid.
+	executablesTTL = 7 * 24 * time.Hour
+)
+
+// commandProvider is a package providing a command.
+type commandProvider struct {
+	Name string
+	Type models.PackageType
+}
+
+// installedCommandProviders returns the installed packages linking the command in the bin or sbin directory
+// of the prefix, from the target of the link: a keg of the Cellar, the Caskroom, or else the app of a cask
+// whose binaries include the command.
+func installedCommandProviders(command, prefix string, installedCasks []models.Package) []commandProvider {
+	var providers []commandProvider
+	for _, dir := range []string{"bin", "sbin"} {
+		target := resolvePath(filepath.Join(prefix, dir, command))
+		if _, err := os.Stat(target); err != nil {
+			continue
+		}
+
+		switch {
+		case strings.Contains(target, "/Cellar/"):
+			_, rest, _ := strings.Cut(target, "/Cellar/")
+			name, _, _ := strings.Cut(rest, "/")
+			providers = append(providers, commandProvider{Name: name, Type: models.PackageTypeFormula})
+		case strings.Contains(target, "/Caskroom/"):
+			_, rest, _ := strings.Cut(target, "/Caskroom/")
+			token, _, _ := strings.Cut(rest, "/")
+			providers = append(providers, commandProvider{Name: token, Type: models.PackageTypeCask})
+		default:
+			for _, pkg := range installedCasks {
+				if pkg.Cask != nil && slices.Contains(pkg.Cask.Binaries(), command) {
+					providers = append(providers, commandProvider{Name: pkg.Name, Type: models.PackageTypeCask})
+					break
+				}
+			}
+		}
+	}
+	return providers
+}
+
+// knownCommandProviders returns the formulae providing the command, from the index of executables,
+// cached for executablesTTL.
+func knownCommandProviders(command string) ([]string, error) {
+	if err := ensureCacheDir(); err != nil {
+		return nil, err
+	}
+
+	data := readCacheFile(cacheFileExecutables, 100)
+	if info, err := os.Stat(filepath.Join(getCacheDir(), cacheFileExecutables)); err != nil || time.Since(info.ModTime()) > executablesTTL {
+		if body, err := fetchFromAPI(executablesAPIURL); err == nil && len(body) > 100 {
+			data = body
+			writeCacheFile(cacheFileExecutables, body)
+		} else if data == nil {
+			return nil, err // Not cached and not available
+		}
+	}
+	return parseExecutables(data, command), nil
+}
+
+// parseExecutables returns the formulae providing the command in the index of executables,
+// with a line per formula: "name(version):command command...".
+func parseExecutables(data []byte, command string) []string {
+	var formulae []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // Some formulae have thousands of executables
+	for scanner.Scan() {
+		formula, commands, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		if slices.Contains(strings.Fields(commands), command) {
+			name, _, _ := strings.Cut(formula, "(")
+			formulae = append(formulae, name)
+		}
+	}
+	return formulae
+}
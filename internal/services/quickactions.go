@@ -0,0 +1,44 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"slices"
+)
+
+// quickActions returns the keys that apply to a package in its current state, for the action bar of the
+// details: install when it isn't installed, update when it is outdated, remove when it is installed.
+// Actions unavailable in the current mode (read-only, Homebrew missing) are left out.
+func (s *InputService) quickActions(pkg *models.Package) []components.DetailsAction {
+	var actions []components.DetailsAction
+	add := func(action *InputAction, label string) {
+		if slices.Contains(s.keyActions, action) {
+			actions = append(actions, components.DetailsAction{Key: action.KeySlug, Label: label})
+		}
+	}
+
+	switch {
+	case !pkg.LocallyInstalled && len(existingCaskApps(*pkg)) > 0:
+		add(s.ActionAdopt, s.ActionAdopt.Name)
+	case !pkg.LocallyInstalled:
+		add(s.ActionInstall, s.ActionInstall.Name)
+	case pkg.Outdated && !s.appService.isHeld(*pkg):
+		add(s.ActionUpdate, s.ActionUpdate.Name)
+	}
+	if pkg.LocallyInstalled {
+		add(s.ActionRemove, s.ActionRemove.Name)
+	}
+
+	if s.appService.isMarked(*pkg) {
+		add(s.ActionMark, i18n.T("Unmark"))
+	} else {
+		add(s.ActionMark, s.ActionMark.Name)
+	}
+	if s.appService.favorites.IsFavorite(*pkg) {
+		add(s.ActionStar, i18n.T("Unstar"))
+	} else {
+		add(s.ActionStar, s.ActionStar.Name)
+	}
+	return actions
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// removedFileName stores the packages recently removed with bbrew, in the state directory.
+const removedFileName = "removed.json"
+
+// maxRemoved is how many removals are kept, the oldest are forgotten first.
+const maxRemoved = 50
+
+// RemovedPackage is a package removed with bbrew, with what it takes to reinstall it.
+type RemovedPackage struct {
+	Name      string    `json:"name"`
+	FullName  string    `json:"full_name,omitempty"`
+	Cask      bool      `json:"cask,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Options   []string  `json:"options,omitempty"` // Install options of formulae, e.g. --with-foo
+	RemovedAt time.Time `json:"removed_at"`
+}
+
+// Package returns the package to reinstall, without its formula or cask.
+func (r RemovedPackage) Package() models.Package {
+	pkg := models.Package{Name: r.Name, FullName: r.FullName, Type: models.PackageTypeFormula}
+	if r.Cask {
+		pkg.Type = models.PackageTypeCask
+	}
+	return pkg
+}
+
+// RemovedServiceInterface defines the contract for the recently removed packages.
+type RemovedServiceInterface interface {
+	Record(pkg models.Package) error
+	List() []RemovedPackage
+	Forget(name string, cask bool) error
+}
+
+// RemovedService keeps the packages removed with bbrew, newest first, to reinstall them:
+// a safety net for brew, which has no undo.
+type RemovedService struct {
+	mu      sync.Mutex
+	removed []RemovedPackage
+}
+
+// NewRemovedService creates a new instance of RemovedService, loading the saved removals.
+var NewRemovedService = func() RemovedServiceInterface {
+	r := &RemovedService{}
+	if data := readStateFile(removedFileName); data != nil {
+		_ = json.Unmarshal(data, &r.removed)
+	}
+	return r
+}
+
+// Record adds a package that was just removed, replacing an older removal of the same package.
+func (r *RemovedService) Record(pkg models.Package) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := RemovedPackage{
+		Name:      pkg.Name,
+		FullName:  pkg.FullName,
+		Cask:      pkg.Type == models.PackageTypeCask,
+		Version:   pkg.InstalledVersion(),
+		RemovedAt: time.Now(),
+	}
+	if pkg.Formula != nil && len(pkg.Formula.Installed) > 0 {
+		for _, option := range pkg.Formula.Installed[0].UsedOptions {
+			entry.Options = append(entry.Options, fmt.Sprint(option))
+		}
+	}
+
+	r.forget(entry.Name, entry.Cask)
+	r.removed = append([]RemovedPackage{entry}, r.removed...)
+	if len(r.removed) > maxRemoved {
+		r.removed = r.removed[:maxRemoved]
+	}
+	return r.save()
+}
+
+// List returns the removed packages, newest first.
+func (r *RemovedService) List() []RemovedPackage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RemovedPackage(nil), r.removed...)
+}
+
+// Forget drops a package from the removals, once reinstalled.
+func (r *RemovedService) Forget(name string, cask bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.forget(name, cask)
+	return r.save()
+}
+
+// forget drops a package from the removals. Callers hold the lock.
+func (r *RemovedService) forget(name string, cask bool) {
+	kept := r.removed[:0]
+	for _, entry := range r.removed {
+		if entry.Name != name || entry.Cask != cask {
+			kept = append(kept, entry)
+		}
+	}
+	r.removed = kept
+}
+
+// save writes the removals to the state directory. Callers hold the lock.
+func (r *RemovedService) save() error {
+	data, err := json.MarshalIndent(r.removed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStateFile(removedFileName, data)
+}
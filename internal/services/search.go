@@ -1,8 +1,11 @@
 package services
 
 import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
 	"bbrew/internal/models"
-	"fmt"
+	"bbrew/internal/ui/components"
+	"slices"
 	"sort"
 	"strings"
 
@@ -17,9 +20,9 @@ func (s *AppService) search(searchText string, scrollToTop bool) {
 
 	// Determine the source list based on the current filter state
 	// If Brewfile mode is active, use brewfilePackages as the base source
-	sourceList := s.packages
+	sourceList := s.store.All()
 	if s.IsBrewfileMode() {
-		sourceList = s.brewfilePackages
+		sourceList = s.store.Brewfile()
 	}
 
 	// Apply active filter on the source list
@@ -27,13 +30,14 @@ func (s *AppService) search(searchText string, scrollToTop bool) {
 
 	if searchText == "" {
 		// Reset to the appropriate list when the search string is empty
-		filteredList = *sourceList
+		filteredList = sourceList
 	} else {
 		// Apply the search filter
 		searchTextLower := strings.ToLower(searchText)
-		for _, info := range *sourceList {
+		for _, info := range sourceList {
 			if strings.Contains(strings.ToLower(info.Name), searchTextLower) ||
-				strings.Contains(strings.ToLower(info.Description), searchTextLower) {
+				strings.Contains(strings.ToLower(info.Description), searchTextLower) ||
+				strings.Contains(strings.ToLower(s.notes.Lookup(info)), searchTextLower) {
 				if !uniquePackages[info.Name] {
 					filteredList = append(filteredList, info)
 					uniquePackages[info.Name] = true
@@ -42,7 +46,7 @@ func (s *AppService) search(searchText string, scrollToTop bool) {
 		}
 
 		// sort by analytics rank
-		sort.Slice(filteredList, func(i, j int) bool {
+		sort.SliceStable(filteredList, func(i, j int) bool { // Same rank (unranked): keep the name order
 			if filteredList[i].Analytics90dRank == 0 {
 				return false
 			}
@@ -53,20 +57,52 @@ func (s *AppService) search(searchText string, scrollToTop bool) {
 		})
 	}
 
-	*s.filteredPackages = filteredList
-	s.setResults(s.filteredPackages, scrollToTop)
+	s.store.SetFiltered(filteredList)
+	s.setResults(filteredList, scrollToTop)
 }
 
 // applyFilter filters packages based on the active filter type.
-func (s *AppService) applyFilter(sourceList *[]models.Package) *[]models.Package {
-	if s.activeFilter == FilterNone {
+func (s *AppService) applyFilter(sourceList []models.Package) []models.Package {
+	return s.filterPackages(sourceList, s.activeFilter)
+}
+
+// filterLabel returns the name of the active filter, shown by the search field and the counter,
+// "" when no filter is active.
+func (s *AppService) filterLabel() string {
+	switch s.activeFilter {
+	case FilterInstalled:
+		return i18n.T("Installed")
+	case FilterOutdated:
+		return i18n.T("Outdated")
+	case FilterLeaves:
+		return i18n.T("Leaves")
+	case FilterCasks:
+		return i18n.T("Casks")
+	case FilterNoBottle:
+		return i18n.T("Source Builds")
+	case FilterMaintained:
+		return i18n.T("Maintained")
+	case FilterVulnerable:
+		return i18n.T("Vulnerable")
+	case FilterFavorites:
+		return i18n.T("Favorites")
+	case FilterLicense:
+		return i18n.T("License: %s", s.licenseFilter)
+	}
+	return ""
+}
+
+// filterPackages returns the packages matching a filter type.
+func (s *AppService) filterPackages(sourceList []models.Package, filter FilterType) []models.Package {
+	if filter == FilterNone {
 		return sourceList
 	}
 
-	filteredSource := &[]models.Package{}
-	for _, info := range *sourceList {
+	bottleTags := GetPlatform().BottleTags()
+	filteredSource := []models.Package{}
+	for _, info := range sourceList {
 		include := false
-		switch s.activeFilter {
+		switch filter {
 		case FilterInstalled:
 			include = info.LocallyInstalled
 		case FilterOutdated:
@@ -75,102 +111,136 @@ func (s *AppService) applyFilter(sourceList *[]models.Package) *[]models.Package
 			include = info.LocallyInstalled && info.InstalledOnRequest
 		case FilterCasks:
 			include = info.Type == models.PackageTypeCask
+		case FilterNoBottle:
+			include = info.Formula != nil && info.Formula.BottleTag(bottleTags) == ""
+		case FilterMaintained:
+			meta := s.github.Lookup(info)
+			include = meta != nil && meta.RecentlyMaintained()
+		case FilterVulnerable:
+			include = info.LocallyInstalled && len(s.advisories.Lookup(info)) > 0
+		case FilterFavorites:
+			include = s.favorites.IsFavorite(info)
+		case FilterLicense:
+			include = info.LocallyInstalled && packageLicense(info) == s.licenseFilter
 		}
 		if include {
-			*filteredSource = append(*filteredSource, info)
+			filteredSource = append(filteredSource, info)
 		}
 	}
 	return filteredSource
 }
 
-// forceRefreshResults forces a refresh of the Homebrew formulae and cask data and updates the results in the UI.
+// forceRefreshResults forces a refresh of the Homebrew formulae and cask data,
+// then publishes PackagesUpdated so that the UI redraws the results.
 func (s *AppService) forceRefreshResults() {
 	// Force refresh all data to get up-to-date versions and installed status
-	_ = s.dataProvider.SetupData(true)
-	s.packages = s.dataProvider.GetPackages()
+	if err := s.dataProvider.SetupData(true); err != nil {
+		appLog.Warn("failed to refresh Homebrew data", "err", err)
+	}
+	s.store.SetAll(*s.dataProvider.GetPackages())
 
 	// If in Brewfile mode, load tap packages and verify installed status
 	if s.IsBrewfileMode() {
 		s.fetchTapPackages()
-		_ = s.loadBrewfilePackages() // Gets fresh installed status via FetchInstalledCaskNames/FormulaNames
-		*s.filteredPackages = *s.brewfilePackages
+		// Gets fresh installed status via FetchInstalledCaskNames/FormulaNames
+		if err := s.loadBrewfilePackages(); err != nil {
+			brewfileLog.Warn("failed to reload the Brewfile", "path", s.brewfilePath, "err", err)
+		}
+		s.store.SetFiltered(s.store.Brewfile())
 	} else {
 		// For non-Brewfile mode, get fresh installed status
 		installedCasks := s.dataProvider.FetchInstalledCaskNames()
 		installedFormulae := s.dataProvider.FetchInstalledFormulaNames()
-		for i := range *s.packages {
-			pkg := &(*s.packages)[i]
+		s.store.UpdateAll(func(pkg *models.Package) {
 			if pkg.Type == models.PackageTypeCask {
-				pkg.LocallyInstalled = installedCasks[pkg.Name]
+				pkg.LocallyInstalled = isInstalledAs(*pkg, installedCasks)
 			} else {
-				pkg.LocallyInstalled = installedFormulae[pkg.Name]
+				pkg.LocallyInstalled = isInstalledAs(*pkg, installedFormulae)
 			}
-		}
-		*s.filteredPackages = *s.packages
+		})
+		s.store.SetFiltered(s.store.All())
 	}
 
-	s.app.QueueUpdateDraw(func() {
-		s.search(s.layout.GetSearch().Field().GetText(), false)
-	})
+	s.events.Publish(events.Event{Type: events.PackagesUpdated})
 }
 
 // setResults updates the results table with the provided data and optionally scrolls to the top.
-func (s *AppService) setResults(data *[]models.Package, scrollToTop bool) {
-	s.layout.GetTable().Clear()
-	s.layout.GetTable().SetTableHeaders("Type", "Name", "Version", "Description", "Downloads")
+// The rendered columns are driven by the configured column specs (see columns.go).
+func (s *AppService) setResults(data []models.Package, scrollToTop bool) {
+	columns := s.visibleColumns()
+	s.sizeColumn = slices.IndexFunc(columns, func(col *columnSpec) bool { return col.id == ColumnSize })
 
-	for i, info := range *data {
-		// Type cell with escaped brackets
-		typeTag := tview.Escape("[F]") // Formula
-		if info.Type == models.PackageTypeCask {
-			typeTag = tview.Escape("[C]") // Cask
+	s.layout.GetTable().Clear()
+	s.highlight = s.layout.GetSearch().Field().GetText()
+	s.layout.GetTable().SetHighlight(s.highlight)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = i18n.T(col.header)
+	}
+	s.layout.GetTable().SetTableHeaders(headers...)
+
+	s.tableRows = s.spaceRows(s.buildTableRows(data))
+	macOSVersion := GetPlatform().MacOSVersion
+	firstPackageRow := 0
+	for i, row := range s.tableRows {
+		if row.spacer {
+			s.layout.GetTable().View().SetCell(i+1, 0, tview.NewTableCell("").SetSelectable(false))
+			continue
 		}
-		typeCell := tview.NewTableCell(typeTag).SetSelectable(true).SetAlign(tview.AlignLeft)
-
-		// Version handling - truncate if too long
-		version := info.Version
-		const maxVersionLen = 15
-		if len(version) > maxVersionLen {
-			version = version[:maxVersionLen-1] + "…"
+		if row.index < 0 {
+			s.layout.GetTable().View().SetCell(i+1, 0, s.renderGroupHeader(row).SetSelectable(true))
+			continue
 		}
-
-		// Name cell
-		nameCell := tview.NewTableCell(info.Name).SetSelectable(true)
-		if info.LocallyInstalled {
-			nameCell.SetTextColor(tcell.ColorGreen)
+		if firstPackageRow == 0 {
+			firstPackageRow = i + 1
 		}
 
-		// Version cell
-		versionCell := tview.NewTableCell(version).SetSelectable(true)
-		if info.LocallyInstalled && info.Outdated {
-			versionCell.SetTextColor(tcell.ColorOrange)
+		// Grey out casks that can't be installed on this macOS version
+		info := data[row.index]
+		unavailable := info.Cask != nil && !info.Cask.SupportsMacOS(macOSVersion)
+		marked := s.isMarked(info)
+		for j, col := range columns {
+			cell := col.render(s, info).SetSelectable(true).SetExpansion(col.expansion)
+			if unavailable {
+				cell.SetTextColor(s.theme.UnavailableColor)
+			}
+			if marked {
+				cell.SetAttributes(tcell.AttrBold)
+			}
+			if col.id == ColumnDescription {
+				s.layout.GetTable().SetFittedCell(i+1, j, cell, info.Description)
+				continue
+			}
+			s.layout.GetTable().View().SetCell(i+1, j, cell)
 		}
-
-		// Downloads cell
-		downloadsCell := tview.NewTableCell(fmt.Sprintf("%d", info.Analytics90dDownloads)).SetSelectable(true).SetAlign(tview.AlignRight)
-
-		// Set cells with new column order: Type, Name, Version, Description, Downloads
-		s.layout.GetTable().View().SetCell(i+1, 0, typeCell.SetExpansion(0))
-		s.layout.GetTable().View().SetCell(i+1, 1, nameCell.SetExpansion(0))
-		s.layout.GetTable().View().SetCell(i+1, 2, versionCell.SetExpansion(0))
-		s.layout.GetTable().View().SetCell(i+1, 3, tview.NewTableCell(info.Description).SetSelectable(true).SetExpansion(1))
-		s.layout.GetTable().View().SetCell(i+1, 4, downloadsCell.SetExpansion(0))
 	}
 
-	// Update the details view with the first item in the list
-	if len(*data) > 0 && scrollToTop {
-		s.layout.GetTable().View().Select(1, 0)
+	// Update the details view with the first package in the list
+	if firstPackageRow > 0 && scrollToTop {
+		s.layout.GetTable().View().Select(firstPackageRow, 0)
 		s.layout.GetTable().View().ScrollToBeginning()
-		s.layout.GetDetails().SetContent(&(*data)[0])
-	} else if len(*data) == 0 {
+		info := data[s.tableRows[firstPackageRow-1].index]
+		s.layout.GetDetails().SetContent(&info)
+	} else if len(data) == 0 {
 		s.layout.GetDetails().SetContent(nil) // Clear details if no results
 	}
 
 	// Update the filter counter
 	// In Brewfile mode, show total Brewfile packages instead of all packages
-	totalCount := len(*s.packages)
+	allCount, brewfileCount, filteredCount := s.store.Counts()
+	totalCount := allCount
 	if s.IsBrewfileMode() {
-		totalCount = len(*s.brewfilePackages)
+		totalCount = brewfileCount
+	}
+	var formulae, casks int
+	for _, info := range data {
+		if info.Type == models.PackageTypeCask {
+			casks++
+		} else {
+			formulae++
+		}
 	}
-	s.layout.GetSearch().UpdateCounter(totalCount, len(*s.filteredPackages))
+	s.layout.GetSearch().UpdateCounter(components.CounterInfo{
+		Total: totalCount, Filtered: filteredCount, Formulae: formulae, Casks: casks, Filter: s.filterLabel(),
+	})
 }
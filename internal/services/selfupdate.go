@@ -4,11 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// selfUpdateFormula is the Homebrew formula Bold Brew is distributed with.
+	selfUpdateFormula = "valkyrie00/bbrew/bbrew"
+
+	// releaseAPIURL returns the GitHub release for a tag.
+	releaseAPIURL = "https://api.github.com/repos/Valkyrie00/bold-brew/releases/tags/%s"
+
+	// cacheFileUpdateCheck stores the result of the last update check.
+	cacheFileUpdateCheck = "update-check.json"
+
+	// updateCheckInterval is the minimum time between two update checks.
+	updateCheckInterval = 24 * time.Hour
 )
 
 type SelfUpdateServiceInterface interface {
 	CheckForUpdates(ctx context.Context) (string, error)
+	GetReleaseNotes(ctx context.Context, version string) (string, error)
 }
 
 type SelfUpdateService struct{}
@@ -19,13 +35,28 @@ type boldBrewStatusInfo struct {
 	} `json:"versions"`
 }
 
+// updateCheckResult is the persisted result of the last update check.
+type updateCheckResult struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	LatestVersion string    `json:"latest_version"`
+}
+
 var NewSelfUpdateService = func() SelfUpdateServiceInterface {
 	return &SelfUpdateService{}
 }
 
 // CheckForUpdates checks for the latest version of the Bold Brew package using Homebrew.
+// The result is cached, so the tap is queried at most once per updateCheckInterval.
 func (s *SelfUpdateService) CheckForUpdates(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "brew", "info", "--json=v1", "valkyrie00/bbrew/bbrew")
+	if data := readCacheFile(cacheFileUpdateCheck, 1); data != nil {
+		var cached updateCheckResult
+		if err := json.Unmarshal(data, &cached); err == nil && cached.LatestVersion != "" &&
+			time.Since(cached.CheckedAt) < updateCheckInterval {
+			return cached.LatestVersion, nil
+		}
+	}
+
+	cmd := brewCommandContext(ctx, "info", "--json=v1", selfUpdateFormula)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		if ctx.Err() != nil {
@@ -43,5 +74,21 @@ func (s *SelfUpdateService) CheckForUpdates(ctx context.Context) (string, error)
 		return "", fmt.Errorf("no version information found")
 	}
 
-	return info[0].Versions.Stable, nil
+	latestVersion := info[0].Versions.Stable
+	if data, err := json.Marshal(updateCheckResult{CheckedAt: time.Now(), LatestVersion: latestVersion}); err == nil {
+		if err := ensureCacheDir(); err == nil {
+			writeCacheFile(cacheFileUpdateCheck, data)
+		}
+	}
+	return latestVersion, nil
+}
+
+// GetReleaseNotes fetches the release notes of the given version from GitHub.
+func (s *SelfUpdateService) GetReleaseNotes(ctx context.Context, version string) (string, error) {
+	var release githubRelease
+	tag := "v" + strings.TrimPrefix(version, "v")
+	if err := fetchGitHubJSON(ctx, fmt.Sprintf(releaseAPIURL, tag), &release); err != nil {
+		return "", fmt.Errorf("failed to fetch release notes: %v", err)
+	}
+	return strings.TrimSpace(release.Body), nil
 }
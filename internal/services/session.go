@@ -0,0 +1,195 @@
+package services
+
+import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionLog records the operations of a session, for the summary shown on quit.
+// What changed is found by comparing the installed packages with those at the start,
+// so that operations run through brew bundle or Update All are accounted for too.
+type sessionLog struct {
+	mu         sync.Mutex
+	started    time.Time
+	baseline   map[string]string // Installed version by package key, once the startup refresh is done
+	operations int               // Completed package operations
+	failures   []sessionFailure
+}
+
+// sessionFailure is an operation that failed during the session.
+type sessionFailure struct {
+	Package string // Package name, or the operation for bulk operations
+	Err     error
+}
+
+// sessionChange is a package installed, updated or removed during the session.
+type sessionChange struct {
+	Name       string
+	OldVersion string
+	NewVersion string
+}
+
+// sessionSummary is what a session changed.
+type sessionSummary struct {
+	Started   time.Time
+	Duration  time.Duration
+	Installed []sessionChange
+	Updated   []sessionChange
+	Removed   []sessionChange
+	Failures  []sessionFailure
+}
+
+func newSessionLog() *sessionLog {
+	return &sessionLog{started: time.Now()}
+}
+
+// sessionKey identifies a package across refreshes: formulae and casks may share a name.
+func sessionKey(pkg models.Package) string {
+	return string(pkg.Type) + ":" + pkg.Name
+}
+
+// installedVersions returns the installed packages, by key. Brewfile packages are included
+// as they may come from taps that aren't part of the full list.
+func (s *AppService) installedVersions() map[string]string {
+	installed := make(map[string]string)
+	for _, list := range [][]models.Package{s.store.All(), s.store.Brewfile()} {
+		for _, pkg := range list {
+			if pkg.LocallyInstalled {
+				installed[sessionKey(pkg)] = pkg.InstalledVersion()
+			}
+		}
+	}
+	return installed
+}
+
+// setBaseline records the installed packages the session is compared with. Only the first call counts.
+func (l *sessionLog) setBaseline(installed map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.baseline == nil {
+		l.baseline = installed
+	}
+}
+
+// recordOperation records a completed operation, and its failure if any.
+func (l *sessionLog) recordOperation(event events.Event) {
+	if event.Operation == events.OperationBrewUpdate || event.Operation == events.OperationCleanup {
+		return // Doesn't change the packages
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.operations++
+	if event.Err != nil {
+		name := event.Operation
+		if event.Package != nil {
+			name = event.Package.Name
+		}
+		l.failures = append(l.failures, sessionFailure{Package: name, Err: event.Err})
+	}
+}
+
+// recordBatchStep records the failed packages of batch operations.
+func (l *sessionLog) recordBatchStep(progress events.Progress) {
+	if progress.Phase != events.PhaseFailed {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.failures = append(l.failures, sessionFailure{Package: progress.Package, Err: progress.Err})
+}
+
+// summary compares the installed packages with the baseline.
+// It returns false if no operation ran, or if they ran before the baseline was known.
+func (l *sessionLog) summary(installed map[string]string) (sessionSummary, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := sessionSummary{Started: l.started, Duration: time.Since(l.started), Failures: l.failures}
+	if l.baseline == nil || l.operations == 0 {
+		return result, false
+	}
+
+	for key, version := range installed {
+		old, existed := l.baseline[key]
+		switch {
+		case !existed:
+			result.Installed = append(result.Installed, sessionChange{Name: keyName(key), NewVersion: version})
+		case old != version:
+			result.Updated = append(result.Updated, sessionChange{Name: keyName(key), OldVersion: old, NewVersion: version})
+		}
+	}
+	for key, version := range l.baseline {
+		if _, exists := installed[key]; !exists {
+			result.Removed = append(result.Removed, sessionChange{Name: keyName(key), OldVersion: version})
+		}
+	}
+
+	for _, changes := range [][]sessionChange{result.Installed, result.Updated, result.Removed} {
+		i18n.SortByName(changes, func(change sessionChange) string { return change.Name })
+	}
+	return result, true
+}
+
+// keyName returns the package name of a session key.
+func keyName(key string) string {
+	_, name, _ := strings.Cut(key, ":")
+	return name
+}
+
+// sessionSummary returns what the session changed, false if it didn't run any operation.
+func (s *AppService) sessionSummary() (sessionSummary, bool) {
+	return s.session.summary(s.installedVersions())
+}
+
+// WriteSessionReport writes the summary of the session to a markdown file,
+// e.g. to keep a record of a machine provisioned from a Brewfile.
+func (s *AppService) WriteSessionReport(path string) error {
+	summary, _ := s.sessionSummary()
+	return os.WriteFile(path, []byte(summary.markdown(s.brewfilePath)), 0600)
+}
+
+// markdown formats the summary as a markdown report.
+func (m sessionSummary) markdown(brewfile string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", i18n.T("Bold Brew session report"))
+	fmt.Fprintf(&b, "- %s: %s\n", i18n.T("Started"), m.Started.Format(time.RFC1123))
+	fmt.Fprintf(&b, "- %s: %s\n", i18n.T("Duration"), m.Duration.Round(time.Second))
+	if brewfile != "" {
+		fmt.Fprintf(&b, "- Brewfile: `%s`\n", brewfile)
+	}
+
+	sections := []struct {
+		title   string
+		changes []sessionChange
+		format  func(c sessionChange) string
+	}{
+		{i18n.T("Installed"), m.Installed, func(c sessionChange) string { return c.NewVersion }},
+		{i18n.T("Updated"), m.Updated, func(c sessionChange) string { return c.OldVersion + " → " + c.NewVersion }},
+		{i18n.T("Removed"), m.Removed, func(c sessionChange) string { return c.OldVersion }},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", section.title, len(section.changes))
+		if len(section.changes) == 0 {
+			fmt.Fprintf(&b, "%s\n", i18n.T("None"))
+		}
+		for _, change := range section.changes {
+			fmt.Fprintf(&b, "- `%s` %s\n", change.Name, section.format(change))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## %s (%d)\n\n", i18n.T("Failures"), len(m.Failures))
+	if len(m.Failures) == 0 {
+		fmt.Fprintf(&b, "%s\n", i18n.T("None"))
+	}
+	for _, failure := range m.Failures {
+		fmt.Fprintf(&b, "- `%s`: %v\n", failure.Package, failure.Err)
+	}
+	return b.String()
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// rcEchoPattern matches the `echo 'line' >> ~/.zshrc` commands of caveats, capturing the line.
+var rcEchoPattern = regexp.MustCompile(`^echo\s+'(.+)'\s*>>\s*\S+$`)
+
+// rcIntroPattern matches the caveat sentences introducing lines to add to a shell profile,
+// e.g. "add the following to your ~/.zshrc:".
+var rcIntroPattern = regexp.MustCompile(`(?i)\b(add|put)\b.*(rc|profile|config\.fish)\b.*:\s*$`)
+
+// shellRCFile returns the profile of the user's shell, from $SHELL: ~/.zshrc, ~/.bashrc
+// (~/.bash_profile on macOS, where terminals open login shells) or fish's config.fish.
+func shellRCFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	switch shell := filepath.Base(os.Getenv("SHELL")); shell {
+	case "zsh":
+		if zdotdir := os.Getenv("ZDOTDIR"); zdotdir != "" {
+			return filepath.Join(zdotdir, ".zshrc"), nil
+		}
+		return filepath.Join(home, ".zshrc"), nil
+	case "bash":
+		if runtime.GOOS == "darwin" {
+			return filepath.Join(home, ".bash_profile"), nil
+		}
+		return filepath.Join(home, ".bashrc"), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "config.fish"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %q", shell)
+	}
+}
+
+// caveatsRCLines extracts the lines caveats ask to add to a shell profile: the content of
+// `echo '...' >> ~/.zshrc` commands, and the lines indented under "add the following to your ~/.zshrc:".
+func caveatsRCLines(caveats string) []string {
+	var lines []string
+	introduced := false
+	for _, line := range strings.Split(caveats, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indented := strings.HasPrefix(line, "  ") && trimmed != ""
+
+		switch {
+		case indented && rcEchoPattern.MatchString(trimmed):
+			lines = append(lines, rcEchoPattern.FindStringSubmatch(trimmed)[1])
+		case indented && introduced:
+			lines = append(lines, trimmed)
+		case !indented && trimmed != "":
+			introduced = rcIntroPattern.MatchString(trimmed)
+		}
+	}
+	return lines
+}
+
+// appendToShellRC appends lines to a shell profile under a comment naming the package, skipping
+// the lines it already has. The profile is backed up first, next to it. Returns the number of lines
+// added and the backup path ("" when the profile didn't exist).
+func appendToShellRC(path, pkg string, lines []string) (int, string, error) {
+	// #nosec G304 -- path is the profile of the user's shell
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return 0, "", err
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(existing), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, line := range lines {
+		if !present[line] {
+			present[line] = true
+			missing = append(missing, line)
+		}
+	}
+	if len(missing) == 0 {
+		return 0, "", nil
+	}
+
+	backup := ""
+	if existing != nil {
+		backup = fmt.Sprintf("%s.bbrew-backup-%s", path, time.Now().Format("20060102-150405"))
+		if err := os.WriteFile(backup, existing, 0600); err != nil {
+			return 0, "", fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return 0, "", err
+	}
+
+	block := fmt.Sprintf("\n# Added by %s for %s\n%s\n", AppName, pkg, strings.Join(missing, "\n"))
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		block = "\n" + block
+	}
+
+	// #nosec G304 -- path is the profile of the user's shell
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, backup, err
+	}
+	defer file.Close()
+	if _, err := file.WriteString(block); err != nil {
+		return 0, backup, err
+	}
+	return len(missing), backup, nil
+}
+
+// displayPath shortens a path in the home directory to ~/...
+func displayPath(path string) string {
+	if home, err := os.UserHomeDir(); err == nil {
+		if rest, found := strings.CutPrefix(path, home+string(filepath.Separator)); found {
+			return "~/" + rest
+		}
+	}
+	return path
+}
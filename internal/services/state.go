@@ -0,0 +1,32 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+)
+
+// getStateDir returns the state directory following XDG Base Directory Specification.
+// Unlike the cache, it holds user data (notes, favorites) that can't be fetched again.
+func getStateDir() string {
+	return filepath.Join(xdg.StateHome, "bbrew")
+}
+
+// readStateFile reads a file of the state directory, returning nil if it doesn't exist.
+func readStateFile(filename string) []byte {
+	// #nosec G304 -- state path is safely constructed from getStateDir
+	data, err := os.ReadFile(filepath.Join(getStateDir(), filename))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// writeStateFile saves data to a file of the state directory, creating the directory if needed.
+func writeStateFile(filename string, data []byte) error {
+	if err := os.MkdirAll(getStateDir(), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(getStateDir(), filename), data, 0600)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// statusRefreshInterval is how often the header system information is refreshed.
+const statusRefreshInterval = time.Minute
+
+// headerStats collects the system information of the header: installed, outdated and held
+// packages from the store, the last `brew update` and the free space of the Homebrew volume.
+func (s *AppService) headerStats() components.HeaderStats {
+	var stats components.HeaderStats
+	for _, pkg := range s.store.All() {
+		if !pkg.LocallyInstalled {
+			continue
+		}
+		if pkg.Type == models.PackageTypeCask {
+			stats.Casks++
+		} else {
+			stats.Formulae++
+		}
+		if pkg.Outdated && s.isHeld(pkg) {
+			stats.Held++
+		} else if pkg.Outdated {
+			stats.Outdated++
+		}
+	}
+
+	if s.brewMissing {
+		return stats // No Homebrew prefix
+	}
+	prefix := s.dataProvider.GetPrefixPath()
+	stats.LastUpdate = lastBrewUpdate(prefix)
+	if free, err := freeDiskSpace(prefix); err == nil {
+		stats.FreeDisk = formatBytes(int64(free)) // #nosec G115 -- free space fits in an int64
+	}
+	return stats
+}
+
+// lastBrewUpdate returns the time of the last `brew update`, from the FETCH_HEAD of the Homebrew
+// repository: the prefix itself on Apple Silicon, its Homebrew directory elsewhere.
+func lastBrewUpdate(prefix string) time.Time {
+	for _, repository := range []string{filepath.Join(prefix, "Homebrew"), prefix} {
+		if info, err := os.Stat(filepath.Join(repository, ".git", "FETCH_HEAD")); err == nil {
+			return info.ModTime()
+		}
+	}
+	return time.Time{}
+}
+
+// freeDiskSpace returns the space available to the user on the volume of the given path.
+func freeDiskSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil // #nosec G115 -- the block size is positive
+}
+
+// refreshHeaderStats collects the header system information and shows it. Called from background goroutines.
+func (s *AppService) refreshHeaderStats() {
+	stats := s.headerStats()
+	s.app.QueueUpdateDraw(func() {
+		s.layout.GetHeader().SetStats(stats)
+	})
+}
+
+// startStatusTicker refreshes the header system information in the background,
+// so that the last update age and the free space stay current.
+func (s *AppService) startStatusTicker() {
+	go func() {
+		defer RecoverCrash()
+		s.refreshHeaderStats()
+		ticker := time.NewTicker(statusRefreshInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.refreshHeaderStats()
+		}
+	}()
+}
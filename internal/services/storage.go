@@ -0,0 +1,204 @@
+package services
+
+import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/components"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// storageEntry is a package taking disk space `brew cleanup <name>` would free: the kegs of its old
+// versions in the Cellar, which are no longer linked, and its downloads in the cache.
+type storageEntry struct {
+	Name        string
+	OldVersions []string
+	KegsSize    int64
+	CacheSize   int64
+}
+
+// Size returns the space cleaning up the package would free.
+func (e storageEntry) Size() int64 {
+	return e.KegsSize + e.CacheSize
+}
+
+// storageReport is the disk usage of Homebrew itself, apart from the installed versions of packages.
+type storageReport struct {
+	CacheDir  string
+	CacheSize int64
+	Entries   []storageEntry // Largest first
+}
+
+// brewCacheDir returns the download cache of Homebrew, from `brew --cache`
+// (~/Library/Caches/Homebrew on macOS, unless HOMEBREW_CACHE says otherwise).
+func brewCacheDir() (string, error) {
+	output, err := brewCommand("--cache").CombinedOutput()
+	if err != nil {
+		return "", commandFailure("brew --cache", err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// buildStorageReport measures the cache and the old kegs of the Cellar. The installed version of a
+// formula is the keg its opt link points to; formulae without one are skipped, as brew would be.
+func buildStorageReport(prefix, cacheDir string) storageReport {
+	report := storageReport{CacheDir: cacheDir, CacheSize: diskUsage(cacheDir)}
+	entries := make(map[string]*storageEntry)
+	entry := func(name string) *storageEntry {
+		if entries[name] == nil {
+			entries[name] = &storageEntry{Name: name}
+		}
+		return entries[name]
+	}
+
+	cellar := filepath.Join(prefix, "Cellar")
+	formulae, _ := os.ReadDir(cellar)
+	for _, formula := range formulae {
+		if !formula.IsDir() {
+			continue
+		}
+		opt := resolvePath(filepath.Join(prefix, "opt", formula.Name()))
+		if filepath.Dir(opt) != resolvePath(filepath.Join(cellar, formula.Name())) {
+			continue
+		}
+		kegs, _ := os.ReadDir(filepath.Join(cellar, formula.Name()))
+		for _, keg := range kegs {
+			if !keg.IsDir() || keg.Name() == filepath.Base(opt) {
+				continue
+			}
+			e := entry(formula.Name())
+			e.OldVersions = append(e.OldVersions, keg.Name())
+			e.KegsSize += diskUsage(filepath.Join(cellar, formula.Name(), keg.Name()))
+		}
+	}
+
+	// The cache links each download as <name>--<version>.<ext>, next to its own files (api, bootsnap...)
+	downloads, _ := os.ReadDir(cacheDir)
+	for _, download := range downloads {
+		name, _, found := strings.Cut(download.Name(), "--")
+		if !found || download.IsDir() {
+			continue
+		}
+		if fileInfo, err := os.Stat(filepath.Join(cacheDir, download.Name())); err == nil && !fileInfo.IsDir() {
+			entry(name).CacheSize += fileInfo.Size()
+		}
+	}
+
+	for _, e := range entries {
+		report.Entries = append(report.Entries, *e)
+	}
+	slices.SortFunc(report.Entries, func(a, b storageEntry) int {
+		if a.Size() != b.Size() {
+			return cmp.Compare(b.Size(), a.Size())
+		}
+		return i18n.Compare(a.Name, b.Name)
+	})
+	return report
+}
+
+// purgeBrewCache removes the content of the download cache, as `rm -rf "$(brew --cache)"` does,
+// keeping the directory itself.
+func purgeBrewCache(cacheDir string) error {
+	home, _ := os.UserHomeDir()
+	if !filepath.IsAbs(cacheDir) || filepath.Dir(cacheDir) == cacheDir || cacheDir == home {
+		return fmt.Errorf("refusing to purge %q as the Homebrew cache", cacheDir)
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return err
+	}
+	brewLog.Info("purging the Homebrew cache", "dir", cacheDir, "entries", len(entries))
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(cacheDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleStorageEvent is called when the user presses the disk usage key (z).
+// It measures the download cache and the old kegs in the background, then lists what `brew cleanup` would free.
+func (s *InputService) handleStorageEvent() {
+	s.layout.GetNotifier().ShowProgress(i18n.T("Measuring the disk usage of Homebrew..."))
+	go func() {
+		defer RecoverCrash()
+		cacheDir, err := brewCacheDir()
+		if err != nil {
+			s.layout.GetNotifier().ShowError(i18n.T("Failed to find the Homebrew cache: %v", err))
+			return
+		}
+		report := buildStorageReport(s.appService.dataProvider.GetPrefixPath(), cacheDir)
+		appLog.Info("measured Homebrew disk usage", "cache", report.CacheSize, "packages", len(report.Entries))
+
+		items := make([]components.StorageItem, len(report.Entries))
+		for i, entry := range report.Entries {
+			items[i] = components.StorageItem{
+				Name: entry.Name, OldVersions: strings.Join(entry.OldVersions, ", "),
+				Kegs: formatBytes(entry.KegsSize), Downloads: formatBytes(entry.CacheSize), Total: formatBytes(entry.Size()),
+			}
+		}
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			s.layout.GetNotifier().Clear()
+			storagePages := s.layout.GetStorage().Build(s.layout.Root(), displayPath(cacheDir), formatBytes(report.CacheSize), items,
+				s.cleanupPackage, func() { s.purgeCache(cacheDir, report.CacheSize) }, s.handleBack)
+			s.appService.GetApp().SetRoot(storagePages, true)
+		})
+	}()
+}
+
+// cleanupPackage asks to confirm, then runs `brew cleanup` for a package of the disk usage report.
+func (s *InputService) cleanupPackage(item components.StorageItem) {
+	s.showModal(components.ModalOptions{
+		Text:   i18n.T("Run brew cleanup %s? It frees %s.", item.Name, item.Total),
+		Cancel: s.handleStorageEvent,
+		Confirm: func() {
+			s.closeModal()
+			if !s.appService.beginOperation(i18n.T("cleaning up %s…", item.Name)) {
+				return
+			}
+			s.layout.GetOutput().BeginOperation(i18n.T("cleaning up %s…", item.Name))
+			go func() {
+				defer RecoverCrash()
+				defer s.appService.endOperation()
+				s.layout.GetNotifier().ShowProgress(i18n.T("Cleaning up %s...", item.Name))
+				err := s.brewService.Cleanup(item.Name, s.appService.app, s.layout.GetOutput().View())
+				if err != nil {
+					s.layout.GetNotifier().ShowError(i18n.T("Failed to clean up %s: %v", item.Name, err))
+				} else {
+					s.layout.GetNotifier().ShowSuccess(i18n.T("Cleaned up %s, %s freed", item.Name, item.Total))
+				}
+				s.appService.events.Publish(events.Event{Type: events.OperationCompleted, Operation: events.OperationCleanup, Err: err})
+			}()
+		},
+	})
+}
+
+// purgeCache asks to confirm, then empties the download cache of Homebrew.
+func (s *InputService) purgeCache(cacheDir string, size int64) {
+	s.showModal(components.ModalOptions{
+		Text: i18n.T("Remove everything in %s (%s)? Homebrew downloads again what it needs.",
+			displayPath(cacheDir), formatBytes(size)),
+		Cancel: s.handleStorageEvent,
+		Confirm: func() {
+			s.closeModal()
+			// Homebrew may be downloading into the cache
+			if !s.appService.beginOperation(i18n.T("purging the Homebrew cache…")) {
+				return
+			}
+			go func() {
+				defer RecoverCrash()
+				defer s.appService.endOperation()
+				s.layout.GetNotifier().ShowProgress(i18n.T("Purging the Homebrew cache..."))
+				if err := purgeBrewCache(cacheDir); err != nil {
+					s.layout.GetNotifier().ShowError(i18n.T("Failed to purge the Homebrew cache: %v", err))
+					return
+				}
+				s.layout.GetNotifier().ShowSuccess(i18n.T("Purged the Homebrew cache, %s freed", formatBytes(size)))
+			}()
+		},
+	})
+}
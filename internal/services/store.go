@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"strings"
+	"sync"
+)
+
+// PackageStore holds the package lists shared between the UI and the background refreshes.
+// All access goes through its methods: reads return copies, so callers can iterate or keep
+// the result without holding the lock, and writes replace the lists atomically.
+type PackageStore struct {
+	mu       sync.RWMutex
+	all      []models.Package // Every known package
+	brewfile []models.Package // Packages listed in the Brewfile (Brewfile mode only)
+	filtered []models.Package // Packages currently shown in the table
+}
+
+// NewPackageStore creates an empty PackageStore.
+func NewPackageStore() *PackageStore {
+	return &PackageStore{}
+}
+
+// All returns a copy of every known package.
+func (p *PackageStore) All() []models.Package {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return copyPackages(p.all)
+}
+
+// Lookup returns a known package by name and type.
+func (p *PackageStore) Lookup(name string, packageType models.PackageType) (models.Package, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, pkg := range p.all {
+		if pkg.Name == name && pkg.Type == packageType {
+			return pkg, true
+		}
+	}
+	return models.Package{}, false
+}
+
+// SetAll replaces the list of known packages.
+func (p *PackageStore) SetAll(packages []models.Package) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.all = copyPackages(packages)
+}
+
+// UpdateAll applies fn to every known package while holding the write lock.
+func (p *PackageStore) UpdateAll(fn func(pkg *models.Package)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := range p.all {
+		fn(&p.all[i])
+	}
+}
+
+// UpdateLists applies fn to every package of the known, Brewfile and filtered lists
+// while holding the write lock.
+func (p *PackageStore) UpdateLists(fn func(pkg *models.Package)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, list := range [][]models.Package{p.all, p.brewfile, p.filtered} {
+		for i := range list {
+			fn(&list[i])
+		}
+	}
+}
+
+// Brewfile returns a copy of the Brewfile packages.
+func (p *PackageStore) Brewfile() []models.Package {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return copyPackages(p.brewfile)
+}
+
+// SetBrewfile replaces the list of Brewfile packages.
+func (p *PackageStore) SetBrewfile(packages []models.Package) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.brewfile = copyPackages(packages)
+}
+
+// Filtered returns a copy of the packages currently shown in the table.
+func (p *PackageStore) Filtered() []models.Package {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return copyPackages(p.filtered)
+}
+
+// SetFiltered replaces the packages currently shown in the table.
+func (p *PackageStore) SetFiltered(packages []models.Package) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.filtered = copyPackages(packages)
+}
+
+// FilteredAt returns the package shown at the given index of the table (0-based, excluding the header).
+func (p *PackageStore) FilteredAt(index int) (models.Package, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if index < 0 || index >= len(p.filtered) {
+		return models.Package{}, false
+	}
+	return p.filtered[index], true
+}
+
+// Counts returns the sizes of the known, Brewfile and filtered lists.
+func (p *PackageStore) Counts() (all, brewfile, filtered int) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.all), len(p.brewfile), len(p.filtered)
+}
+
+// copyPackages returns a shallow copy of a package list.
+func copyPackages(packages []models.Package) []models.Package {
+	if packages == nil {
+		return nil
+	}
+	result := make([]models.Package, len(packages))
+	copy(result, packages)
+	return result
+}
+
+// packageKey identifies a package by name and type, as formulae and casks may share a name.
+type packageKey struct {
+	name   string
+	isCask bool
+}
+
+// packageKeyOf returns the key of a package under its full name.
+func packageKeyOf(pkg models.Package) packageKey {
+	name := pkg.FullName
+	if name == "" {
+		name = pkg.Name // Packages cached before the full name was recorded
+	}
+	return packageKey{name: name, isCask: pkg.Type == models.PackageTypeCask}
+}
+
+// packageIndex looks packages up by short name ("tool") or full name ("user/repo/tool").
+type packageIndex map[packageKey]models.Package
+
+// newPackageIndex indexes packages by full and short name, then by the old names of renamed packages.
+// Like brew, short names resolve to homebrew-core and homebrew-cask packages first.
+func newPackageIndex(packages []models.Package) packageIndex {
+	index := make(packageIndex, len(packages)*2)
+	for _, pkg := range packages {
+		index[packageKeyOf(pkg)] = pkg
+	}
+	for _, pkg := range packages {
+		key := packageKey{name: pkg.Name, isCask: pkg.Type == models.PackageTypeCask}
+		if _, exists := index[key]; !exists {
+			index[key] = pkg
+		}
+	}
+	for _, pkg := range packages {
+		for _, oldName := range pkg.OldNames() {
+			key := packageKey{name: oldName, isCask: pkg.Type == models.PackageTypeCask}
+			if _, exists := index[key]; !exists {
+				index[key] = pkg
+			}
+		}
+	}
+	return index
+}
+
+// lookup returns the package referenced by a Brewfile entry name, short or tap-qualified.
+func (index packageIndex) lookup(name string, isCask bool) (models.Package, bool) {
+	name = strings.TrimPrefix(name, "homebrew/core/")
+	name = strings.TrimPrefix(name, "homebrew/cask/")
+	pkg, exists := index[packageKey{name: name, isCask: isCask}]
+	return pkg, exists
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// The remote formulae and casks (~12k packages) are kept as summaries, with only what the table, search
+// and filters use: the bottle files of other platforms, checksums, caveats and artifacts are dropped.
+// The full details are read back from formula.json or cask.json when a package is shown, at the byte range
+// of its entry recorded while decoding, or fetched from the API if the cache changed meanwhile.
+
+// caskDetailsAPIURL is the full JSON of a single cask, fetched when it isn't in the cache.
+const caskDetailsAPIURL = "https://formulae.brew.sh/api/cask/%s.json"
+
+// detailSpan is the byte range of a package entry in a cache file.
+type detailSpan struct {
+	start, end int64
+}
+
+// detailIndex maps the packages to the byte range of their entry, by cache file.
+type detailIndex struct {
+	mu    sync.RWMutex
+	spans map[string]map[string]detailSpan
+}
+
+// set replaces the byte ranges of the entries of a cache file.
+func (i *detailIndex) set(cacheFile string, spans map[string]detailSpan) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.spans == nil {
+		i.spans = make(map[string]map[string]detailSpan)
+	}
+	i.spans[cacheFile] = spans
+}
+
+// lookup returns the byte range of the entry of a package in a cache file.
+func (i *detailIndex) lookup(cacheFile, name string) (detailSpan, bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	span, exists := i.spans[cacheFile][name]
+	return span, exists
+}
+
+// decodeEntries decodes a JSON array one entry at a time, calling each with the entry and its byte range in data,
+// so that the full entries are never all in memory at once.
+func decodeEntries[T any](source string, data []byte, each func(entry *T, span detailSpan)) error {
+	defer startup.begin("decode " + source)()
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if token != json.Delim('[') {
+		return fmt.Errorf("%s: expected a JSON array", source)
+	}
+	for decoder.More() {
+		start := decoder.InputOffset() // May include the separator of the previous entry
+		var entry T
+		if err := decoder.Decode(&entry); err != nil {
+			return err
+		}
+		each(&entry, detailSpan{start: start, end: decoder.InputOffset()})
+	}
+	_, err := decoder.Token()
+	return err
+}
+
+// decodeFormulaSummaries decodes formula.json as summaries, recording the byte range of each formula.
+func (d *DataProvider) decodeFormulaSummaries(data []byte) ([]models.Formula, error) {
+	tags := GetPlatform().BottleTags()
+	spans := make(map[string]detailSpan)
+	var formulae []models.Formula
+	err := decodeEntries(cacheFileFormulae, data, func(formula *models.Formula, span detailSpan) {
+		formulae = append(formulae, formula.Summary(tags))
+		spans[formula.Name] = span
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.details.set(cacheFileFormulae, spans)
+	return formulae, nil
+}
+
+// decodeCaskSummaries decodes cask.json as summaries, recording the byte range of each cask.
+func (d *DataProvider) decodeCaskSummaries(data []byte) ([]models.Cask, error) {
+	spans := make(map[string]detailSpan)
+	var casks []models.Cask
+	err := decodeEntries(cacheFileCasks, data, func(cask *models.Cask, span detailSpan) {
+		casks = append(casks, cask.Summary())
+		spans[cask.Token] = span
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.details.set(cacheFileCasks, spans)
+	return casks, nil
+}
+
+// readDetails decodes the entry of a package from a cache file, at the byte range recorded with its summary.
+// Callers check the name and version of the result, in case the file was replaced meanwhile.
+func (d *DataProvider) readDetails(cacheFile, name string, v any) bool {
+	span, exists := d.details.lookup(cacheFile, name)
+	if !exists {
+		return false
+	}
+
+	// #nosec G304 -- cacheFile path is safely constructed from getCacheDir
+	file, err := os.Open(filepath.Join(getCacheDir(), cacheFile))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	data := make([]byte, span.end-span.start)
+	if _, err := file.ReadAt(data, span.start); err != nil {
+		return false
+	}
+	return json.Unmarshal(bytes.TrimLeft(data, ", \t\r\n"), v) == nil
+}
+
+// FetchCask returns the full cask of a summary, at the given version: from cask.json,
+// or else from the API. The API result is cached until the cask has a new version.
+func (d *DataProvider) FetchCask(token, version string) (*models.Cask, error) {
+	var cask models.Cask
+	if d.readDetails(cacheFileCasks, token, &cask) && cask.Token == token && cask.Version == version {
+		return &cask, nil
+	}
+
+	if err := ensureCacheDir(); err != nil {
+		return nil, err
+	}
+	cacheFile := "cask-info-" + url.PathEscape(token) + ".json"
+	if data := readCacheFile(cacheFile, formulaDetailsMinSize); data != nil {
+		cask = models.Cask{}
+		if err := json.Unmarshal(data, &cask); err == nil && cask.Token == token && cask.Version == version {
+			return &cask, nil
+		}
+	}
+
+	body, err := fetchFromAPI(fmt.Sprintf(caskDetailsAPIURL, url.PathEscape(token)))
+	if err != nil {
+		return nil, err
+	}
+	cask = models.Cask{}
+	if err := json.Unmarshal(body, &cask); err != nil {
+		return nil, err
+	}
+	if cask.Token != token {
+		return nil, fmt.Errorf("cask %s not found", token)
+	}
+	writeCacheFile(cacheFile, body)
+	return &cask, nil
+}
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// `brew services` runs the service of a formula (its `service do` block) through launchd on macOS.
+// On Linux, bbrew generates a systemd user unit from the same definition instead, and manages it
+// with systemctl --user: units are named bbrew-<formula>, next to the one of the scheduled runner.
+
+// formulaService is the service definition of a formula, as in the JSON API, for Linux.
+type formulaService struct {
+	Run          []string
+	RunType      string // "immediate" (the default), "interval" or "cron"
+	Interval     int    // Seconds between runs, for the interval run type
+	KeepAlive    bool
+	WorkingDir   string
+	LogPath      string
+	ErrorLogPath string
+	Environment  map[string]string
+}
+
+// parseFormulaService reads the service definition of a formula, with $HOMEBREW_PREFIX expanded.
+// The command may be given for each OS ({"linux": [...], "macos": [...]}), the Linux one is used.
+func parseFormulaService(raw interface{}, prefix string) (formulaService, bool) {
+	definition, ok := raw.(map[string]interface{})
+	if !ok {
+		return formulaService{}, false
+	}
+	expand := func(value string) string {
+		return strings.ReplaceAll(value, "$HOMEBREW_PREFIX", prefix)
+	}
+	text := func(key string) string {
+		value, _ := definition[key].(string)
+		return expand(value)
+	}
+
+	service := formulaService{
+		RunType: text("run_type"), WorkingDir: text("working_dir"),
+		LogPath: text("log_path"), ErrorLogPath: text("error_log_path"),
+	}
+	run := definition["run"]
+	if byOS, ok := run.(map[string]interface{}); ok {
+		run = byOS["linux"]
+	}
+	switch run := run.(type) {
+	case string:
+		service.Run = []string{expand(run)}
+	case []interface{}:
+		for _, arg := range run {
+			if arg, ok := arg.(string); ok {
+				service.Run = append(service.Run, expand(arg))
+			}
+		}
+	}
+	if len(service.Run) == 0 {
+		return formulaService{}, false
+	}
+	if service.RunType == "" {
+		service.RunType = "immediate"
+	}
+	if interval, ok := definition["interval"].(float64); ok {
+		service.Interval = int(interval)
+	}
+
+	// keep_alive is true, or a hash of conditions such as {"always": true} or {"crashed": true}
+	switch keepAlive := definition["keep_alive"].(type) {
+	case bool:
+		service.KeepAlive = keepAlive
+	case map[string]interface{}:
+		for _, condition := range keepAlive {
+			if enabled, ok := condition.(bool); ok && enabled {
+				service.KeepAlive = true
+			}
+		}
+	}
+	if variables, ok := definition["environment_variables"].(map[string]interface{}); ok {
+		service.Environment = make(map[string]string, len(variables))
+		for name, value := range variables {
+			if value, ok := value.(string); ok {
+				service.Environment[name] = expand(value)
+			}
+		}
+	}
+	return service, true
+}
+
+// systemdUnitName returns the name of the units of the service of a formula, without suffix.
+// @ is replaced, as it introduces the instance of a template unit in systemd.
+func systemdUnitName(formula string) string {
+	return "bbrew-" + strings.ReplaceAll(formula, "@", "-")
+}
+
+// systemdQuote quotes an argument of ExecStart or Environment when needed, and escapes the specifiers
+// of systemd (%).
+func systemdQuote(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+// formulaUnits returns the systemd user units of the service of a formula, by file name: a service,
+// and a timer for services run at an interval. Cron services are not supported.
+func formulaUnits(formula string, service formulaService) (map[string]string, error) {
+	name := systemdUnitName(formula)
+	var unit strings.Builder
+	fmt.Fprintf(&unit, "[Unit]\nDescription=%s (Homebrew service, managed by Bold Brew)\n\n[Service]\n", formula)
+
+	args := make([]string, len(service.Run))
+	for i, arg := range service.Run {
+		args[i] = systemdQuote(arg)
+	}
+	switch service.RunType {
+	case "immediate":
+		fmt.Fprintf(&unit, "Type=simple\nExecStart=%s\n", strings.Join(args, " "))
+		if service.KeepAlive {
+			fmt.Fprintf(&unit, "Restart=always\nRestartSec=5\n")
+		}
+	case "interval":
+		if service.Interval <= 0 {
+			return nil, fmt.Errorf("the service of %s has no interval", formula)
+		}
+		fmt.Fprintf(&unit, "Type=oneshot\nExecStart=%s\n", strings.Join(args, " "))
+	default:
+		return nil, fmt.Errorf("the %s run type of the service of %s is not supported", service.RunType, formula)
+	}
+
+	if service.WorkingDir != "" {
+		fmt.Fprintf(&unit, "WorkingDirectory=%s\n", systemdQuote(service.WorkingDir))
+	}
+	names := make([]string, 0, len(service.Environment))
+	for variable := range service.Environment {
+		names = append(names, variable)
+	}
+	slices.Sort(names)
+	for _, variable := range names {
+		fmt.Fprintf(&unit, "Environment=%s\n", systemdQuote(variable+"="+service.Environment[variable]))
+	}
+	if service.LogPath != "" {
+		fmt.Fprintf(&unit, "StandardOutput=append:%s\n", service.LogPath)
+	}
+	if service.ErrorLogPath != "" {
+		fmt.Fprintf(&unit, "StandardError=append:%s\n", service.ErrorLogPath)
+	}
+
+	units := map[string]string{}
+	if service.RunType == "interval" {
+		units[name+".service"] = unit.String()
+		units[name+".timer"] = fmt.Sprintf(`[Unit]
+Description=Run %s every %ds (Homebrew service, managed by Bold Brew)
+
+[Timer]
+OnActiveSec=0
+OnUnitActiveSec=%ds
+
+[Install]
+WantedBy=timers.target
+`, formula, service.Interval, service.Interval)
+		return units, nil
+	}
+	unit.WriteString("\n[Install]\nWantedBy=default.target\n")
+	units[name+".service"] = unit.String()
+	return units, nil
+}
+
+// mainUnit returns the unit started and enabled for a service: its timer for services run at an interval.
+func mainUnit(formula string, service formulaService) string {
+	if service.RunType == "interval" {
+		return systemdUnitName(formula) + ".timer"
+	}
+	return systemdUnitName(formula) + ".service"
+}
+
+// systemdUserDir returns the directory of the systemd user units.
+func systemdUserDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
+
+// systemctl runs `systemctl --user` with the given arguments, returning its trimmed output.
+func systemctl(args ...string) (string, error) {
+	args = append([]string{"--user"}, args...)
+	output, err := exec.Command("systemctl", args...).CombinedOutput() // #nosec G204 -- fixed command
+	if err != nil {
+		return strings.TrimSpace(string(output)), commandFailure("systemctl "+strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// writeFormulaUnits writes the units of the service of a formula, then reloads systemd.
+func writeFormulaUnits(formula string, service formulaService) error {
+	units, err := formulaUnits(formula, service)
+	if err != nil {
+		return err
+	}
+	dir, err := systemdUserDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	for file, content := range units {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0600); err != nil {
+			return err
+		}
+	}
+	_, err = systemctl("daemon-reload")
+	return err
+}
+
+// serviceStatus returns the state of the unit of a service (active, inactive, failed...), "" when bbrew
+// didn't generate it yet, and whether it is enabled at login.
+func serviceStatus(formula string, service formulaService) (state string, enabled bool) {
+	dir, err := systemdUserDir()
+	if err != nil {
+		return "", false
+	}
+	unit := mainUnit(formula, service)
+	if _, err := os.Stat(filepath.Join(dir, unit)); err != nil {
+		return "", false
+	}
+	state, _ = systemctl("is-active", unit) // Fails for any state but active
+	enabledState, _ := systemctl("is-enabled", unit)
+	return state, enabledState == "enabled"
+}
+
+// servicePackages returns the installed formulae with a service definition for Linux, sorted by name.
+func (s *AppService) servicePackages() []models.Package {
+	var packages []models.Package
+	for _, pkg := range s.store.All() {
+		if pkg.Formula != nil && pkg.LocallyInstalled && pkg.Formula.Service != nil {
+			if _, ok := parseFormulaService(pkg.Formula.Service, s.dataProvider.GetPrefixPath()); ok {
+				packages = append(packages, pkg)
+			}
+		}
+	}
+	i18n.SortByName(packages, func(pkg models.Package) string { return pkg.Name })
+	return packages
+}
+
+// handleServicesEvent is called when the user presses the services key (J).
+// On Linux, it lists the services of the installed formulae with the state of their systemd user units.
+func (s *InputService) handleServicesEvent() {
+	if GetPlatform().OS != "linux" {
+		s.layout.GetNotifier().ShowWarning(i18n.T("On macOS, services are managed by brew services"))
+		return
+	}
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		s.layout.GetNotifier().ShowError(i18n.T("systemctl not found: services need systemd"))
+		return
+	}
+	s.showServices(0)
+}
+
+// showServices reads the state of the services in the background, then shows them with the given row selected.
+func (s *InputService) showServices(selected int) {
+	go func() {
+		defer RecoverCrash()
+		prefix := s.appService.dataProvider.GetPrefixPath()
+		packages := s.appService.servicePackages()
+		items := make([]components.ServiceItem, len(packages))
+		for i, pkg := range packages {
+			service, _ := parseFormulaService(pkg.Formula.Service, prefix)
+			state, enabled := serviceStatus(pkg.Name, service)
+			items[i] = components.ServiceItem{Name: pkg.Name, State: state, Enabled: enabled, Command: strings.Join(service.Run, " ")}
+		}
+
+		s.appService.GetApp().QueueUpdateDraw(func() {
+			servicesPages := s.layout.GetServices().Build(s.layout.Root(), items, selected,
+				func(row int, action components.ServiceAction) { s.runServiceAction(packages[row], row, action) },
+				s.handleBack)
+			s.appService.GetApp().SetRoot(servicesPages, true)
+		})
+	}()
+}
+
+// runServiceAction starts, stops, enables or disables the service of a formula, generating its units
+// first so that they follow the installed version, then shows the services again.
+func (s *InputService) runServiceAction(pkg models.Package, row int, action components.ServiceAction) {
+	service, _ := parseFormulaService(pkg.Formula.Service, s.appService.dataProvider.GetPrefixPath())
+	unit := mainUnit(pkg.Name, service)
+	go func() {
+		defer RecoverCrash()
+		var err error
+		if action == components.ServiceStart || action == components.ServiceEnable {
+			err = writeFormulaUnits(pkg.Name, service)
+		}
+		if err == nil {
+			switch action {
+			case components.ServiceStart:
+				_, err = systemctl("start", unit)
+			case components.ServiceStop:
+				_, err = systemctl("stop", unit)
+			case components.ServiceEnable:
+				_, err = systemctl("enable", "--now", unit)
+			case components.ServiceDisable:
+				_, err = systemctl("disable", unit)
+			}
+		}
+		appLog.Info("service action", "formula", pkg.Name, "unit", unit, "action", string(action), "err", err)
+		switch {
+		case err != nil:
+			s.layout.GetNotifier().ShowError(i18n.T("Failed to %s %s: %v", string(action), unit, err))
+		case action == components.ServiceStart:
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Started the service of %s", pkg.Name))
+		case action == components.ServiceStop:
+			s.layout.GetNotifier().ShowSuccess(i18n.T("Stopped the service of %s", pkg.Name))
+		case action == components.ServiceEnable:
+			s.layout.GetNotifier().ShowSuccess(i18n.T("The service of %s now starts at login", pkg.Name))
+		case action == components.ServiceDisable:
+			s.layout.GetNotifier().ShowSuccess(i18n.T("The service of %s no longer starts at login", pkg.Name))
+		}
+		s.showServices(row)
+	}()
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// tapFinding is a problem found in a package of a tap by `brew audit` or `brew style`.
+type tapFinding struct {
+	Package string
+	Source  string // "audit" or "style"
+	Message string
+}
+
+// tapAuditResult groups the findings of a tap by package, in name order.
+type tapAuditResult struct {
+	Packages []string
+	Findings map[string][]tapFinding
+}
+
+// styleLinePattern matches an offense of `brew style` (RuboCop):
+// "/path/Formula/foo.rb:12:5: C: [Correctable] FormulaAudit/Desc: Description shouldn't...".
+var styleLinePattern = regexp.MustCompile(`^(.+\.rb):(\d+):(\d+): \w: (.+)$`)
+
+// auditTap runs `brew audit --strict` and `brew style` on a local tap, grouping their findings by package.
+// Both commands fail when they find problems, which is only an error when there is no finding to show.
+func auditTap(tap string) (tapAuditResult, error) {
+	var findings []tapFinding
+	var errs []error
+
+	output, err := brewCommand("audit", "--strict", "--tap", tap).CombinedOutput()
+	auditFindings := parseAuditOutput(string(output))
+	if err != nil && len(auditFindings) == 0 {
+		errs = append(errs, commandFailure("brew audit", err, output))
+	}
+	findings = append(findings, auditFindings...)
+
+	output, err = brewCommand("style", tap).CombinedOutput()
+	styleFindings := parseStyleOutput(string(output))
+	if err != nil && len(styleFindings) == 0 {
+		errs = append(errs, commandFailure("brew style", err, output))
+	}
+	findings = append(findings, styleFindings...)
+
+	result := tapAuditResult{Findings: make(map[string][]tapFinding)}
+	for _, finding := range findings {
+		if _, exists := result.Findings[finding.Package]; !exists {
+			result.Packages = append(result.Packages, finding.Package)
+		}
+		result.Findings[finding.Package] = append(result.Findings[finding.Package], finding)
+	}
+	i18n.SortByName(result.Packages, func(name string) string { return name })
+
+	if len(errs) == 2 {
+		return result, errors.Join(errs...) // Nothing ran, e.g. not a tap
+	}
+	if len(errs) == 1 {
+		brewLog.Warn("tap audit incomplete", "tap", tap, "err", errs[0])
+	}
+	return result, nil
+}
+
+// commandFailure returns the error of a failed command, with the last line of its output.
+func commandFailure(command string, err error, output []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return fmt.Errorf("%s: %s", command, strings.TrimPrefix(last, "Error: "))
+	}
+	return fmt.Errorf("%s: %w", command, err)
+}
+
+// parseAuditOutput parses the output of `brew audit`: the name of each package with problems,
+// followed by its problems as "  * message" for formulae, or " - message" after "audit for name: failed" for casks.
+func parseAuditOutput(output string) []tapFinding {
+	var findings []tapFinding
+	current := ""
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "Error:") || strings.HasPrefix(trimmed, "==>"):
+			continue
+		case strings.HasPrefix(trimmed, "audit for ") && strings.HasSuffix(trimmed, ": failed"):
+			current = shortPackageName(strings.TrimSuffix(strings.TrimPrefix(trimmed, "audit for "), ": failed"))
+		case strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "- "):
+			if current != "" {
+				findings = append(findings, tapFinding{Package: current, Source: "audit", Message: trimmed[2:]})
+			}
+		case line == trimmed && !strings.Contains(trimmed, " "):
+			current = shortPackageName(strings.TrimSuffix(trimmed, ":"))
+		}
+	}
+	return findings
+}
+
+// parseStyleOutput parses the offenses of `brew style`, naming the package by its file.
+func parseStyleOutput(output string) []tapFinding {
+	var findings []tapFinding
+	for _, line := range strings.Split(output, "\n") {
+		match := styleLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		findings = append(findings, tapFinding{
+			Package: strings.TrimSuffix(filepath.Base(match[1]), ".rb"),
+			Source:  "style",
+			Message: fmt.Sprintf("line %s, col %s: %s", match[2], match[3], match[4]),
+		})
+	}
+	return findings
+}
+
+// shortPackageName returns the name of a package without its tap, e.g. "foo" for "user/tap/foo".
+func shortPackageName(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
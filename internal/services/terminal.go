@@ -0,0 +1,248 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// notificationProtocol identifies the OSC sequence used for desktop notifications.
+type notificationProtocol int
+
+const (
+	notifyNone   notificationProtocol = iota
+	notifyOSC9                        // iTerm2, WezTerm, Ghostty, kitty, Windows Terminal
+	notifyOSC777                      // VTE-based terminals, foot, urxvt
+)
+
+// Completion alerts of long operations (completion_alert setting), for users who switch windows meanwhile.
+const (
+	alertBell  = "bell"  // Rings the terminal bell
+	alertFlash = "flash" // Flashes the screen (reverse video)
+)
+
+// completionAlertAfter is how long an operation must run for its completion to trigger the alert.
+const completionAlertAfter = 10 * time.Second
+
+// TerminalServiceInterface defines the contract for terminal integration
+// (window title, progress and notifications), so that users who background
+// the TUI in another tab or tmux window can see its state at a glance.
+type TerminalServiceInterface interface {
+	SetActivity(activity string)
+	SetProgress(current, total int)
+	ClearActivity()
+	Done(message string)
+	Copy(text string)
+	SetCompletionAlert(alert string)
+	SetReducedMotion(reduced bool)
+	Restore()
+}
+
+// TerminalService writes title and OSC sequences to the terminal.
+// Sequences are written from the tview event loop, so they never interleave with screen updates.
+type TerminalService struct {
+	app      *tview.Application
+	mu       sync.Mutex
+	screen   tcell.Screen
+	protocol notificationProtocol
+	inTmux   bool
+
+	pending  []string // Sequences waiting for the event loop to write them, in order
+	flushing bool     // Whether a flush of the pending sequences is queued
+
+	alert   string    // Completion alert, "" for none
+	reduced bool      // Reduced motion: no progress, flash nor desktop notification
+	started time.Time // Start of the current activity, zero when idle
+}
+
+// NewTerminalService creates a new TerminalService, detecting the terminal capabilities.
+var NewTerminalService = func(app *tview.Application) TerminalServiceInterface {
+	t := &TerminalService{
+		app:      app,
+		protocol: detectNotificationProtocol(),
+		inTmux:   os.Getenv("TMUX") != "",
+	}
+
+	// Capture the screen once it exists, to get access to the underlying tty
+	app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		t.mu.Lock()
+		t.screen = screen
+		t.mu.Unlock()
+	})
+
+	app.SetTitle(AppName)
+	return t
+}
+
+// detectNotificationProtocol guesses the supported notification sequence from the environment.
+func detectNotificationProtocol() notificationProtocol {
+	termProgram := os.Getenv("TERM_PROGRAM")
+	term := os.Getenv("TERM")
+
+	switch {
+	case termProgram == "iTerm.app", termProgram == "WezTerm", termProgram == "ghostty",
+		strings.Contains(term, "kitty"), os.Getenv("WT_SESSION") != "":
+		return notifyOSC9
+	case os.Getenv("VTE_VERSION") != "", strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "rxvt"):
+		return notifyOSC777
+	}
+	return notifyNone
+}
+
+// SetCompletionAlert sets the alert of the completion of long operations: "bell", "flash" or "" for none.
+func (t *TerminalService) SetCompletionAlert(alert string) {
+	t.mu.Lock()
+	t.alert = alert
+	t.mu.Unlock()
+}
+
+// SetReducedMotion turns off the progress of the tab, the flash alert (the bell rings instead) and the
+// desktop notifications, which come and go on their own.
+func (t *TerminalService) SetReducedMotion(reduced bool) {
+	t.mu.Lock()
+	t.reduced = reduced
+	t.mu.Unlock()
+}
+
+// SetActivity shows the current activity in the terminal title, e.g. "Bold Brew — upgrading 4 packages…".
+func (t *TerminalService) SetActivity(activity string) {
+	t.mu.Lock()
+	if t.started.IsZero() {
+		t.started = time.Now()
+	}
+	t.mu.Unlock()
+	t.app.SetTitle(fmt.Sprintf("%s — %s", AppName, activity))
+}
+
+// SetProgress reports the progress of a running operation (OSC 9;4, shown in the tab or taskbar).
+func (t *TerminalService) SetProgress(current, total int) {
+	t.mu.Lock()
+	reduced := t.reduced
+	t.mu.Unlock()
+	if t.protocol != notifyOSC9 || total <= 0 || reduced {
+		return
+	}
+	t.write(fmt.Sprintf("\x1b]9;4;1;%d\x07", current*100/total))
+}
+
+// ClearActivity resets the title and progress without notifying.
+func (t *TerminalService) ClearActivity() {
+	t.mu.Lock()
+	t.started = time.Time{}
+	t.mu.Unlock()
+	t.app.SetTitle(AppName)
+	if t.protocol == notifyOSC9 {
+		t.write("\x1b]9;4;0\x07")
+	}
+}
+
+// Done resets the title and progress, and sends a desktop notification with the outcome, except with reduced motion.
+// Operations that ran long enough also trigger the completion alert.
+func (t *TerminalService) Done(message string) {
+	t.mu.Lock()
+	alert, started, reduced := t.alert, t.started, t.reduced
+	t.mu.Unlock()
+	t.ClearActivity()
+
+	if !started.IsZero() && time.Since(started) >= completionAlertAfter {
+		if reduced && alert == alertFlash {
+			alert = alertBell
+		}
+		t.alertCompletion(alert)
+	}
+	if reduced {
+		return
+	}
+
+	switch t.protocol {
+	case notifyOSC9:
+		t.write(fmt.Sprintf("\x1b]9;%s: %s\x07", AppName, sanitizeOSC(message)))
+	case notifyOSC777:
+		t.write(fmt.Sprintf("\x1b]777;notify;%s;%s\x07", AppName, sanitizeOSC(message)))
+	}
+}
+
+// alertCompletion rings the bell or flashes the screen.
+func (t *TerminalService) alertCompletion(alert string) {
+	switch alert {
+	case alertBell:
+		go t.app.QueueUpdate(func() {
+			t.mu.Lock()
+			screen := t.screen
+			t.mu.Unlock()
+			if screen != nil {
+				_ = screen.Beep()
+			}
+		})
+	case alertFlash:
+		t.write("\x1b[?5h") // Reverse video on, then off
+		time.AfterFunc(150*time.Millisecond, func() { t.write("\x1b[?5l") })
+	}
+}
+
+// Restore gives the terminal back to the shell, leaving the alternate screen and raw mode.
+// It doesn't go through the event loop, which may be dead after a crash: the screen can't be used afterwards.
+func (t *TerminalService) Restore() {
+	t.mu.Lock()
+	screen := t.screen
+	t.mu.Unlock()
+	if screen != nil {
+		screen.Fini() // Safe to call more than once
+	}
+}
+
+// Copy puts text on the clipboard through the terminal (OSC 52), which also works over SSH.
+// Terminals that don't support it ignore the sequence.
+func (t *TerminalService) Copy(text string) {
+	t.write("\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07")
+}
+
+// write sends a raw sequence to the terminal from the event loop, wrapping it for tmux passthrough.
+// It doesn't wait for the event loop, as it's also called from there (key handlers, modal callbacks),
+// where waiting would never return: the sequences are kept in order until the queued flush writes them.
+func (t *TerminalService) write(sequence string) {
+	if t.inTmux {
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+
+	t.mu.Lock()
+	t.pending = append(t.pending, sequence)
+	queued := t.flushing
+	t.flushing = true
+	t.mu.Unlock()
+	if !queued {
+		go t.app.QueueUpdate(t.flush)
+	}
+}
+
+// flush writes the pending sequences to the tty, from the event loop.
+func (t *TerminalService) flush() {
+	t.mu.Lock()
+	screen, pending := t.screen, t.pending
+	t.pending, t.flushing = nil, false
+	t.mu.Unlock()
+	if screen == nil {
+		return
+	}
+	if tty, ok := screen.Tty(); ok {
+		for _, sequence := range pending {
+			_, _ = tty.Write([]byte(sequence))
+		}
+	}
+}
+
+// sanitizeOSC strips characters that would terminate or break an OSC sequence.
+func sanitizeOSC(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return ' '
+		}
+		return r
+	}, text)
+}
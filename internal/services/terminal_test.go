@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestCopyFromEventLoop(t *testing.T) {
+	app := tview.NewApplication().SetScreen(tcell.NewSimulationScreen(""))
+	terminal := NewTerminalService(app).(*TerminalService)
+
+	go app.Run()
+	defer app.Stop()
+
+	runOnEventLoop(t, app, func() { terminal.Copy("brew install wget") })
+	waitFlushed(t, terminal)
+}
+
+// waitFlushed waits for the event loop to write the pending sequences of the terminal.
+func waitFlushed(t *testing.T, terminal *TerminalService) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		terminal.mu.Lock()
+		flushing := terminal.flushing
+		terminal.mu.Unlock()
+		if !flushing {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the pending sequences were never written")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// copyRecorder reports the texts copied through the terminal.
+type copyRecorder struct {
+	*TerminalService
+	copied chan string
+}
+
+func (r *copyRecorder) Copy(text string) {
+	r.TerminalService.Copy(text)
+	r.copied <- text
+}
+
+// recordCopies replaces the terminal of s with one reporting the copied texts.
+func recordCopies(s *AppService) *copyRecorder {
+	recorder := &copyRecorder{TerminalService: s.terminal.(*TerminalService), copied: make(chan string, 1)}
+	s.terminal = recorder
+	return recorder
+}
+
+// waitCopied waits for want to be copied, then for the event loop to write it to the terminal.
+func waitCopied(t *testing.T, app *tview.Application, recorder *copyRecorder, want string) {
+	t.Helper()
+	select {
+	case got := <-recorder.copied:
+		if got != want {
+			t.Fatalf("copied %q, want %q", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%q was never copied", want)
+	}
+	runOnEventLoop(t, app, func() {})
+	waitFlushed(t, recorder.TerminalService)
+}
+
+// pressKeys sends key presses to the focused primitive, through the event loop.
+func pressKeys(app *tview.Application, keys ...*tcell.EventKey) {
+	go func() {
+		for _, key := range keys {
+			app.QueueEvent(key)
+		}
+	}()
+}
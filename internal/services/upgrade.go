@@ -0,0 +1,332 @@
+package services
+
+import (
+	"bbrew/internal/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// UpgradePolicy decides what the scheduled upgrade runner does with an outdated package.
+type UpgradePolicy string
+
+const (
+	PolicyAsk  UpgradePolicy = "ask"  // Update All confirms, the scheduled runner only notifies (default)
+	PolicyAuto UpgradePolicy = "auto" // Upgrade without asking
+	PolicyHold UpgradePolicy = "hold" // Leave the package alone, Update All included
+)
+
+// upgradePolicyAliases maps the alternative names accepted in the config to their policy.
+var upgradePolicyAliases = map[UpgradePolicy]UpgradePolicy{
+	"notify": PolicyAsk,
+	"never":  PolicyHold,
+}
+
+// upgradePolicyLabels describes each policy in the UI.
+var upgradePolicyLabels = map[UpgradePolicy]string{
+	PolicyAsk:  "ask (notify only)",
+	PolicyAuto: "auto-upgrade",
+	PolicyHold: "hold (never upgrade)",
+}
+
+// upgradePolicyTags are the short labels of the Policy column; the default policy has none.
+var upgradePolicyTags = map[UpgradePolicy]string{
+	PolicyAuto: "auto",
+	PolicyHold: "hold",
+}
+
+// upgradePolicyCycle is the order the policy key cycles through.
+var upgradePolicyCycle = []UpgradePolicy{PolicyAsk, PolicyAuto, PolicyHold}
+
+// nextUpgradePolicy returns the policy that follows the given one in the cycle.
+func nextUpgradePolicy(policy UpgradePolicy) UpgradePolicy {
+	for i, p := range upgradePolicyCycle {
+		if p == policy {
+			return upgradePolicyCycle[(i+1)%len(upgradePolicyCycle)]
+		}
+	}
+	return upgradePolicyCycle[0]
+}
+
+// isHeld reports whether an installed package is held by its upgrade policy: Update All, `bbrew upgrade`
+// and the outdated count of the header leave it alone. This is how casks, which brew can't pin, are held.
+func (s *AppService) isHeld(pkg models.Package) bool {
+	return pkg.LocallyInstalled && s.config.UpgradePolicyOf(pkg.Name) == PolicyHold
+}
+
+// heldPackages splits the outdated packages into those Update All upgrades and those
+// held by their upgrade policy. Pinned packages are left to brew, which skips them.
+func (s *AppService) heldPackages() (upgradable, held []models.Package) {
+	for _, pkg := range s.store.All() {
+		if !pkg.LocallyInstalled || !pkg.Outdated {
+			continue
+		}
+		if s.isHeld(pkg) {
+			held = append(held, pkg)
+		} else if pkg.Formula == nil || !pkg.Formula.Pinned {
+			upgradable = append(upgradable, pkg)
+		}
+	}
+	return upgradable, held
+}
+
+const (
+	// scheduleLabel identifies the launchd agent and the systemd units of the scheduled runner.
+	scheduleLabel = "com.bold-brew.upgrade"
+	scheduleUnit  = "bbrew-upgrade"
+)
+
+// outdatedPackage is an entry of `brew outdated --json=v2`.
+type outdatedPackage struct {
+	Name              string   `json:"name"`
+	InstalledVersions []string `json:"installed_versions"`
+	CurrentVersion    string   `json:"current_version"`
+	Pinned            bool     `json:"pinned"`
+	isCask            bool
+}
+
+// fetchOutdatedPackages lists the outdated formulae and casks.
+func fetchOutdatedPackages() ([]outdatedPackage, error) {
+	output, err := brewCommand("outdated", "--json=v2").Output()
+	if err != nil {
+		return nil, fmt.Errorf("brew outdated failed: %w", err)
+	}
+
+	var result struct {
+		Formulae []outdatedPackage `json:"formulae"`
+		Casks    []outdatedPackage `json:"casks"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse brew outdated: %w", err)
+	}
+	for i := range result.Casks {
+		result.Casks[i].isCask = true
+	}
+	return append(result.Formulae, result.Casks...), nil
+}
+
+// upgradeRun is the outcome of a scheduled upgrade: the packages upgraded, failed to upgrade,
+// and left to the user by their policy.
+type upgradeRun struct {
+	upgraded, failed, pending []string
+}
+
+// RunScheduledUpgrade checks the outdated packages once and applies their upgrade policies:
+// "auto" packages are upgraded, "ask" ones are reported with a desktop notification.
+// It runs without the TUI, from `bbrew upgrade` or its scheduled job.
+func RunScheduledUpgrade(out io.Writer) error {
+	_, err := scheduledUpgrade(LoadConfig(), out)
+	return err
+}
+
+// scheduledUpgrade runs a scheduled upgrade with the given config, reporting it to the webhook if any.
+func scheduledUpgrade(config *Config, out io.Writer) (upgradeRun, error) {
+	var run upgradeRun
+	// Every brew command runs without auto-update (see brewCommand): refresh the metadata first,
+	// or an unattended machine never sees the new versions
+	if output, err := brewCommand("update").CombinedOutput(); err != nil {
+		brewLog.Warn("brew update failed before the scheduled upgrade", "err", commandFailure("brew update", err, output))
+	}
+
+	outdated, err := fetchOutdatedPackages()
+	if err != nil {
+		report := newWebhookReport("scheduled_upgrade", "Upgrading")
+		report.Summary, report.Error = "the scheduled upgrade failed", err.Error()
+		if webhookErr := sendWebhook(config.Webhook, report); webhookErr != nil {
+			fmt.Fprintf(out, "Failed to send the webhook: %v\n", webhookErr)
+		}
+		runUpgradeHook(config, out, run, err)
+		return run, err
+	}
+
+	for _, pkg := range outdated {
+		if pkg.Pinned {
+			continue // Pinned packages are held by brew itself
+		}
+
+		switch config.UpgradePolicyOf(pkg.Name) {
+		case PolicyHold:
+			continue
+		case PolicyAuto:
+			fmt.Fprintf(out, "Upgrading %s to %s...\n", pkg.Name, pkg.CurrentVersion)
+			args := []string{"upgrade", pkg.Name}
+			if pkg.isCask {
+				args = []string{"upgrade", "--cask", pkg.Name}
+			}
+			cmd := brewCommand(args...)
+			cmd.Stdout, cmd.Stderr = out, out
+			if err := cmd.Run(); err != nil {
+				fmt.Fprintf(out, "Failed to upgrade %s: %v\n", pkg.Name, err)
+				run.failed = append(run.failed, pkg.Name)
+			} else {
+				run.upgraded = append(run.upgraded, pkg.Name)
+			}
+		default:
+			fmt.Fprintf(out, "%s: %s -> %s\n", pkg.Name, strings.Join(pkg.InstalledVersions, ", "), pkg.CurrentVersion)
+			run.pending = append(run.pending, pkg.Name)
+		}
+	}
+
+	var summary []string
+	if len(run.upgraded) > 0 {
+		summary = append(summary, "Upgraded: "+strings.Join(run.upgraded, ", "))
+	}
+	if len(run.failed) > 0 {
+		summary = append(summary, "Failed: "+strings.Join(run.failed, ", "))
+	}
+	if len(run.pending) > 0 {
+		summary = append(summary, "Updates available: "+strings.Join(run.pending, ", "))
+	}
+	if len(summary) == 0 {
+		fmt.Fprintln(out, "Everything is up to date.")
+		return run, nil
+	}
+
+	message := strings.Join(summary, "\n")
+	fmt.Fprintln(out, message)
+	sendDesktopNotification(AppName, message)
+
+	report := newWebhookReport("scheduled_upgrade", "Upgrading")
+	report.Summary = strings.Join(summary, "; ")
+	report.Succeeded, report.Failed, report.Pending = run.upgraded, run.failed, run.pending
+	if err := sendWebhook(config.Webhook, report); err != nil {
+		fmt.Fprintf(out, "Failed to send the webhook: %v\n", err)
+	}
+	if len(run.upgraded)+len(run.failed) > 0 {
+		runUpgradeHook(config, out, run, nil)
+	}
+	return run, nil
+}
+
+// runUpgradeHook runs the post_update_all hook after a scheduled upgrade that upgraded packages or failed.
+func runUpgradeHook(config *Config, out io.Writer, run upgradeRun, err error) {
+	if config.Hooks == nil {
+		return
+	}
+	if err == nil && len(run.failed) > 0 {
+		err = fmt.Errorf("failed to upgrade %s", strings.Join(run.failed, ", "))
+	}
+	env := append(resultHookEnv(err),
+		"BBREW_PACKAGES="+strings.Join(append(slices.Clone(run.upgraded), run.failed...), " "),
+		"BBREW_FAILED="+strings.Join(run.failed, " "))
+	runScheduledHook(out, "post_update_all", config.Hooks.PostUpdateAll, env)
+}
+
+// sendDesktopNotification shows a notification outside of a terminal (the scheduled job has none):
+// through osascript on macOS, or notify-send on Linux when available.
+func sendDesktopNotification(title, message string) {
+	switch GetPlatform().OS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		_ = exec.Command("osascript", "-e", script).Run() // #nosec G204 -- fixed command, quoted arguments
+	case "linux":
+		if path, err := exec.LookPath("notify-send"); err == nil {
+			_ = exec.Command(path, title, message).Run() // #nosec G204 -- fixed command
+		}
+	}
+}
+
+// InstallUpgradeSchedule writes a launchd agent (macOS) or a systemd user timer (Linux)
+// that runs `bbrew upgrade` at the given interval. It returns the written files and
+// the command that enables the schedule.
+func InstallUpgradeSchedule(interval time.Duration) (files []string, enable string, err error) {
+	if interval < time.Hour {
+		return nil, "", fmt.Errorf("the interval must be at least one hour")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, "", err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, "", err
+	}
+
+	units := map[string]string{}
+	switch GetPlatform().OS {
+	case "darwin":
+		path := filepath.Join(home, "Library", "LaunchAgents", scheduleLabel+".plist")
+		units[path] = launchdAgent(executable, interval, filepath.Join(home, "Library", "Logs", scheduleUnit+".log"))
+		enable = "launchctl load -w " + path
+	case "linux":
+		dir := filepath.Join(home, ".config", "systemd", "user")
+		units[filepath.Join(dir, scheduleUnit+".service")] = systemdService(executable)
+		units[filepath.Join(dir, scheduleUnit+".timer")] = systemdTimer(interval)
+		enable = "systemctl --user daemon-reload && systemctl --user enable --now " + scheduleUnit + ".timer"
+	default:
+		return nil, "", fmt.Errorf("scheduling is not supported on %s", GetPlatform().OS)
+	}
+
+	for path, content := range units {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return nil, "", err
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, "", err
+		}
+		files = append(files, path)
+	}
+	return files, enable, nil
+}
+
+// launchdAgent returns a launchd agent running `bbrew upgrade` every interval, its output written
+// to logPath. Homebrew's bin directories are added to PATH, since launchd starts jobs with a minimal one.
+func launchdAgent(executable string, interval time.Duration, logPath string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>%s</string>
+		<string>upgrade</string>
+	</array>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>PATH</key>
+		<string>/opt/homebrew/bin:/usr/local/bin:/usr/bin:/bin</string>
+	</dict>
+	<key>StartInterval</key>
+	<integer>%d</integer>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, scheduleLabel, executable, int(interval.Seconds()), logPath, logPath)
+}
+
+// systemdService returns a systemd user service running `bbrew upgrade` once.
+func systemdService(executable string) string {
+	return fmt.Sprintf(`[Unit]
+Description=Bold Brew scheduled upgrade
+
+[Service]
+Type=oneshot
+Environment=PATH=/home/linuxbrew/.linuxbrew/bin:/usr/local/bin:/usr/bin:/bin
+ExecStart=%s upgrade
+`, systemdQuote(executable))
+}
+
+// systemdTimer returns a systemd user timer starting the service every interval.
+func systemdTimer(interval time.Duration) string {
+	return fmt.Sprintf(`[Unit]
+Description=Run Bold Brew scheduled upgrade every %s
+
+[Timer]
+OnBootSec=15min
+OnUnitActiveSec=%ds
+
+[Install]
+WantedBy=timers.target
+`, interval, int(interval.Seconds()))
+}
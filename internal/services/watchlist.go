@@ -0,0 +1,143 @@
+package services
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/models"
+	"bbrew/internal/ui/components"
+	"encoding/json"
+	"sync"
+)
+
+// watchlistFileName stores the watched packages and their last seen versions, in the state directory.
+const watchlistFileName = "watchlist.json"
+
+// WatchChange is a new version of a watched package since it was last seen.
+type WatchChange struct {
+	Package     models.Package
+	SeenVersion string
+}
+
+// WatchlistServiceInterface defines the contract for watched packages.
+type WatchlistServiceInterface interface {
+	IsWatched(pkg models.Package) bool
+	Toggle(pkg models.Package) (bool, error)
+	Changes(packages []models.Package) []WatchChange
+	MarkSeen(packages []models.Package) error
+}
+
+// WatchlistService keeps the packages the user watches, installed or not,
+// with the version they last saw to report new versions on refresh.
+type WatchlistService struct {
+	mu      sync.Mutex
+	watched map[string]string // Last seen version, keyed by "type/name"
+}
+
+// NewWatchlistService creates a new instance of WatchlistService, loading the saved watchlist.
+var NewWatchlistService = func() WatchlistServiceInterface {
+	w := &WatchlistService{watched: make(map[string]string)}
+	if data := readStateFile(watchlistFileName); data != nil {
+		_ = json.Unmarshal(data, &w.watched)
+	}
+	return w
+}
+
+// IsWatched returns true if the package is on the watchlist.
+func (w *WatchlistService) IsWatched(pkg models.Package) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, watched := w.watched[userDataKey(pkg)]
+	return watched
+}
+
+// Toggle adds a package to the watchlist, seen at its current version, or removes it.
+// Returns whether the package is now watched.
+func (w *WatchlistService) Toggle(pkg models.Package) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := userDataKey(pkg)
+	_, watched := w.watched[key]
+	if watched {
+		delete(w.watched, key)
+	} else {
+		w.watched[key] = pkg.Version
+	}
+	return !watched, w.save()
+}
+
+// Changes returns the watched packages whose version differs from the last seen one.
+func (w *WatchlistService) Changes(packages []models.Package) []WatchChange {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changes []WatchChange
+	for _, pkg := range packages {
+		if seen, watched := w.watched[userDataKey(pkg)]; watched && pkg.Version != "" && seen != pkg.Version {
+			changes = append(changes, WatchChange{Package: pkg, SeenVersion: seen})
+		}
+	}
+	return changes
+}
+
+// MarkSeen records the current version of the given watched packages as seen.
+func (w *WatchlistService) MarkSeen(packages []models.Package) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, pkg := range packages {
+		if _, watched := w.watched[userDataKey(pkg)]; watched {
+			w.watched[userDataKey(pkg)] = pkg.Version
+		}
+	}
+	return w.save()
+}
+
+// save writes the watchlist to the state directory. Callers hold the lock.
+func (w *WatchlistService) save() error {
+	data, err := json.MarshalIndent(w.watched, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeStateFile(watchlistFileName, data)
+}
+
+// checkWatchlist counts the watched packages with a new version after a refresh,
+// notifying the user when new ones show up. Runs in the event loop.
+func (s *AppService) checkWatchlist() {
+	changes := len(s.watchlist.Changes(s.store.All()))
+	if changes > s.watchChanges {
+		s.layout.GetNotifier().ShowWarning(i18n.T("%d watched packages have new versions (press V)", changes))
+	}
+	if changes != s.watchChanges {
+		s.watchChanges = changes
+		s.updateHeader()
+	}
+}
+
+// watchItems returns the watched packages with their last seen and current versions.
+func (s *AppService) watchItems() []components.WatchItem {
+	seen := make(map[string]string)
+	for _, change := range s.watchlist.Changes(s.store.All()) {
+		seen[userDataKey(change.Package)] = change.SeenVersion
+	}
+
+	var items []components.WatchItem
+	for _, pkg := range s.store.All() {
+		if !s.watchlist.IsWatched(pkg) {
+			continue
+		}
+		item := components.WatchItem{Name: pkg.Name, SeenVersion: pkg.Version, Version: pkg.Version}
+		if version, changed := seen[userDataKey(pkg)]; changed {
+			item.SeenVersion = version
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// markWatchlistSeen records the current versions of the watched packages as seen, clearing the header badge.
+func (s *AppService) markWatchlistSeen() error {
+	s.watchChanges = 0
+	s.updateHeader()
+	return s.watchlist.MarkSeen(s.store.All())
+}
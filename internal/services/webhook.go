@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// WebhookConfig posts a message to a URL when a Brewfile batch or a scheduled upgrade finishes,
+// to follow the provisioning of several machines from one place (e.g. a Slack or Discord channel).
+type WebhookConfig struct {
+	// URL receives a POST with a JSON body: {"text": ...} for Slack, {"content": ...} for Discord
+	// (from the host of the URL), else the fields of the report with the message as "text".
+	URL string `json:"url"`
+
+	// Template is the text/template of the message, run on a webhookReport. Defaults to defaultWebhookTemplate.
+	Template string `json:"template,omitempty"`
+
+	// OnlyFailures skips the runs without failures.
+	OnlyFailures bool `json:"only_failures,omitempty"`
+}
+
+// defaultWebhookTemplate is the message of the webhook when the config sets no template.
+const defaultWebhookTemplate = `{{.Host}}: {{.Summary}}{{if .Error}}: {{.Error}}{{end}}{{if .Brewfile}} ({{.Brewfile}}){{end}}` +
+	`{{if .Failed}}
+Failed: {{join .Failed ", "}}{{end}}`
+
+// webhookReport is the outcome of a run, given to the template and sent as is to generic webhooks.
+type webhookReport struct {
+	Host      string    `json:"host"`
+	Event     string    `json:"event"` // "batch" or "scheduled_upgrade"
+	Operation string    `json:"operation"`
+	Brewfile  string    `json:"brewfile,omitempty"`
+	Summary   string    `json:"summary"`
+	Succeeded []string  `json:"succeeded"`
+	Failed    []string  `json:"failed"`
+	Skipped   int       `json:"skipped"`
+	Pending   []string  `json:"pending,omitempty"` // Scheduled upgrades: updates left to the user
+	Error     string    `json:"error,omitempty"`   // The run failed as a whole
+	Time      time.Time `json:"time"`
+}
+
+// newWebhookReport returns a report of the given event, for this host and now.
+func newWebhookReport(event, operation string) webhookReport {
+	host, _ := os.Hostname()
+	return webhookReport{Host: host, Event: event, Operation: operation, Time: time.Now()}
+}
+
+// sendWebhook posts the report to the webhook of the config, if any. Runs without failure are
+// skipped with only_failures. It blocks up to 10 seconds.
+func sendWebhook(config *WebhookConfig, report webhookReport) error {
+	failed := len(report.Failed) > 0 || report.Error != ""
+	if config == nil || config.URL == "" || (config.OnlyFailures && !failed) {
+		return nil
+	}
+
+	body, err := webhookPayload(config, report)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(config.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// webhookPayload renders the message of the report and wraps it in the JSON body expected by the webhook.
+func webhookPayload(config *WebhookConfig, report webhookReport) ([]byte, error) {
+	text := config.Template
+	if text == "" {
+		text = defaultWebhookTemplate
+	}
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("webhook template: %w", err)
+	}
+	var message strings.Builder
+	if err := tmpl.Execute(&message, report); err != nil {
+		return nil, fmt.Errorf("webhook template: %w", err)
+	}
+
+	host := ""
+	if u, err := url.Parse(config.URL); err == nil {
+		host = u.Hostname()
+	}
+	switch {
+	case host == "hooks.slack.com":
+		return json.Marshal(map[string]string{"text": message.String()})
+	case host == "discord.com" || host == "discordapp.com":
+		return json.Marshal(map[string]string{"content": message.String()})
+	}
+	return json.Marshal(struct {
+		webhookReport
+		Text string `json:"text"`
+	}{report, message.String()})
+}
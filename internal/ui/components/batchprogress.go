@@ -0,0 +1,227 @@
+package components
+
+import (
+	"bbrew/internal/events"
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// batchBarWidth is the width of the progress bar, in cells.
+const batchBarWidth = 30
+
+// BatchProgress displays a modal overlay with the progress of a batch operation:
+// a progress bar and the status of each package, then a summary once the batch is over.
+type BatchProgress struct {
+	pages    *tview.Pages
+	view     *tview.TextView
+	hint     *tview.TextView
+	theme    *theme.Theme
+	title    string
+	names    []string
+	steps    []events.Progress // Last step of each package, by index
+	finished string            // Summary, once the batch is over
+}
+
+// NewBatchProgress creates a new batch progress component
+func NewBatchProgress(theme *theme.Theme) *BatchProgress {
+	return &BatchProgress{
+		theme: theme,
+	}
+}
+
+// View returns the batch progress pages (for overlay functionality)
+func (b *BatchProgress) View() *tview.Pages {
+	return b.pages
+}
+
+// HasFocus returns true if the batch progress is currently open and focused
+func (b *BatchProgress) HasFocus() bool {
+	return b.view != nil && b.view.HasFocus()
+}
+
+// Start resets the progress for a new batch over the given packages.
+func (b *BatchProgress) Start(title string, names []string) {
+	b.title = title
+	b.names = names
+	b.steps = make([]events.Progress, len(names))
+	b.finished = ""
+	b.render()
+}
+
+// Update records a step of the batch.
+func (b *BatchProgress) Update(progress events.Progress) {
+	if progress.Index < 0 || progress.Index >= len(b.steps) {
+		return
+	}
+	b.steps[progress.Index] = progress
+	b.render()
+}
+
+// Finish marks the batch as over, showing the given summary.
+func (b *BatchProgress) Finish(summary string) {
+	b.finished = summary
+	b.render()
+}
+
+// Failed returns the number of packages that failed so far.
+func (b *BatchProgress) Failed() int {
+	failed := 0
+	for _, step := range b.steps {
+		if step.Phase == events.PhaseFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Build creates the batch progress as an overlay on top of the main content.
+// onClose is called when the overlay is dismissed; the batch keeps running in the background.
+// onRetry is called when the user asks to retry the failed packages of a finished batch.
+func (b *BatchProgress) Build(mainContent tview.Primitive, onClose, onRetry func()) *tview.Pages {
+	b.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	b.view.SetBackgroundColor(b.theme.ModalBgColor)
+	b.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'F' && b.finished != "" && b.Failed() > 0:
+			onRetry()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	b.hint = tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	b.hint.SetBackgroundColor(b.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(b.view, 0, 1, true).
+		AddItem(b.hint, 1, 0, false)
+	content.SetBackgroundColor(b.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(b.theme.BorderColor).
+		SetTitle(" " + b.title + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the progress as overlay
+	b.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("batch", centered, true, true)
+
+	b.render()
+	return b.pages
+}
+
+// render redraws the progress bar and the package list
+func (b *BatchProgress) render() {
+	if b.view == nil {
+		return
+	}
+
+	completed := 0
+	for _, step := range b.steps {
+		if step.Phase != "" && step.Phase != events.PhaseRunning {
+			completed++
+		}
+	}
+
+	var sb strings.Builder
+	filled := 0
+	if len(b.steps) > 0 {
+		filled = completed * batchBarWidth / len(b.steps)
+	}
+	if b.theme.ReducedMotion {
+		// A static count instead of a moving bar
+		sb.WriteString(i18n.T("%d of %d done", completed, len(b.steps)) + "\n\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("[%s]%s[-]%s %d/%d\n\n",
+			theme.ColorTag(b.theme.SuccessColor), strings.Repeat(b.theme.Symbols.BarFull, filled),
+			strings.Repeat(b.theme.Symbols.BarEmpty, batchBarWidth-filled), completed, len(b.steps)))
+	}
+
+	if b.finished == "" {
+		for i, name := range b.names {
+			sb.WriteString(b.formatStep(name, b.steps[i]))
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf("[%s::b]%s[-:-:-]\n", theme.ColorTag(b.theme.SuccessColor), tview.Escape(b.finished)))
+		b.writeSummary(&sb)
+	}
+	b.view.SetText(sb.String())
+
+	hint := i18n.T("esc: hide (keeps running)")
+	if b.finished != "" && b.Failed() > 0 {
+		hint = i18n.T("F: retry failed | esc: close")
+	} else if b.finished != "" {
+		hint = i18n.T("esc: close")
+	}
+	b.hint.SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(b.theme.LegendColor), hint))
+}
+
+// writeSummary lists the packages of a finished batch by outcome
+func (b *BatchProgress) writeSummary(sb *strings.Builder) {
+	sections := []struct {
+		phase events.Phase
+		title string
+	}{
+		{events.PhaseFailed, i18n.T("Failed")},
+		{events.PhaseDone, i18n.T("Succeeded")},
+		{events.PhaseSkipped, i18n.T("Skipped")},
+	}
+	for _, section := range sections {
+		var lines strings.Builder
+		count := 0
+		for i, name := range b.names {
+			if b.steps[i].Phase == section.phase {
+				lines.WriteString(b.formatStep(name, b.steps[i]))
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n[%s::b]%s (%d)[-:-:-]\n", theme.ColorTag(b.theme.SectionTitleColor), section.title, count))
+		sb.WriteString(lines.String())
+	}
+}
+
+// formatStep formats the status line of a package
+func (b *BatchProgress) formatStep(name string, step events.Progress) string {
+	name = tview.Escape(name)
+	switch step.Phase {
+	case events.PhaseRunning:
+		return fmt.Sprintf(" [%s]%s %s[-]\n", theme.ColorTag(b.theme.WarningColor), b.theme.Symbols.Arrow, name)
+	case events.PhaseDone:
+		return fmt.Sprintf(" [%s]%s[-] %s\n", theme.ColorTag(b.theme.SuccessColor), b.theme.Symbols.Check, name)
+	case events.PhaseSkipped:
+		return fmt.Sprintf(" [%s]- %s (%s)[-]\n", theme.ColorTag(b.theme.UnavailableColor), name, tview.Escape(step.Reason))
+	case events.PhaseFailed:
+		return fmt.Sprintf(" [%s]%s %s: %s[-]\n", theme.ColorTag(b.theme.ErrorColor), b.theme.Symbols.Cross, name, tview.Escape(step.Err.Error()))
+	}
+	return "   " + name + "\n"
+}
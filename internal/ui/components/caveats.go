@@ -0,0 +1,103 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Caveats displays a modal overlay with the caveats of a newly installed package
+// (PATH additions, launch agents, ...), so that they don't get lost in the output
+type Caveats struct {
+	pages *tview.Pages
+	view  *tview.TextView
+	theme *theme.Theme
+}
+
+// NewCaveats creates a new caveats component
+func NewCaveats(theme *theme.Theme) *Caveats {
+	return &Caveats{
+		theme: theme,
+	}
+}
+
+// View returns the caveats pages (for overlay functionality)
+func (c *Caveats) View() *tview.Pages {
+	return c.pages
+}
+
+// HasFocus returns true if the caveats are currently open and focused
+func (c *Caveats) HasFocus() bool {
+	return c.view != nil && c.view.HasFocus()
+}
+
+// Build creates the caveats of a package as an overlay on top of the main content.
+// snippet holds the shell lines the caveats suggest; onCopy is called when the user copies them
+// and is only offered when there are some. rcFile is the shell profile the caveats ask to edit, if any:
+// onAppend is then called when the user adds the lines to it. onClose is called when the overlay is dismissed.
+func (c *Caveats) Build(mainContent tview.Primitive, name, caveats, snippet, rcFile string,
+	onCopy, onAppend, onClose func()) *tview.Pages {
+	text := tview.Escape(caveats)
+	if snippet != "" {
+		text += fmt.Sprintf("\n\n[%s::b]%s[-:-:-]\n[%s]%s[-]", theme.ColorTag(c.theme.SectionTitleColor), i18n.T("Suggested commands"),
+			theme.ColorTag(c.theme.SuccessColor), tview.Escape(snippet))
+	}
+
+	c.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true).
+		SetText(text)
+	c.view.SetBackgroundColor(c.theme.ModalBgColor)
+	c.view.SetTextColor(c.theme.DefaultTextColor)
+
+	c.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Key() == tcell.KeyEnter, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'c' && snippet != "":
+			onCopy()
+			return nil
+		case event.Rune() == 'a' && rcFile != "":
+			onAppend()
+			return nil
+		}
+		return event // Let the text view scroll
+	})
+
+	hint := i18n.T("enter/esc: close")
+	if snippet != "" {
+		hint = i18n.T("c: copy commands | enter/esc: close")
+	}
+	if rcFile != "" {
+		hint = i18n.T("a: add to %s", rcFile) + " | " + hint
+	}
+	frame := tview.NewFrame(c.view).
+		SetBorders(1, 1, 1, 0, 2, 2).
+		AddText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(c.theme.LegendColor), hint), false, tview.AlignLeft, c.theme.LegendColor)
+	frame.SetBackgroundColor(c.theme.ModalBgColor)
+	frame.SetBorderColor(c.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" " + i18n.T("Caveats for %s", name) + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the frame in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the caveats as overlay
+	c.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("caveats", centered, true, true)
+
+	return c.pages
+}
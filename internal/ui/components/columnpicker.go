@@ -0,0 +1,143 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ColumnItem is a single entry in the column picker.
+type ColumnItem struct {
+	ID      string
+	Label   string
+	Visible bool
+}
+
+// ColumnPicker displays a modal overlay to toggle and reorder table columns
+type ColumnPicker struct {
+	pages *tview.Pages
+	list  *tview.List
+	theme *theme.Theme
+	items []ColumnItem
+}
+
+// NewColumnPicker creates a new column picker component
+func NewColumnPicker(theme *theme.Theme) *ColumnPicker {
+	return &ColumnPicker{
+		theme: theme,
+	}
+}
+
+// View returns the column picker pages (for overlay functionality)
+func (c *ColumnPicker) View() *tview.Pages {
+	return c.pages
+}
+
+// HasFocus returns true if the column picker is currently open and focused
+func (c *ColumnPicker) HasFocus() bool {
+	return c.list != nil && c.list.HasFocus()
+}
+
+// Build creates the column picker as an overlay on top of the main content.
+// onDone is called with the final column order and visibility when the picker is closed.
+func (c *ColumnPicker) Build(mainContent tview.Primitive, items []ColumnItem, onDone func(items []ColumnItem)) *tview.Pages {
+	c.items = make([]ColumnItem, len(items))
+	copy(c.items, items)
+
+	c.list = tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	c.list.SetBackgroundColor(c.theme.ModalBgColor)
+	c.refreshList(0)
+
+	c.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		current := c.list.GetCurrentItem()
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			onDone(c.items)
+			return nil
+		case event.Key() == tcell.KeyEnter || event.Rune() == ' ':
+			c.items[current].Visible = !c.items[current].Visible
+			c.refreshList(current)
+			return nil
+		case event.Rune() == 'K' || (event.Key() == tcell.KeyUp && event.Modifiers()&tcell.ModShift != 0):
+			c.moveItem(current, current-1)
+			return nil
+		case event.Rune() == 'J' || (event.Key() == tcell.KeyDown && event.Modifiers()&tcell.ModShift != 0):
+			c.moveItem(current, current+1)
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", c.getColorTag(c.theme.LegendColor), i18n.T("space: toggle | J/K: move | esc: apply")))
+	hint.SetBackgroundColor(c.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(c.list, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(c.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(c.theme.BorderColor).
+		SetTitle(" " + i18n.T("Columns") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Calculate box dimensions
+	boxHeight := len(c.items) + 3
+	boxWidth := 45
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, boxHeight, 0, true).
+			AddItem(nil, 0, 1, false),
+			boxWidth, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and picker as overlay
+	c.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("columns", centered, true, true)
+
+	return c.pages
+}
+
+// moveItem swaps the item at from with the item at to, keeping the cursor on the moved item.
+func (c *ColumnPicker) moveItem(from, to int) {
+	if to < 0 || to >= len(c.items) {
+		return
+	}
+	c.items[from], c.items[to] = c.items[to], c.items[from]
+	c.refreshList(to)
+}
+
+// refreshList redraws the list entries and restores the cursor position.
+func (c *ColumnPicker) refreshList(current int) {
+	c.list.Clear()
+	for _, item := range c.items {
+		mark := " "
+		if item.Visible {
+			mark = "x"
+		}
+		c.list.AddItem(tview.Escape(fmt.Sprintf("[%s] %s", mark, item.Label)), "", 0, nil)
+	}
+	c.list.SetCurrentItem(current)
+}
+
+// getColorTag converts a tcell.Color to a tview color tag
+func (c *ColumnPicker) getColorTag(color tcell.Color) string {
+	return theme.ColorTag(color)
+}
@@ -1,200 +1,539 @@
 package components
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/models"
 	"bbrew/internal/ui/theme"
+	"bbrew/internal/version"
 	"fmt"
 	"strings"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
 )
 
+// DetailsAction is a key shown in the action bar of the details, e.g. "i" to install.
+type DetailsAction struct {
+	Key   string
+	Label string
+}
+
 type Details struct {
-	view  *tview.TextView
-	theme *theme.Theme
+	box     *tview.Flex     // Bordered frame of the content and the action bar
+	view    *tview.TextView // Content, scrolling
+	actions *tview.TextView // Action bar, at the bottom
+	theme   *theme.Theme
+
+	// Host bottle tags, used to tell whether a formula will be built from source
+	hostBottleTag string
+	hostArch      string
+	bottleTags    []string
+
+	// Host macOS version, used to check cask requirements ("" on Linux)
+	hostMacOS string
+
+	// Returns the cached GitHub metadata of a package, if any
+	githubLookup func(pkg *models.Package) *models.GitHubMetadata
+
+	// Returns the known vulnerabilities of the installed version of a package
+	advisoryLookup func(pkg *models.Package) []models.Vulnerability
+
+	// Returns the personal note attached to a package, if any
+	noteLookup func(pkg *models.Package) string
+
+	// Returns the label of the upgrade policy of a package
+	policyLookup func(pkg *models.Package) string
+
+	// Returns whether a package is protected from removal
+	protectedLookup func(pkg *models.Package) bool
+
+	// Returns whether a package is held at its installed version by its upgrade policy
+	heldLookup func(pkg *models.Package) bool
+
+	// Returns the Linux alternatives of a cask, e.g. "Flatpak org.mozilla.firefox", "" if none
+	alternativeLookup func(pkg *models.Package) string
+
+	// Returns the keys available for a package, for the action bar
+	actionsLookup func(pkg *models.Package) []DetailsAction
+
+	// Returns whether a formula is installed, by name, to tell which build dependencies a source build installs
+	installedLookup func(name string) bool
 }
 
-func NewDetails(theme *theme.Theme) *Details {
+func NewDetails(t *theme.Theme) *Details {
 	details := &Details{
-		view:  tview.NewTextView(),
-		theme: theme,
+		view:    tview.NewTextView(),
+		actions: tview.NewTextView(),
+		theme:   t,
 	}
 
 	details.view.SetDynamicColors(true)
 	details.view.SetTextAlign(tview.AlignLeft)
-	details.view.SetTitle("Details")
-	details.view.SetTitleColor(theme.TitleColor)
-	details.view.SetTitleAlign(tview.AlignLeft)
-	details.view.SetBorder(true)
+	details.view.SetWordWrap(true) // Long descriptions break between words
 	details.view.SetBorderPadding(0, 0, 1, 1)
+	details.actions.SetDynamicColors(true)
+	details.actions.SetBorderPadding(0, 0, 1, 1)
+
+	details.box = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(details.view, 0, 1, false).
+		AddItem(details.actions, 1, 0, false)
+	details.box.SetTitle(i18n.T("Details"))
+	details.box.SetTitleColor(t.TitleColor)
+	details.box.SetTitleAlign(tview.AlignLeft)
+	details.box.SetBorder(true)
 	return details
 }
 
+// SetBottleTags sets the host bottle tag, its architecture label and the tags usable
+// on the host, in order of preference.
+func (d *Details) SetBottleTags(hostTag, hostArch string, tags []string) {
+	d.hostBottleTag = hostTag
+	d.hostArch = hostArch
+	d.bottleTags = tags
+}
+
+// SetMacOSVersion sets the host macOS version, used to flag casks that can't be installed.
+func (d *Details) SetMacOSVersion(version string) {
+	d.hostMacOS = version
+}
+
+// SetGitHubLookup sets the function used to retrieve the GitHub metadata of a package.
+func (d *Details) SetGitHubLookup(lookup func(pkg *models.Package) *models.GitHubMetadata) {
+	d.githubLookup = lookup
+}
+
+// SetAdvisoryLookup sets the function used to retrieve the known vulnerabilities of a package.
+func (d *Details) SetAdvisoryLookup(lookup func(pkg *models.Package) []models.Vulnerability) {
+	d.advisoryLookup = lookup
+}
+
+// SetNoteLookup sets the function used to retrieve the personal note of a package.
+func (d *Details) SetNoteLookup(lookup func(pkg *models.Package) string) {
+	d.noteLookup = lookup
+}
+
+// SetPolicyLookup sets the function used to retrieve the upgrade policy label of a package.
+func (d *Details) SetPolicyLookup(lookup func(pkg *models.Package) string) {
+	d.policyLookup = lookup
+}
+
+// SetProtectedLookup sets the function used to tell whether a package is protected from removal.
+func (d *Details) SetProtectedLookup(lookup func(pkg *models.Package) bool) {
+	d.protectedLookup = lookup
+}
+
+// SetHeldLookup sets the function used to tell whether a package is held at its installed version.
+func (d *Details) SetHeldLookup(lookup func(pkg *models.Package) bool) {
+	d.heldLookup = lookup
+}
+
+// SetAlternativeLookup sets the function used to get the Linux alternatives of a cask.
+func (d *Details) SetAlternativeLookup(lookup func(pkg *models.Package) string) {
+	d.alternativeLookup = lookup
+}
+
+// SetInstalledLookup sets the function used to tell whether a formula is installed.
+func (d *Details) SetInstalledLookup(lookup func(name string) bool) {
+	d.installedLookup = lookup
+}
+
+// SetActionsLookup sets the function returning the keys available for a package, shown at the
+// bottom of the details.
+func (d *Details) SetActionsLookup(lookup func(pkg *models.Package) []DetailsAction) {
+	d.actionsLookup = lookup
+}
+
+// IconArea returns the box of cells at the top right of the details where an icon can be drawn, as of the
+// last layout. ok is false when the details are too narrow to spare it.
+func (d *Details) IconArea() (x, y, width, height int, ok bool) {
+	innerX, innerY, innerWidth, innerHeight := d.view.GetInnerRect()
+	width, height = 10, 5 // About square with the usual cell size
+	if innerWidth < 44 || innerHeight < height {
+		return 0, 0, 0, 0, false
+	}
+	return innerX + innerWidth - width, innerY, width, height, true
+}
+
 func (d *Details) SetContent(pkg *models.Package) {
 	if pkg == nil {
 		d.view.SetText("")
+		d.actions.SetText("")
 		return
 	}
+	d.actions.SetText(d.getActionBar(pkg))
 
 	// Installation status with colors
-	installedStatus := "[red]Not installed[-]"
+	installedStatus := "[red]" + i18n.T("Not installed") + "[-]"
 	if pkg.LocallyInstalled {
-		installedStatus = "[green]Installed[-]"
+		installedStatus = "[green]" + i18n.T("Installed") + "[-]"
 		if pkg.Outdated {
-			installedStatus = "[orange]Update available[-]"
+			installedStatus = "[orange]" + i18n.T("Update available") + "[-]"
 		}
 	}
 
+	// Show the upgrade delta for outdated packages
+	versionLabel := pkg.Version
+	if installed, latest := pkg.InstalledVersion(), pkg.LatestVersion(); pkg.Outdated && installed != "" && installed != latest {
+		versionLabel = FormatVersionDelta(d.theme, installed, latest)
+	}
+
 	// Type tag with escaped brackets
 	typeTag := tview.Escape("[F]") // Formula
-	typeLabel := "Formula"
+	typeLabel := i18n.T("Formula")
 	if pkg.Type == models.PackageTypeCask {
 		typeTag = tview.Escape("[C]") // Cask
-		typeLabel = "Cask"
+		typeLabel = i18n.T("Cask")
 	}
 
-	// Section separator
-	separator := "[dim]────────────────────────[-]"
-
 	// Basic information with status
-	basicInfo := fmt.Sprintf(
-		"[yellow::b]%s[-]\n%s\n"+
-			"[blue]• Type:[-] %s %s\n"+
-			"[blue]• Name:[-] %s\n"+
-			"[blue]• Display Name:[-] %s\n"+
-			"[blue]• Version:[-] %s\n"+
-			"[blue]• Status:[-] %s\n"+
-			"[blue]• Homepage:[-] %s\n\n"+
-			"[yellow::b]Description[-]\n%s\n%s",
-		pkg.Name, separator,
-		typeTag, typeLabel,
-		pkg.Name,
-		pkg.DisplayName,
-		pkg.Version,
-		installedStatus,
-		pkg.Homepage,
-		separator,
-		pkg.Description,
-	)
+	basicInfo := d.section(pkg.Name) + d.getBadges(pkg) +
+		d.field("Type", typeTag+" "+typeLabel) +
+		d.field("Name", pkg.Name) +
+		d.field("Display Name", pkg.DisplayName) +
+		d.field("Version", versionLabel) +
+		d.field("Status", installedStatus) +
+		d.field("Homepage", pkg.Homepage) +
+		d.field("Tap", d.tapLabel(pkg))
+	if pkg.BrewfileComment != "" {
+		basicInfo += d.field("Brewfile note", tview.Escape(pkg.BrewfileComment))
+	}
+	if pkg.RenamedFrom != "" {
+		basicInfo += d.field("Renamed", i18n.T("%s in the Brewfile, now %s", tview.Escape(pkg.RenamedFrom), pkg.Name))
+	}
+	if d.alternativeLookup != nil {
+		if alternative := d.alternativeLookup(pkg); alternative != "" {
+			basicInfo += d.field("Linux alternative", tview.Escape(alternative))
+		}
+	}
+	if d.policyLookup != nil && pkg.LocallyInstalled {
+		basicInfo += d.field("Upgrade policy", d.policyLookup(pkg))
+	}
+	if d.protectedLookup != nil && d.protectedLookup(pkg) {
+		basicInfo += d.field("Protection", i18n.T("protected from removal"))
+	}
+	if d.noteLookup != nil {
+		if note := d.noteLookup(pkg); note != "" {
+			basicInfo += d.field("My note", tview.Escape(note))
+		}
+	}
+	basicInfo += "\n" +
+		d.section(i18n.T("Description")) + pkg.Description
 
 	// Installation details
 	installDetails := d.getPackageInstallationDetails(pkg)
+	if securityInfo := d.getSecurityInfo(pkg); securityInfo != "" {
+		installDetails += "\n\n" + securityInfo
+	}
 
-	// Dependencies (only for formulae)
+	// Bottle availability and dependencies (only for formulae)
+	bottleInfo := ""
 	dependenciesInfo := ""
 	if pkg.Type == models.PackageTypeFormula && pkg.Formula != nil {
+		bottleInfo = d.getBottleInfo(pkg.Formula)
 		dependenciesInfo = d.getDependenciesInfo(pkg.Formula)
 	}
 
 	analyticsInfo := d.getAnalyticsInfo(pkg)
 
 	parts := []string{basicInfo, installDetails}
+	if bottleInfo != "" {
+		parts = append(parts, bottleInfo)
+	}
 	if dependenciesInfo != "" {
 		parts = append(parts, dependenciesInfo)
 	}
+	if sourceInfo := d.getSourceInfo(pkg); sourceInfo != "" {
+		parts = append(parts, sourceInfo)
+	}
 	parts = append(parts, analyticsInfo)
+	if githubInfo := d.getGitHubInfo(pkg); githubInfo != "" {
+		parts = append(parts, githubInfo)
+	}
 
 	d.view.SetText(strings.Join(parts, "\n\n"))
 }
 
-func (d *Details) getPackageInstallationDetails(pkg *models.Package) string {
-	separator := "[dim]────────────────────────[-]"
+// section formats a section title followed by the separator line
+func (d *Details) section(title string) string {
+	return fmt.Sprintf("[%s::b]%s[-:-:-]\n[::d]%s[-:-:-]\n",
+		theme.ColorTag(d.theme.SectionTitleColor), title, strings.Repeat(d.theme.Symbols.Separator, 24))
+}
+
+// field formats a "label: value" line, translating the label
+func (d *Details) field(label, value string) string {
+	return fmt.Sprintf("[%s]%s %s:[-] %s\n",
+		theme.ColorTag(d.theme.FieldLabelColor), d.theme.Symbols.Bullet, i18n.T(label), value)
+}
 
+// tapLabel returns the tap of the package, highlighting third-party taps
+func (d *Details) tapLabel(pkg *models.Package) string {
+	tap := pkg.Tap()
+	if pkg.IsThirdPartyTap() {
+		return fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.ThirdPartyColor), tap)
+	}
+	return tap
+}
+
+// yesNo returns a translated "Yes" or "No"
+func (d *Details) yesNo(value bool) string {
+	if value {
+		return i18n.T("Yes")
+	}
+	return i18n.T("No")
+}
+
+func (d *Details) getPackageInstallationDetails(pkg *models.Package) string {
 	if !pkg.LocallyInstalled {
-		return fmt.Sprintf("[yellow::b]Installation[-]\n%s\nNot installed", separator)
+		return d.section(i18n.T("Installation")) + i18n.T("Not installed")
 	}
 
 	// For formulae, show detailed installation info
 	if pkg.Type == models.PackageTypeFormula && pkg.Formula != nil && len(pkg.Formula.Installed) > 0 {
-		packagePrefix := pkg.Formula.LocalPath
-
-		installedOnRequest := "No"
-		if pkg.Formula.Installed[0].InstalledOnRequest {
-			installedOnRequest = "Yes"
-		}
-
-		installedAsDependency := "No"
-		if pkg.Formula.Installed[0].InstalledAsDependency {
-			installedAsDependency = "Yes"
-		}
-
-		return fmt.Sprintf(
-			"[yellow::b]Installation Details[-]\n%s\n"+
-				"[blue]• Path:[-] %s\n"+
-				"[blue]• Installed on request:[-] %s\n"+
-				"[blue]• Installed as dependency:[-] %s\n"+
-				"[blue]• Installed version:[-] %s",
-			separator,
-			packagePrefix,
-			installedOnRequest,
-			installedAsDependency,
-			pkg.Formula.Installed[0].Version,
-		)
+		installed := pkg.Formula.Installed[0]
+		return strings.TrimSuffix(d.section(i18n.T("Installation Details"))+
+			d.field("Path", pkg.Formula.LocalPath)+
+			d.field("Installed on request", d.yesNo(installed.InstalledOnRequest))+
+			d.field("Installed as dependency", d.yesNo(installed.InstalledAsDependency))+
+			d.field("Installed version", installed.Version), "\n")
 	}
 
 	// For casks, show simpler installation info
 	if pkg.Type == models.PackageTypeCask && pkg.Cask != nil {
-		installedVersion := "Unknown"
+		installedVersion := i18n.T("Unknown")
 		if pkg.Cask.Installed != nil {
 			installedVersion = *pkg.Cask.Installed
 		}
 
-		return fmt.Sprintf(
-			"[yellow::b]Installation Details[-]\n%s\n"+
-				"[blue]• Type:[-] Desktop Application\n"+
-				"[blue]• Installed version:[-] %s",
-			separator,
-			installedVersion,
-		)
+		return strings.TrimSuffix(d.section(i18n.T("Installation Details"))+
+			d.field("Type", i18n.T("Desktop Application"))+
+			d.field("Installed version", installedVersion), "\n")
 	}
 
-	return fmt.Sprintf("[yellow::b]Installation[-]\n%s\nInstalled", separator)
+	return d.section(i18n.T("Installation")) + i18n.T("Installed")
 }
 
-func (d *Details) getDependenciesInfo(info *models.Formula) string {
-	separator := "[dim]────────────────────────[-]"
-	title := fmt.Sprintf("[yellow::b]Dependencies[-]\n%s\n", separator)
-
-	if len(info.Dependencies) == 0 {
-		return title + "No dependencies"
-	}
-
-	// Format dependencies in multiple columns or with separators
-	deps := ""
-	for i, dep := range info.Dependencies {
-		deps += dep
-		if i < len(info.Dependencies)-1 {
-			if (i+1)%3 == 0 {
-				deps += "\n"
-			} else {
-				deps += ", "
+// getBadges returns a line of highlighted badges for noteworthy package traits, or "" if none apply.
+func (d *Details) getBadges(pkg *models.Package) string {
+	line := ""
+	badge := func(text string, color tcell.Color) {
+		line += fmt.Sprintf("[black:%s] %s [-:-] ", theme.ColorTag(color), text)
+	}
+
+	if pkg.Formula != nil && len(d.bottleTags) > 0 && pkg.Formula.BottleTag(d.bottleTags) == "" {
+		badge(i18n.T("No %s bottle", d.hostArch), d.theme.OutdatedColor)
+	}
+	if d.heldLookup != nil && d.heldLookup(pkg) {
+		if pkg.Outdated {
+			badge(i18n.T("Held: %s available", pkg.LatestVersion()), d.theme.WarningColor)
+		} else {
+			badge(i18n.T("Held"), d.theme.WarningColor)
+		}
+	}
+	if vulnerabilities := d.vulnerabilities(pkg); len(vulnerabilities) > 0 {
+		badge(i18n.T("%d known vulnerabilities", len(vulnerabilities)), d.theme.ErrorColor)
+	}
+	if pkg.Cask != nil {
+		if requirement := pkg.Cask.MacOSRequirement(); requirement != "" {
+			color := d.theme.OutdatedColor
+			if !pkg.Cask.SupportsMacOS(d.hostMacOS) {
+				color = d.theme.ErrorColor
 			}
+			badge(i18n.T("Requires macOS %s", requirement), color)
 		}
 	}
 
-	return title + deps
+	if line == "" {
+		return ""
+	}
+	return line + "\n"
+}
+
+func (d *Details) getBottleInfo(info *models.Formula) string {
+	if len(d.bottleTags) == 0 {
+		return ""
+	}
+
+	tag := info.BottleTag(d.bottleTags)
+	status := fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.SuccessColor), i18n.T("Available (%s)", tag))
+	if tag == "" {
+		status = fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.OutdatedColor),
+			i18n.T("Not available for %s, will build from source", d.hostBottleTag))
+	}
+
+	return strings.TrimSuffix(d.section(i18n.T("Bottle"))+
+		d.field("Bottle", status)+
+		d.field("Est. install time", i18n.T("~%d min", info.EstimatedInstallMinutes(tag != ""))), "\n")
+}
+
+// getSourceInfo returns the source and download URLs with their checksums, for mirroring packages.
+// Formulae show their source and the bottle of the host; casks the artifact they download.
+func (d *Details) getSourceInfo(pkg *models.Package) string {
+	var fields string
+	add := func(label, value string) {
+		if value != "" {
+			fields += d.field(label, tview.Escape(value))
+		}
+	}
+
+	switch {
+	case pkg.Formula != nil:
+		add("Source", pkg.Formula.Urls.Stable.URL)
+		add("Source checksum", pkg.Formula.Urls.Stable.Checksum)
+		if tag := pkg.Formula.BottleTag(d.bottleTags); tag != "" {
+			file := pkg.Formula.Bottle.Stable.Files[tag]
+			add("Bottle URL", file.URL)
+			add("Bottle SHA-256", file.Sha256)
+		}
+	case pkg.Cask != nil:
+		add("Download URL", pkg.Cask.URL)
+		add("SHA-256", pkg.Cask.SHA256)
+	}
+	if fields == "" {
+		return ""
+	}
+
+	hint := fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.LegendColor), i18n.T("y: copy URL | d: download"))
+	return d.section(i18n.T("Source")) + fields + hint
+}
+
+// getDependenciesInfo lists the runtime dependencies, installed along with the formula, apart from the
+// build dependencies, only needed to build it from source. Without a bottle for the host, it tells which
+// build dependencies the build installs first, for the user to understand why a small formula pulls
+// in cmake or rust.
+func (d *Details) getDependenciesInfo(info *models.Formula) string {
+	title := d.section(i18n.T("Dependencies"))
+
+	if len(info.Dependencies) == 0 && len(info.BuildDependencies) == 0 {
+		return title + i18n.T("No dependencies")
+	}
+
+	runtime := i18n.T("none")
+	if len(info.Dependencies) > 0 {
+		runtime = strings.Join(info.Dependencies, ", ")
+	}
+	text := title + d.field("Runtime", runtime)
+	if len(info.BuildDependencies) == 0 {
+		return strings.TrimSuffix(text, "\n")
+	}
+
+	text += d.field("Build only", strings.Join(info.BuildDependencies, ", "))
+	if len(d.bottleTags) == 0 || info.BottleTag(d.bottleTags) != "" || d.installedLookup == nil {
+		return strings.TrimSuffix(text, "\n")
+	}
+	if missing := info.MissingBuildDependencies(d.installedLookup); len(missing) > 0 {
+		text += fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.OutdatedColor),
+			i18n.T("No bottle: building from source installs %s first", strings.Join(missing, ", ")))
+	}
+	return strings.TrimSuffix(text, "\n")
 }
 
 func (d *Details) getAnalyticsInfo(pkg *models.Package) string {
-	separator := "[dim]────────────────────────[-]"
-	p := message.NewPrinter(language.English)
+	p := i18n.Printer()
+
+	return strings.TrimSuffix(d.section(i18n.T("Analytics"))+
+		d.field("90d Global Rank", p.Sprintf("%d", pkg.Analytics90dRank))+
+		d.field("90d Downloads", p.Sprintf("%d", pkg.Analytics90dDownloads)), "\n")
+}
+
+// vulnerabilities returns the known vulnerabilities of the package, if any.
+func (d *Details) vulnerabilities(pkg *models.Package) []models.Vulnerability {
+	if d.advisoryLookup == nil {
+		return nil
+	}
+	return d.advisoryLookup(pkg)
+}
+
+// getSecurityInfo returns the Security section listing known vulnerabilities, or "" if none.
+// Each entry tells whether upgrading to the latest version resolves it.
+func (d *Details) getSecurityInfo(pkg *models.Package) string {
+	vulnerabilities := d.vulnerabilities(pkg)
+	if len(vulnerabilities) == 0 {
+		return ""
+	}
+
+	info := d.section(i18n.T("Security"))
+	for _, vuln := range vulnerabilities {
+		summary := vuln.Summary
+		if summary == "" {
+			summary = vuln.ID
+		}
+		info += fmt.Sprintf("[%s]%s %s[-] %s\n", theme.ColorTag(d.theme.ErrorColor),
+			d.theme.Symbols.Bullet, vuln.CVE(), tview.Escape(summary))
+
+		fix := i18n.T("No fix available")
+		switch {
+		case vuln.FixedBy(pkg.Version):
+			fix = fmt.Sprintf("%s, [%s]%s[-]", i18n.T("Fixed in %s", strings.Join(vuln.FixedVersions, ", ")),
+				theme.ColorTag(d.theme.SuccessColor), i18n.T("resolved by upgrading"))
+		case len(vuln.FixedVersions) > 0:
+			fix = fmt.Sprintf("%s, %s", i18n.T("Fixed in %s", strings.Join(vuln.FixedVersions, ", ")),
+				i18n.T("not fixed in %s yet", pkg.Version))
+		}
+		info += "  " + fix + "\n"
+	}
+	return strings.TrimSuffix(info, "\n")
+}
+
+// getGitHubInfo returns the GitHub section, or "" if no metadata is known for the package.
+func (d *Details) getGitHubInfo(pkg *models.Package) string {
+	if d.githubLookup == nil {
+		return ""
+	}
+	meta := d.githubLookup(pkg)
+	if meta == nil {
+		return ""
+	}
+
+	p := i18n.Printer()
+	lastPush := meta.PushedAt.Format("2006-01-02")
+	if !meta.RecentlyMaintained() {
+		lastPush = fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.OutdatedColor), lastPush)
+	}
+
+	info := d.section("GitHub") +
+		d.field("Repository", meta.Repository) +
+		d.field("Stars", p.Sprintf("%d", meta.Stars)) +
+		d.field("Last push", lastPush)
+	if meta.Archived {
+		info += d.field("Archived", fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.ErrorColor), i18n.T("Yes")))
+	}
+	return strings.TrimSuffix(info, "\n")
+}
 
-	return fmt.Sprintf(
-		"[yellow::b]Analytics[-]\n%s\n"+
-			"[blue]• 90d Global Rank:[-] %s\n"+
-			"[blue]• 90d Downloads:[-] %s",
-		separator,
-		p.Sprintf("%d", pkg.Analytics90dRank),
-		p.Sprintf("%d", pkg.Analytics90dDownloads),
-	)
+// getActionBar formats the keys available for a package, e.g. "i Install  space Mark".
+func (d *Details) getActionBar(pkg *models.Package) string {
+	if d.actionsLookup == nil {
+		return ""
+	}
+	actions := d.actionsLookup(pkg)
+	labels := make([]string, len(actions))
+	for i, action := range actions {
+		labels[i] = fmt.Sprintf("[%s::b]%s[-::-] [%s]%s[-]", theme.ColorTag(d.theme.WarningColor), tview.Escape(action.Key),
+			theme.ColorTag(d.theme.LegendColor), tview.Escape(action.Label))
+	}
+	return strings.Join(labels, "  ")
 }
 
-func (d *Details) View() *tview.TextView {
-	return d.view
+func (d *Details) View() *tview.Flex {
+	return d.box
 }
 
 func (d *Details) Clear() {
 	d.view.Clear()
+	d.actions.Clear()
+}
+
+// FormatVersionDelta renders "installed → latest" with color tags, highlighting the
+// part of the latest version that differs from the installed one (e.g. 1.2.3 → 1.[green]4.0[-]).
+// Major version changes, which may break things, are highlighted as warnings.
+func FormatVersionDelta(t *theme.Theme, installed, latest string) string {
+	common, changed := version.SplitChanged(installed, latest)
+	color := t.SuccessColor
+	if version.Delta(installed, latest) == version.ChangeMajor {
+		color = t.WarningColor
+	}
+
+	return fmt.Sprintf("[%s]%s[-] %s %s[%s]%s[-]",
+		theme.ColorTag(t.OutdatedColor), tview.Escape(installed), t.Symbols.Arrow,
+		tview.Escape(common), theme.ColorTag(color), tview.Escape(changed))
 }
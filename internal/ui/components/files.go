@@ -0,0 +1,139 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// FileItem is a file an installed package put on disk, with its formatted size.
+type FileItem struct {
+	Path string
+	Size string
+	Note string // e.g. the command shadowing it in the PATH
+}
+
+// Files displays a modal overlay listing the files of an installed package, filtered by path
+type Files struct {
+	pages  *tview.Pages
+	table  *tview.Table
+	filter *tview.InputField
+	theme  *theme.Theme
+}
+
+// NewFiles creates a new files component
+func NewFiles(theme *theme.Theme) *Files {
+	return &Files{
+		theme: theme,
+	}
+}
+
+// View returns the files pages (for overlay functionality)
+func (f *Files) View() *tview.Pages {
+	return f.pages
+}
+
+// HasFocus returns true if the files overlay is currently open and focused
+func (f *Files) HasFocus() bool {
+	return f.filter != nil && f.filter.HasFocus()
+}
+
+// Build creates the file listing as an overlay on top of the main content, with the given title.
+// onClose is called when the overlay is dismissed.
+func (f *Files) Build(mainContent tview.Primitive, title string, items []FileItem, onClose func()) *tview.Pages {
+	f.table = tview.NewTable().
+		SetSelectable(true, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	f.table.SetBackgroundColor(f.theme.ModalBgColor)
+
+	f.filter = tview.NewInputField().
+		SetLabel(i18n.T("Filter: ")).
+		SetLabelColor(f.theme.LegendColor).
+		SetFieldBackgroundColor(f.theme.ModalBgColor).
+		SetFieldTextColor(f.theme.DefaultTextColor)
+	f.filter.SetBackgroundColor(f.theme.ModalBgColor)
+
+	f.render(items, "")
+	f.filter.SetChangedFunc(func(text string) {
+		f.render(items, text)
+	})
+
+	// The filter keeps the focus, the navigation keys scroll the list
+	f.filter.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			if f.filter.GetText() != "" {
+				f.filter.SetText("")
+			} else {
+				onClose()
+			}
+			return nil
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			f.table.InputHandler()(event, func(tview.Primitive) {})
+			return nil
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(f.theme.LegendColor), i18n.T("type to filter | ↑/↓: scroll | esc: clear filter, close")))
+	hint.SetBackgroundColor(f.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(f.filter, 1, 0, true).
+		AddItem(f.table, 0, 1, false).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(f.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(f.theme.BorderColor).
+		SetTitle(" " + title + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 4, true).
+			AddItem(nil, 0, 1, false),
+			0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the files as overlay
+	f.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("files", centered, true, true)
+
+	return f.pages
+}
+
+// render fills the table with the files whose path contains the filter, case-insensitively.
+func (f *Files) render(items []FileItem, filter string) {
+	f.table.Clear()
+	filter = strings.ToLower(filter)
+	row := 0
+	for _, item := range items {
+		if filter != "" && !strings.Contains(strings.ToLower(item.Path), filter) {
+			continue
+		}
+		f.table.SetCell(row, 0, tview.NewTableCell(tview.Escape(item.Path)).
+			SetTextColor(f.theme.DefaultTextColor).SetExpansion(1))
+		f.table.SetCell(row, 1, tview.NewTableCell(item.Size).
+			SetTextColor(f.theme.LegendColor).SetAlign(tview.AlignRight))
+		f.table.SetCell(row, 2, tview.NewTableCell(tview.Escape(item.Note)).
+			SetTextColor(f.theme.WarningColor))
+		row++
+	}
+	if row == 0 {
+		f.table.SetCell(0, 0, tview.NewTableCell(i18n.T("No files match")).
+			SetTextColor(f.theme.LegendColor).SetSelectable(false))
+	}
+	f.table.Select(0, 0)
+	f.table.ScrollToBeginning()
+}
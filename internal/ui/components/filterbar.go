@@ -0,0 +1,49 @@
+package components
+
+import (
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// FilterTab is a filter of the tab bar, applied by its number key.
+type FilterTab struct {
+	Key   string
+	Label string
+}
+
+// FilterBar shows the quick filters above the table, the active one highlighted
+type FilterBar struct {
+	view  *tview.TextView
+	theme *theme.Theme
+}
+
+// NewFilterBar creates a new filter tab bar
+func NewFilterBar(theme *theme.Theme) *FilterBar {
+	return &FilterBar{
+		view:  tview.NewTextView().SetDynamicColors(true),
+		theme: theme,
+	}
+}
+
+func (f *FilterBar) View() *tview.TextView {
+	return f.view
+}
+
+// SetTabs shows the tabs, with the one at the active index highlighted: -1 when the active filter
+// is not one of them.
+func (f *FilterBar) SetTabs(tabs []FilterTab, active int) {
+	labels := make([]string, len(tabs))
+	for i, tab := range tabs {
+		label := tview.Escape(fmt.Sprintf(" %s %s ", tab.Key, tab.Label))
+		if i == active {
+			label = fmt.Sprintf("[::r]%s[::-]", label)
+		} else {
+			label = fmt.Sprintf("[%s]%s[-]", theme.ColorTag(f.theme.LegendColor), label)
+		}
+		labels[i] = label
+	}
+	f.view.SetText(strings.Join(labels, " "))
+}
@@ -1,14 +1,32 @@
 package components
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/ui/theme"
 	"fmt"
+	"strings"
+	"time"
+
 	"github.com/rivo/tview"
 )
 
+// HeaderStats is the system information shown on the second line of the header.
+// Zero values are left out.
+type HeaderStats struct {
+	Formulae   int       // Installed formulae
+	Casks      int       // Installed casks
+	Outdated   int       // Installed packages with an update, except held ones
+	Held       int       // Installed packages with an update held by their upgrade policy
+	LastUpdate time.Time // Last `brew update`
+	FreeDisk   string    // Free space on the Homebrew prefix volume, formatted
+}
+
 type Header struct {
 	view  *tview.TextView
 	theme *theme.Theme
+
+	title string
+	stats HeaderStats
 }
 
 func NewHeader(theme *theme.Theme) *Header {
@@ -23,9 +41,60 @@ func NewHeader(theme *theme.Theme) *Header {
 }
 
 func (h *Header) Update(name, version, brewVersion string) {
-	h.view.SetText(fmt.Sprintf(" %s %s - %s", name, version, brewVersion))
+	h.title = fmt.Sprintf(" %s %s - %s", name, version, brewVersion)
+	h.render()
+}
+
+// SetStats updates the system information line.
+func (h *Header) SetStats(stats HeaderStats) {
+	h.stats = stats
+	h.render()
 }
 
 func (h *Header) View() *tview.TextView {
 	return h.view
 }
+
+func (h *Header) render() {
+	var parts []string
+	if h.stats.Formulae > 0 || h.stats.Casks > 0 {
+		parts = append(parts, typeCounts(h.stats.Formulae, h.stats.Casks))
+	}
+	if h.stats.Outdated > 0 {
+		parts = append(parts, fmt.Sprintf("[%s]%s[-]", theme.ColorTag(h.theme.OutdatedColor),
+			tview.Escape(i18n.T("%d outdated [o]", h.stats.Outdated))))
+	}
+	if h.stats.Held > 0 {
+		parts = append(parts, i18n.T("%d held", h.stats.Held))
+	}
+	if !h.stats.LastUpdate.IsZero() {
+		parts = append(parts, i18n.T("brew updated %s ago", formatAge(time.Since(h.stats.LastUpdate))))
+	}
+	if h.stats.FreeDisk != "" {
+		parts = append(parts, i18n.T("%s free", h.stats.FreeDisk))
+	}
+
+	text := h.title
+	if len(parts) > 0 {
+		text += fmt.Sprintf("\n [%s]%s[-]", theme.ColorTag(h.theme.LegendColor),
+			strings.Join(parts, " "+h.theme.Symbols.Bullet+" "))
+	}
+	h.view.SetText(text)
+}
+
+// typeCounts formats the counts of formulae and casks ("12 formulae, 1 cask"), for the header and the search counter.
+func typeCounts(formulae, casks int) string {
+	return i18n.T("%d formulae, %d casks", formulae, casks)
+}
+
+// formatAge formats a duration in its largest unit: 5m, 3h, 2d.
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,135 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// HealthCheck is a check of the environment, with the problems it found.
+type HealthCheck struct {
+	Name     string
+	Passed   string // Shown when there is no problem
+	Problems []HealthProblem
+}
+
+// HealthProblem is a problem found by a check, with its details and how to fix it.
+type HealthProblem struct {
+	Summary string
+	Details []string
+	Fix     string
+}
+
+// Health displays a modal overlay with the checks of the environment and the problems they found
+type Health struct {
+	pages *tview.Pages
+	view  *tview.TextView
+	theme *theme.Theme
+}
+
+// NewHealth creates a new health component
+func NewHealth(theme *theme.Theme) *Health {
+	return &Health{
+		theme: theme,
+	}
+}
+
+// View returns the health pages (for overlay functionality)
+func (h *Health) View() *tview.Pages {
+	return h.pages
+}
+
+// HasFocus returns true if the health screen is currently open and focused
+func (h *Health) HasFocus() bool {
+	return h.view != nil && h.view.HasFocus()
+}
+
+// Build creates the health screen as an overlay on top of the main content.
+// onRerun is called when the user asks to check again, and onClose when the overlay is dismissed.
+func (h *Health) Build(mainContent tview.Primitive, checks []HealthCheck, onRerun, onClose func()) *tview.Pages {
+	h.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(h.render(checks))
+	h.view.SetBackgroundColor(h.theme.ModalBgColor)
+	h.view.SetTextColor(h.theme.DefaultTextColor)
+
+	h.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'r':
+			onRerun()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := fmt.Sprintf("[%s]%s[-]", theme.ColorTag(h.theme.LegendColor), i18n.T("r: check again | esc: close"))
+	frame := tview.NewFrame(h.view).
+		SetBorders(1, 1, 1, 0, 2, 2).
+		AddText(hint, false, tview.AlignLeft, h.theme.LegendColor)
+	frame.SetBackgroundColor(h.theme.ModalBgColor)
+	frame.SetBorderColor(h.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" " + i18n.T("Health") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the frame in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 4, true).
+			AddItem(nil, 0, 1, false),
+			0, 6, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the health screen as overlay
+	h.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("health", centered, true, true)
+
+	return h.pages
+}
+
+// render lists each check with its problems, their details indented and their fix highlighted
+func (h *Health) render(checks []HealthCheck) string {
+	var sb strings.Builder
+	for i, check := range checks {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		if len(check.Problems) == 0 {
+			sb.WriteString(fmt.Sprintf("[%s]%s[-] [::b]%s[-:-:-]\n", theme.ColorTag(h.theme.SuccessColor), h.theme.Symbols.Check,
+				tview.Escape(check.Name)))
+			sb.WriteString(fmt.Sprintf("  [%s]%s[-]\n", theme.ColorTag(h.theme.LegendColor), tview.Escape(check.Passed)))
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("[%s]%s[-] [::b]%s[-:-:-] (%d)\n", theme.ColorTag(h.theme.WarningColor), h.theme.Symbols.Cross,
+			tview.Escape(check.Name), len(check.Problems)))
+		for _, problem := range check.Problems {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", h.theme.Symbols.Bullet, tview.Escape(problem.Summary)))
+			for _, detail := range problem.Details {
+				sb.WriteString(fmt.Sprintf("      [%s]%s[-]\n", theme.ColorTag(h.theme.LegendColor), tview.Escape(detail)))
+			}
+			for _, line := range strings.Split(problem.Fix, "\n") {
+				if line != "" {
+					sb.WriteString(fmt.Sprintf("    [%s]%s[-]\n", theme.ColorTag(h.theme.SuccessColor), tview.Escape(line)))
+				}
+			}
+		}
+	}
+	return sb.String()
+}
@@ -1,6 +1,7 @@
 package components
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/ui/theme"
 	"fmt"
 	"strings"
@@ -9,11 +10,23 @@ import (
 	"github.com/rivo/tview"
 )
 
+// HelpEntry is a key and what it does.
+type HelpEntry struct {
+	Key         string
+	Description string
+}
+
+// HelpSection is a titled group of help entries.
+type HelpSection struct {
+	Title   string
+	Entries []HelpEntry
+}
+
 // HelpScreen displays a modal overlay with all keyboard shortcuts
 type HelpScreen struct {
-	pages      *tview.Pages
-	theme      *theme.Theme
-	isBrewfile bool
+	pages    *tview.Pages
+	theme    *theme.Theme
+	sections []HelpSection
 }
 
 // NewHelpScreen creates a new help screen component
@@ -28,9 +41,9 @@ func (h *HelpScreen) View() *tview.Pages {
 	return h.pages
 }
 
-// SetBrewfileMode sets whether Brewfile-specific commands should be shown
-func (h *HelpScreen) SetBrewfileMode(enabled bool) {
-	h.isBrewfile = enabled
+// SetSections sets the key bindings to show, generated from the registered actions
+func (h *HelpScreen) SetSections(sections []HelpSection) {
+	h.sections = sections
 }
 
 // Build creates the help screen as an overlay on top of the main content
@@ -51,15 +64,12 @@ func (h *HelpScreen) Build(mainContent tview.Primitive) *tview.Pages {
 	frame.SetBackgroundColor(h.theme.ModalBgColor)
 	frame.SetBorderColor(h.theme.BorderColor)
 	frame.SetBorder(true).
-		SetTitle(" Help ").
+		SetTitle(" " + i18n.T("Help") + " ").
 		SetTitleAlign(tview.AlignCenter)
 
-	// Calculate box dimensions
-	boxHeight := 22
-	boxWidth := 55
-	if h.isBrewfile {
-		boxHeight = 26 // Extra space for Brewfile section
-	}
+	// Fit the box to the content: the lines, plus the frame padding and border
+	boxHeight := strings.Count(content, "\n") + 5
+	boxWidth := 60
 
 	// Center the frame in a flex layout
 	centered := tview.NewFlex().
@@ -83,39 +93,18 @@ func (h *HelpScreen) Build(mainContent tview.Primitive) *tview.Pages {
 func (h *HelpScreen) buildHelpContent() string {
 	var sb strings.Builder
 
-	// Navigation section
-	sb.WriteString(h.formatSection("NAVIGATION"))
-	sb.WriteString(h.formatKey("↑/↓, j/k", "Navigate list"))
-	sb.WriteString(h.formatKey("/", "Focus search"))
-	sb.WriteString(h.formatKey("Esc", "Back to table"))
-	sb.WriteString(h.formatKey("q", "Quit"))
-	sb.WriteString("\n")
-
-	// Filters section
-	sb.WriteString(h.formatSection("FILTERS"))
-	sb.WriteString(h.formatKey("f", "Toggle installed"))
-	sb.WriteString(h.formatKey("o", "Toggle outdated"))
-	sb.WriteString(h.formatKey("l", "Toggle leaves"))
-	sb.WriteString(h.formatKey("c", "Toggle casks"))
-	sb.WriteString("\n")
-
-	// Actions section
-	sb.WriteString(h.formatSection("ACTIONS"))
-	sb.WriteString(h.formatKey("i", "Install selected"))
-	sb.WriteString(h.formatKey("u", "Update selected"))
-	sb.WriteString(h.formatKey("r", "Remove selected"))
-	sb.WriteString(h.formatKey("Ctrl+U", "Update all"))
-
-	// Brewfile section (only if in Brewfile mode)
-	if h.isBrewfile {
-		sb.WriteString("\n")
-		sb.WriteString(h.formatSection("BREWFILE"))
-		sb.WriteString(h.formatKey("Ctrl+A", "Install all"))
-		sb.WriteString(h.formatKey("Ctrl+R", "Remove all"))
+	for i, section := range h.sections {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(h.formatSection(section.Title))
+		for _, entry := range section.Entries {
+			sb.WriteString(h.formatKey(entry.Key, entry.Description))
+		}
 	}
 
 	sb.WriteString("\n")
-	sb.WriteString(fmt.Sprintf("[%s]Press any key to close[-]", h.getColorTag(h.theme.LegendColor)))
+	sb.WriteString(fmt.Sprintf("[%s]%s[-]", h.getColorTag(h.theme.LegendColor), i18n.T("Press any key to close")))
 
 	return sb.String()
 }
@@ -127,10 +116,10 @@ func (h *HelpScreen) formatSection(title string) string {
 
 // formatKey formats a key-description pair
 func (h *HelpScreen) formatKey(key, description string) string {
-	return fmt.Sprintf("  [%s]%-12s[-] %s\n", h.getColorTag(h.theme.WarningColor), key, description)
+	return fmt.Sprintf("  [%s]%-12s[-] %s\n", h.getColorTag(h.theme.WarningColor), tview.Escape(key), description)
 }
 
 // getColorTag converts a tcell.Color to a tview color tag
 func (h *HelpScreen) getColorTag(color tcell.Color) string {
-	return fmt.Sprintf("#%06x", color.Hex())
+	return theme.ColorTag(color)
 }
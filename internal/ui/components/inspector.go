@@ -0,0 +1,173 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// inspectorFoldDepth is the depth below which objects and arrays start collapsed.
+const inspectorFoldDepth = 1
+
+// Inspector displays a modal overlay with the raw JSON of a package as a foldable tree
+type Inspector struct {
+	pages *tview.Pages
+	tree  *tview.TreeView
+	theme *theme.Theme
+}
+
+// NewInspector creates a new inspector component
+func NewInspector(theme *theme.Theme) *Inspector {
+	return &Inspector{
+		theme: theme,
+	}
+}
+
+// View returns the inspector pages (for overlay functionality)
+func (i *Inspector) View() *tview.Pages {
+	return i.pages
+}
+
+// HasFocus returns true if the inspector is currently open and focused
+func (i *Inspector) HasFocus() bool {
+	return i.tree != nil && i.tree.HasFocus()
+}
+
+// Build creates the inspector as an overlay on top of the main content, showing the given JSON.
+// onClose is called when the overlay is dismissed.
+func (i *Inspector) Build(mainContent tview.Primitive, title string, data []byte, onClose func()) (*tview.Pages, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	root := tview.NewTreeNode(tview.Escape(title)).SetColor(i.theme.SectionTitleColor)
+	if err := i.decodeValue(decoder, root, 0); err != nil {
+		return nil, err
+	}
+
+	i.tree = tview.NewTreeView().
+		SetRoot(root).
+		SetCurrentNode(root).
+		SetGraphicsColor(i.theme.BorderColor)
+	i.tree.SetBackgroundColor(i.theme.ModalBgColor)
+	i.tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	i.tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == ' ':
+			if node := i.tree.GetCurrentNode(); node != nil {
+				node.SetExpanded(!node.IsExpanded())
+			}
+			return nil
+		case event.Rune() == '+':
+			root.ExpandAll()
+			return nil
+		case event.Rune() == '-':
+			root.CollapseAll()
+			root.SetExpanded(true)
+			return nil
+		}
+		return event // j/k and arrows are handled by the tree view
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(i.theme.LegendColor), i18n.T("enter: fold | +/-: expand/collapse all | esc: close")))
+	hint.SetBackgroundColor(i.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(i.tree, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(i.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(i.theme.BorderColor).
+		SetTitle(" " + i18n.T("Raw Info") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 8, true).
+			AddItem(nil, 0, 1, false),
+			0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the inspector as overlay
+	i.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("inspector", centered, true, true)
+
+	return i.pages, nil
+}
+
+// decodeValue reads the next JSON value and attaches it to node. Objects and arrays become
+// child nodes (keeping the original key order), scalars are appended to the node text.
+func (i *Inspector) decodeValue(decoder *json.Decoder, node *tview.TreeNode, depth int) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch delim := token.(type) {
+	case json.Delim:
+		count := 0
+		for decoder.More() {
+			label := fmt.Sprintf("[%s]%d[-]", theme.ColorTag(i.theme.FieldLabelColor), count)
+			if delim == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				label = fmt.Sprintf("[%s]%s[-]", theme.ColorTag(i.theme.FieldLabelColor), tview.Escape(fmt.Sprint(key)))
+			}
+
+			child := tview.NewTreeNode(label)
+			if err := i.decodeValue(decoder, child, depth+1); err != nil {
+				return err
+			}
+			node.AddChild(child)
+			count++
+		}
+		if _, err := decoder.Token(); err != nil { // Closing delimiter
+			return err
+		}
+
+		summary := fmt.Sprintf("{%d}", count)
+		if delim == '[' {
+			summary = fmt.Sprintf("[%d]", count)
+		}
+		node.SetText(node.GetText() + " " + tview.Escape(summary)).
+			SetExpanded(depth < inspectorFoldDepth)
+	default:
+		node.SetText(node.GetText() + ": " + i.formatScalar(token))
+	}
+	return nil
+}
+
+// formatScalar renders a JSON scalar with syntax highlighting.
+func (i *Inspector) formatScalar(token json.Token) string {
+	switch value := token.(type) {
+	case string:
+		return fmt.Sprintf("[%s]%s[-]", theme.ColorTag(i.theme.SuccessColor), tview.Escape(fmt.Sprintf("%q", value)))
+	case json.Number:
+		return fmt.Sprintf("[%s]%s[-]", theme.ColorTag(i.theme.WarningColor), value.String())
+	case bool:
+		return fmt.Sprintf("[%s]%t[-]", theme.ColorTag(i.theme.OutdatedColor), value)
+	case nil:
+		return fmt.Sprintf("[%s]null[-]", theme.ColorTag(i.theme.OutdatedColor))
+	}
+	return tview.Escape(strings.TrimSpace(fmt.Sprint(token)))
+}
@@ -0,0 +1,169 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// InventoryCategory is how a package differs between this machine and another one.
+type InventoryCategory int
+
+const (
+	InventoryOnlyHere InventoryCategory = iota
+	InventoryOnlyThere
+	InventoryMismatch
+)
+
+// inventoryTitles are the section titles of the categories.
+var inventoryTitles = map[InventoryCategory]string{
+	InventoryOnlyHere:  "Only here",
+	InventoryOnlyThere: "Only there",
+	InventoryMismatch:  "Version mismatch",
+}
+
+// InventoryItem is a package that differs between this machine and another one.
+type InventoryItem struct {
+	Category     InventoryCategory
+	Name         string
+	FullName     string // Tap-qualified name, to install packages of taps not added here
+	Cask         bool
+	HereVersion  string
+	ThereVersion string
+}
+
+// InventoryDiff displays a modal overlay comparing the packages of this machine with another one
+type InventoryDiff struct {
+	pages *tview.Pages
+	table *tview.Table
+	theme *theme.Theme
+}
+
+// NewInventoryDiff creates a new inventory comparison component
+func NewInventoryDiff(theme *theme.Theme) *InventoryDiff {
+	return &InventoryDiff{
+		theme: theme,
+	}
+}
+
+// View returns the inventory comparison pages (for overlay functionality)
+func (d *InventoryDiff) View() *tview.Pages {
+	return d.pages
+}
+
+// HasFocus returns true if the inventory comparison is currently open and focused
+func (d *InventoryDiff) HasFocus() bool {
+	return d.table != nil && d.table.HasFocus()
+}
+
+// Build creates the inventory comparison as an overlay on top of the main content, one section per category.
+// onApply is called with the selected package to bring this machine in line with the other one,
+// onExport to write what the other machine is missing, and onClose when the overlay is dismissed.
+func (d *InventoryDiff) Build(mainContent tview.Primitive, other string, items []InventoryItem,
+	onApply func(item InventoryItem), onExport, onClose func()) *tview.Pages {
+	d.table = tview.NewTable().
+		SetSelectable(true, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	d.table.SetBackgroundColor(d.theme.ModalBgColor)
+
+	rowItems := make(map[int]InventoryItem)
+	row := 0
+	for _, category := range []InventoryCategory{InventoryOnlyHere, InventoryOnlyThere, InventoryMismatch} {
+		var section []InventoryItem
+		for _, item := range items {
+			if item.Category == category {
+				section = append(section, item)
+			}
+		}
+
+		title := fmt.Sprintf("%s (%d)", i18n.T(inventoryTitles[category]), len(section))
+		d.table.SetCell(row, 0, tview.NewTableCell(title).
+			SetTextColor(d.theme.SectionTitleColor).SetAttributes(tcell.AttrBold).SetSelectable(false))
+		row++
+
+		for _, item := range section {
+			name := item.Name
+			if item.Cask {
+				name += " (cask)"
+			}
+			versions := item.HereVersion + item.ThereVersion
+			if category == InventoryMismatch {
+				versions = fmt.Sprintf("%s %s %s", item.HereVersion, d.theme.Symbols.Arrow, item.ThereVersion)
+			}
+			d.table.SetCell(row, 0, tview.NewTableCell("  "+tview.Escape(name)).SetTextColor(d.theme.DefaultTextColor))
+			d.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(versions)).SetTextColor(d.theme.LegendColor))
+			rowItems[row] = item
+			row++
+		}
+	}
+	d.table.Select(firstSelectableRow(rowItems), 0)
+
+	d.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			selected, _ := d.table.GetSelection()
+			if item, ok := rowItems[selected]; ok {
+				onApply(item)
+			}
+			return nil
+		case event.Rune() == 'e':
+			onExport()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(d.theme.LegendColor),
+			i18n.T("enter: match here (install, update, remove) | e: Brewfile for there | esc: close")))
+	hint.SetBackgroundColor(d.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(d.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(d.theme.BorderColor).
+		SetTitle(" " + i18n.T("This machine vs %s", other) + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the comparison as overlay
+	d.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("inventory", centered, true, true)
+
+	return d.pages
+}
+
+// firstSelectableRow returns the first row showing a package, 0 if there is none.
+func firstSelectableRow(rowItems map[int]InventoryItem) int {
+	first := 0
+	for row := range rowItems {
+		if first == 0 || row < first {
+			first = row
+		}
+	}
+	return first
+}
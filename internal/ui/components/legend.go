@@ -10,6 +10,11 @@ import (
 type Legend struct {
 	view  *tview.TextView
 	theme *theme.Theme
+
+	// Last legend set, drawn again when the disabled keys change
+	entries   []struct{ KeySlug, Name string }
+	activeKey string
+	disabled  map[string]bool // Keys shown dimmed, their action unavailable for now
 }
 
 func NewLegend(theme *theme.Theme) *Legend {
@@ -29,6 +34,9 @@ func (l *Legend) View() *tview.TextView {
 }
 
 func (l *Legend) GetFormattedLabel(keySlug, label string, active bool) string {
+	if l.disabled[keySlug] {
+		return fmt.Sprintf("[::d]%s[::-]", tview.Escape(fmt.Sprintf("[%s] %s", keySlug, label)))
+	}
 	if active {
 		return fmt.Sprintf("[yellow::b]%s[-]", tview.Escape(fmt.Sprintf("[%s] %s", keySlug, label)))
 	}
@@ -37,6 +45,7 @@ func (l *Legend) GetFormattedLabel(keySlug, label string, active bool) string {
 }
 
 func (l *Legend) SetLegend(legend []struct{ KeySlug, Name string }, activeKey string) {
+	l.entries, l.activeKey = legend, activeKey
 	var builder strings.Builder
 	for i, item := range legend {
 		active := item.KeySlug == activeKey
@@ -49,6 +58,15 @@ func (l *Legend) SetLegend(legend []struct{ KeySlug, Name string }, activeKey st
 	l.SetText(builder.String())
 }
 
+// SetDisabled dims the given keys in the legend, while their action is unavailable.
+func (l *Legend) SetDisabled(keySlugs []string) {
+	l.disabled = make(map[string]bool, len(keySlugs))
+	for _, keySlug := range keySlugs {
+		l.disabled[keySlug] = true
+	}
+	l.SetLegend(l.entries, l.activeKey)
+}
+
 func (l *Legend) SetText(text string) {
 	l.view.SetText(text)
 }
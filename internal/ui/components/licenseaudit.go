@@ -0,0 +1,112 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// LicenseAuditItem is a license and the names of the installed packages using it.
+type LicenseAuditItem struct {
+	License  string
+	Packages []string
+}
+
+// LicenseAudit displays a modal overlay grouping the installed packages by license
+type LicenseAudit struct {
+	pages *tview.Pages
+	list  *tview.List
+	theme *theme.Theme
+}
+
+// NewLicenseAudit creates a new license audit component
+func NewLicenseAudit(theme *theme.Theme) *LicenseAudit {
+	return &LicenseAudit{
+		theme: theme,
+	}
+}
+
+// View returns the license audit pages (for overlay functionality)
+func (l *LicenseAudit) View() *tview.Pages {
+	return l.pages
+}
+
+// HasFocus returns true if the license audit is currently open and focused
+func (l *LicenseAudit) HasFocus() bool {
+	return l.list != nil && l.list.HasFocus()
+}
+
+// Build creates the license audit as an overlay on top of the main content.
+// onSelect is called with the chosen license, onExport when the user asks for a CSV export,
+// and onClose when the overlay is dismissed.
+func (l *LicenseAudit) Build(mainContent tview.Primitive, items []LicenseAuditItem,
+	onSelect func(license string), onExport, onClose func()) *tview.Pages {
+	l.list = tview.NewList().
+		SetHighlightFullLine(true).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true)).
+		SetSecondaryTextColor(l.theme.LegendColor)
+	l.list.SetBackgroundColor(l.theme.ModalBgColor)
+
+	total := 0
+	for _, item := range items {
+		total += len(item.Packages)
+		l.list.AddItem(
+			fmt.Sprintf("[%s]%s[-] (%d)", theme.ColorTag(l.theme.WarningColor), tview.Escape(item.License), len(item.Packages)),
+			"  "+tview.Escape(strings.Join(item.Packages, ", ")), 0, nil)
+	}
+	l.list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		onSelect(items[index].License)
+	})
+
+	l.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'e':
+			onExport()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(l.theme.LegendColor), i18n.T("enter: filter | e: export CSV | esc: close")))
+	hint.SetBackgroundColor(l.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(l.list, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(l.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(l.theme.BorderColor).
+		SetTitle(" " + i18n.T("Licenses (%d installed packages)", total) + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the audit as overlay
+	l.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("licenses", centered, true, true)
+
+	return l.pages
+}
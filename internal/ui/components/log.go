@@ -0,0 +1,82 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Log displays a modal overlay with the output of a failed command, scrolled to its end
+type Log struct {
+	pages *tview.Pages
+	view  *tview.TextView
+	theme *theme.Theme
+}
+
+// NewLog creates a new log component
+func NewLog(theme *theme.Theme) *Log {
+	return &Log{
+		theme: theme,
+	}
+}
+
+// View returns the log pages (for overlay functionality)
+func (l *Log) View() *tview.Pages {
+	return l.pages
+}
+
+// HasFocus returns true if the log is currently open and focused
+func (l *Log) HasFocus() bool {
+	return l.view != nil && l.view.HasFocus()
+}
+
+// Build creates the log as an overlay on top of the main content.
+// onClose is called when the overlay is dismissed.
+func (l *Log) Build(mainContent tview.Primitive, title, output string, onClose func()) *tview.Pages {
+	l.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true).
+		SetText(tview.Escape(output))
+	l.view.SetBackgroundColor(l.theme.ModalBgColor)
+	l.view.SetTextColor(l.theme.DefaultTextColor)
+	l.view.ScrollToEnd() // Errors are at the end
+
+	l.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Key() == tcell.KeyEnter, event.Rune() == 'q':
+			onClose()
+			return nil
+		}
+		return event // Let the text view scroll
+	})
+
+	hint := fmt.Sprintf("[%s]%s[-]", theme.ColorTag(l.theme.LegendColor), i18n.T("enter/esc: close"))
+	frame := tview.NewFrame(l.view).
+		SetBorders(1, 1, 1, 0, 2, 2).
+		AddText(hint, false, tview.AlignLeft, l.theme.LegendColor)
+	frame.SetBackgroundColor(l.theme.ModalBgColor)
+	frame.SetBorderColor(l.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" " + title + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the frame in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the log as overlay
+	l.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("log", centered, true, true)
+
+	return l.pages
+}
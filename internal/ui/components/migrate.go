@@ -0,0 +1,116 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// MigrateItem is an app installed outside Homebrew, with the cask that can adopt it.
+type MigrateItem struct {
+	App     string // Path of the app, e.g. /Applications/Firefox.app
+	Cask    string
+	Version string
+}
+
+// Migrate displays a modal overlay with the apps installed outside Homebrew that casks can adopt
+type Migrate struct {
+	pages *tview.Pages
+	table *tview.Table
+	theme *theme.Theme
+}
+
+// NewMigrate creates a new migration component
+func NewMigrate(theme *theme.Theme) *Migrate {
+	return &Migrate{
+		theme: theme,
+	}
+}
+
+// View returns the migration pages (for overlay functionality)
+func (m *Migrate) View() *tview.Pages {
+	return m.pages
+}
+
+// HasFocus returns true if the migration view is currently open and focused
+func (m *Migrate) HasFocus() bool {
+	return m.table != nil && m.table.HasFocus()
+}
+
+// Build creates the migration view as an overlay on top of the main content.
+// onAdopt is called with the selected app, onAdoptAll to adopt all of them, and onClose when the overlay is dismissed.
+func (m *Migrate) Build(mainContent tview.Primitive, items []MigrateItem,
+	onAdopt func(item MigrateItem), onAdoptAll, onClose func()) *tview.Pages {
+	m.table = tview.NewTable().
+		SetSelectable(len(items) > 0, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	m.table.SetBackgroundColor(m.theme.ModalBgColor)
+
+	if len(items) == 0 {
+		m.table.SetCell(0, 0, tview.NewTableCell(i18n.T("All the apps with a cask are already managed by Homebrew.")).
+			SetTextColor(m.theme.DefaultTextColor))
+	}
+	for row, item := range items {
+		m.table.SetCell(row, 0, tview.NewTableCell(tview.Escape(item.App)).SetTextColor(m.theme.DefaultTextColor))
+		m.table.SetCell(row, 1, tview.NewTableCell(m.theme.Symbols.Arrow+" "+tview.Escape(item.Cask)).
+			SetTextColor(m.theme.SuccessColor))
+		m.table.SetCell(row, 2, tview.NewTableCell(tview.Escape(item.Version)).SetTextColor(m.theme.LegendColor))
+	}
+
+	m.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			if row, _ := m.table.GetSelection(); row < len(items) {
+				onAdopt(items[row])
+			}
+			return nil
+		case event.Rune() == 'a' && len(items) > 0:
+			onAdoptAll()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(m.theme.LegendColor),
+			i18n.T("enter: adopt | a: adopt all | esc: close")))
+	hint.SetBackgroundColor(m.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(m.table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(m.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(m.theme.BorderColor).
+		SetTitle(" " + i18n.T("Migrate to Homebrew (%d apps)", len(items)) + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the migration view as overlay
+	m.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("migrate", centered, true, true)
+
+	return m.pages
+}
@@ -1,15 +1,50 @@
 package components
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/ui/theme"
+	"fmt"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// ModalButton identifies a button of the confirmation modal.
+type ModalButton int
+
+const (
+	ModalConfirm ModalButton = iota
+	ModalCancel
+)
+
+// ModalOptions configures a confirmation modal.
+type ModalOptions struct {
+	Text    string
+	Confirm func()
+	Cancel  func()
+	Default ModalButton // Button focused when the modal opens, activated by Enter
+
+	// ActionType enables the "don't ask again for this session" checkbox for this kind of action.
+	// Leave it empty for actions that must always be confirmed.
+	ActionType string
+
+	// Option adds a checkbox for an option of the action, toggled by its key. Confirm reads its value.
+	Option *ModalOption
+}
+
+// ModalOption is a checkbox of a confirmation modal, e.g. a flag of the command to run.
+type ModalOption struct {
+	Label string
+	Key   rune
+	Value *bool
+}
+
 type Modal struct {
-	view  *tview.Modal
-	theme *theme.Theme
+	view    *tview.Modal
+	theme   *theme.Theme
+	options ModalOptions
+	dontAsk bool            // State of the "don't ask again" checkbox
+	skipped map[string]bool // Action types confirmed without asking for the rest of the session
 }
 
 func NewModal(theme *theme.Theme) *Modal {
@@ -27,30 +62,110 @@ func NewModal(theme *theme.Theme) *Modal {
 		SetButtonTextColor(theme.ButtonTextColor).
 		SetButtonActivatedStyle(activatedStyle)
 
-	return &Modal{
-		view:  modal,
-		theme: theme,
+	m := &Modal{
+		view:    modal,
+		theme:   theme,
+		skipped: make(map[string]bool),
 	}
+	modal.SetInputCapture(m.handleKey)
+	return m
 }
 
 func (m *Modal) View() *tview.Modal {
 	return m.view
 }
 
-func (m *Modal) Build(text string, confirmFunc func(), cancelFunc func()) *tview.Modal {
+// HasFocus returns true if the modal is currently open and focused
+func (m *Modal) HasFocus() bool {
+	return m.view.HasFocus()
+}
+
+// Skipped returns true if the user chose not to be asked again for this action type.
+func (m *Modal) Skipped(actionType string) bool {
+	return actionType != "" && m.skipped[actionType]
+}
+
+// Build prepares the modal for the given options.
+// y confirms, n and Esc cancel, Enter activates the focused button, d toggles the "don't ask again" checkbox.
+func (m *Modal) Build(options ModalOptions) *tview.Modal {
+	m.options = options
+	m.dontAsk = false
+
 	m.view.ClearButtons()
 	m.view.
-		SetText(text).
+		SetText(m.text()).
 		// Add padding to button labels with spaces for better visual appearance
-		AddButtons([]string{"  Confirm  ", "  Cancel  "}).
+		AddButtons([]string{"  " + i18n.T("Confirm") + "  ", "  " + i18n.T("Cancel") + "  "}).
 		SetDoneFunc(func(buttonIndex int, _ string) {
-			switch buttonIndex {
-			case 0:
-				confirmFunc()
-			case 1:
-				cancelFunc()
+			if buttonIndex == int(ModalConfirm) {
+				m.confirm()
+			} else {
+				m.options.Cancel() // Cancel button, or Esc (-1)
 			}
-		})
+		}).
+		SetFocus(int(options.Default))
 
 	return m.view
 }
+
+// UpdateText replaces the text of the open modal, e.g. with details loaded after it opened.
+// It only does if the text is still from, so that a late update never lands in another modal.
+func (m *Modal) UpdateText(from, to string) {
+	if m.options.Text != from || !m.view.HasFocus() {
+		return
+	}
+	m.options.Text = to
+	m.view.SetText(m.text())
+}
+
+// confirm records the "don't ask again" choice and runs the confirmation
+func (m *Modal) confirm() {
+	if m.dontAsk && m.options.ActionType != "" {
+		m.skipped[m.options.ActionType] = true
+	}
+	m.options.Confirm()
+}
+
+// handleKey handles the y/n shortcuts and the checkbox key
+func (m *Modal) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Rune() {
+	case 'y', 'Y':
+		m.confirm()
+		return nil
+	case 'n', 'N':
+		m.options.Cancel()
+		return nil
+	case 'd', 'D':
+		if m.options.ActionType != "" {
+			m.dontAsk = !m.dontAsk
+			m.view.SetText(m.text())
+		}
+		return nil
+	}
+	if option := m.options.Option; option != nil && event.Rune() == option.Key {
+		*option.Value = !*option.Value
+		m.view.SetText(m.text())
+		return nil
+	}
+	return event
+}
+
+// text returns the modal text, with the checkbox and the shortcuts
+func (m *Modal) text() string {
+	text := m.options.Text
+	if option := m.options.Option; option != nil {
+		text += "\n\n" + checkbox(*option.Value) + fmt.Sprintf("%s (%c)", option.Label, option.Key)
+	}
+	if m.options.ActionType != "" {
+		text += "\n\n" + checkbox(m.dontAsk) + i18n.T("Don't ask again this session (d)")
+	}
+	return text + "\n\n" + i18n.T("y: confirm | n: cancel")
+}
+
+// checkbox returns a checkbox in the given state, escaped for the modal text
+func checkbox(checked bool) string {
+	if checked {
+		return tview.Escape("[x] ")
+	}
+	return tview.Escape("[ ] ")
+}
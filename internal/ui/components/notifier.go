@@ -1,14 +1,48 @@
 package components
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/ui/theme"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// NotifierAction is a button of an interactive notification.
+type NotifierAction struct {
+	Label string
+	Run   func()
+}
+
+// defaultSuccessExpiry is how long success messages stay before the status comes back.
+const defaultSuccessExpiry = 5 * time.Second
+
+// spinnerInterval is the time between two frames of the spinner of in-progress messages.
+const spinnerInterval = 100 * time.Millisecond
+
 type Notifier struct {
 	view  *tview.TextView
 	theme *theme.Theme
+
+	// Interactive notifications: the message stays with its actions until another notification replaces it
+	mu       sync.Mutex
+	message  string
+	actions  []NotifierAction
+	selected int
+	onDone   func()
+	focused  bool // Whether the view has the focus, tracked as the view can't be asked from its focus callbacks
+
+	generation int           // Incremented by each notification, stopping the spinner or expiry of the previous one
+	idle       bool          // No notification is shown, only the status
+	status     string        // Persistent status, shown whenever no notification is
+	pinned     bool          // An error is shown until dismissed (see Dismiss)
+	hidden     func()        // Shows the latest notification received while an error was pinned
+	expiry     time.Duration // 0 keeps success messages until replaced
+	redraw     func()        // Redraws the screen after a change from a timer
 }
 
 func NewNotifier(theme *theme.Theme) *Notifier {
@@ -16,28 +50,280 @@ func NewNotifier(theme *theme.Theme) *Notifier {
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignRight)
 
-	return &Notifier{
-		view:  notifierView,
-		theme: theme,
+	n := &Notifier{
+		view:   notifierView,
+		theme:  theme,
+		idle:   true,
+		expiry: defaultSuccessExpiry,
+		redraw: func() {},
 	}
+	notifierView.SetInputCapture(n.handleKey)
+	notifierView.SetFocusFunc(func() { n.setFocused(true) })
+	notifierView.SetBlurFunc(func() { n.setFocused(false) })
+	return n
+}
+
+// setFocused records a focus change and renders the actions again. The view is locked while it calls
+// its focus callbacks, so the rendering and the redraw happen outside of them.
+func (n *Notifier) setFocused(focused bool) {
+	n.mu.Lock()
+	n.focused = focused
+	redraw := n.redraw
+	n.mu.Unlock()
+	go func() {
+		n.render()
+		redraw()
+	}()
 }
 
 func (n *Notifier) View() *tview.TextView {
 	return n.view
 }
 
+// HasFocus returns true if the actions of an interactive notification are focused
+func (n *Notifier) HasFocus() bool {
+	return n.view.HasFocus()
+}
+
+// SetRedraw sets the function redrawing the screen when the spinner turns or a message expires,
+// which happens outside of the event loop.
+func (n *Notifier) SetRedraw(redraw func()) {
+	n.mu.Lock()
+	n.redraw = redraw
+	n.mu.Unlock()
+}
+
+// SetSuccessExpiry sets how long success messages stay, 0 to keep them until replaced.
+func (n *Notifier) SetSuccessExpiry(expiry time.Duration) {
+	n.mu.Lock()
+	n.expiry = expiry
+	n.mu.Unlock()
+}
+
+// SetStatus sets the persistent status, shown whenever no notification is, e.g. while recording a macro.
+// An empty status removes it.
+func (n *Notifier) SetStatus(status string) {
+	n.mu.Lock()
+	n.status = status
+	idle := n.idle
+	n.mu.Unlock()
+	if idle {
+		n.Clear()
+	}
+}
+
+// ShowSuccess shows a success message, replaced by the status after a few seconds (see SetSuccessExpiry).
 func (n *Notifier) ShowSuccess(message string) {
-	n.view.SetTextColor(n.theme.SuccessColor).SetText(fmt.Sprintf(" %s ", message))
+	n.show(n.theme.SuccessColor, message, false, true)
 }
 
 func (n *Notifier) ShowWarning(message string) {
-	n.view.SetTextColor(n.theme.WarningColor).SetText(fmt.Sprintf(" %s ", message))
+	n.show(n.theme.WarningColor, message, false, false)
 }
 
+// ShowProgress shows the message of an operation in progress, with a spinner until another notification
+// replaces it.
+func (n *Notifier) ShowProgress(message string) {
+	n.show(n.theme.WarningColor, message, true, false)
+}
+
+// ShowError shows an error, pinned until the user dismisses it: notifications received meanwhile
+// wait behind it.
 func (n *Notifier) ShowError(message string) {
-	n.view.SetTextColor(n.theme.ErrorColor).SetText(fmt.Sprintf(" %s ", message))
+	n.mu.Lock()
+	n.generation++
+	n.message, n.actions = "", nil
+	n.idle, n.pinned, n.hidden = false, true, nil
+	n.mu.Unlock()
+	n.view.SetTextColor(n.theme.ErrorColor).SetText(fmt.Sprintf(" %s [%s]%s[-] ", message,
+		theme.ColorTag(n.theme.LegendColor), i18n.T("(esc: dismiss)")))
+}
+
+// show displays a notification, with a spinner or expiring after a while, unless an error is pinned:
+// then it waits for the error to be dismissed.
+func (n *Notifier) show(color tcell.Color, message string, spin, expire bool) {
+	n.mu.Lock()
+	if n.pinned {
+		n.hidden = func() { n.show(color, message, spin, expire) }
+		n.mu.Unlock()
+		return
+	}
+	n.generation++
+	generation := n.generation
+	n.message, n.actions = "", nil
+	n.idle = false
+	expiry := n.expiry
+	n.mu.Unlock()
+
+	spin = spin && !n.theme.ReducedMotion
+	frames := []rune(n.theme.Symbols.Spinner)
+	if spin && len(frames) > 0 {
+		n.view.SetTextColor(color).SetText(fmt.Sprintf(" %c %s ", frames[0], message))
+		go n.spin(generation, message, frames)
+	} else {
+		n.view.SetTextColor(color).SetText(fmt.Sprintf(" %s ", message))
+	}
+	// Messages don't go away on their own with reduced motion
+	if expire && expiry > 0 && !n.theme.ReducedMotion {
+		time.AfterFunc(expiry, func() { n.expire(generation) })
+	}
 }
 
+// spin turns the spinner of an in-progress message until another notification replaces it.
+func (n *Notifier) spin(generation int, message string, frames []rune) {
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+	for frame := 1; ; frame++ {
+		<-ticker.C
+		n.mu.Lock()
+		if n.generation != generation {
+			n.mu.Unlock()
+			return
+		}
+		redraw := n.redraw
+		n.view.SetText(fmt.Sprintf(" %c %s ", frames[frame%len(frames)], message))
+		n.mu.Unlock()
+		redraw()
+	}
+}
+
+// expire replaces a success message with the status, unless another notification replaced it meanwhile.
+func (n *Notifier) expire(generation int) {
+	n.mu.Lock()
+	current, redraw := n.generation == generation, n.redraw
+	n.mu.Unlock()
+	if current {
+		n.Clear()
+		redraw()
+	}
+}
+
+// ShowErrorActions shows an error with actions, which the user can pick once the notifier is focused (see Activate).
+func (n *Notifier) ShowErrorActions(message string, actions []NotifierAction) {
+	n.mu.Lock()
+	n.generation++
+	n.message, n.actions, n.selected = message, actions, 0
+	n.idle, n.pinned, n.hidden = false, true, nil
+	n.mu.Unlock()
+	n.view.SetTextColor(n.theme.ErrorColor)
+	n.render()
+}
+
+// HasActions returns true if the current notification has actions.
+func (n *Notifier) HasActions() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.actions) > 0
+}
+
+// Activate prepares the actions of the current notification for selection: left/right select one,
+// Enter runs it and Esc dismisses the selection. onDone is called in both cases, to move the focus back;
+// the caller focuses the notifier view.
+func (n *Notifier) Activate(onDone func()) {
+	n.mu.Lock()
+	n.onDone = onDone
+	n.mu.Unlock()
+}
+
+// IsPinned returns true while an error waits to be dismissed.
+func (n *Notifier) IsPinned() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.pinned
+}
+
+// Dismiss removes a pinned error, showing the latest notification it hid, or the status.
+func (n *Notifier) Dismiss() {
+	n.mu.Lock()
+	hidden := n.hidden
+	n.pinned, n.hidden = false, nil
+	n.mu.Unlock()
+	if hidden != nil {
+		hidden()
+		return
+	}
+	n.Clear()
+}
+
+// Clear removes the notification, showing the status instead. A pinned error stays until dismissed,
+// only the notification waiting behind it is removed.
 func (n *Notifier) Clear() {
-	n.view.Clear()
+	n.mu.Lock()
+	if n.pinned {
+		n.hidden = nil
+		n.mu.Unlock()
+		return
+	}
+	n.generation++
+	n.message, n.actions = "", nil
+	n.idle = true
+	status := n.status
+	n.mu.Unlock()
+	if status == "" {
+		n.view.Clear()
+		return
+	}
+	n.view.SetTextColor(n.theme.WarningColor).SetText(fmt.Sprintf(" %s ", status))
+}
+
+// handleKey selects and runs the actions of a focused interactive notification
+func (n *Notifier) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	n.mu.Lock()
+	actions, onDone := n.actions, n.onDone
+	switch {
+	case len(actions) == 0: // Replaced by another notification meanwhile
+		n.mu.Unlock()
+		if onDone != nil {
+			onDone()
+		}
+		return nil
+	case event.Key() == tcell.KeyLeft || event.Key() == tcell.KeyBacktab || event.Rune() == 'h':
+		n.selected = (n.selected + len(actions) - 1) % len(actions)
+	case event.Key() == tcell.KeyRight || event.Key() == tcell.KeyTab || event.Rune() == 'l':
+		n.selected = (n.selected + 1) % len(actions)
+	case event.Key() == tcell.KeyEnter:
+		action := actions[n.selected]
+		n.pinned, n.hidden = false, nil // The action shows its own notifications
+		n.mu.Unlock()
+		if onDone != nil {
+			onDone()
+		}
+		action.Run()
+		return nil
+	case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+		n.mu.Unlock()
+		if onDone != nil {
+			onDone()
+		}
+		return nil
+	}
+	n.mu.Unlock()
+	n.render()
+	return nil
+}
+
+// render draws an interactive notification: the message and its actions, the selected one highlighted while focused
+func (n *Notifier) render() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.actions) == 0 {
+		return
+	}
+
+	focused := n.focused
+	labels := make([]string, len(n.actions))
+	for i, action := range n.actions {
+		label := tview.Escape("[" + action.Label + "]")
+		if focused && i == n.selected {
+			// Same style as the activated buttons of the modal
+			label = fmt.Sprintf("[black:%s:b]%s[-:-:-]", theme.ColorTag(n.theme.SuccessColor), label)
+		}
+		labels[i] = label
+	}
+
+	hint := ""
+	if !focused {
+		hint = " " + i18n.T("(!: actions)")
+	}
+	n.view.SetText(fmt.Sprintf(" %s  %s%s ", n.message, strings.Join(labels, " "), hint))
 }
@@ -1,27 +1,46 @@
 package components
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/rivo/tview"
 )
 
+// outputMaxLines is how much output the session keeps: the oldest lines are dropped beyond it.
+const outputMaxLines = 10000
+
 type Output struct {
 	view  *tview.TextView
 	theme *theme.Theme
+
+	// Operations of the session, by the region of their separator ("op1", "op2", ...), the one
+	// jumped to with JumpToOperation, -1 when following the end of the output, and the oldest one
+	// still in the output, the separators of the older ones trimmed with the oldest lines
+	operations int
+	current    int
+	oldest     int
 }
 
 func NewOutput(theme *theme.Theme) *Output {
 	output := &Output{
-		view:  tview.NewTextView(),
-		theme: theme,
+		view:    tview.NewTextView(),
+		theme:   theme,
+		current: -1,
+		oldest:  1,
 	}
 
 	output.view.SetDynamicColors(true)
+	output.view.SetRegions(true) // Operation separators, to jump between them
+	output.view.SetMaxLines(outputMaxLines)
 	output.view.SetScrollable(true)
 	output.view.SetWrap(true)
 	output.view.SetTextAlign(tview.AlignLeft)
 	output.view.SetBorder(true)
-	output.view.SetTitle("Output")
+	output.view.SetTitle(i18n.T("Output"))
 	output.view.SetTitleColor(theme.TitleColor)
 	output.view.SetTitleAlign(tview.AlignLeft)
 	output.view.SetBorderPadding(0, 0, 1, 1)
@@ -36,6 +55,65 @@ func (o *Output) Clear() {
 	o.view.Clear()
 }
 
+// BeginOperation starts the output of an operation under the output of the previous ones, with a
+// separator line giving the time and the name of the operation, e.g. "── 14:02:11 installing wget… ──".
+func (o *Output) BeginOperation(name string) {
+	o.operations++
+	o.current = -1
+	o.view.Highlight()
+	if o.view.GetText(false) != "" {
+		fmt.Fprintln(o.view)
+	}
+	rule := strings.Repeat(o.theme.Symbols.Separator, 2)
+	fmt.Fprintf(o.view, "[\"%s\"][%s::b]%s %s %s %s[-::-][\"\"]\n", operationRegion(o.operations),
+		theme.ColorTag(o.theme.SectionTitleColor), rule, time.Now().Format("15:04:05"), tview.Escape(name), rule)
+	o.view.ScrollToEnd()
+}
+
+// JumpToOperation scrolls to the separator of the previous (-1) or next (1) operation, from the one
+// jumped to last or from the end. Jumping past the last operation follows the end of the output again.
+// Operations trimmed from the output are skipped. Returns false when there is no operation to jump to.
+func (o *Output) JumpToOperation(direction int) bool {
+	oldest := o.oldestOperation()
+	if oldest > o.operations || (o.current < 0 && direction > 0) {
+		return false
+	}
+	current := o.current
+	if current < 0 {
+		current = o.operations + 1 // The end, after the last operation
+	}
+	current += direction
+	if current < oldest {
+		if direction < 0 {
+			return false
+		}
+		current = oldest // The one jumped to last was trimmed meanwhile
+	}
+	if current > o.operations {
+		o.current = -1
+		o.view.Highlight()
+		o.view.ScrollToEnd()
+		return true
+	}
+	o.current = current
+	o.view.Highlight(operationRegion(current)).ScrollToHighlight()
+	return true
+}
+
+// oldestOperation returns the oldest operation whose separator is still in the output, past the last
+// operation when none is: the output keeps outputMaxLines lines, dropping the oldest when drawn.
+func (o *Output) oldestOperation() int {
+	for o.oldest <= o.operations && o.view.GetRegionText(operationRegion(o.oldest)) == "" {
+		o.oldest++
+	}
+	return o.oldest
+}
+
+// operationRegion returns the region of the separator of an operation.
+func operationRegion(operation int) string {
+	return fmt.Sprintf("op%d", operation)
+}
+
 func (o *Output) Write(text string) {
 	o.view.SetText(text)
 }
@@ -0,0 +1,145 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ProfileItem is a named package set of the config.
+type ProfileItem struct {
+	Name     string
+	Brewfile string
+	Filter   string
+	Group    string
+	Current  bool // The profile is open
+}
+
+// Profiles displays a modal overlay to pick a profile
+type Profiles struct {
+	pages *tview.Pages
+	table *tview.Table
+	theme *theme.Theme
+}
+
+// NewProfiles creates a new profile picker component
+func NewProfiles(theme *theme.Theme) *Profiles {
+	return &Profiles{
+		theme: theme,
+	}
+}
+
+// View returns the profile picker pages (for overlay functionality)
+func (p *Profiles) View() *tview.Pages {
+	return p.pages
+}
+
+// HasFocus returns true if the profile picker is currently open and focused
+func (p *Profiles) HasFocus() bool {
+	return p.table != nil && p.table.HasFocus()
+}
+
+// Build creates the profile picker as an overlay on top of the main content.
+// onOpen is called with the selected profile to switch to it, onDiff to compare it with this machine,
+// onSave to save the packages shown as a new profile, and onClose when the overlay is dismissed.
+func (p *Profiles) Build(mainContent tview.Primitive, items []ProfileItem,
+	onOpen, onDiff func(item ProfileItem), onSave, onClose func()) *tview.Pages {
+	p.table = tview.NewTable().
+		SetSelectable(len(items) > 0, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	p.table.SetBackgroundColor(p.theme.ModalBgColor)
+
+	if len(items) == 0 {
+		p.table.SetCell(0, 0, tview.NewTableCell(i18n.T("No profiles yet: press s to save the packages shown as one.")).
+			SetTextColor(p.theme.LegendColor))
+	}
+	for row, item := range items {
+		name := " " + tview.Escape(item.Name)
+		if item.Current {
+			name = fmt.Sprintf(" [%s::b]%s[-:-:-]", theme.ColorTag(p.theme.SuccessColor), tview.Escape(item.Name))
+		}
+		details := item.Brewfile
+		if item.Group != "" {
+			details += " " + i18n.T("(group %s)", item.Group)
+		}
+		if item.Filter != "" {
+			details += " " + i18n.T("(filter %s)", item.Filter)
+		}
+		p.table.SetCell(row, 0, tview.NewTableCell(name).SetTextColor(p.theme.DefaultTextColor))
+		p.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(details)).SetTextColor(p.theme.LegendColor))
+	}
+	for row, item := range items {
+		if item.Current {
+			p.table.Select(row, 0)
+		}
+	}
+
+	selected := func() (ProfileItem, bool) {
+		row, _ := p.table.GetSelection()
+		if row < len(items) {
+			return items[row], true
+		}
+		return ProfileItem{}, false
+	}
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			if item, ok := selected(); ok {
+				onOpen(item)
+			}
+			return nil
+		case event.Rune() == 'd':
+			if item, ok := selected(); ok {
+				onDiff(item)
+			}
+			return nil
+		case event.Rune() == 's':
+			onSave()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(p.theme.LegendColor),
+			i18n.T("enter: open | d: compare with this machine | s: save the packages shown | esc: close")))
+	hint.SetBackgroundColor(p.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(p.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(p.theme.BorderColor).
+		SetTitle(" " + i18n.T("Profiles") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 2, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the picker as overlay
+	p.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("profiles", centered, true, true)
+
+	return p.pages
+}
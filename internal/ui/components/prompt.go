@@ -0,0 +1,95 @@
+package components
+
+import (
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Prompt displays a modal overlay asking for a line of text, e.g. the note of a package or a file path
+type Prompt struct {
+	pages *tview.Pages
+	field *tview.InputField
+	theme *theme.Theme
+}
+
+// NewPrompt creates a new prompt component
+func NewPrompt(theme *theme.Theme) *Prompt {
+	return &Prompt{
+		theme: theme,
+	}
+}
+
+// View returns the prompt pages (for overlay functionality)
+func (p *Prompt) View() *tview.Pages {
+	return p.pages
+}
+
+// HasFocus returns true if the prompt is currently open and focused
+func (p *Prompt) HasFocus() bool {
+	return p.field != nil && p.field.HasFocus()
+}
+
+// Build creates the prompt as an overlay on top of the main content, with the given title, hint and initial text.
+// onDone is called with the entered text when Enter is pressed, onCancel when Esc is pressed.
+func (p *Prompt) Build(mainContent tview.Primitive, title, hint, text string, onDone func(text string), onCancel func()) *tview.Pages {
+	p.field = tview.NewInputField().
+		SetText(text).
+		SetFieldBackgroundColor(p.theme.DefaultBgColor).
+		SetFieldTextColor(p.theme.DefaultTextColor)
+	p.field.SetBackgroundColor(p.theme.ModalBgColor)
+	p.field.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			onDone(p.field.GetText())
+		case tcell.KeyEscape:
+			onCancel()
+		}
+	})
+
+	hintView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(p.theme.LegendColor), hint))
+	hintView.SetBackgroundColor(p.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.field, 1, 0, true).
+		AddItem(nil, 1, 0, false).
+		AddItem(hintView, 1, 0, false)
+	content.SetBackgroundColor(p.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(p.theme.BorderColor).
+		SetTitle(" " + title + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Calculate box dimensions
+	boxHeight := 5
+	boxWidth := 70
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, boxHeight, 0, true).
+			AddItem(nil, 0, 1, false),
+			boxWidth, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the prompt as overlay
+	p.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("prompt", centered, true, true)
+
+	return p.pages
+}
+
+// BuildPassword creates the prompt like Build, masking the entered text, e.g. for the password of sudo.
+func (p *Prompt) BuildPassword(mainContent tview.Primitive, title, hint string, onDone func(password string), onCancel func()) *tview.Pages {
+	pages := p.Build(mainContent, title, hint, "", onDone, onCancel)
+	p.field.SetMaskCharacter('*')
+	return pages
+}
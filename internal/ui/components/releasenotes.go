@@ -0,0 +1,92 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ReleaseNotes displays a modal overlay with the changelog of a new version,
+// either of Bold Brew itself or of an outdated package
+type ReleaseNotes struct {
+	pages *tview.Pages
+	view  *tview.TextView
+	theme *theme.Theme
+}
+
+// NewReleaseNotes creates a new release notes component
+func NewReleaseNotes(theme *theme.Theme) *ReleaseNotes {
+	return &ReleaseNotes{
+		theme: theme,
+	}
+}
+
+// View returns the release notes pages (for overlay functionality)
+func (r *ReleaseNotes) View() *tview.Pages {
+	return r.pages
+}
+
+// HasFocus returns true if the release notes are currently open and focused
+func (r *ReleaseNotes) HasFocus() bool {
+	return r.view != nil && r.view.HasFocus()
+}
+
+// SetNotes replaces the displayed notes (e.g. once they have been fetched).
+func (r *ReleaseNotes) SetNotes(notes string) {
+	if r.view != nil {
+		r.view.SetText(tview.Escape(notes)).ScrollToBeginning()
+	}
+}
+
+// Build creates the release notes as an overlay on top of the main content.
+// onUpdate is called when the user asks to update now, onClose when the overlay is dismissed.
+func (r *ReleaseNotes) Build(mainContent tview.Primitive, title string, onUpdate, onClose func()) *tview.Pages {
+	r.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true).
+		SetText(i18n.T("Loading release notes..."))
+	r.view.SetBackgroundColor(r.theme.ModalBgColor)
+	r.view.SetTextColor(r.theme.DefaultTextColor)
+
+	r.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'u':
+			onUpdate()
+			return nil
+		}
+		return event // Let the text view scroll
+	})
+
+	hint := fmt.Sprintf("[%s]%s[-]", theme.ColorTag(r.theme.LegendColor), i18n.T("u: update now | esc: close"))
+	frame := tview.NewFrame(r.view).
+		SetBorders(1, 1, 1, 0, 2, 2).
+		AddText(hint, false, tview.AlignLeft, r.theme.LegendColor)
+	frame.SetBackgroundColor(r.theme.ModalBgColor)
+	frame.SetBorderColor(r.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" " + title + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the frame in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the notes as overlay
+	r.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("releasenotes", centered, true, true)
+
+	return r.pages
+}
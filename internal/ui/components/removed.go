@@ -0,0 +1,119 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// RemovedItem is a package removed recently, which can be reinstalled.
+type RemovedItem struct {
+	Name      string
+	Cask      bool
+	Version   string
+	Options   []string
+	RemovedAt time.Time
+}
+
+// Removed displays a modal overlay with the recently removed packages, to restore them
+type Removed struct {
+	pages *tview.Pages
+	table *tview.Table
+	theme *theme.Theme
+}
+
+// NewRemoved creates a new recently removed packages component
+func NewRemoved(theme *theme.Theme) *Removed {
+	return &Removed{
+		theme: theme,
+	}
+}
+
+// View returns the recently removed packages pages (for overlay functionality)
+func (r *Removed) View() *tview.Pages {
+	return r.pages
+}
+
+// HasFocus returns true if the recently removed packages are currently open and focused
+func (r *Removed) HasFocus() bool {
+	return r.table != nil && r.table.HasFocus()
+}
+
+// Build creates the recently removed packages as an overlay on top of the main content, newest first.
+// onRestore is called with the selected package to reinstall it, onClose when the overlay is dismissed.
+func (r *Removed) Build(mainContent tview.Primitive, items []RemovedItem, onRestore func(item RemovedItem), onClose func()) *tview.Pages {
+	r.table = tview.NewTable().
+		SetSelectable(len(items) > 0, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	r.table.SetBackgroundColor(r.theme.ModalBgColor)
+
+	if len(items) == 0 {
+		r.table.SetCell(0, 0, tview.NewTableCell(i18n.T("No packages removed yet.")).SetTextColor(r.theme.LegendColor))
+	}
+	for row, item := range items {
+		name := item.Name
+		if item.Cask {
+			name += " (cask)"
+		}
+		r.table.SetCell(row, 0, tview.NewTableCell(" "+tview.Escape(name)).SetTextColor(r.theme.DefaultTextColor))
+		r.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(item.Version)).SetTextColor(r.theme.LegendColor))
+		r.table.SetCell(row, 2, tview.NewTableCell(tview.Escape(strings.Join(item.Options, " "))).SetTextColor(r.theme.LegendColor))
+		r.table.SetCell(row, 3, tview.NewTableCell(i18n.T("%s ago", formatAge(time.Since(item.RemovedAt)))).
+			SetTextColor(r.theme.LegendColor).SetAlign(tview.AlignRight))
+	}
+
+	r.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			if selected, _ := r.table.GetSelection(); selected < len(items) {
+				onRestore(items[selected])
+			}
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(r.theme.LegendColor), i18n.T("enter: reinstall | esc: close")))
+	hint.SetBackgroundColor(r.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(r.table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(r.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(r.theme.BorderColor).
+		SetTitle(" " + i18n.T("Recently removed") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the list as overlay
+	r.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("removed", centered, true, true)
+
+	return r.pages
+}
@@ -1,8 +1,10 @@
 package components
 
 import (
+	"bbrew/internal/i18n"
 	"bbrew/internal/ui/theme"
 	"fmt"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -20,7 +22,7 @@ func NewSearch(theme *theme.Theme) *Search {
 		theme:   theme,
 	}
 
-	search.field.SetLabel(tview.Escape("Search (All): "))
+	search.field.SetLabel(tview.Escape(i18n.T("Search (All): ")))
 	search.field.SetLabelColor(theme.SearchLabelColor)
 	search.field.SetFieldStyle(tcell.StyleDefault.Italic(true).Underline(true))
 	search.field.SetFieldBackgroundColor(theme.DefaultBgColor)
@@ -35,8 +37,26 @@ func (s *Search) SetHandlers(done func(key tcell.Key), changed func(text string)
 	s.field.SetChangedFunc(changed)
 }
 
-func (s *Search) UpdateCounter(total, filtered int) {
-	s.counter.SetText(fmt.Sprintf("Total: %d | Filtered: %d", total, filtered))
+// CounterInfo is what the counter next to the search field reports.
+type CounterInfo struct {
+	Total    int    // Packages of the source: all of them, or those of the Brewfile
+	Filtered int    // Packages shown
+	Formulae int    // Shown packages that are formulae
+	Casks    int    // Shown packages that are casks
+	Filter   string // Name of the active filter, "" for none
+}
+
+// UpdateCounter shows the number of packages, with the composition of those shown and the active filter,
+// e.g. "Total: 9,012 | Filtered: 134 (97 formulae, 37 casks) | Installed".
+func (s *Search) UpdateCounter(info CounterInfo) {
+	text := i18n.T("Total: %d | Filtered: %d", info.Total, info.Filtered)
+	if info.Filtered > 0 {
+		text += " (" + typeCounts(info.Formulae, info.Casks) + ")"
+	}
+	if info.Filter != "" {
+		text += fmt.Sprintf(" | [%s]%s[-]", theme.ColorTag(s.theme.SearchLabelColor), tview.Escape(info.Filter))
+	}
+	s.counter.SetText(text)
 }
 
 func (s *Search) Field() *tview.InputField {
@@ -0,0 +1,151 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ServiceAction is what the user asks of a service, named after the systemctl command running it.
+type ServiceAction string
+
+const (
+	ServiceStart   ServiceAction = "start"
+	ServiceStop    ServiceAction = "stop"
+	ServiceEnable  ServiceAction = "enable"
+	ServiceDisable ServiceAction = "disable"
+)
+
+// ServiceItem is the service of an installed formula, with the state of its unit.
+type ServiceItem struct {
+	Name    string
+	State   string // active, inactive, failed... "" when the unit is not generated yet
+	Enabled bool   // Started at login
+	Command string
+}
+
+// Services displays a modal overlay with the services of the installed formulae
+type Services struct {
+	pages *tview.Pages
+	table *tview.Table
+	theme *theme.Theme
+}
+
+// NewServices creates a new services component
+func NewServices(theme *theme.Theme) *Services {
+	return &Services{
+		theme: theme,
+	}
+}
+
+// View returns the services pages (for overlay functionality)
+func (s *Services) View() *tview.Pages {
+	return s.pages
+}
+
+// HasFocus returns true if the services view is currently open and focused
+func (s *Services) HasFocus() bool {
+	return s.table != nil && s.table.HasFocus()
+}
+
+// Build creates the services view as an overlay on top of the main content, with the given row selected.
+// onAction is called with the row of a service and what to do with it, and onClose when the overlay is dismissed.
+func (s *Services) Build(mainContent tview.Primitive, items []ServiceItem, selected int,
+	onAction func(row int, action ServiceAction), onClose func()) *tview.Pages {
+	s.table = tview.NewTable().
+		SetSelectable(len(items) > 0, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	s.table.SetBackgroundColor(s.theme.ModalBgColor)
+
+	if len(items) == 0 {
+		s.table.SetCell(0, 0, tview.NewTableCell(i18n.T("No installed formula has a service.")).
+			SetTextColor(s.theme.DefaultTextColor))
+	}
+	for row, item := range items {
+		state, stateColor := item.State, s.theme.LegendColor
+		switch item.State {
+		case "":
+			state = i18n.T("not generated")
+		case "active":
+			stateColor = s.theme.SuccessColor
+		case "failed":
+			stateColor = s.theme.ErrorColor
+		}
+		enabled := ""
+		if item.Enabled {
+			enabled = i18n.T("at login")
+		}
+		s.table.SetCell(row, 0, tview.NewTableCell(tview.Escape(item.Name)).SetTextColor(s.theme.DefaultTextColor))
+		s.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(state)).SetTextColor(stateColor))
+		s.table.SetCell(row, 2, tview.NewTableCell(enabled).SetTextColor(s.theme.SuccessColor))
+		s.table.SetCell(row, 3, tview.NewTableCell(tview.Escape(item.Command)).SetTextColor(s.theme.LegendColor).
+			SetExpansion(1))
+	}
+	if selected < len(items) {
+		s.table.Select(selected, 0)
+	}
+
+	s.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := s.table.GetSelection()
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		case row >= len(items):
+			return event
+		case event.Rune() == 's':
+			onAction(row, ServiceStart)
+			return nil
+		case event.Rune() == 'x':
+			onAction(row, ServiceStop)
+			return nil
+		case event.Rune() == 'e' && items[row].Enabled:
+			onAction(row, ServiceDisable)
+			return nil
+		case event.Rune() == 'e':
+			onAction(row, ServiceEnable)
+			return nil
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(s.theme.LegendColor),
+			i18n.T("s: start | x: stop | e: start at login or not | esc: close")))
+	hint.SetBackgroundColor(s.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(s.table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(s.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(s.theme.BorderColor).
+		SetTitle(" " + i18n.T("Services (systemd user units)") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 3, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the services as overlay
+	s.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("services", centered, true, true)
+
+	return s.pages
+}
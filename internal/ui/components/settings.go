@@ -0,0 +1,116 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// SettingItem is a setting of the settings screen, with its current value.
+type SettingItem struct {
+	Name        string
+	Value       string
+	Description string
+	Toggle      bool // Enter toggles the setting
+}
+
+// Settings displays a modal overlay with settings, toggled with Enter
+type Settings struct {
+	pages *tview.Pages
+	table *tview.Table
+	theme *theme.Theme
+}
+
+// NewSettings creates a new settings component
+func NewSettings(theme *theme.Theme) *Settings {
+	return &Settings{
+		theme: theme,
+	}
+}
+
+// View returns the settings pages (for overlay functionality)
+func (s *Settings) View() *tview.Pages {
+	return s.pages
+}
+
+// HasFocus returns true if the settings screen is currently open and focused
+func (s *Settings) HasFocus() bool {
+	return s.table != nil && s.table.HasFocus()
+}
+
+// Build creates the settings screen as an overlay on top of the main content, with the given row selected.
+// onToggle is called with the row of a setting to toggle, and onClose when the overlay is dismissed.
+func (s *Settings) Build(mainContent tview.Primitive, items []SettingItem, selected int,
+	onToggle func(row int), onClose func()) *tview.Pages {
+	s.table = tview.NewTable().
+		SetSelectable(true, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	s.table.SetBackgroundColor(s.theme.ModalBgColor)
+
+	for row, item := range items {
+		valueColor := s.theme.LegendColor
+		if item.Toggle {
+			valueColor = s.theme.SuccessColor
+		}
+		s.table.SetCell(row, 0, tview.NewTableCell(" "+tview.Escape(item.Name)).SetTextColor(s.theme.DefaultTextColor))
+		s.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(item.Value)).SetTextColor(valueColor))
+		s.table.SetCell(row, 2, tview.NewTableCell(tview.Escape(item.Description)).SetTextColor(s.theme.LegendColor).
+			SetExpansion(1))
+	}
+	if selected < len(items) {
+		s.table.Select(selected, 0)
+	}
+
+	s.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			if row, _ := s.table.GetSelection(); row < len(items) && items[row].Toggle {
+				onToggle(row)
+			}
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(s.theme.LegendColor), i18n.T("enter: toggle | esc: close")))
+	hint.SetBackgroundColor(s.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(s.table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(s.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(s.theme.BorderColor).
+		SetTitle(" " + i18n.T("Settings") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 2, true).
+			AddItem(nil, 0, 1, false),
+			0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the settings as overlay
+	s.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("settings", centered, true, true)
+
+	return s.pages
+}
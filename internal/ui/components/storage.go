@@ -0,0 +1,144 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// StorageItem is a package with old versions or downloads that `brew cleanup` would remove, sizes formatted.
+type StorageItem struct {
+	Name        string
+	OldVersions string
+	Kegs        string
+	Downloads   string
+	Total       string
+}
+
+// Storage displays a modal overlay with the disk usage of Homebrew's cache and old versions
+type Storage struct {
+	pages *tview.Pages
+	table *tview.Table
+	theme *theme.Theme
+}
+
+// NewStorage creates a new disk usage component
+func NewStorage(theme *theme.Theme) *Storage {
+	return &Storage{
+		theme: theme,
+	}
+}
+
+// View returns the disk usage pages (for overlay functionality)
+func (s *Storage) View() *tview.Pages {
+	return s.pages
+}
+
+// HasFocus returns true if the disk usage report is currently open and focused
+func (s *Storage) HasFocus() bool {
+	return s.table != nil && s.table.HasFocus()
+}
+
+// Build creates the disk usage report as an overlay on top of the main content: the size of the download
+// cache, then the packages with something to clean up, largest first. onClean is called with the selected
+// package, onPurge to empty the cache, and onClose when the overlay is dismissed.
+func (s *Storage) Build(mainContent tview.Primitive, cacheDir, cacheSize string, items []StorageItem,
+	onClean func(item StorageItem), onPurge, onClose func()) *tview.Pages {
+	s.table = tview.NewTable().
+		SetSelectable(len(items) > 0, false).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true)).
+		SetFixed(3, 0)
+	s.table.SetBackgroundColor(s.theme.ModalBgColor)
+
+	headerCell := func(text string) *tview.TableCell {
+		return tview.NewTableCell(text).SetTextColor(s.theme.LegendColor).SetSelectable(false)
+	}
+	s.table.SetCell(0, 0, tview.NewTableCell(i18n.T("Download cache")).SetTextColor(s.theme.DefaultTextColor).
+		SetSelectable(false))
+	s.table.SetCell(0, 1, tview.NewTableCell(tview.Escape(cacheDir)).SetTextColor(s.theme.LegendColor).SetSelectable(false))
+	s.table.SetCell(0, 4, tview.NewTableCell(tview.Escape(cacheSize)).SetTextColor(s.theme.WarningColor).
+		SetAlign(tview.AlignRight).SetSelectable(false))
+	s.table.SetCell(1, 0, headerCell(""))
+	s.table.SetCell(2, 0, headerCell(i18n.T("Package")))
+	s.table.SetCell(2, 1, headerCell(i18n.T("Old versions")))
+	s.table.SetCell(2, 2, headerCell(i18n.T("Kegs")).SetAlign(tview.AlignRight))
+	s.table.SetCell(2, 3, headerCell(i18n.T("Downloads")).SetAlign(tview.AlignRight))
+	s.table.SetCell(2, 4, headerCell(i18n.T("Total")).SetAlign(tview.AlignRight))
+
+	if len(items) == 0 {
+		s.table.SetCell(3, 0, tview.NewTableCell(i18n.T("No old versions nor downloads to clean up.")).
+			SetTextColor(s.theme.DefaultTextColor).SetSelectable(false))
+	}
+	for i, item := range items {
+		row := i + 3
+		s.table.SetCell(row, 0, tview.NewTableCell(tview.Escape(item.Name)).SetTextColor(s.theme.DefaultTextColor))
+		s.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(item.OldVersions)).SetTextColor(s.theme.LegendColor).
+			SetExpansion(1))
+		s.table.SetCell(row, 2, tview.NewTableCell(tview.Escape(item.Kegs)).SetAlign(tview.AlignRight).
+			SetTextColor(s.theme.LegendColor))
+		s.table.SetCell(row, 3, tview.NewTableCell(tview.Escape(item.Downloads)).SetAlign(tview.AlignRight).
+			SetTextColor(s.theme.LegendColor))
+		s.table.SetCell(row, 4, tview.NewTableCell(tview.Escape(item.Total)).SetAlign(tview.AlignRight).
+			SetTextColor(s.theme.WarningColor))
+	}
+	if len(items) > 0 {
+		s.table.Select(3, 0)
+	}
+
+	s.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			if row, _ := s.table.GetSelection(); row >= 3 && row-3 < len(items) {
+				onClean(items[row-3])
+			}
+			return nil
+		case event.Rune() == 'x':
+			onPurge()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(s.theme.LegendColor),
+			i18n.T("enter: brew cleanup the package | x: purge the cache | esc: close")))
+	hint.SetBackgroundColor(s.theme.ModalBgColor)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(s.table, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(s.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(s.theme.BorderColor).
+		SetTitle(" " + i18n.T("Disk usage of Homebrew") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 3, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the disk usage report as overlay
+	s.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("storage", centered, true, true)
+
+	return s.pages
+}
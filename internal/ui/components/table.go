@@ -2,20 +2,34 @@ package components
 
 import (
 	"bbrew/internal/ui/theme"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
 	"github.com/rivo/tview"
 )
 
+// minFittedWidth is the width a fitted column keeps however wide the other columns are.
+const minFittedWidth = 10
+
 type Table struct {
 	view  *tview.Table
 	theme *theme.Theme
+
+	// Column whose texts are cut in the middle to fit the width left by the others, -1 if none,
+	// with their full texts by row
+	fittedColumn int
+	fittedTexts  map[int]string
+
+	highlight string // Search text highlighted in the fitted column
 }
 
 func NewTable(theme *theme.Theme) *Table {
 	table := &Table{
-		view:  tview.NewTable(),
-		theme: theme,
+		view:         tview.NewTable(),
+		theme:        theme,
+		fittedColumn: -1,
+		fittedTexts:  make(map[int]string),
 	}
 	table.view.SetBorders(false)
 	table.view.SetSelectable(true, false)
@@ -24,6 +38,12 @@ func NewTable(theme *theme.Theme) *Table {
 	// Use reverse video for selection to ensure visibility on any terminal theme
 	table.view.SetSelectedStyle(tcell.StyleDefault.Reverse(true))
 
+	// The fitted column is cut before each draw, to the width of the terminal at the time
+	table.view.SetDrawFunc(func(_ tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		table.fitColumn(width, height)
+		return x, y, width, height
+	})
+
 	return table
 }
 
@@ -37,6 +57,89 @@ func (t *Table) View() *tview.Table {
 
 func (t *Table) Clear() {
 	t.view.Clear()
+	t.fittedColumn = -1
+	clear(t.fittedTexts)
+}
+
+// SetFittedCell sets a cell of the fitted column, e.g. the descriptions: rather than cut at the end by
+// tview, pushing the next columns out, its text is cut in the middle to the width left by the other
+// columns when the table is drawn. text is the full text, unescaped.
+func (t *Table) SetFittedCell(row, column int, cell *tview.TableCell, text string) {
+	t.fittedColumn = column
+	t.fittedTexts[row] = text
+	t.view.SetCell(row, column, cell.SetText(HighlightMatches(t.theme, text, t.highlight)))
+}
+
+// SetHighlight sets the search text highlighted in the cells of the fitted column.
+func (t *Table) SetHighlight(query string) {
+	t.highlight = query
+}
+
+// FittedCellPosition returns where the cell of the fitted column of a row was last drawn,
+// false if the row has none.
+func (t *Table) FittedCellPosition(row int) (x, y, width int, exists bool) {
+	if _, exists := t.fittedTexts[row]; !exists {
+		return 0, 0, 0, false
+	}
+	x, y, width = t.view.GetCell(row, t.fittedColumn).GetLastPosition()
+	return x, y, width, true
+}
+
+// fitColumn cuts the texts of the fitted column in the rows about to be drawn, to the width left by the
+// other columns. tview sizes the columns from the rows it draws, which are around the current offset, or
+// around the selection when the draw scrolls to it.
+func (t *Table) fitColumn(width, height int) {
+	if t.fittedColumn < 0 || len(t.fittedTexts) == 0 {
+		return
+	}
+	rowCount, columnCount := t.view.GetRowCount(), t.view.GetColumnCount()
+	offset, _ := t.view.GetOffset()
+	selected, _ := t.view.GetSelection()
+	var rows []int
+	for row := 1; row < rowCount; row++ {
+		if (row >= offset-height && row <= offset+2*height) || (row >= selected-height && row <= selected+height) {
+			rows = append(rows, row)
+		}
+	}
+
+	others := 0
+	for column := 0; column < columnCount; column++ {
+		if column == t.fittedColumn {
+			continue
+		}
+		columnWidth := 0
+		for _, row := range append([]int{0}, rows...) {
+			if cell := t.view.GetCell(row, column); cell != nil {
+				columnWidth = max(columnWidth, tview.TaggedStringWidth(cell.Text))
+			}
+		}
+		others += columnWidth + 1 // Columns are separated by a space
+	}
+
+	available := max(width-others, minFittedWidth)
+	for _, row := range rows {
+		if text, exists := t.fittedTexts[row]; exists {
+			text = TruncateMiddle(text, available, t.theme.Symbols.Ellipsis)
+			t.view.GetCell(row, t.fittedColumn).SetText(HighlightMatches(t.theme, text, t.highlight))
+		}
+	}
+}
+
+// TruncateMiddle shortens a text to the given width in cells, replacing its middle with the ellipsis,
+// so that both its start and its end stay readable. Wide characters (CJK, emoji) count for two cells.
+func TruncateMiddle(text string, width int, ellipsis string) string {
+	textWidth := runewidth.StringWidth(text)
+	if textWidth <= width {
+		return text
+	}
+	keep := width - runewidth.StringWidth(ellipsis)
+	if keep <= 0 {
+		return runewidth.Truncate(text, width, "")
+	}
+	tailWidth := keep / 3 // The start of a description says more than its end
+	head := strings.TrimRight(runewidth.Truncate(text, keep-tailWidth, ""), " ")
+	tail := strings.TrimLeft(runewidth.TruncateLeft(text, textWidth-tailWidth, ""), " ")
+	return head + ellipsis + tail
 }
 
 func (t *Table) SetTableHeaders(headers ...string) {
@@ -50,3 +153,25 @@ func (t *Table) SetTableHeaders(headers ...string) {
 		})
 	}
 }
+
+// HighlightMatches escapes a text for a table cell, highlighting the occurrences of the query in it,
+// ignoring case, to show why a row matches the search.
+func HighlightMatches(t *theme.Theme, text, query string) string {
+	lower, lowerQuery := strings.ToLower(text), strings.ToLower(query)
+	if query == "" || len(lower) != len(text) { // Lowering changed the offsets: no highlight
+		return tview.Escape(text)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, lowerQuery)
+		if i < 0 {
+			b.WriteString(tview.Escape(text))
+			return b.String()
+		}
+		end := i + len(lowerQuery)
+		b.WriteString(tview.Escape(text[:i]))
+		b.WriteString("[" + theme.ColorTag(t.MatchColor) + "::bu]" + tview.Escape(text[i:end]) + "[-::-]")
+		text, lower = text[end:], lower[end:]
+	}
+}
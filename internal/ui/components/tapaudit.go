@@ -0,0 +1,147 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TapAuditItem is a package of a tap with the problems found by `brew audit` and `brew style`.
+type TapAuditItem struct {
+	Package  string
+	Findings []TapAuditFinding
+}
+
+// TapAuditFinding is a problem found in a package, with the command that reported it.
+type TapAuditFinding struct {
+	Source  string
+	Message string
+}
+
+// TapAudit displays a modal overlay with the packages of a tap having problems, and the problems of the highlighted one
+type TapAudit struct {
+	pages    *tview.Pages
+	list     *tview.List
+	findings *tview.TextView
+	theme    *theme.Theme
+}
+
+// NewTapAudit creates a new tap audit component
+func NewTapAudit(theme *theme.Theme) *TapAudit {
+	return &TapAudit{
+		theme: theme,
+	}
+}
+
+// View returns the tap audit pages (for overlay functionality)
+func (t *TapAudit) View() *tview.Pages {
+	return t.pages
+}
+
+// HasFocus returns true if the tap audit is currently open and focused
+func (t *TapAudit) HasFocus() bool {
+	return t.list != nil && t.list.HasFocus()
+}
+
+// Build creates the tap audit of the given tap as an overlay on top of the main content.
+// onSelect is called with the chosen package, onRerun when the user asks to audit again,
+// and onClose when the overlay is dismissed.
+func (t *TapAudit) Build(mainContent tview.Primitive, tap string, items []TapAuditItem,
+	onSelect func(name string), onRerun, onClose func()) *tview.Pages {
+	t.list = tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	t.list.SetBackgroundColor(t.theme.ModalBgColor)
+
+	t.findings = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWordWrap(true)
+	t.findings.SetBackgroundColor(t.theme.ModalBgColor)
+	t.findings.SetTextColor(t.theme.DefaultTextColor)
+	t.findings.SetBorder(true).SetBorderColor(t.theme.BorderColor)
+
+	total := 0
+	for _, item := range items {
+		total += len(item.Findings)
+		t.list.AddItem(fmt.Sprintf("[%s]%s[-] (%d)", theme.ColorTag(t.theme.WarningColor), tview.Escape(item.Package), len(item.Findings)), "", 0, nil)
+	}
+	if len(items) == 0 {
+		t.findings.SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(t.theme.SuccessColor), i18n.T("No problems found")))
+	} else {
+		t.render(items[0])
+	}
+	t.list.SetChangedFunc(func(index int, _, _ string, _ rune) {
+		t.render(items[index])
+	})
+	t.list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		onSelect(items[index].Package)
+	})
+
+	t.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'r':
+			onRerun()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetText(fmt.Sprintf("[%s]%s[-]", theme.ColorTag(t.theme.LegendColor), i18n.T("enter: select in table | r: audit again | esc: close")))
+	hint.SetBackgroundColor(t.theme.ModalBgColor)
+
+	panes := tview.NewFlex().
+		AddItem(t.list, 0, 1, true).
+		AddItem(t.findings, 0, 3, false)
+
+	content := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(panes, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+	content.SetBackgroundColor(t.theme.ModalBgColor)
+	content.SetBorder(true).
+		SetBorderColor(t.theme.BorderColor).
+		SetTitle(" " + i18n.T("Audit of %s (%d problems in %d packages)", tap, total, len(items)) + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the box in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, 0, 4, true).
+			AddItem(nil, 0, 1, false),
+			0, 6, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the audit as overlay
+	t.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("tapaudit", centered, true, true)
+
+	return t.pages
+}
+
+// render shows the findings of a package, tagged by the command that reported them.
+func (t *TapAudit) render(item TapAuditItem) {
+	var b strings.Builder
+	for _, finding := range item.Findings {
+		fmt.Fprintf(&b, "[%s]%s[-] %s\n", theme.ColorTag(t.theme.LegendColor), finding.Source, tview.Escape(finding.Message))
+	}
+	t.findings.SetTitle(" " + tview.Escape(item.Package) + " ")
+	t.findings.SetText(b.String())
+	t.findings.ScrollToBeginning()
+}
@@ -0,0 +1,78 @@
+package components
+
+import (
+	"bbrew/internal/ui/theme"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tooltipMinWidth is the width of a tooltip shown under a narrow cell, borders included.
+const tooltipMinWidth = 32
+
+// Tooltip shows the full text of a table cell cut to fit its column, e.g. a long description,
+// in a small box under the cell. Any key closes it.
+type Tooltip struct {
+	pages *tview.Pages
+	view  *tview.TextView
+	theme *theme.Theme
+}
+
+// NewTooltip creates a new tooltip component
+func NewTooltip(theme *theme.Theme) *Tooltip {
+	return &Tooltip{
+		theme: theme,
+	}
+}
+
+// View returns the tooltip pages (for overlay functionality)
+func (t *Tooltip) View() *tview.Pages {
+	return t.pages
+}
+
+// HasFocus returns true if the tooltip is currently open and focused
+func (t *Tooltip) HasFocus() bool {
+	return t.view != nil && t.view.HasFocus()
+}
+
+// Build creates the tooltip as an overlay on top of the main content, under the cell drawn at x, y with
+// the given width (screen coordinates), or above it when there is no room below. onClose is called when
+// a key is pressed.
+func (t *Tooltip) Build(mainContent tview.Primitive, title, text string, x, y, width int, onClose func()) *tview.Pages {
+	_, _, screenWidth, screenHeight := mainContent.GetRect()
+	width = min(max(width+2, tooltipMinWidth), screenWidth)
+	lines := tview.WordWrap(text, max(width-4, 1)) // Borders and padding
+	height := min(len(lines)+2, max(screenHeight/2, 3))
+
+	x = min(max(x-1, 0), screenWidth-width) // The text lines up with the cell
+	if y+1+height > screenHeight {
+		y = max(y-height, 0)
+	} else {
+		y++
+	}
+
+	t.view = tview.NewTextView().
+		SetText(tview.Escape(strings.Join(lines, "\n"))).
+		SetDynamicColors(true)
+	t.view.SetBackgroundColor(t.theme.ModalBgColor)
+	t.view.SetTextColor(t.theme.DefaultTextColor)
+	t.view.SetBorder(true).
+		SetBorderColor(t.theme.BorderColor).
+		SetTitle(" "+tview.Escape(title)+" ").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderPadding(0, 0, 1, 1)
+	t.view.SetRect(x, y, width, height)
+
+	t.view.SetInputCapture(func(_ *tcell.EventKey) *tcell.EventKey {
+		onClose()
+		return nil
+	})
+
+	// The tooltip keeps its position: it is not resized to the screen like the other overlays
+	t.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("tooltip", t.view, false, true)
+
+	return t.pages
+}
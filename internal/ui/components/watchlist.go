@@ -0,0 +1,131 @@
+package components
+
+import (
+	"bbrew/internal/i18n"
+	"bbrew/internal/ui/theme"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// WatchItem is a watched package, with the version the user last saw.
+type WatchItem struct {
+	Name        string
+	SeenVersion string
+	Version     string
+}
+
+// Changed returns true if the package has a new version since it was last seen.
+func (w WatchItem) Changed() bool {
+	return w.Version != "" && w.SeenVersion != w.Version
+}
+
+// Watchlist displays a modal overlay with the watched packages and their new versions
+type Watchlist struct {
+	pages *tview.Pages
+	view  *tview.TextView
+	theme *theme.Theme
+}
+
+// NewWatchlist creates a new watchlist component
+func NewWatchlist(theme *theme.Theme) *Watchlist {
+	return &Watchlist{
+		theme: theme,
+	}
+}
+
+// View returns the watchlist pages (for overlay functionality)
+func (w *Watchlist) View() *tview.Pages {
+	return w.pages
+}
+
+// HasFocus returns true if the watchlist is currently open and focused
+func (w *Watchlist) HasFocus() bool {
+	return w.view != nil && w.view.HasFocus()
+}
+
+// Build creates the watchlist as an overlay on top of the main content.
+// onClose is called when the overlay is dismissed.
+func (w *Watchlist) Build(mainContent tview.Primitive, items []WatchItem, onClose func()) *tview.Pages {
+	w.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(w.render(items))
+	w.view.SetBackgroundColor(w.theme.ModalBgColor)
+	w.view.SetTextColor(w.theme.DefaultTextColor)
+
+	w.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape, event.Rune() == 'q':
+			onClose()
+			return nil
+		case event.Rune() == 'j':
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		case event.Rune() == 'k':
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		return event
+	})
+
+	hint := fmt.Sprintf("[%s]%s[-]", theme.ColorTag(w.theme.LegendColor), i18n.T("esc: close (marks new versions as seen)"))
+	frame := tview.NewFrame(w.view).
+		SetBorders(1, 1, 1, 0, 2, 2).
+		AddText(hint, false, tview.AlignLeft, w.theme.LegendColor)
+	frame.SetBackgroundColor(w.theme.ModalBgColor)
+	frame.SetBorderColor(w.theme.BorderColor)
+	frame.SetBorder(true).
+		SetTitle(" " + i18n.T("Watchlist") + " ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the frame in a flex layout
+	centered := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(frame, 0, 3, true).
+			AddItem(nil, 0, 1, false),
+			0, 2, true).
+		AddItem(nil, 0, 1, false)
+
+	// Create pages with main content as background and the watchlist as overlay
+	w.pages = tview.NewPages().
+		AddPage("main", mainContent, true, true).
+		AddPage("watchlist", centered, true, true)
+
+	return w.pages
+}
+
+// render lists the new versions first, then the other watched packages
+func (w *Watchlist) render(items []WatchItem) string {
+	if len(items) == 0 {
+		return i18n.T("No watched packages yet: press W on a package to watch it.")
+	}
+
+	var changed, unchanged strings.Builder
+	for _, item := range items {
+		name := tview.Escape(item.Name)
+		if item.Changed() {
+			changed.WriteString(fmt.Sprintf(" [%s]%s[-] %s %s [%s]%s[-]\n", theme.ColorTag(w.theme.OutdatedColor), name,
+				tview.Escape(item.SeenVersion), w.theme.Symbols.Arrow, theme.ColorTag(w.theme.SuccessColor), tview.Escape(item.Version)))
+		} else {
+			unchanged.WriteString(fmt.Sprintf(" %s %s\n", name, tview.Escape(item.Version)))
+		}
+	}
+
+	var sb strings.Builder
+	title := func(text string) {
+		sb.WriteString(fmt.Sprintf("[%s::b]%s[-:-:-]\n", theme.ColorTag(w.theme.SectionTitleColor), text))
+	}
+	if changed.Len() > 0 {
+		title(i18n.T("New versions since last seen"))
+		sb.WriteString(changed.String() + "\n")
+	}
+	if unchanged.Len() > 0 {
+		title(i18n.T("No changes"))
+		sb.WriteString(unchanged.String())
+	}
+	return sb.String()
+}
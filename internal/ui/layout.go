@@ -17,38 +17,101 @@ type LayoutInterface interface {
 	GetDetails() *components.Details
 	GetOutput() *components.Output
 	GetLegend() *components.Legend
+	GetFilterBar() *components.FilterBar
 	GetNotifier() *components.Notifier
 	GetModal() *components.Modal
 	GetHelpScreen() *components.HelpScreen
+	GetColumnPicker() *components.ColumnPicker
+	GetReleaseNotes() *components.ReleaseNotes
+	GetLicenseAudit() *components.LicenseAudit
+	GetInspector() *components.Inspector
+	GetBatchProgress() *components.BatchProgress
+	GetPrompt() *components.Prompt
+	GetWatchlist() *components.Watchlist
+	GetHealth() *components.Health
+	GetSettings() *components.Settings
+	GetStorage() *components.Storage
+	GetServices() *components.Services
+	GetInventoryDiff() *components.InventoryDiff
+	GetCaveats() *components.Caveats
+	GetLog() *components.Log
+	GetMigrate() *components.Migrate
+	GetFiles() *components.Files
+	GetTapAudit() *components.TapAudit
+	GetRemoved() *components.Removed
+	GetProfiles() *components.Profiles
+	GetTooltip() *components.Tooltip
 }
 
 type Layout struct {
-	mainContent *tview.Grid
-	header      *components.Header
-	search      *components.Search
-	table       *components.Table
-	details     *components.Details
-	output      *components.Output
-	legend      *components.Legend
-	notifier    *components.Notifier
-	modal       *components.Modal
-	helpScreen  *components.HelpScreen
-	theme       *theme.Theme
+	mainContent   *tview.Grid
+	header        *components.Header
+	search        *components.Search
+	table         *components.Table
+	details       *components.Details
+	output        *components.Output
+	legend        *components.Legend
+	filterBar     *components.FilterBar
+	notifier      *components.Notifier
+	modal         *components.Modal
+	helpScreen    *components.HelpScreen
+	columnPicker  *components.ColumnPicker
+	releaseNotes  *components.ReleaseNotes
+	licenseAudit  *components.LicenseAudit
+	inspector     *components.Inspector
+	batchProgress *components.BatchProgress
+	prompt        *components.Prompt
+	watchlist     *components.Watchlist
+	health        *components.Health
+	settings      *components.Settings
+	storage       *components.Storage
+	services      *components.Services
+	inventory     *components.InventoryDiff
+	caveats       *components.Caveats
+	log           *components.Log
+	migrate       *components.Migrate
+	files         *components.Files
+	tapAudit      *components.TapAudit
+	removed       *components.Removed
+	profiles      *components.Profiles
+	tooltip       *components.Tooltip
+	theme         *theme.Theme
 }
 
 func NewLayout(theme *theme.Theme) LayoutInterface {
 	return &Layout{
-		mainContent: tview.NewGrid(),
-		header:      components.NewHeader(theme),
-		search:      components.NewSearch(theme),
-		table:       components.NewTable(theme),
-		details:     components.NewDetails(theme),
-		output:      components.NewOutput(theme),
-		legend:      components.NewLegend(theme),
-		notifier:    components.NewNotifier(theme),
-		modal:       components.NewModal(theme),
-		helpScreen:  components.NewHelpScreen(theme),
-		theme:       theme,
+		mainContent:   tview.NewGrid(),
+		header:        components.NewHeader(theme),
+		search:        components.NewSearch(theme),
+		table:         components.NewTable(theme),
+		details:       components.NewDetails(theme),
+		output:        components.NewOutput(theme),
+		legend:        components.NewLegend(theme),
+		filterBar:     components.NewFilterBar(theme),
+		notifier:      components.NewNotifier(theme),
+		modal:         components.NewModal(theme),
+		helpScreen:    components.NewHelpScreen(theme),
+		columnPicker:  components.NewColumnPicker(theme),
+		releaseNotes:  components.NewReleaseNotes(theme),
+		licenseAudit:  components.NewLicenseAudit(theme),
+		inspector:     components.NewInspector(theme),
+		batchProgress: components.NewBatchProgress(theme),
+		prompt:        components.NewPrompt(theme),
+		watchlist:     components.NewWatchlist(theme),
+		health:        components.NewHealth(theme),
+		settings:      components.NewSettings(theme),
+		storage:       components.NewStorage(theme),
+		services:      components.NewServices(theme),
+		inventory:     components.NewInventoryDiff(theme),
+		caveats:       components.NewCaveats(theme),
+		log:           components.NewLog(theme),
+		migrate:       components.NewMigrate(theme),
+		files:         components.NewFiles(theme),
+		tapAudit:      components.NewTapAudit(theme),
+		removed:       components.NewRemoved(theme),
+		profiles:      components.NewProfiles(theme),
+		tooltip:       components.NewTooltip(theme),
+		theme:         theme,
 	}
 }
 
@@ -63,15 +126,26 @@ func (l *Layout) setupLayout() {
 		AddItem(l.search.Field(), 0, 1, false).
 		AddItem(l.search.Counter(), 0, 1, false)
 
+	// The compact row spacing drops the margins, to fit more of the table
+	margin, searchHeight := 3, 2
+	if l.theme.RowSpacing == theme.RowSpacingCompact {
+		margin, searchHeight = 0, 1
+	}
+
 	filtersArea := tview.NewFrame(searchRow).
-		SetBorders(0, 0, 0, 0, 3, 3)
+		SetBorders(0, 0, 0, 0, margin, margin)
+
+	// Quick filters, as tabs above the table
+	filterTabs := tview.NewFrame(l.filterBar.View()).
+		SetBorders(0, 0, 0, 0, margin, margin)
 
 	tableFrame := tview.NewFrame(l.table.View()).
-		SetBorders(0, 0, 0, 0, 3, 3)
+		SetBorders(0, 0, 0, 0, margin, margin)
 
-	// Left column with search and table
+	// Left column with search, quick filters and table
 	leftColumn := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(filtersArea, 2, 0, false).
+		AddItem(filtersArea, searchHeight, 0, false).
+		AddItem(filterTabs, 1, 0, false).
 		AddItem(tableFrame, 0, 4, false)
 
 	// Right column with details and output
@@ -90,7 +164,7 @@ func (l *Layout) setupLayout() {
 
 	// Final layout
 	l.mainContent.
-		SetRows(1, 0, 1).
+		SetRows(2, 0, 1).
 		SetColumns(0).
 		SetBorders(true).
 		AddItem(headerContent, 0, 0, 1, 1, 0, 0, false).
@@ -106,12 +180,33 @@ func (l *Layout) Root() tview.Primitive {
 	return l.mainContent
 }
 
-func (l *Layout) GetHeader() *components.Header         { return l.header }
-func (l *Layout) GetSearch() *components.Search         { return l.search }
-func (l *Layout) GetTable() *components.Table           { return l.table }
-func (l *Layout) GetDetails() *components.Details       { return l.details }
-func (l *Layout) GetOutput() *components.Output         { return l.output }
-func (l *Layout) GetLegend() *components.Legend         { return l.legend }
-func (l *Layout) GetNotifier() *components.Notifier     { return l.notifier }
-func (l *Layout) GetModal() *components.Modal           { return l.modal }
-func (l *Layout) GetHelpScreen() *components.HelpScreen { return l.helpScreen }
+func (l *Layout) GetHeader() *components.Header               { return l.header }
+func (l *Layout) GetSearch() *components.Search               { return l.search }
+func (l *Layout) GetTable() *components.Table                 { return l.table }
+func (l *Layout) GetDetails() *components.Details             { return l.details }
+func (l *Layout) GetOutput() *components.Output               { return l.output }
+func (l *Layout) GetLegend() *components.Legend               { return l.legend }
+func (l *Layout) GetFilterBar() *components.FilterBar         { return l.filterBar }
+func (l *Layout) GetNotifier() *components.Notifier           { return l.notifier }
+func (l *Layout) GetModal() *components.Modal                 { return l.modal }
+func (l *Layout) GetHelpScreen() *components.HelpScreen       { return l.helpScreen }
+func (l *Layout) GetColumnPicker() *components.ColumnPicker   { return l.columnPicker }
+func (l *Layout) GetReleaseNotes() *components.ReleaseNotes   { return l.releaseNotes }
+func (l *Layout) GetLicenseAudit() *components.LicenseAudit   { return l.licenseAudit }
+func (l *Layout) GetInspector() *components.Inspector         { return l.inspector }
+func (l *Layout) GetBatchProgress() *components.BatchProgress { return l.batchProgress }
+func (l *Layout) GetPrompt() *components.Prompt               { return l.prompt }
+func (l *Layout) GetWatchlist() *components.Watchlist         { return l.watchlist }
+func (l *Layout) GetHealth() *components.Health               { return l.health }
+func (l *Layout) GetSettings() *components.Settings           { return l.settings }
+func (l *Layout) GetStorage() *components.Storage             { return l.storage }
+func (l *Layout) GetServices() *components.Services           { return l.services }
+func (l *Layout) GetInventoryDiff() *components.InventoryDiff { return l.inventory }
+func (l *Layout) GetCaveats() *components.Caveats             { return l.caveats }
+func (l *Layout) GetLog() *components.Log                     { return l.log }
+func (l *Layout) GetMigrate() *components.Migrate             { return l.migrate }
+func (l *Layout) GetFiles() *components.Files                 { return l.files }
+func (l *Layout) GetTapAudit() *components.TapAudit           { return l.tapAudit }
+func (l *Layout) GetRemoved() *components.Removed             { return l.removed }
+func (l *Layout) GetProfiles() *components.Profiles           { return l.profiles }
+func (l *Layout) GetTooltip() *components.Tooltip             { return l.tooltip }
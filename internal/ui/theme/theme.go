@@ -1,10 +1,51 @@
 package theme
 
 import (
+	"fmt"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// Options controls the accessibility variants of the theme.
+type Options struct {
+	HighContrast  bool   // Bright colors on an explicit black background
+	ASCII         bool   // Plain ASCII symbols instead of unicode ones
+	RowSpacing    string // Spacing of the package table: RowSpacingCompact, RowSpacingPadded or "" for the default
+	ReducedMotion bool   // No moving progress bars, screen flashes nor transient notifications
+}
+
+// Row spacings of the package table.
+const (
+	RowSpacingCompact = "compact" // No margins around the table and the search field
+	RowSpacingPadded  = "padded"  // A blank line between rows
+)
+
+// Symbols holds the decorative characters used across the UI.
+type Symbols struct {
+	Bullet    string
+	Separator string
+	Arrow     string
+	Ellipsis  string
+	UpDown    string
+	Expanded  string
+	Collapsed string
+	Check     string
+	Cross     string
+	BarFull   string
+	BarEmpty  string
+	Star      string
+	Spinner   string // Frames of the spinner of in-progress notifications, one per character
+}
+
+// unicodeSymbols are the default symbols.
+var unicodeSymbols = Symbols{Bullet: "•", Separator: "─", Arrow: "→", Ellipsis: "…", UpDown: "↑/↓", Expanded: "▾", Collapsed: "▸",
+	Check: "✓", Cross: "✗", BarFull: "█", BarEmpty: "░", Star: "★", Spinner: "⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏"}
+
+// asciiSymbols are used in ASCII mode, for limited fonts and screen readers.
+var asciiSymbols = Symbols{Bullet: "*", Separator: "-", Arrow: "->", Ellipsis: "...", UpDown: "up/down", Expanded: "v", Collapsed: ">",
+	Check: "ok", Cross: "x", BarFull: "#", BarEmpty: ".", Star: "*", Spinner: `|/-\`}
+
 type Theme struct {
 	// Application-specific colors
 	DefaultTextColor tcell.Color
@@ -22,6 +63,21 @@ type Theme struct {
 	LegendColor      tcell.Color
 	TableHeaderColor tcell.Color
 	SearchLabelColor tcell.Color
+	MatchColor       tcell.Color // Text matching the search in the table
+
+	// Package state and Details colors
+	OutdatedColor     tcell.Color
+	UnavailableColor  tcell.Color
+	ThirdPartyColor   tcell.Color // Packages from taps other than homebrew/core and homebrew/cask
+	SectionTitleColor tcell.Color
+	FieldLabelColor   tcell.Color
+
+	// Decorative characters (plain ASCII in ASCII mode)
+	Symbols Symbols
+
+	// Layout and motion variants (see Options)
+	RowSpacing    string
+	ReducedMotion bool
 
 	// tview global styles (mapped to tview.Styles)
 	PrimitiveBackgroundColor    tcell.Color
@@ -36,7 +92,7 @@ type Theme struct {
 	ContrastSecondaryTextColor  tcell.Color
 }
 
-func NewTheme() *Theme {
+func NewTheme(opts Options) *Theme {
 	theme := &Theme{
 		// Application-specific colors
 		DefaultTextColor: tcell.ColorDefault,
@@ -57,6 +113,15 @@ func NewTheme() *Theme {
 		LegendColor:      tcell.ColorDefault,
 		TableHeaderColor: tcell.ColorBlue,
 		SearchLabelColor: tcell.ColorPurple,
+		MatchColor:       tcell.ColorPurple,
+
+		OutdatedColor:     tcell.ColorOrange,
+		UnavailableColor:  tcell.ColorGray,
+		ThirdPartyColor:   tcell.ColorTeal,
+		SectionTitleColor: tcell.ColorYellow,
+		FieldLabelColor:   tcell.ColorBlue,
+
+		Symbols: unicodeSymbols,
 
 		// tview global styles - use terminal default colors for better compatibility
 		// By default, tview uses hardcoded colors (like tcell.ColorBlack) which don't
@@ -73,6 +138,15 @@ func NewTheme() *Theme {
 		ContrastSecondaryTextColor:  tcell.ColorDefault,
 	}
 
+	if opts.HighContrast {
+		theme.applyHighContrast()
+	}
+	if opts.ASCII {
+		theme.Symbols = asciiSymbols
+	}
+	theme.RowSpacing = opts.RowSpacing
+	theme.ReducedMotion = opts.ReducedMotion
+
 	// Apply theme to tview global styles
 	tview.Styles.PrimitiveBackgroundColor = theme.PrimitiveBackgroundColor
 	tview.Styles.ContrastBackgroundColor = theme.ContrastBackgroundColor
@@ -88,3 +162,50 @@ func NewTheme() *Theme {
 
 	return theme
 }
+
+// applyHighContrast switches to bright colors on an explicit black background,
+// instead of relying on the terminal palette.
+func (t *Theme) applyHighContrast() {
+	t.DefaultTextColor = tcell.ColorWhite
+	t.DefaultBgColor = tcell.ColorBlack
+	t.WarningColor = tcell.ColorYellow
+	t.SuccessColor = tcell.ColorLime
+	t.ErrorColor = tcell.ColorRed
+
+	t.TitleColor = tcell.ColorWhite
+	t.LabelColor = tcell.ColorYellow
+	t.ButtonBgColor = tcell.ColorWhite
+	t.ButtonTextColor = tcell.ColorBlack
+
+	t.ModalBgColor = tcell.ColorBlack
+	t.LegendColor = tcell.ColorWhite
+	t.TableHeaderColor = tcell.ColorAqua
+	t.SearchLabelColor = tcell.ColorAqua
+	t.MatchColor = tcell.ColorFuchsia
+
+	t.OutdatedColor = tcell.ColorYellow
+	t.UnavailableColor = tcell.ColorSilver
+	t.ThirdPartyColor = tcell.ColorFuchsia
+	t.SectionTitleColor = tcell.ColorYellow
+	t.FieldLabelColor = tcell.ColorAqua
+
+	t.PrimitiveBackgroundColor = tcell.ColorBlack
+	t.ContrastBackgroundColor = tcell.ColorBlack
+	t.MoreContrastBackgroundColor = tcell.ColorBlack
+	t.BorderColor = tcell.ColorWhite
+	t.GraphicsColor = tcell.ColorWhite
+	t.PrimaryTextColor = tcell.ColorWhite
+	t.SecondaryTextColor = tcell.ColorYellow
+	t.TertiaryTextColor = tcell.ColorAqua
+	t.InverseTextColor = tcell.ColorBlack
+	t.ContrastSecondaryTextColor = tcell.ColorWhite
+}
+
+// ColorTag converts a tcell.Color to a tview color tag value (e.g. "#ffff00").
+// The terminal default color maps to "-", which resets to the default.
+func ColorTag(color tcell.Color) string {
+	if color == tcell.ColorDefault {
+		return "-"
+	}
+	return fmt.Sprintf("#%06x", color.Hex())
+}
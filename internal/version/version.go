@@ -0,0 +1,274 @@
+// Package version compares the versions of Homebrew packages.
+//
+// They mostly follow semver, with a few Homebrew conventions:
+//
+//	1.2.3_1        formula revision, a rebuild of the same version
+//	1.2.3,4567     cask build number (or other comma-separated part)
+//	1.2.3-rc1      pre-release, before 1.2.3
+//	1.1.1w         letter suffix, after 1.1.1
+//	latest         unversioned cask, never compared
+//	HEAD-abc1234   formula built from the head of its repository, never compared
+package version
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Latest is the version of the casks without a version, which update themselves.
+const Latest = "latest"
+
+// preReleases ranks the words marking a pre-release, which come before the release.
+var preReleases = map[string]int{
+	"dev":     1,
+	"alpha":   2,
+	"a":       2, // Only followed by a number, e.g. 1.0a1
+	"beta":    3,
+	"b":       3,
+	"pre":     4,
+	"preview": 4,
+	"rc":      5,
+}
+
+// Version is a parsed package version.
+type Version struct {
+	Raw      string
+	Parts    []string // Numeric and word components of the version, before the comma
+	Build    []string // Components after the comma, for cask versions
+	Revision int      // Formula revision, after the last underscore
+}
+
+// Parse splits a version into its components. It never fails: any string is a version.
+func Parse(raw string) Version {
+	v := Version{Raw: raw}
+	main := strings.TrimSpace(raw)
+	if i := strings.LastIndex(main, "_"); i != -1 {
+		if revision, err := strconv.Atoi(main[i+1:]); err == nil {
+			v.Revision, main = revision, main[:i]
+		}
+	}
+	main, build, _ := strings.Cut(main, ",")
+	v.Parts, v.Build = components(main), components(build)
+	return v
+}
+
+// components splits a version on its separators and between digits and letters: "1.0rc2" → 1, 0, rc, 2.
+func components(s string) []string {
+	var parts []string
+	current := []rune{}
+	flush := func() {
+		if len(current) > 0 {
+			parts = append(parts, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case len(current) > 0 && unicode.IsDigit(r) != unicode.IsDigit(current[len(current)-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return parts
+}
+
+// IsLatest reports whether the version is the unversioned "latest" of casks.
+func (v Version) IsLatest() bool {
+	return strings.EqualFold(strings.TrimSpace(v.Raw), Latest)
+}
+
+// Compare compares two versions, returning -1, 0 or 1. Missing components count as zero,
+// so "13" equals "13.0". The build part and the revision break the ties, in that order.
+func (v Version) Compare(other Version) int {
+	if result := compareComponents(v.Parts, other.Parts); result != 0 {
+		return result
+	}
+	if result := compareComponents(v.Build, other.Build); result != 0 {
+		return result
+	}
+	return compareInts(v.Revision, other.Revision)
+}
+
+// Compare parses and compares two versions, returning -1, 0 or 1.
+func Compare(a, b string) int {
+	return Parse(a).Compare(Parse(b))
+}
+
+// IsHead reports whether the version is a build of the head of a repository (HEAD-abc1234).
+func (v Version) IsHead() bool {
+	return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(v.Raw)), "HEAD")
+}
+
+// Newer reports whether latest is newer than installed. Unversioned casks ("latest"), head builds
+// and empty versions are never newer, since nothing tells.
+func Newer(installed, latest string) bool {
+	a, b := Parse(installed), Parse(latest)
+	if a.IsLatest() || b.IsLatest() || a.IsHead() || b.IsHead() || len(a.Parts) == 0 || len(b.Parts) == 0 {
+		return false
+	}
+	return a.Compare(b) < 0
+}
+
+// compareComponents compares the components of two versions in order.
+func compareComponents(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if result := compareComponent(at(a, i), at(b, i), a, b, i); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// compareComponent compares the components at index i of two versions, "" when missing.
+// Numbers compare by value, a missing component counts as zero. Pre-releases come before anything else,
+// other words after numbers and missing components (1.1.1w is after 1.1.1).
+func compareComponent(a, b string, partsA, partsB []string, i int) int {
+	numA, numB := isNumber(a), isNumber(b)
+	switch {
+	case a == b:
+		return 0
+	case numA && numB:
+		return compareNumbers(a, b)
+	}
+
+	rankA, rankB := preReleaseRank(partsA, i), preReleaseRank(partsB, i)
+	switch {
+	case rankA > 0 && rankB > 0:
+		if rankA != rankB {
+			return compareInts(rankA, rankB)
+		}
+		return strings.Compare(a, b)
+	case rankA > 0:
+		return -1
+	case rankB > 0:
+		return 1
+	case a == "":
+		return compareMissing(b)
+	case b == "":
+		return -compareMissing(a)
+	case numA:
+		return 1 // Numbers after words
+	case numB:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// compareMissing compares a missing component with a present one: before a word or a positive number.
+func compareMissing(present string) int {
+	if isNumber(present) {
+		return compareNumbers("0", present)
+	}
+	return -1
+}
+
+// preReleaseRank returns the rank of the component at index i if it marks a pre-release, else 0.
+// The single letters "a" and "b" only do when followed by a number, so 1.1.1b stays a patch letter.
+func preReleaseRank(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	rank := preReleases[parts[i]]
+	if len(parts[i]) == 1 && (i+1 >= len(parts) || !isNumber(parts[i+1])) {
+		return 0
+	}
+	return rank
+}
+
+// isNumber reports whether a component is a number: only digits, of any length (dates, build numbers).
+func isNumber(part string) bool {
+	if part == "" {
+		return false
+	}
+	for i := 0; i < len(part); i++ {
+		if part[i] < '0' || part[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNumbers compares two numeric components by value, without parsing them, so that no length
+// overflows: without leading zeros, the longer number is the larger, and equal lengths compare by digit.
+func compareNumbers(a, b string) int {
+	a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return compareInts(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+// compareInts compares two numbers, returning -1, 0 or 1.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Change is the most significant component that differs between two versions.
+type Change int
+
+const (
+	ChangeNone     Change = iota
+	ChangeRevision        // Same version, rebuilt (1.2.3_1)
+	ChangeBuild           // Same version, new build (cask 1.2.3,4567)
+	ChangePatch           // Third component or beyond (1.2.3 → 1.2.4)
+	ChangeMinor           // Second component (1.2 → 1.3)
+	ChangeMajor           // First component (1.x → 2.0)
+)
+
+// Delta returns the most significant change from installed to latest.
+func Delta(installed, latest string) Change {
+	a, b := Parse(installed), Parse(latest)
+	for i := 0; i < len(a.Parts) || i < len(b.Parts); i++ {
+		if compareComponent(at(a.Parts, i), at(b.Parts, i), a.Parts, b.Parts, i) != 0 {
+			switch i {
+			case 0:
+				return ChangeMajor
+			case 1:
+				return ChangeMinor
+			}
+			return ChangePatch
+		}
+	}
+	if compareComponents(a.Build, b.Build) != 0 {
+		return ChangeBuild
+	}
+	if a.Revision != b.Revision {
+		return ChangeRevision
+	}
+	return ChangeNone
+}
+
+// at returns the component at index i, "" when missing.
+func at(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return ""
+}
+
+// SplitChanged splits latest into the prefix it shares with installed, cut at a separator so that
+// whole components are kept together (1.9 → 1.10 shares "1.", not "1.1"), and the part that changed.
+func SplitChanged(installed, latest string) (common, changed string) {
+	n := 0
+	for n < len(installed) && n < len(latest) && installed[n] == latest[n] {
+		n++
+	}
+	if n < len(installed) || n < len(latest) {
+		for n > 0 && !strings.ContainsRune(".-_,+", rune(latest[n-1])) {
+			n--
+		}
+	}
+	return latest[:n], latest[n:]
+}
@@ -0,0 +1,86 @@
+package version
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		// Numeric components compare by value, whatever their length
+		{"1.10", "1.9", 1},
+		{"1.9", "1.10", -1},
+		{"1.02", "1.2", 0},
+		{"1.0.00", "1", 0},
+		{"13", "13.0", 0},
+		{"99999999999999999999", "1", 1},
+		{"20240101000000000000", "20240101000000000001", -1},
+		{"1.2.99999999999999999999", "1.2.100000000000000000000", -1},
+
+		// Formula revisions break the ties
+		{"1.2.3_1", "1.2.3", 1},
+		{"1.2.3_1", "1.2.3_2", -1},
+		{"1.2.4", "1.2.3_9", 1},
+
+		// Cask builds break the ties, before the revision
+		{"1.2.3,4567", "1.2.3,4566", 1},
+		{"1.2.3,4567", "1.2.4,1", -1},
+
+		// Pre-releases come before the release, in order
+		{"1.0rc1", "1.0", -1},
+		{"1.0-rc1", "1.0-rc2", -1},
+		{"1.0-beta2", "1.0-rc1", -1},
+		{"2.0a1", "2.0b1", -1},
+		{"2.0", "2.0a1", 1},
+		{"1.0-dev", "1.0-alpha", -1},
+
+		// Letter suffixes come after the release
+		{"1.1.1w", "1.1.1", 1},
+		{"1.1.1b", "1.1.1a", 1},
+		{"1.1.1b", "1.1.1", 1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		installed, latest string
+		want              bool
+	}{
+		{"1.9", "1.10", true},
+		{"1.10", "1.9", false},
+		{"1.2.3", "1.2.3_1", true},
+		{"1.0", "1.0rc1", false},
+		{"latest", "1.0", false},
+		{"HEAD-abc1234", "1.0", false},
+		{"", "1.0", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.installed, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.installed, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		installed, latest string
+		want              Change
+	}{
+		{"1.9", "2.0", ChangeMajor},
+		{"1.9", "1.10", ChangeMinor},
+		{"1.2.3", "1.2.4", ChangePatch},
+		{"1.2.3,1", "1.2.3,2", ChangeBuild},
+		{"1.2.3", "1.2.3_1", ChangeRevision},
+		{"1.2.3", "1.2.3", ChangeNone},
+	}
+	for _, tt := range tests {
+		if got := Delta(tt.installed, tt.latest); got != tt.want {
+			t.Errorf("Delta(%q, %q) = %d, want %d", tt.installed, tt.latest, got, tt.want)
+		}
+	}
+}